@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/domain"
+)
+
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// SessionResponse carries the CSRF token a session's browser has to echo
+// back in X-CSRF-Token. The session secret itself only travels in the
+// cookie.
+type SessionResponse struct {
+	ID         uuid.UUID    `json:"id"`
+	CreatedAt  time.Time    `json:"created_at"`
+	LastSeenAt time.Time    `json:"last_seen_at"`
+	ExpiresAt  time.Time    `json:"expires_at"`
+	CSRFToken  string       `json:"csrf_token"`
+	User       UserResponse `json:"user"`
+}
+
+// PasswordResetResponse is returned only once, to the admin who issued the
+// reset, and carries the plaintext token.
+type PasswordResetResponse struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Token     string    `json:"token"`
+}
+
+func SessionToResponse(session *domain.Session, user *domain.User) SessionResponse {
+	return SessionResponse{
+		ID:         session.ID,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+		CSRFToken:  session.CSRFToken,
+		User:       UserToResponse(user),
+	}
+}
+
+func PasswordResetToResponse(reset *domain.PasswordReset) PasswordResetResponse {
+	return PasswordResetResponse{
+		ID:        reset.ID,
+		UserID:    reset.UserID,
+		ExpiresAt: reset.ExpiresAt,
+		Token:     reset.Token,
+	}
+}
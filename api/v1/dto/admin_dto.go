@@ -0,0 +1,94 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/hel1th/rssagg/internal/domain"
+)
+
+type AdminUserResponse struct {
+	UserResponse
+	DisabledAt  *time.Time `json:"disabled_at"`
+	FollowCount int64      `json:"follow_count"`
+	FeedCount   int64      `json:"feed_count"`
+}
+
+type AdminFeedResponse struct {
+	FeedResponse
+	DisabledAt     *time.Time `json:"disabled_at"`
+	LastFetchError *string    `json:"last_fetch_error"`
+}
+
+type ScraperWorkerResponse struct {
+	ID                string     `json:"id"`
+	StartedAt         time.Time  `json:"started_at"`
+	LastBeatAt        time.Time  `json:"last_beat_at"`
+	LastRunStartedAt  *time.Time `json:"last_run_started_at"`
+	LastRunFinishedAt *time.Time `json:"last_run_finished_at"`
+	LastRunFeeds      int        `json:"last_run_feeds"`
+}
+
+type ScraperFeedStatsResponse struct {
+	Total           int64      `json:"total"`
+	Disabled        int64      `json:"disabled"`
+	NeverFetched    int64      `json:"never_fetched"`
+	Failing         int64      `json:"failing"`
+	OldestFetchedAt *time.Time `json:"oldest_fetched_at"`
+}
+
+type ScraperStatusResponse struct {
+	Workers      []ScraperWorkerResponse  `json:"workers"`
+	Feeds        ScraperFeedStatsResponse `json:"feeds"`
+	FailingFeeds []AdminFeedResponse      `json:"failing_feeds"`
+}
+
+func AdminUsersToResponse(users []*domain.AdminUser) []AdminUserResponse {
+	responses := make([]AdminUserResponse, len(users))
+	for i, user := range users {
+		responses[i] = AdminUserResponse{
+			UserResponse: UserToResponse(&user.User),
+			DisabledAt:   user.DisabledAt,
+			FollowCount:  user.FollowCount,
+			FeedCount:    user.FeedCount,
+		}
+	}
+	return responses
+}
+
+func AdminFeedToResponse(feed *domain.Feed) AdminFeedResponse {
+	return AdminFeedResponse{
+		FeedResponse:   FeedToResponse(feed),
+		DisabledAt:     feed.DisabledAt,
+		LastFetchError: feed.LastFetchError,
+	}
+}
+
+func ScraperStatusToResponse(status *domain.ScraperStatus) ScraperStatusResponse {
+	response := ScraperStatusResponse{
+		Workers: make([]ScraperWorkerResponse, len(status.Workers)),
+		Feeds: ScraperFeedStatsResponse{
+			Total:           status.TotalFeeds,
+			Disabled:        status.DisabledFeeds,
+			NeverFetched:    status.NeverFetchedFeeds,
+			Failing:         status.FailingFeedCount,
+			OldestFetchedAt: status.OldestFetchedAt,
+		},
+		FailingFeeds: make([]AdminFeedResponse, len(status.FailingFeeds)),
+	}
+
+	for i, worker := range status.Workers {
+		response.Workers[i] = ScraperWorkerResponse{
+			ID:                worker.ID,
+			StartedAt:         worker.StartedAt,
+			LastBeatAt:        worker.LastBeatAt,
+			LastRunStartedAt:  worker.LastRunStartedAt,
+			LastRunFinishedAt: worker.LastRunFinishedAt,
+			LastRunFeeds:      worker.LastRunFeeds,
+		}
+	}
+	for i, feed := range status.FailingFeeds {
+		response.FailingFeeds[i] = AdminFeedToResponse(feed)
+	}
+
+	return response
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/domain"
+)
+
+type AuditEntryResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ActorID    *uuid.UUID `json:"actor_id"`
+	UserID     *uuid.UUID `json:"user_id"`
+	Action     string     `json:"action"`
+	TargetType string     `json:"target_type"`
+	TargetID   *uuid.UUID `json:"target_id"`
+	IP         string     `json:"ip,omitempty"`
+	UserAgent  string     `json:"user_agent,omitempty"`
+	Before     any        `json:"before"`
+	After      any        `json:"after"`
+}
+
+func AuditEntryToResponse(entry *domain.AuditEntry) AuditEntryResponse {
+	return AuditEntryResponse{
+		ID:         entry.ID,
+		CreatedAt:  entry.CreatedAt,
+		ActorID:    entry.ActorID,
+		UserID:     entry.UserID,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		IP:         entry.IP,
+		UserAgent:  entry.UserAgent,
+		Before:     entry.Before,
+		After:      entry.After,
+	}
+}
+
+func AuditEntriesToResponse(entries []*domain.AuditEntry) []AuditEntryResponse {
+	responses := make([]AuditEntryResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = AuditEntryToResponse(entry)
+	}
+	return responses
+}
@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"net/http"
+
+	"github.com/hel1th/rssagg/internal/domain"
+)
+
+// ProblemContentType is the media type of Problem responses.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details response. Type is always
+// about:blank, so Title is the status text; Code says what kind of error
+// it is, and Detail what went wrong.
+type Problem struct {
+	Type   string           `json:"type"`
+	Title  string           `json:"title"`
+	Status int              `json:"status"`
+	Detail string           `json:"detail,omitempty"`
+	Code   domain.ErrorCode `json:"code"`
+}
+
+func NewProblem(status int, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   ErrorCodeForStatus(status),
+	}
+}
+
+// ErrorStatus is the HTTP status of errors with code.
+func ErrorStatus(code domain.ErrorCode) int {
+	switch code {
+	case domain.ErrorCodeInvalid:
+		return http.StatusBadRequest
+	case domain.ErrorCodeUnauthorized:
+		return http.StatusUnauthorized
+	case domain.ErrorCodeForbidden:
+		return http.StatusForbidden
+	case domain.ErrorCodeNotFound:
+		return http.StatusNotFound
+	case domain.ErrorCodeConflict:
+		return http.StatusConflict
+	case domain.ErrorCodeRateLimited:
+		return http.StatusTooManyRequests
+	case domain.ErrorCodeUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ErrorCodeForStatus is the code of errors served with status, for
+// responses not made from a domain error.
+func ErrorCodeForStatus(status int) domain.ErrorCode {
+	switch {
+	case status == http.StatusUnauthorized:
+		return domain.ErrorCodeUnauthorized
+	case status == http.StatusForbidden:
+		return domain.ErrorCodeForbidden
+	case status == http.StatusNotFound:
+		return domain.ErrorCodeNotFound
+	case status == http.StatusConflict:
+		return domain.ErrorCodeConflict
+	case status == http.StatusTooManyRequests:
+		return domain.ErrorCodeRateLimited
+	case status == http.StatusBadGateway, status == http.StatusServiceUnavailable, status == http.StatusGatewayTimeout:
+		return domain.ErrorCodeUnavailable
+	case status >= 400 && status < 500:
+		return domain.ErrorCodeInvalid
+	default:
+		return domain.ErrorCodeInternal
+	}
+}
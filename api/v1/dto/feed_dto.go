@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,6 +21,15 @@ type FeedResponse struct {
 	URL           string     `json:"url"`
 	UserID        uuid.UUID  `json:"user_id"`
 	LastFetchedAt *time.Time `json:"last_fetched_at,omitempty"`
+	SiteTitle     *string    `json:"site_title,omitempty"`
+	SiteURL       *string    `json:"site_url,omitempty"`
+}
+
+type FeedDirectoryEntryResponse struct {
+	FeedResponse
+	FollowerCount int64      `json:"follower_count"`
+	PostsPerWeek  float64    `json:"posts_per_week"`
+	LastPostAt    *time.Time `json:"last_post_at,omitempty"`
 }
 
 func FeedToResponse(feed *domain.Feed) FeedResponse {
@@ -31,6 +41,8 @@ func FeedToResponse(feed *domain.Feed) FeedResponse {
 		URL:           feed.URL,
 		UserID:        feed.UserID,
 		LastFetchedAt: feed.LastFetchedAt,
+		SiteTitle:     feed.SiteTitle,
+		SiteURL:       feed.SiteURL,
 	}
 }
 
@@ -41,3 +53,20 @@ func FeedsToResponse(feeds []*domain.Feed) []FeedResponse {
 	}
 	return responses
 }
+
+func FeedDirectoryEntryToResponse(entry *domain.FeedDirectoryEntry) FeedDirectoryEntryResponse {
+	return FeedDirectoryEntryResponse{
+		FeedResponse:  FeedToResponse(&entry.Feed),
+		FollowerCount: entry.FollowerCount,
+		PostsPerWeek:  math.Round(entry.PostsPerWeek()*100) / 100,
+		LastPostAt:    entry.LastPostAt,
+	}
+}
+
+func FeedDirectoryToResponse(entries []*domain.FeedDirectoryEntry) []FeedDirectoryEntryResponse {
+	responses := make([]FeedDirectoryEntryResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = FeedDirectoryEntryToResponse(entry)
+	}
+	return responses
+}
@@ -11,21 +11,51 @@ type CreateFeedFollowRequest struct {
 	FeedID uuid.UUID `json:"feed_id"`
 }
 
+type UpdateFeedFollowRequest struct {
+	FolderID         *uuid.UUID `json:"folder_id"`
+	Title            *string    `json:"title"`
+	Muted            *bool      `json:"muted"`
+	Priority         *int32     `json:"priority"`
+	NotificationMode *string    `json:"notification_mode"`
+}
+
 type FeedFollowResponse struct {
-	ID        uuid.UUID `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	UserID    uuid.UUID `json:"user_id"`
-	FeedID    uuid.UUID `json:"feed_id"`
+	ID               uuid.UUID  `json:"id"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	UserID           uuid.UUID  `json:"user_id"`
+	FeedID           uuid.UUID  `json:"feed_id"`
+	Title            string     `json:"title"`
+	FeedName         string     `json:"feed_name"`
+	FolderID         *uuid.UUID `json:"folder_id,omitempty"`
+	Muted            bool       `json:"muted"`
+	Priority         int32      `json:"priority"`
+	NotificationMode string     `json:"notification_mode"`
+}
+
+func (req UpdateFeedFollowRequest) ToDomain() domain.FeedFollowUpdate {
+	return domain.FeedFollowUpdate{
+		FolderID:         req.FolderID,
+		Title:            req.Title,
+		Muted:            req.Muted,
+		Priority:         req.Priority,
+		NotificationMode: req.NotificationMode,
+	}
 }
 
 func FeedFollowToResponse(ff *domain.FeedFollow) FeedFollowResponse {
 	return FeedFollowResponse{
-		ID:        ff.ID,
-		CreatedAt: ff.CreatedAt,
-		UpdatedAt: ff.UpdatedAt,
-		UserID:    ff.UserID,
-		FeedID:    ff.FeedID,
+		ID:               ff.ID,
+		CreatedAt:        ff.CreatedAt,
+		UpdatedAt:        ff.UpdatedAt,
+		UserID:           ff.UserID,
+		FeedID:           ff.FeedID,
+		Title:            ff.DisplayTitle(),
+		FeedName:         ff.FeedName,
+		FolderID:         ff.FolderID,
+		Muted:            ff.Muted,
+		Priority:         ff.Priority,
+		NotificationMode: ff.NotificationMode,
 	}
 }
 
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/domain"
+)
+
+type CreateFeedTokenRequest struct {
+	Name string `json:"name"`
+}
+
+type FeedTokenResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Name       string     `json:"name"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// CreatedFeedTokenResponse is returned only once, on creation, and carries
+// the plaintext token together with ready-to-subscribe output URLs.
+type CreatedFeedTokenResponse struct {
+	FeedTokenResponse
+	Token       string `json:"token"`
+	TimelineURL string `json:"timeline_url"`
+	StarredURL  string `json:"starred_url"`
+}
+
+func FeedTokenToResponse(token *domain.FeedToken) FeedTokenResponse {
+	return FeedTokenResponse{
+		ID:         token.ID,
+		CreatedAt:  token.CreatedAt,
+		Name:       token.Name,
+		LastUsedAt: token.LastUsedAt,
+	}
+}
+
+func FeedTokensToResponse(tokens []*domain.FeedToken) []FeedTokenResponse {
+	responses := make([]FeedTokenResponse, len(tokens))
+	for i, token := range tokens {
+		responses[i] = FeedTokenToResponse(token)
+	}
+	return responses
+}
@@ -0,0 +1,144 @@
+package dto
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hel1th/rssagg/internal/domain"
+)
+
+// Fever encodes booleans as 0/1, times as unix seconds and ID lists as
+// comma separated strings.
+
+type FeverGroupResponse struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+type FeverFeedResponse struct {
+	ID                int64  `json:"id"`
+	FaviconID         int64  `json:"favicon_id"`
+	Title             string `json:"title"`
+	URL               string `json:"url"`
+	SiteURL           string `json:"site_url"`
+	IsSpark           int    `json:"is_spark"`
+	LastUpdatedOnTime int64  `json:"last_updated_on_time"`
+}
+
+type FeverFeedsGroupResponse struct {
+	GroupID int64  `json:"group_id"`
+	FeedIDs string `json:"feed_ids"`
+}
+
+type FeverFaviconResponse struct {
+	ID   int64  `json:"id"`
+	Data string `json:"data"`
+}
+
+type FeverItemResponse struct {
+	ID            int64  `json:"id"`
+	FeedID        int64  `json:"feed_id"`
+	Title         string `json:"title"`
+	Author        string `json:"author"`
+	HTML          string `json:"html"`
+	URL           string `json:"url"`
+	IsSaved       int    `json:"is_saved"`
+	IsRead        int    `json:"is_read"`
+	CreatedOnTime int64  `json:"created_on_time"`
+}
+
+func FeverGroupsToResponse(groups []*domain.FeverGroup) []FeverGroupResponse {
+	responses := make([]FeverGroupResponse, len(groups))
+	for i, group := range groups {
+		responses[i] = FeverGroupResponse{
+			ID:    group.ID,
+			Title: group.Title,
+		}
+	}
+	return responses
+}
+
+func FeverFeedsToResponse(feeds []*domain.FeverFeed) []FeverFeedResponse {
+	responses := make([]FeverFeedResponse, len(feeds))
+	for i, feed := range feeds {
+		responses[i] = FeverFeedResponse{
+			ID:        feed.ID,
+			FaviconID: feed.ID,
+			Title:     feed.Title,
+			URL:       feed.URL,
+			SiteURL:   feed.SiteURL,
+		}
+		if feed.LastUpdated != nil {
+			responses[i].LastUpdatedOnTime = feed.LastUpdated.Unix()
+		}
+	}
+	return responses
+}
+
+// FeverFeedsGroupsToResponse lists the feeds in each group. Feeds outside
+// any folder are left out, Fever has no group for them.
+func FeverFeedsGroupsToResponse(feeds []*domain.FeverFeed) []FeverFeedsGroupResponse {
+	byGroup := make(map[int64][]int64)
+	for _, feed := range feeds {
+		if feed.GroupID != nil {
+			byGroup[*feed.GroupID] = append(byGroup[*feed.GroupID], feed.ID)
+		}
+	}
+
+	responses := make([]FeverFeedsGroupResponse, 0, len(byGroup))
+	for groupID, feedIDs := range byGroup {
+		responses = append(responses, FeverFeedsGroupResponse{
+			GroupID: groupID,
+			FeedIDs: FeverIDList(feedIDs),
+		})
+	}
+	sort.Slice(responses, func(i, j int) bool {
+		return responses[i].GroupID < responses[j].GroupID
+	})
+	return responses
+}
+
+func FeverFaviconsToResponse(favicons []*domain.FeverFavicon) []FeverFaviconResponse {
+	responses := make([]FeverFaviconResponse, len(favicons))
+	for i, favicon := range favicons {
+		responses[i] = FeverFaviconResponse{
+			ID:   favicon.ID,
+			Data: favicon.Data,
+		}
+	}
+	return responses
+}
+
+func FeverItemsToResponse(items []*domain.FeverItem) []FeverItemResponse {
+	responses := make([]FeverItemResponse, len(items))
+	for i, item := range items {
+		responses[i] = FeverItemResponse{
+			ID:            item.ID,
+			FeedID:        item.FeedID,
+			Title:         item.Title,
+			Author:        item.Author,
+			HTML:          item.HTML,
+			URL:           item.URL,
+			IsSaved:       feverBool(item.Saved),
+			IsRead:        feverBool(item.Read),
+			CreatedOnTime: item.CreatedOn.Unix(),
+		}
+	}
+	return responses
+}
+
+func FeverIDList(ids []int64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+func feverBool(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/domain"
+)
+
+type CreateFilterRuleRequest struct {
+	Name      string     `json:"name"`
+	Scope     string     `json:"scope"`
+	FolderID  *uuid.UUID `json:"folder_id"`
+	FeedID    *uuid.UUID `json:"feed_id"`
+	Field     string     `json:"field"`
+	MatchType string     `json:"match_type"`
+	Pattern   string     `json:"pattern"`
+	Action    string     `json:"action"`
+	TagName   *string    `json:"tag_name"`
+	Enabled   *bool      `json:"enabled"`
+}
+
+type FilterRuleResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Name      string     `json:"name"`
+	Scope     string     `json:"scope"`
+	FolderID  *uuid.UUID `json:"folder_id,omitempty"`
+	FeedID    *uuid.UUID `json:"feed_id,omitempty"`
+	Field     string     `json:"field"`
+	MatchType string     `json:"match_type"`
+	Pattern   string     `json:"pattern"`
+	Action    string     `json:"action"`
+	TagName   *string    `json:"tag_name,omitempty"`
+	Enabled   bool       `json:"enabled"`
+}
+
+type FilterDryRunResponse struct {
+	Scanned int            `json:"scanned"`
+	Matched int            `json:"matched"`
+	Posts   []PostResponse `json:"posts"`
+}
+
+func (req CreateFilterRuleRequest) ToDomain(userID uuid.UUID) *domain.FilterRule {
+	rule := domain.NewFilterRule(userID)
+	rule.Name = req.Name
+	rule.FolderID = req.FolderID
+	rule.FeedID = req.FeedID
+	rule.Pattern = req.Pattern
+	rule.Action = req.Action
+	rule.TagName = req.TagName
+	if req.Scope != "" {
+		rule.Scope = req.Scope
+	}
+	if req.Field != "" {
+		rule.Field = req.Field
+	}
+	if req.MatchType != "" {
+		rule.MatchType = req.MatchType
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	return rule
+}
+
+func FilterRuleToResponse(rule *domain.FilterRule) FilterRuleResponse {
+	return FilterRuleResponse{
+		ID:        rule.ID,
+		CreatedAt: rule.CreatedAt,
+		UpdatedAt: rule.UpdatedAt,
+		Name:      rule.Name,
+		Scope:     rule.Scope,
+		FolderID:  rule.FolderID,
+		FeedID:    rule.FeedID,
+		Field:     rule.Field,
+		MatchType: rule.MatchType,
+		Pattern:   rule.Pattern,
+		Action:    rule.Action,
+		TagName:   rule.TagName,
+		Enabled:   rule.Enabled,
+	}
+}
+
+func FilterRulesToResponse(rules []*domain.FilterRule) []FilterRuleResponse {
+	responses := make([]FilterRuleResponse, len(rules))
+	for i, rule := range rules {
+		responses[i] = FilterRuleToResponse(rule)
+	}
+	return responses
+}
+
+func FilterDryRunToResponse(result *domain.FilterDryRun) FilterDryRunResponse {
+	posts := make([]PostResponse, len(result.Posts))
+	for i, post := range result.Posts {
+		posts[i] = PostToResponse(*post)
+	}
+	return FilterDryRunResponse{
+		Scanned: result.Scanned,
+		Matched: result.Matched,
+		Posts:   posts,
+	}
+}
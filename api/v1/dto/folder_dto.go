@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/domain"
+)
+
+type CreateFolderRequest struct {
+	Name string `json:"name"`
+}
+
+type FolderResponse struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name"`
+}
+
+func FolderToResponse(folder *domain.Folder) FolderResponse {
+	return FolderResponse{
+		ID:        folder.ID,
+		CreatedAt: folder.CreatedAt,
+		UpdatedAt: folder.UpdatedAt,
+		Name:      folder.Name,
+	}
+}
+
+func FoldersToResponse(folders []*domain.Folder) []FolderResponse {
+	responses := make([]FolderResponse, len(folders))
+	for i, folder := range folders {
+		responses[i] = FolderToResponse(folder)
+	}
+	return responses
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "github.com/hel1th/rssagg/internal/domain"
+
+type HealthCheckResponse struct {
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	Message   string  `json:"message,omitempty"`
+}
+
+type HealthResponse struct {
+	Status string                         `json:"status"`
+	Checks map[string]HealthCheckResponse `json:"checks,omitempty"`
+}
+
+func HealthToResponse(health *domain.Health) HealthResponse {
+	response := HealthResponse{
+		Status: string(health.Status),
+		Checks: make(map[string]HealthCheckResponse, len(health.Checks)),
+	}
+	for _, check := range health.Checks {
+		response.Checks[check.Name] = HealthCheckResponse{
+			Status:    string(check.Status),
+			Critical:  check.Critical,
+			LatencyMS: float64(check.Latency.Microseconds()) / 1000,
+			Message:   check.Message,
+		}
+	}
+	return response
+}
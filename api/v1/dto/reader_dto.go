@@ -0,0 +1,205 @@
+package dto
+
+import (
+	"strconv"
+
+	"github.com/hel1th/rssagg/internal/domain"
+)
+
+// Google Reader API responses. Times are unix seconds unless the field name
+// says otherwise, and large numbers are sent as strings.
+
+type ReaderLoginResponse struct {
+	SID  string `json:"SID"`
+	LSID string `json:"LSID"`
+	Auth string `json:"Auth"`
+}
+
+type ReaderUserInfoResponse struct {
+	UserID        string `json:"userId"`
+	UserName      string `json:"userName"`
+	UserProfileID string `json:"userProfileId"`
+	UserEmail     string `json:"userEmail"`
+}
+
+type ReaderCategory struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+type ReaderSubscriptionResponse struct {
+	ID            string           `json:"id"`
+	Title         string           `json:"title"`
+	Categories    []ReaderCategory `json:"categories"`
+	URL           string           `json:"url"`
+	HTMLURL       string           `json:"htmlUrl"`
+	IconURL       string           `json:"iconUrl"`
+	FirstItemMsec string           `json:"firstitemmsec"`
+}
+
+type ReaderSubscriptionListResponse struct {
+	Subscriptions []ReaderSubscriptionResponse `json:"subscriptions"`
+}
+
+type ReaderQuickAddResponse struct {
+	NumResults int    `json:"numResults"`
+	Query      string `json:"query"`
+	StreamID   string `json:"streamId"`
+	StreamName string `json:"streamName"`
+}
+
+type ReaderTagResponse struct {
+	ID   string `json:"id"`
+	Type string `json:"type,omitempty"`
+}
+
+type ReaderTagListResponse struct {
+	Tags []ReaderTagResponse `json:"tags"`
+}
+
+type ReaderLink struct {
+	Href string `json:"href"`
+	Type string `json:"type,omitempty"`
+}
+
+type ReaderContent struct {
+	Direction string `json:"direction"`
+	Content   string `json:"content"`
+}
+
+type ReaderOrigin struct {
+	StreamID string `json:"streamId"`
+	Title    string `json:"title"`
+	HTMLURL  string `json:"htmlUrl"`
+}
+
+type ReaderItemResponse struct {
+	ID            string        `json:"id"`
+	CrawlTimeMsec string        `json:"crawlTimeMsec"`
+	TimestampUsec string        `json:"timestampUsec"`
+	Published     int64         `json:"published"`
+	Updated       int64         `json:"updated"`
+	Title         string        `json:"title"`
+	Author        string        `json:"author,omitempty"`
+	Canonical     []ReaderLink  `json:"canonical"`
+	Alternate     []ReaderLink  `json:"alternate"`
+	Summary       ReaderContent `json:"summary"`
+	Categories    []string      `json:"categories"`
+	Origin        ReaderOrigin  `json:"origin"`
+}
+
+type ReaderStreamContentsResponse struct {
+	Direction    string               `json:"direction"`
+	ID           string               `json:"id"`
+	Updated      int64                `json:"updated"`
+	Items        []ReaderItemResponse `json:"items"`
+	Continuation string               `json:"continuation,omitempty"`
+}
+
+type ReaderItemRefResponse struct {
+	ID              string   `json:"id"`
+	DirectStreamIDs []string `json:"directStreamIds"`
+	TimestampUsec   string   `json:"timestampUsec"`
+}
+
+type ReaderItemIDsResponse struct {
+	ItemRefs     []ReaderItemRefResponse `json:"itemRefs"`
+	Continuation string                  `json:"continuation,omitempty"`
+}
+
+func ReaderSubscriptionsToResponse(subscriptions []*domain.ReaderSubscription) ReaderSubscriptionListResponse {
+	responses := make([]ReaderSubscriptionResponse, len(subscriptions))
+	for i, subscription := range subscriptions {
+		categories := []ReaderCategory{}
+		if subscription.Folder != nil {
+			categories = append(categories, ReaderCategory{
+				ID:    domain.ReaderLabelPrefix + *subscription.Folder,
+				Label: *subscription.Folder,
+			})
+		}
+		responses[i] = ReaderSubscriptionResponse{
+			ID:            ReaderFeedStreamID(subscription.FeedID),
+			Title:         subscription.Title,
+			Categories:    categories,
+			URL:           subscription.URL,
+			HTMLURL:       subscription.SiteURL,
+			FirstItemMsec: strconv.FormatInt(subscription.CreatedAt.UnixMilli(), 10),
+		}
+	}
+	return ReaderSubscriptionListResponse{Subscriptions: responses}
+}
+
+func ReaderTagsToResponse(labels []string) ReaderTagListResponse {
+	tags := []ReaderTagResponse{{ID: domain.ReaderStreamStarred}}
+	for _, label := range labels {
+		tags = append(tags, ReaderTagResponse{
+			ID:   domain.ReaderLabelPrefix + label,
+			Type: "folder",
+		})
+	}
+	return ReaderTagListResponse{Tags: tags}
+}
+
+func ReaderItemsToResponse(items []*domain.ReaderItem) []ReaderItemResponse {
+	responses := make([]ReaderItemResponse, len(items))
+	for i, item := range items {
+		categories := []string{domain.ReaderStreamReadingList}
+		if item.Folder != nil {
+			categories = append(categories, domain.ReaderLabelPrefix+*item.Folder)
+		}
+		if item.Read {
+			categories = append(categories, domain.ReaderStreamRead)
+		}
+		if item.Starred {
+			categories = append(categories, domain.ReaderStreamStarred)
+		}
+
+		responses[i] = ReaderItemResponse{
+			ID:            domain.ReaderItemID(item.ID),
+			CrawlTimeMsec: strconv.FormatInt(item.CrawledAt.UnixMilli(), 10),
+			TimestampUsec: strconv.FormatInt(item.CrawledAt.UnixMicro(), 10),
+			Published:     item.PublishedAt.Unix(),
+			Updated:       item.PublishedAt.Unix(),
+			Title:         item.Title,
+			Author:        item.Author,
+			Canonical:     []ReaderLink{{Href: item.URL}},
+			Alternate:     []ReaderLink{{Href: item.URL, Type: "text/html"}},
+			Summary: ReaderContent{
+				Direction: "ltr",
+				Content:   item.Content,
+			},
+			Categories: categories,
+			Origin: ReaderOrigin{
+				StreamID: ReaderFeedStreamID(item.FeedID),
+				Title:    item.FeedTitle,
+				HTMLURL:  item.FeedSiteURL,
+			},
+		}
+	}
+	return responses
+}
+
+func ReaderItemRefsToResponse(refs []*domain.ReaderItemRef) []ReaderItemRefResponse {
+	responses := make([]ReaderItemRefResponse, len(refs))
+	for i, ref := range refs {
+		responses[i] = ReaderItemRefResponse{
+			ID:              strconv.FormatInt(ref.ID, 10),
+			DirectStreamIDs: []string{},
+			TimestampUsec:   strconv.FormatInt(ref.CrawledAt.UnixMicro(), 10),
+		}
+	}
+	return responses
+}
+
+func ReaderFeedStreamID(feedID int64) string {
+	return domain.ReaderFeedPrefix + strconv.FormatInt(feedID, 10)
+}
+
+// ReaderContinuation formats the continuation for the next page, or "" on
+// the last page.
+func ReaderContinuation(continuation *int64) string {
+	if continuation == nil {
+		return ""
+	}
+	return strconv.FormatInt(*continuation, 10)
+}
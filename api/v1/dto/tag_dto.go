@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/domain"
+)
+
+type CreateTagRequest struct {
+	Name string `json:"name"`
+}
+
+type PostTagRequest struct {
+	PostID uuid.UUID `json:"post_id"`
+	TagID  uuid.UUID `json:"tag_id"`
+}
+
+type TagResponse struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name"`
+	PostCount int64     `json:"post_count"`
+}
+
+func TagToResponse(tag *domain.Tag) TagResponse {
+	return TagResponse{
+		ID:        tag.ID,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+		Name:      tag.Name,
+		PostCount: tag.PostCount,
+	}
+}
+
+func TagsToResponse(tags []*domain.Tag) []TagResponse {
+	responses := make([]TagResponse, len(tags))
+	for i, tag := range tags {
+		responses[i] = TagToResponse(tag)
+	}
+	return responses
+}
@@ -8,7 +8,10 @@ import (
 )
 
 type CreateUserRequest struct {
-	Name string `json:"name"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
 }
 
 
@@ -17,7 +20,10 @@ type UserResponse struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	Name      string    `json:"name"`
-	APIKey    string    `json:"api_key"`
+	Username  *string   `json:"username,omitempty"`
+	Email     *string   `json:"email,omitempty"`
+	Role      string    `json:"role"`
+	APIKey    string    `json:"api_key,omitempty"`
 }
 
 type PostResponse struct {
@@ -29,6 +35,11 @@ type PostResponse struct {
 	PublishedAt time.Time `json:"published_at"`
 	URL         string    `json:"url"`
 	FeedID      uuid.UUID `json:"feed_id"`
+	Author      *string   `json:"author,omitempty"`
+	FeedTitle   string    `json:"feed_title,omitempty"`
+	Read        bool      `json:"read"`
+	Starred     bool      `json:"starred"`
+	Seq         int64     `json:"seq,omitempty"`
 }
 
 
@@ -38,6 +49,9 @@ func UserToResponse(user *domain.User) UserResponse {
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
 		Name:      user.Name,
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      user.Role,
 		APIKey:    user.APIKey,
 	}
 }
@@ -52,6 +66,8 @@ func PostToResponse(post domain.Post) PostResponse {
 		PublishedAt: post.PublishedAt,
 		URL:         post.URL,
 		FeedID:      post.FeedID,
+		Author:      post.Author,
+		Seq:         post.Seq,
 	}
 }
 
@@ -62,3 +78,19 @@ func PostsToResponse(posts []domain.Post) []PostResponse {
 	}
 	return responses
 }
+
+func TimelinePostToResponse(post *domain.TimelinePost) PostResponse {
+	response := PostToResponse(post.Post)
+	response.FeedTitle = post.FeedTitle
+	response.Read = post.Read
+	response.Starred = post.Starred
+	return response
+}
+
+func TimelinePostsToResponse(posts []*domain.TimelinePost) []PostResponse {
+	responses := make([]PostResponse, len(posts))
+	for i, post := range posts {
+		responses[i] = TimelinePostToResponse(post)
+	}
+	return responses
+}
@@ -0,0 +1,114 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/domain"
+)
+
+type CreateWebhookRequest struct {
+	Name     string     `json:"name"`
+	URL      string     `json:"url"`
+	FeedID   *uuid.UUID `json:"feed_id"`
+	FolderID *uuid.UUID `json:"folder_id"`
+	Keyword  *string    `json:"keyword"`
+	Enabled  *bool      `json:"enabled"`
+}
+
+type WebhookResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Name      string     `json:"name"`
+	URL       string     `json:"url"`
+	FeedID    *uuid.UUID `json:"feed_id,omitempty"`
+	FolderID  *uuid.UUID `json:"folder_id,omitempty"`
+	Keyword   *string    `json:"keyword,omitempty"`
+	Enabled   bool       `json:"enabled"`
+}
+
+// CreatedWebhookResponse is returned only once, on creation, and carries the
+// secret used to verify delivery signatures.
+type CreatedWebhookResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	WebhookID      uuid.UUID  `json:"webhook_id"`
+	PostID         *uuid.UUID `json:"post_id,omitempty"`
+	Event          string     `json:"event"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at"`
+	ResponseStatus *int       `json:"response_status"`
+	LastError      *string    `json:"last_error"`
+}
+
+func (req CreateWebhookRequest) ToDomain(userID uuid.UUID) *domain.Webhook {
+	hook := domain.NewWebhook(userID)
+	hook.Name = req.Name
+	hook.URL = req.URL
+	hook.FeedID = req.FeedID
+	hook.FolderID = req.FolderID
+	hook.Keyword = req.Keyword
+	if req.Enabled != nil {
+		hook.Enabled = *req.Enabled
+	}
+	return hook
+}
+
+func WebhookToResponse(hook *domain.Webhook) WebhookResponse {
+	return WebhookResponse{
+		ID:        hook.ID,
+		CreatedAt: hook.CreatedAt,
+		UpdatedAt: hook.UpdatedAt,
+		Name:      hook.Name,
+		URL:       hook.URL,
+		FeedID:    hook.FeedID,
+		FolderID:  hook.FolderID,
+		Keyword:   hook.Keyword,
+		Enabled:   hook.Enabled,
+	}
+}
+
+func WebhooksToResponse(hooks []*domain.Webhook) []WebhookResponse {
+	responses := make([]WebhookResponse, len(hooks))
+	for i, hook := range hooks {
+		responses[i] = WebhookToResponse(hook)
+	}
+	return responses
+}
+
+func WebhookDeliveryToResponse(delivery *domain.WebhookDelivery) WebhookDeliveryResponse {
+	response := WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+		WebhookID:      delivery.WebhookID,
+		PostID:         delivery.PostID,
+		Event:          delivery.Event,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastAttemptAt:  delivery.LastAttemptAt,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+	}
+	if delivery.Status == domain.WebhookDeliveryPending {
+		response.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	return response
+}
+
+func WebhookDeliveriesToResponse(deliveries []*domain.WebhookDelivery) []WebhookDeliveryResponse {
+	responses := make([]WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		responses[i] = WebhookDeliveryToResponse(delivery)
+	}
+	return responses
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hel1th/rssagg/api/v1/dto"
+	"github.com/hel1th/rssagg/api/v1/middleware"
+	"github.com/hel1th/rssagg/internal/audit"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/service"
+)
+
+type AccountHandler struct {
+	accountService service.AccountService
+}
+
+func NewAccountHandler(accountService service.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+func (h *AccountHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req dto.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Error parsing JSON: %v", err))
+		return
+	}
+
+	user, session, err := h.accountService.Login(r.Context(), req.Username, req.Password, r.UserAgent(), audit.ClientIP(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidCredentials):
+			respondWithError(w, http.StatusUnauthorized, "Invalid username or password")
+		case errors.Is(err, domain.ErrUserDisabled):
+			respondWithError(w, http.StatusForbidden, "User is disabled")
+		default:
+			respondWithServiceError(w, r, err, "Failed to log in")
+		}
+		return
+	}
+
+	http.SetCookie(w, sessionCookie(session.Token, session.ExpiresAt))
+	respondWithJSON(w, http.StatusOK, dto.SessionToResponse(session, user))
+}
+
+func (h *AccountHandler) Logout(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	session, ok := middleware.GetSessionFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusBadRequest, "Not logged in with a session")
+		return
+	}
+
+	if err := h.accountService.Logout(r.Context(), session.ID); err != nil {
+		respondWithServiceError(w, r, err, "Failed to log out")
+		return
+	}
+
+	http.SetCookie(w, sessionCookie("", time.Unix(0, 0)))
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Logged out"})
+}
+
+func (h *AccountHandler) GetSession(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	session, ok := middleware.GetSessionFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusBadRequest, "Not logged in with a session")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.SessionToResponse(session, user))
+}
+
+func (h *AccountHandler) ChangePassword(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	var req dto.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Error parsing JSON: %v", err))
+		return
+	}
+
+	session, _ := middleware.GetSessionFromContext(r.Context())
+	err := h.accountService.ChangePassword(r.Context(), user, session, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		respondWithPasswordError(w, r, err, "Failed to change password")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Password changed, other sessions were logged out"})
+}
+
+func (h *AccountHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Error parsing JSON: %v", err))
+		return
+	}
+
+	if err := h.accountService.ResetPassword(r.Context(), req.Token, req.NewPassword); err != nil {
+		respondWithPasswordError(w, r, err, "Failed to reset password")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Password reset, all sessions were logged out"})
+}
+
+func respondWithPasswordError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidPassword):
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Password must be %d to %d bytes", domain.MinPasswordLength, domain.MaxPasswordLength))
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		respondWithError(w, http.StatusForbidden, "Current password is wrong")
+	case errors.Is(err, domain.ErrUsernameRequired):
+		respondWithError(w, http.StatusBadRequest, "User has no username to log in with")
+	case errors.Is(err, domain.ErrInvalidPasswordReset):
+		respondWithError(w, http.StatusBadRequest, "Invalid or expired password reset token")
+	default:
+		respondWithServiceError(w, r, err, msg)
+	}
+}
+
+// sessionCookie is only sent over HTTPS and never to scripts. An expiry in
+// the past clears it.
+func sessionCookie(token string, expiresAt time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     domain.SessionCookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  expiresAt,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
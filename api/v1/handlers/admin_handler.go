@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hel1th/rssagg/api/v1/dto"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/service"
+)
+
+type AdminHandler struct {
+	adminService   service.AdminService
+	scraperService service.ScraperService
+}
+
+func NewAdminHandler(adminService service.AdminService, scraperService service.ScraperService) *AdminHandler {
+	return &AdminHandler{
+		adminService:   adminService,
+		scraperService: scraperService,
+	}
+}
+
+func (h *AdminHandler) SearchUsers(w http.ResponseWriter, r *http.Request, admin *domain.User) {
+	query := domain.AdminUserQuery{
+		Search: strings.TrimSpace(r.URL.Query().Get("search")),
+	}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil {
+			query.Limit = parsedLimit
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil {
+			query.Offset = parsedOffset
+		}
+	}
+
+	users, total, err := h.adminService.SearchUsers(r.Context(), query)
+	if err != nil {
+		respondWithServiceError(w, r, err, "Failed to get users")
+		return
+	}
+
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	respondWithJSON(w, http.StatusOK, dto.AdminUsersToResponse(users))
+}
+
+func (h *AdminHandler) DisableUser(w http.ResponseWriter, r *http.Request, admin *domain.User) {
+	h.setUserDisabled(w, r, admin, true)
+}
+
+func (h *AdminHandler) EnableUser(w http.ResponseWriter, r *http.Request, admin *domain.User) {
+	h.setUserDisabled(w, r, admin, false)
+}
+
+func (h *AdminHandler) setUserDisabled(w http.ResponseWriter, r *http.Request, admin *domain.User, disabled bool) {
+	userID, ok := parseUUIDParam(w, r, "id", "user ID")
+	if !ok {
+		return
+	}
+
+	if err := h.adminService.SetUserDisabled(r.Context(), admin, userID, disabled); err != nil {
+		respondWithAdminError(w, r, err, "Failed to update user")
+		return
+	}
+
+	message := "User enabled"
+	if disabled {
+		message = "User disabled"
+	}
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": message})
+}
+
+func (h *AdminHandler) DeleteUser(w http.ResponseWriter, r *http.Request, admin *domain.User) {
+	userID, ok := parseUUIDParam(w, r, "id", "user ID")
+	if !ok {
+		return
+	}
+
+	if err := h.adminService.DeleteUser(r.Context(), admin, userID); err != nil {
+		respondWithAdminError(w, r, err, "Failed to delete user")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "User deleted"})
+}
+
+func (h *AdminHandler) IssuePasswordReset(w http.ResponseWriter, r *http.Request, admin *domain.User) {
+	userID, ok := parseUUIDParam(w, r, "user_id", "user ID")
+	if !ok {
+		return
+	}
+
+	reset, err := h.adminService.IssuePasswordReset(r.Context(), admin, userID)
+	if err != nil {
+		respondWithAdminError(w, r, err, "Failed to issue password reset")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, dto.PasswordResetToResponse(reset))
+}
+
+func (h *AdminHandler) RefreshFeed(w http.ResponseWriter, r *http.Request, admin *domain.User) {
+	feedID, ok := parseUUIDParam(w, r, "id", "feed ID")
+	if !ok {
+		return
+	}
+
+	newPosts, err := h.adminService.RefreshFeed(r.Context(), admin, feedID)
+	if err != nil {
+		if errors.Is(err, domain.ErrFeedNotFound) {
+			respondWithError(w, http.StatusNotFound, "Feed not found")
+		} else {
+			// The fetch error is on the feed as last_fetch_error
+			slog.ErrorContext(r.Context(), "Failed to refresh feed", "feed_id", feedID, "error", err)
+			respondWithError(w, http.StatusBadGateway, "Failed to refresh feed, see its last_fetch_error")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]int{"new_posts": newPosts})
+}
+
+func (h *AdminHandler) DisableFeed(w http.ResponseWriter, r *http.Request, admin *domain.User) {
+	h.setFeedDisabled(w, r, admin, true)
+}
+
+func (h *AdminHandler) EnableFeed(w http.ResponseWriter, r *http.Request, admin *domain.User) {
+	h.setFeedDisabled(w, r, admin, false)
+}
+
+func (h *AdminHandler) setFeedDisabled(w http.ResponseWriter, r *http.Request, admin *domain.User, disabled bool) {
+	feedID, ok := parseUUIDParam(w, r, "id", "feed ID")
+	if !ok {
+		return
+	}
+
+	if err := h.adminService.SetFeedDisabled(r.Context(), admin, feedID, disabled); err != nil {
+		respondWithAdminError(w, r, err, "Failed to update feed")
+		return
+	}
+
+	message := "Feed enabled"
+	if disabled {
+		message = "Feed disabled"
+	}
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": message})
+}
+
+func (h *AdminHandler) ReassignFeed(w http.ResponseWriter, r *http.Request, admin *domain.User) {
+	feedID, ok := parseUUIDParam(w, r, "id", "feed ID")
+	if !ok {
+		return
+	}
+	userID, ok := parseUUIDParam(w, r, "user_id", "user ID")
+	if !ok {
+		return
+	}
+
+	if err := h.adminService.ReassignFeed(r.Context(), admin, feedID, userID); err != nil {
+		respondWithAdminError(w, r, err, "Failed to reassign feed")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Feed reassigned"})
+}
+
+func (h *AdminHandler) GetScraperStatus(w http.ResponseWriter, r *http.Request, admin *domain.User) {
+	status, err := h.scraperService.Status(r.Context())
+	if err != nil {
+		respondWithServiceError(w, r, err, "Failed to get scraper status")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.ScraperStatusToResponse(status))
+}
+
+func (h *AdminHandler) PurgePosts(w http.ResponseWriter, r *http.Request, admin *domain.User) {
+	var query domain.PurgePostsQuery
+
+	if r.URL.Query().Get("feed_id") != "" {
+		feedID, ok := parseUUIDParam(w, r, "feed_id", "feed ID")
+		if !ok {
+			return
+		}
+		query.FeedID = &feedID
+	}
+
+	if beforeStr := r.URL.Query().Get("before"); beforeStr != "" {
+		before, err := time.Parse(time.RFC3339, beforeStr)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid before, expected an RFC 3339 time")
+			return
+		}
+		query.Before = &before
+	}
+
+	purged, err := h.adminService.PurgePosts(r.Context(), admin, query)
+	if err != nil {
+		respondWithAdminError(w, r, err, "Failed to purge posts")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]int64{"purged": purged})
+}
+
+func respondWithAdminError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		respondWithError(w, http.StatusNotFound, "User not found")
+	case errors.Is(err, domain.ErrFeedNotFound):
+		respondWithError(w, http.StatusNotFound, "Feed not found")
+	case errors.Is(err, domain.ErrCannotModifySelf):
+		respondWithError(w, http.StatusBadRequest, "Admins cannot disable or delete themselves")
+	case errors.Is(err, domain.ErrInvalidPurgeQuery):
+		respondWithError(w, http.StatusBadRequest, "feed_id, before or both are required")
+	case errors.Is(err, domain.ErrAdminRequired):
+		respondWithError(w, http.StatusForbidden, "Admin role required")
+	case errors.Is(err, domain.ErrUsernameRequired):
+		respondWithError(w, http.StatusBadRequest, "User has no username to log in with")
+	default:
+		respondWithServiceError(w, r, err, msg)
+	}
+}
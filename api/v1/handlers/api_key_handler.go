@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hel1th/rssagg/api/v1/dto"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/service"
+)
+
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	var req dto.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Error parsing JSON: %v", err))
+		return
+	}
+
+	key, err := h.apiKeyService.CreateAPIKey(r.Context(), user, req.Name, req.Scope, req.ExpiresAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidAPIKeyName):
+			respondWithError(w, http.StatusBadRequest, "Invalid API key name")
+		case errors.Is(err, domain.ErrInvalidAPIKeyScope):
+			respondWithError(w, http.StatusBadRequest, "Invalid scope, expected one of: read, write, admin")
+		case errors.Is(err, domain.ErrInvalidAPIKeyExpiry):
+			respondWithError(w, http.StatusBadRequest, "Expiry must be in the future")
+		default:
+			respondWithServiceError(w, r, err, "Failed to create API key")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, dto.CreatedAPIKeyResponse{
+		APIKeyResponse: dto.APIKeyToResponse(key),
+		Key:            key.Key,
+	})
+}
+
+func (h *APIKeyHandler) GetUserAPIKeys(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	keys, err := h.apiKeyService.GetUserAPIKeys(r.Context(), user.ID)
+	if err != nil {
+		respondWithServiceError(w, r, err, "Failed to get API keys")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.APIKeysToResponse(keys))
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	keyID, ok := parseUUIDParam(w, r, "id", "API key ID")
+	if !ok {
+		return
+	}
+
+	err := h.apiKeyService.RevokeAPIKey(r.Context(), keyID, user.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			respondWithError(w, http.StatusNotFound, "API key not found")
+		} else {
+			respondWithServiceError(w, r, err, "Failed to revoke API key")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "API key revoked"})
+}
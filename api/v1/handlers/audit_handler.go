@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hel1th/rssagg/api/v1/dto"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/service"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// GetUserLog lists what the user did and what was done to their account
+// and subscriptions, newest first.
+func (h *AuditHandler) GetUserLog(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	query := auditQuery(r)
+	query.UserID = &user.ID
+
+	h.respondWithEntries(w, r, query, user)
+}
+
+// ExportUserLog writes the user's whole log as JSON Lines, oldest first.
+func (h *AuditHandler) ExportUserLog(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	query := auditQuery(r)
+	query.UserID = &user.ID
+
+	h.export(w, r, query, user)
+}
+
+// GetLog lists every user's log, or one user's by user_id, for admins.
+func (h *AuditHandler) GetLog(w http.ResponseWriter, r *http.Request, admin *domain.User) {
+	query, ok := adminAuditQuery(w, r)
+	if !ok {
+		return
+	}
+
+	h.respondWithEntries(w, r, query, nil)
+}
+
+func (h *AuditHandler) ExportLog(w http.ResponseWriter, r *http.Request, admin *domain.User) {
+	query, ok := adminAuditQuery(w, r)
+	if !ok {
+		return
+	}
+
+	h.export(w, r, query, nil)
+}
+
+// respondWithEntries redacts entries for viewer unless it's nil.
+func (h *AuditHandler) respondWithEntries(w http.ResponseWriter, r *http.Request, query domain.AuditQuery, viewer *domain.User) {
+	entries, err := h.auditService.GetEntries(r.Context(), query)
+	if err != nil {
+		respondWithServiceError(w, r, err, "Failed to get audit log")
+		return
+	}
+
+	if viewer != nil {
+		for _, entry := range entries {
+			entry.RedactFor(viewer.ID)
+		}
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.AuditEntriesToResponse(entries))
+}
+
+// export streams entries as they are read. The response starts with the
+// first entry, so an error reading a later one can only end it early.
+func (h *AuditHandler) export(w http.ResponseWriter, r *http.Request, query domain.AuditQuery, viewer *domain.User) {
+	rc := http.NewResponseController(w)
+	// The server's WriteTimeout would otherwise cut long exports short.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		slog.WarnContext(r.Context(), "Error clearing audit log export write deadline", "error", err)
+	}
+
+	started := false
+	start := func() {
+		w.Header().Set("Content-Type", "application/jsonl")
+		w.Header().Set("Content-Disposition", `attachment; filename="audit_log.jsonl"`)
+		w.WriteHeader(http.StatusOK)
+		started = true
+	}
+
+	encoder := json.NewEncoder(w)
+	err := h.auditService.Export(r.Context(), query, func(entry *domain.AuditEntry) error {
+		if !started {
+			start()
+		}
+		if viewer != nil {
+			entry.RedactFor(viewer.ID)
+		}
+		return encoder.Encode(dto.AuditEntryToResponse(entry))
+	})
+	switch {
+	case err != nil && !started:
+		respondWithServiceError(w, r, err, "Failed to export audit log")
+	case err != nil:
+		slog.ErrorContext(r.Context(), "Audit log export ended early", "error", err)
+	case !started:
+		start()
+	}
+}
+
+func auditQuery(r *http.Request) domain.AuditQuery {
+	query := domain.AuditQuery{
+		Action: r.URL.Query().Get("action"),
+	}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil {
+			query.Limit = parsedLimit
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil {
+			query.Offset = parsedOffset
+		}
+	}
+
+	return query
+}
+
+func adminAuditQuery(w http.ResponseWriter, r *http.Request) (domain.AuditQuery, bool) {
+	query := auditQuery(r)
+
+	if r.URL.Query().Get("user_id") != "" {
+		userID, ok := parseUUIDParam(w, r, "user_id", "user ID")
+		if !ok {
+			return query, false
+		}
+		query.UserID = &userID
+	}
+
+	return query, true
+}
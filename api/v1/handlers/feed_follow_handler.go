@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -30,13 +31,13 @@ func (h *FeedFollowHandler) FollowFeed(w http.ResponseWriter, r *http.Request, u
 
 	feedFollow, err := h.feedFollowService.FollowFeed(r.Context(), user.ID, req.FeedID)
 	if err != nil {
-		switch err {
-		case domain.ErrInvalidFeedID:
+		switch {
+		case errors.Is(err, domain.ErrInvalidFeedID):
 			respondWithError(w, http.StatusBadRequest, "Invalid feed ID")
-		case domain.ErrDuplicateFeedFollow:
+		case errors.Is(err, domain.ErrDuplicateFeedFollow):
 			respondWithError(w, http.StatusConflict, "Already following this feed")
 		default:
-			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to follow feed: %v", err))
+			respondWithServiceError(w, r, err, "Failed to follow feed")
 		}
 		return
 	}
@@ -47,13 +48,54 @@ func (h *FeedFollowHandler) FollowFeed(w http.ResponseWriter, r *http.Request, u
 func (h *FeedFollowHandler) GetUserFeedFollows(w http.ResponseWriter, r *http.Request, user *domain.User) {
 	feedFollows, err := h.feedFollowService.GetUserFeedFollows(r.Context(), user.ID)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get feed follows: %v", err))
+		respondWithServiceError(w, r, err, "Failed to get feed follows")
 		return
 	}
 
 	respondWithJSON(w, http.StatusOK, dto.FeedFollowsToResponse(feedFollows))
 }
 
+func (h *FeedFollowHandler) UpdateFeedFollow(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	feedFollowIDStr := r.URL.Query().Get("id")
+	if feedFollowIDStr == "" {
+		respondWithError(w, http.StatusBadRequest, "Feed follow ID is required")
+		return
+	}
+
+	feedFollowID, err := uuid.Parse(feedFollowIDStr)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid feed follow ID format")
+		return
+	}
+
+	var req dto.UpdateFeedFollowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Error parsing JSON: %v", err))
+		return
+	}
+
+	feedFollow, err := h.feedFollowService.UpdateFeedFollow(r.Context(), feedFollowID, user.ID, req.ToDomain())
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrFeedFollowNotFound):
+			respondWithError(w, http.StatusNotFound, "Feed follow not found")
+		case errors.Is(err, domain.ErrFeedFollowTitleTooLong):
+			respondWithError(w, http.StatusBadRequest, "Title is too long")
+		case errors.Is(err, domain.ErrInvalidFeedFollowPriority):
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Priority must be between %d and %d", domain.MinFeedFollowPriority, domain.MaxFeedFollowPriority))
+		case errors.Is(err, domain.ErrInvalidNotificationMode):
+			respondWithError(w, http.StatusBadRequest, "Invalid notification mode, expected one of: off, instant, digest")
+		case errors.Is(err, domain.ErrFolderNotFound):
+			respondWithError(w, http.StatusBadRequest, "Folder not found")
+		default:
+			respondWithServiceError(w, r, err, "Failed to update feed follow")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.FeedFollowToResponse(feedFollow))
+}
+
 func (h *FeedFollowHandler) UnfollowFeed(w http.ResponseWriter, r *http.Request, user *domain.User) {
 	feedFollowIDStr := r.URL.Query().Get("id")
 	if feedFollowIDStr == "" {
@@ -69,13 +111,11 @@ func (h *FeedFollowHandler) UnfollowFeed(w http.ResponseWriter, r *http.Request,
 
 	err = h.feedFollowService.UnfollowFeed(r.Context(), feedFollowID, user.ID)
 	if err != nil {
-		switch err {
-		case domain.ErrFeedFollowNotFound:
+		switch {
+		case errors.Is(err, domain.ErrFeedFollowNotFound):
 			respondWithError(w, http.StatusNotFound, "Feed follow not found")
-		case domain.ErrCannotUnfollowFeed:
-			respondWithError(w, http.StatusForbidden, "Cannot unfollow this feed")
 		default:
-			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to unfollow feed: %v", err))
+			respondWithServiceError(w, r, err, "Failed to unfollow feed")
 		}
 		return
 	}
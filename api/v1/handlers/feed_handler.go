@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/hel1th/rssagg/api/v1/dto"
@@ -30,15 +33,15 @@ func (h *FeedHandler) CreateFeed(w http.ResponseWriter, r *http.Request, user *d
 
 	feed, err := h.feedService.CreateFeed(r.Context(), req.Name, req.URL, user.ID)
 	if err != nil {
-		switch err {
-		case domain.ErrInvalidFeedName:
+		switch {
+		case errors.Is(err, domain.ErrInvalidFeedName):
 			respondWithError(w, http.StatusBadRequest, "Invalid feed name")
-		case domain.ErrInvalidFeedURL:
+		case errors.Is(err, domain.ErrInvalidFeedURL):
 			respondWithError(w, http.StatusBadRequest, "Invalid feed URL")
-		case domain.ErrDuplicateFeed:
+		case errors.Is(err, domain.ErrDuplicateFeed):
 			respondWithError(w, http.StatusConflict, "Feed already exists")
 		default:
-			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to create feed: %v", err))
+			respondWithServiceError(w, r, err, "Failed to create feed")
 		}
 		return
 	}
@@ -46,14 +49,44 @@ func (h *FeedHandler) CreateFeed(w http.ResponseWriter, r *http.Request, user *d
 	respondWithJSON(w, http.StatusCreated, dto.FeedToResponse(feed))
 }
 
-func (h *FeedHandler) GetAllFeeds(w http.ResponseWriter, r *http.Request) {
-	feeds, err := h.feedService.GetAllFeeds(r.Context())
+func (h *FeedHandler) GetFeedDirectory(w http.ResponseWriter, r *http.Request) {
+	query := domain.FeedDirectoryQuery{
+		Search: strings.TrimSpace(r.URL.Query().Get("search")),
+		Sort:   r.URL.Query().Get("sort"),
+	}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		query.Limit = parsedLimit
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		parsedOffset, err := strconv.Atoi(offsetStr)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+		query.Offset = parsedOffset
+	}
+
+	entries, total, err := h.feedService.ListFeedDirectory(r.Context(), query)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get feeds: %v", err))
+		if errors.Is(err, domain.ErrInvalidFeedSort) {
+			respondWithError(w, http.StatusBadRequest, "Invalid sort, expected one of: followers, activity, newest")
+		} else if errors.Is(err, domain.ErrInvalidFeedOffset) {
+			respondWithError(w, http.StatusBadRequest, "Invalid offset")
+		} else {
+			respondWithServiceError(w, r, err, "Failed to get feeds")
+		}
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, dto.FeedsToResponse(feeds))
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	respondWithJSON(w, http.StatusOK, dto.FeedDirectoryToResponse(entries))
 }
 
 func (h *FeedHandler) GetFeedByID(w http.ResponseWriter, r *http.Request) {
@@ -71,10 +104,10 @@ func (h *FeedHandler) GetFeedByID(w http.ResponseWriter, r *http.Request) {
 
 	feed, err := h.feedService.GetFeedByID(r.Context(), feedID)
 	if err != nil {
-		if err == domain.ErrFeedNotFound {
+		if errors.Is(err, domain.ErrFeedNotFound) {
 			respondWithError(w, http.StatusNotFound, "Feed not found")
 		} else {
-			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get feed: %v", err))
+			respondWithServiceError(w, r, err, "Failed to get feed")
 		}
 		return
 	}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hel1th/rssagg/api/v1/dto"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/service"
+)
+
+type FeedTokenHandler struct {
+	feedTokenService service.FeedTokenService
+}
+
+func NewFeedTokenHandler(feedTokenService service.FeedTokenService) *FeedTokenHandler {
+	return &FeedTokenHandler{
+		feedTokenService: feedTokenService,
+	}
+}
+
+func (h *FeedTokenHandler) CreateFeedToken(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	var req dto.CreateFeedTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Error parsing JSON: %v", err))
+		return
+	}
+
+	token, err := h.feedTokenService.CreateToken(r.Context(), req.Name, user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidFeedTokenName):
+			respondWithError(w, http.StatusBadRequest, "Invalid feed token name")
+		default:
+			respondWithServiceError(w, r, err, "Failed to create feed token")
+		}
+		return
+	}
+
+	query := url.Values{"token": {token.Token}}.Encode()
+	respondWithJSON(w, http.StatusCreated, dto.CreatedFeedTokenResponse{
+		FeedTokenResponse: dto.FeedTokenToResponse(token),
+		Token:             token.Token,
+		TimelineURL:       fmt.Sprintf("%s/v1/output/timeline?%s", baseURL(r), query),
+		StarredURL:        fmt.Sprintf("%s/v1/output/starred?%s", baseURL(r), query),
+	})
+}
+
+func (h *FeedTokenHandler) GetUserFeedTokens(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	tokens, err := h.feedTokenService.GetUserTokens(r.Context(), user.ID)
+	if err != nil {
+		respondWithServiceError(w, r, err, "Failed to get feed tokens")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.FeedTokensToResponse(tokens))
+}
+
+func (h *FeedTokenHandler) DeleteFeedToken(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	tokenID, ok := parseUUIDParam(w, r, "id", "Feed token ID")
+	if !ok {
+		return
+	}
+
+	err := h.feedTokenService.DeleteToken(r.Context(), tokenID, user.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrFeedTokenNotFound) {
+			respondWithError(w, http.StatusNotFound, "Feed token not found")
+		} else {
+			respondWithServiceError(w, r, err, "Failed to delete feed token")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Feed token revoked"})
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hel1th/rssagg/api/v1/dto"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/service"
+)
+
+// feverAPIVersion is the Fever API version we implement.
+const feverAPIVersion = 3
+
+// FeverHandler speaks the Fever API so clients like Reeder can sync with
+// rssagg. Everything goes through a single endpoint: the query string
+// selects what to return and the form carries api_key and mark actions.
+type FeverHandler struct {
+	feverService service.FeverService
+}
+
+func NewFeverHandler(feverService service.FeverService) *FeverHandler {
+	return &FeverHandler{
+		feverService: feverService,
+	}
+}
+
+func (h *FeverHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid form")
+		return
+	}
+
+	response := map[string]interface{}{
+		"api_version": feverAPIVersion,
+		"auth":        0,
+	}
+
+	user, key, err := h.feverService.Authenticate(r.Context(), r.FormValue("api_key"))
+	if err != nil {
+		// Fever reports failed auth in the body, not with a status code.
+		respondWithJSON(w, http.StatusOK, response)
+		return
+	}
+	response["auth"] = 1
+
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	if err := h.mark(r, user, key); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInsufficientScope):
+			respondWithError(w, http.StatusForbidden, "API key scope does not allow marking, requires \"write\"")
+		case errors.Is(err, domain.ErrInvalidFeverMark):
+			respondWithError(w, http.StatusBadRequest, "Invalid mark, expected item (read, unread, saved, unsaved), feed or group (read)")
+		case errors.Is(err, domain.ErrFeverItemNotFound):
+			respondWithError(w, http.StatusNotFound, "Item not found")
+		default:
+			h.fail(w, r, "marking", err)
+		}
+		return
+	}
+
+	lastRefreshed, err := h.feverService.GetLastRefreshedAt(ctx, user.ID)
+	if err != nil {
+		h.fail(w, r, "getting last refresh time", err)
+		return
+	}
+	response["last_refreshed_on_time"] = lastRefreshed.Unix()
+
+	var feeds []*domain.FeverFeed
+	if query.Has("groups") || query.Has("feeds") {
+		feeds, err = h.feverService.GetFeeds(ctx, user.ID)
+		if err != nil {
+			h.fail(w, r, "getting feeds", err)
+			return
+		}
+		response["feeds_groups"] = dto.FeverFeedsGroupsToResponse(feeds)
+	}
+
+	if query.Has("groups") {
+		groups, err := h.feverService.GetGroups(ctx, user.ID)
+		if err != nil {
+			h.fail(w, r, "getting groups", err)
+			return
+		}
+		response["groups"] = dto.FeverGroupsToResponse(groups)
+	}
+
+	if query.Has("feeds") {
+		response["feeds"] = dto.FeverFeedsToResponse(feeds)
+	}
+
+	if query.Has("favicons") {
+		favicons, err := h.feverService.GetFavicons(ctx, user.ID)
+		if err != nil {
+			h.fail(w, r, "getting favicons", err)
+			return
+		}
+		response["favicons"] = dto.FeverFaviconsToResponse(favicons)
+	}
+
+	if query.Has("items") {
+		items, total, err := h.feverService.GetItems(ctx, user.ID, feverItemQuery(r))
+		if err != nil {
+			h.fail(w, r, "getting items", err)
+			return
+		}
+		response["items"] = dto.FeverItemsToResponse(items)
+		response["total_items"] = total
+	}
+
+	if query.Has("links") {
+		// Hot links are a Fever feature we have no equivalent for.
+		response["links"] = []interface{}{}
+	}
+
+	if query.Has("unread_item_ids") {
+		ids, err := h.feverService.GetUnreadItemIDs(ctx, user.ID)
+		if err != nil {
+			h.fail(w, r, "getting unread items", err)
+			return
+		}
+		response["unread_item_ids"] = dto.FeverIDList(ids)
+	}
+
+	if query.Has("saved_item_ids") {
+		ids, err := h.feverService.GetSavedItemIDs(ctx, user.ID)
+		if err != nil {
+			h.fail(w, r, "getting saved items", err)
+			return
+		}
+		response["saved_item_ids"] = dto.FeverIDList(ids)
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// mark applies the mark action in the form, if any.
+func (h *FeverHandler) mark(r *http.Request, user *domain.User, key *domain.APIKey) error {
+	mark := r.FormValue("mark")
+	if mark == "" {
+		return nil
+	}
+	if !key.Allows(domain.APIKeyScopeWrite) {
+		return domain.ErrInsufficientScope
+	}
+
+	as := r.FormValue("as")
+	id, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		return domain.ErrInvalidFeverMark
+	}
+
+	before := time.Now()
+	if unix, err := strconv.ParseInt(r.FormValue("before"), 10, 64); err == nil {
+		before = time.Unix(unix, 0)
+	}
+
+	switch mark {
+	case "item":
+		return h.feverService.MarkItem(r.Context(), user.ID, id, as)
+	case "feed":
+		if as != domain.FeverMarkRead {
+			return domain.ErrInvalidFeverMark
+		}
+		return h.feverService.MarkFeedRead(r.Context(), user.ID, id, before)
+	case "group":
+		if as != domain.FeverMarkRead {
+			return domain.ErrInvalidFeverMark
+		}
+		if id < 0 {
+			// Negative groups are Fever's Sparks, which we don't have.
+			return nil
+		}
+		return h.feverService.MarkGroupRead(r.Context(), user.ID, id, before)
+	default:
+		return domain.ErrInvalidFeverMark
+	}
+}
+
+func (h *FeverHandler) fail(w http.ResponseWriter, r *http.Request, action string, err error) {
+	slog.ErrorContext(r.Context(), "Fever error", "action", action, "error", err)
+	respondWithError(w, http.StatusInternalServerError, "Internal Server Error")
+}
+
+func feverItemQuery(r *http.Request) domain.FeverItemQuery {
+	query := r.URL.Query()
+
+	var itemQuery domain.FeverItemQuery
+	if id, err := strconv.ParseInt(query.Get("since_id"), 10, 64); err == nil {
+		itemQuery.SinceID = &id
+	}
+	if id, err := strconv.ParseInt(query.Get("max_id"), 10, 64); err == nil {
+		itemQuery.MaxID = &id
+	}
+	if query.Has("with_ids") {
+		itemQuery.WithIDs = []int64{}
+		for _, part := range strings.Split(query.Get("with_ids"), ",") {
+			if id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64); err == nil {
+				itemQuery.WithIDs = append(itemQuery.WithIDs, id)
+			}
+		}
+	}
+	return itemQuery
+}
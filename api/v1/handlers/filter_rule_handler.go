@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/api/v1/dto"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/service"
+)
+
+type FilterRuleHandler struct {
+	filterRuleService service.FilterRuleService
+}
+
+func NewFilterRuleHandler(filterRuleService service.FilterRuleService) *FilterRuleHandler {
+	return &FilterRuleHandler{
+		filterRuleService: filterRuleService,
+	}
+}
+
+func (h *FilterRuleHandler) CreateRule(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	var req dto.CreateFilterRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Error parsing JSON: %v", err))
+		return
+	}
+
+	rule, err := h.filterRuleService.CreateRule(r.Context(), req.ToDomain(user.ID))
+	if err != nil {
+		respondWithFilterRuleError(w, r, err, "Failed to create filter rule")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, dto.FilterRuleToResponse(rule))
+}
+
+func (h *FilterRuleHandler) GetUserRules(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	rules, err := h.filterRuleService.GetUserRules(r.Context(), user.ID)
+	if err != nil {
+		respondWithServiceError(w, r, err, "Failed to get filter rules")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.FilterRulesToResponse(rules))
+}
+
+func (h *FilterRuleHandler) DeleteRule(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	ruleID, ok := parseFilterRuleID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.filterRuleService.DeleteRule(r.Context(), ruleID, user.ID); err != nil {
+		respondWithFilterRuleError(w, r, err, "Failed to delete filter rule")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Filter rule deleted"})
+}
+
+func (h *FilterRuleHandler) ApplyRule(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	ruleID, ok := parseFilterRuleID(w, r)
+	if !ok {
+		return
+	}
+
+	applied, err := h.filterRuleService.ApplyRule(r.Context(), ruleID, user.ID)
+	if err != nil {
+		respondWithFilterRuleError(w, r, err, "Failed to apply filter rule")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"message":       "Filter rule applied",
+		"rule_id":       ruleID,
+		"matched_posts": applied,
+	})
+}
+
+func (h *FilterRuleHandler) DryRun(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	var req dto.CreateFilterRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Error parsing JSON: %v", err))
+		return
+	}
+	if req.Name == "" {
+		req.Name = "dry run"
+	}
+
+	result, err := h.filterRuleService.DryRun(r.Context(), req.ToDomain(user.ID))
+	if err != nil {
+		respondWithFilterRuleError(w, r, err, "Failed to run filter rule")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.FilterDryRunToResponse(result))
+}
+
+func parseFilterRuleID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	ruleIDStr := r.URL.Query().Get("id")
+	if ruleIDStr == "" {
+		respondWithError(w, http.StatusBadRequest, "Filter rule ID is required")
+		return uuid.Nil, false
+	}
+
+	ruleID, err := uuid.Parse(ruleIDStr)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid filter rule ID format")
+		return uuid.Nil, false
+	}
+
+	return ruleID, true
+}
+
+func respondWithFilterRuleError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrFilterRuleNotFound):
+		respondWithError(w, http.StatusNotFound, "Filter rule not found")
+	case errors.Is(err, domain.ErrFolderNotFound):
+		respondWithError(w, http.StatusBadRequest, "Folder not found")
+	case errors.Is(err, domain.ErrFeedFollowNotFound):
+		respondWithError(w, http.StatusBadRequest, "Feed not followed")
+	case errors.Is(err, domain.ErrInvalidFilterRuleName):
+		respondWithError(w, http.StatusBadRequest, "Invalid filter rule name")
+	case errors.Is(err, domain.ErrInvalidFilterScope):
+		respondWithError(w, http.StatusBadRequest, "Invalid scope, expected global, folder (with folder_id) or feed (with feed_id)")
+	case errors.Is(err, domain.ErrInvalidFilterField):
+		respondWithError(w, http.StatusBadRequest, "Invalid field, expected one of: any, title, content, author, url")
+	case errors.Is(err, domain.ErrInvalidFilterMatchType):
+		respondWithError(w, http.StatusBadRequest, "Invalid match type, expected one of: substring, regex")
+	case errors.Is(err, domain.ErrInvalidFilterPattern):
+		respondWithError(w, http.StatusBadRequest, "Invalid pattern")
+	case errors.Is(err, domain.ErrInvalidFilterAction):
+		respondWithError(w, http.StatusBadRequest, "Invalid action, expected hide, mark_read, star or tag (with tag_name)")
+	case errors.Is(err, domain.ErrInvalidTagName):
+		respondWithError(w, http.StatusBadRequest, "Invalid tag name")
+	default:
+		respondWithServiceError(w, r, err, msg)
+	}
+}
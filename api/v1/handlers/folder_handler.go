@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/api/v1/dto"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/service"
+)
+
+type FolderHandler struct {
+	folderService service.FolderService
+}
+
+func NewFolderHandler(folderService service.FolderService) *FolderHandler {
+	return &FolderHandler{
+		folderService: folderService,
+	}
+}
+
+func (h *FolderHandler) CreateFolder(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	var req dto.CreateFolderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Error parsing JSON: %v", err))
+		return
+	}
+
+	folder, err := h.folderService.CreateFolder(r.Context(), req.Name, user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidFolderName):
+			respondWithError(w, http.StatusBadRequest, "Invalid folder name")
+		case errors.Is(err, domain.ErrDuplicateFolder):
+			respondWithError(w, http.StatusConflict, "Folder already exists")
+		default:
+			respondWithServiceError(w, r, err, "Failed to create folder")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, dto.FolderToResponse(folder))
+}
+
+func (h *FolderHandler) GetUserFolders(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	folders, err := h.folderService.GetUserFolders(r.Context(), user.ID)
+	if err != nil {
+		respondWithServiceError(w, r, err, "Failed to get folders")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.FoldersToResponse(folders))
+}
+
+func (h *FolderHandler) DeleteFolder(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	folderIDStr := r.URL.Query().Get("id")
+	if folderIDStr == "" {
+		respondWithError(w, http.StatusBadRequest, "Folder ID is required")
+		return
+	}
+
+	folderID, err := uuid.Parse(folderIDStr)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid folder ID format")
+		return
+	}
+
+	err = h.folderService.DeleteFolder(r.Context(), folderID, user.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrFolderNotFound) {
+			respondWithError(w, http.StatusNotFound, "Folder not found")
+		} else {
+			respondWithServiceError(w, r, err, "Failed to delete folder")
+		}
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Folder deleted"})
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/hel1th/rssagg/api/v1/dto"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/service"
+)
+
+type HealthHandler struct {
+	healthService service.HealthService
+}
+
+func NewHealthHandler(healthService service.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// Live reports that the process is up and serving, and nothing more, so
+// that an outage elsewhere doesn't get it restarted.
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, dto.HealthResponse{Status: string(domain.HealthOK)})
+}
+
+// Ready reports each dependency's status. It fails with 503 only when a
+// critical one is down; a degraded process keeps taking traffic.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	health := h.healthService.Ready(r.Context())
+
+	code := http.StatusOK
+	if health.Status == domain.HealthDown {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	respondWithJSON(w, code, dto.HealthToResponse(health))
+}
+
+// NotFound answers requests for routes that don't exist.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	respondWithError(w, http.StatusNotFound, "Not found")
+}
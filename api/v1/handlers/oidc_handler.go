@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/hel1th/rssagg/api/v1/dto"
+	"github.com/hel1th/rssagg/internal/audit"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/service"
+)
+
+type OIDCHandler struct {
+	oidcService  service.OIDCService
+	postLoginURL string
+}
+
+// NewOIDCHandler returns a handler that sends browsers to postLoginURL once
+// they are logged in, or answers with the session as JSON when it is empty.
+func NewOIDCHandler(oidcService service.OIDCService, postLoginURL string) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService:  oidcService,
+		postLoginURL: postLoginURL,
+	}
+}
+
+func (h *OIDCHandler) Login(w http.ResponseWriter, r *http.Request) {
+	login, err := h.oidcService.BeginLogin(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to start single sign-on", "error", err)
+		respondWithError(w, http.StatusBadGateway, "Failed to start single sign-on")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     domain.OIDCStateCookieName,
+		Value:    strings.Join([]string{login.State, login.Nonce, login.CodeVerifier}, "."),
+		Path:     "/v1/oidc",
+		MaxAge:   int(domain.OIDCLoginTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, login.AuthURL, http.StatusFound)
+}
+
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		respondWithError(w, http.StatusUnauthorized, fmt.Sprintf("Identity provider refused login: %s %s", providerErr, query.Get("error_description")))
+		return
+	}
+
+	// The login can only be finished once, by the browser that started it
+	http.SetCookie(w, &http.Cookie{
+		Name:     domain.OIDCStateCookieName,
+		Path:     "/v1/oidc",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	login, ok := loginFromCookie(r)
+	state := query.Get("state")
+	if !ok || state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(login.State)) != 1 {
+		respondWithError(w, http.StatusBadRequest, "Login state is missing or does not match, start again")
+		return
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		respondWithError(w, http.StatusBadRequest, "code is required")
+		return
+	}
+
+	user, session, err := h.oidcService.CompleteLogin(r.Context(), login, code, r.UserAgent(), audit.ClientIP(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrOIDCLoginFailed):
+			respondWithError(w, http.StatusUnauthorized, "Single sign-on login failed")
+		case errors.Is(err, domain.ErrOIDCSignupDisabled):
+			respondWithError(w, http.StatusForbidden, "No user is linked to this identity")
+		case errors.Is(err, domain.ErrOIDCEmailTaken):
+			respondWithError(w, http.StatusConflict, "A user with this email already exists and can't be linked to this identity")
+		case errors.Is(err, domain.ErrUserDisabled):
+			respondWithError(w, http.StatusForbidden, "User is disabled")
+		default:
+			respondWithServiceError(w, r, err, "Failed to log in")
+		}
+		return
+	}
+
+	http.SetCookie(w, sessionCookie(session.Token, session.ExpiresAt))
+	if h.postLoginURL != "" {
+		http.Redirect(w, r, h.postLoginURL, http.StatusFound)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, dto.SessionToResponse(session, user))
+}
+
+func loginFromCookie(r *http.Request) (domain.OIDCLogin, bool) {
+	cookie, err := r.Cookie(domain.OIDCStateCookieName)
+	if err != nil {
+		return domain.OIDCLogin{}, false
+	}
+
+	parts := strings.Split(cookie.Value, ".")
+	if len(parts) != 3 {
+		return domain.OIDCLogin{}, false
+	}
+
+	return domain.OIDCLogin{
+		State:        parts[0],
+		Nonce:        parts[1],
+		CodeVerifier: parts[2],
+	}, true
+}
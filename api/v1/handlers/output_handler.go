@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/rss"
+	"github.com/hel1th/rssagg/internal/service"
+)
+
+// OutputHandler serves a user's timeline, folders, tags and starred posts as
+// RSS, Atom or JSON Feed. Its routes are authenticated by feed token.
+type OutputHandler struct {
+	syndicationService service.SyndicationService
+}
+
+func NewOutputHandler(syndicationService service.SyndicationService) *OutputHandler {
+	return &OutputHandler{
+		syndicationService: syndicationService,
+	}
+}
+
+func (h *OutputHandler) TimelineFeed(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	feed, err := h.syndicationService.TimelineFeed(r.Context(), user)
+	if err != nil {
+		respondWithServiceError(w, r, err, "Failed to build timeline feed")
+		return
+	}
+
+	respondWithFeed(w, r, feed)
+}
+
+func (h *OutputHandler) FolderFeed(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	folderID, ok := parseUUIDParam(w, r, "id", "Folder ID")
+	if !ok {
+		return
+	}
+
+	feed, err := h.syndicationService.FolderFeed(r.Context(), user.ID, folderID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrFolderNotFound):
+			respondWithError(w, http.StatusNotFound, "Folder not found")
+		default:
+			respondWithServiceError(w, r, err, "Failed to build folder feed")
+		}
+		return
+	}
+
+	respondWithFeed(w, r, feed)
+}
+
+func (h *OutputHandler) TagFeed(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	tagID, ok := parseUUIDParam(w, r, "id", "Tag ID")
+	if !ok {
+		return
+	}
+
+	feed, err := h.syndicationService.TagFeed(r.Context(), user.ID, tagID)
+	if err != nil {
+		respondWithTagError(w, r, err, "Failed to build tag feed")
+		return
+	}
+
+	respondWithFeed(w, r, feed)
+}
+
+func (h *OutputHandler) StarredFeed(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	feed, err := h.syndicationService.StarredFeed(r.Context(), user)
+	if err != nil {
+		respondWithServiceError(w, r, err, "Failed to build starred feed")
+		return
+	}
+
+	respondWithFeed(w, r, feed)
+}
+
+// respondWithFeed renders an output feed in the format given by the
+// "format" query parameter, defaulting to RSS.
+func respondWithFeed(w http.ResponseWriter, r *http.Request, feed *domain.OutputFeed) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = rss.FormatRSS
+	}
+	if !rss.IsSupportedFormat(format) {
+		respondWithError(w, http.StatusBadRequest, "Invalid format, expected one of: rss, atom, json")
+		return
+	}
+
+	feed.FeedURL = requestURL(r)
+	if feed.Link == "" {
+		feed.Link = feed.FeedURL
+	}
+
+	w.Header().Set("Content-Type", rss.ContentType(format))
+	w.WriteHeader(http.StatusOK)
+	if err := rss.Render(w, format, feed); err != nil {
+		slog.ErrorContext(r.Context(), "Error rendering output feed", "format", format, "error", err)
+	}
+}
+
+func requestURL(r *http.Request) string {
+	return baseURL(r) + r.URL.RequestURI()
+}
+
+// baseURL returns the scheme and host the client used to reach the API,
+// honouring X-Forwarded-Proto when running behind a proxy.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return fmt.Sprintf("%s://%s", scheme, r.Host)
+}
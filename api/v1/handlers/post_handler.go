@@ -1,10 +1,11 @@
 package handlers
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/google/uuid"
 	"github.com/hel1th/rssagg/api/v1/dto"
 	"github.com/hel1th/rssagg/internal/domain"
 	"github.com/hel1th/rssagg/internal/service"
@@ -24,31 +25,45 @@ func (h *PostHandler) GetPostsForUser(w http.ResponseWriter, r *http.Request, us
 	limitStr := r.URL.Query().Get("limit")
 	offsetStr := r.URL.Query().Get("offset")
 
-	limit := 10
-	offset := 0
+	query := domain.TimelineQuery{
+		Tag:     r.URL.Query().Get("tag"),
+		Starred: r.URL.Query().Get("starred") == "true",
+		Sort:    r.URL.Query().Get("sort"),
+		Limit:   10,
+	}
+
+	if folderIDStr := r.URL.Query().Get("folder_id"); folderIDStr != "" {
+		folderID, err := uuid.Parse(folderIDStr)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid folder ID format")
+			return
+		}
+		query.FolderID = &folderID
+	}
 
 	if limitStr != "" {
 		if parsedLimit, err := strconv.Atoi(limitStr); err == nil {
-			limit = parsedLimit
+			query.Limit = parsedLimit
 		}
 	}
 
 	if offsetStr != "" {
 		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil {
-			offset = parsedOffset
+			query.Offset = parsedOffset
 		}
 	}
 
-	posts, err := h.postService.GetPostsForUser(r.Context(), user.ID, limit, offset)
+	posts, err := h.postService.GetPostsForUser(r.Context(), user.ID, query)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get posts: %v", err))
+		if errors.Is(err, domain.ErrInvalidTimelineSort) {
+			respondWithError(w, http.StatusBadRequest, "Invalid sort, expected one of: latest, priority")
+		} else if errors.Is(err, domain.ErrTagNotFound) {
+			respondWithError(w, http.StatusNotFound, "Tag not found")
+		} else {
+			respondWithServiceError(w, r, err, "Failed to get posts")
+		}
 		return
 	}
 
-	postValues := make([]domain.Post, len(posts))
-	for i, post := range posts {
-		postValues[i] = *post
-	}
-
-	respondWithJSON(w, http.StatusOK, dto.PostsToResponse(postValues))
+	respondWithJSON(w, http.StatusOK, dto.TimelinePostsToResponse(posts))
 }
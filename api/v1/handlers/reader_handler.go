@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hel1th/rssagg/api/v1/dto"
+	"github.com/hel1th/rssagg/internal/auth"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/service"
+)
+
+// ReaderHandler speaks the Google Reader API dialect of FreshRSS and
+// Miniflux, so clients like NetNewsWire, FeedMe and Read You can sync with
+// rssagg. Subscriptions are followed feeds ("feed/<seq>"), labels are
+// folders and items are posts, identified by their seq.
+type ReaderHandler struct {
+	readerService service.ReaderService
+}
+
+func NewReaderHandler(readerService service.ReaderService) *ReaderHandler {
+	return &ReaderHandler{
+		readerService: readerService,
+	}
+}
+
+// ClientLogin takes the user name as Email and the API key as Passwd.
+func (h *ReaderHandler) ClientLogin(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Error=BadAuthentication", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.readerService.Login(r.Context(), r.Form.Get("Email"), r.Form.Get("Passwd"))
+	if err != nil {
+		if err != domain.ErrInvalidReaderCredentials {
+			slog.ErrorContext(r.Context(), "Error logging in to the Reader API", "error", err)
+		}
+		http.Error(w, "Error=BadAuthentication", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Form.Get("output") == "json" {
+		respondWithJSON(w, http.StatusOK, dto.ReaderLoginResponse{
+			SID:  token,
+			LSID: token,
+			Auth: token,
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "SID=%s\nLSID=%s\nAuth=%s\n", token, token, token)
+}
+
+// Token returns the edit token clients send back as T. The auth token
+// already proves who's asking, so it doubles as the edit token.
+func (h *ReaderHandler) Token(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	token, _ := auth.GetGoogleLoginToken(r.Header)
+	respondWithText(w, token)
+}
+
+func (h *ReaderHandler) UserInfo(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	respondWithJSON(w, http.StatusOK, dto.ReaderUserInfoResponse{
+		UserID:        user.ID.String(),
+		UserName:      user.Name,
+		UserProfileID: user.ID.String(),
+	})
+}
+
+func (h *ReaderHandler) SubscriptionList(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	subscriptions, err := h.readerService.GetSubscriptions(r.Context(), user.ID)
+	if err != nil {
+		respondWithReaderError(w, r, err, "Failed to get subscriptions")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.ReaderSubscriptionsToResponse(subscriptions))
+}
+
+// SubscriptionEdit subscribes (ac=subscribe), unsubscribes (ac=unsubscribe)
+// or renames and relabels (ac=edit) the streams in s.
+func (h *ReaderHandler) SubscriptionEdit(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	if err := r.ParseForm(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid form")
+		return
+	}
+
+	title := r.Form.Get("t")
+	addLabel := r.Form.Get("a")
+	removeLabel := r.Form.Get("r")
+
+	for _, streamID := range r.Form["s"] {
+		var err error
+		switch r.Form.Get("ac") {
+		case "subscribe":
+			_, err = h.readerService.Subscribe(r.Context(), user.ID, streamID, title, addLabel)
+		case "unsubscribe":
+			err = h.readerService.Unsubscribe(r.Context(), user.ID, streamID)
+		case "edit":
+			err = h.readerService.EditSubscription(r.Context(), user.ID, streamID, title, addLabel, removeLabel)
+		default:
+			err = domain.ErrInvalidReaderAction
+		}
+		if err != nil {
+			respondWithReaderError(w, r, err, "Failed to edit subscription")
+			return
+		}
+	}
+
+	respondWithText(w, "OK")
+}
+
+func (h *ReaderHandler) QuickAdd(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	if err := r.ParseForm(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid form")
+		return
+	}
+
+	feedURL := r.Form.Get("quickadd")
+	subscription, err := h.readerService.Subscribe(r.Context(), user.ID, feedURL, "", "")
+	if err != nil {
+		respondWithReaderError(w, r, err, "Failed to subscribe")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.ReaderQuickAddResponse{
+		NumResults: 1,
+		Query:      feedURL,
+		StreamID:   dto.ReaderFeedStreamID(subscription.FeedID),
+		StreamName: subscription.Title,
+	})
+}
+
+func (h *ReaderHandler) TagList(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	labels, err := h.readerService.GetLabels(r.Context(), user.ID)
+	if err != nil {
+		respondWithReaderError(w, r, err, "Failed to get tags")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.ReaderTagsToResponse(labels))
+}
+
+// StreamContents returns a page of items of the stream named in the path,
+// e.g. /stream/contents/feed%2F3, or in s.
+func (h *ReaderHandler) StreamContents(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	query := readerStreamQuery(r)
+	if streamID, err := url.PathUnescape(chi.URLParam(r, "*")); err == nil && streamID != "" {
+		query.StreamID = streamID
+	}
+	if query.StreamID == "" {
+		query.StreamID = domain.ReaderStreamReadingList
+	}
+
+	items, continuation, err := h.readerService.GetStreamItems(r.Context(), user.ID, query)
+	if err != nil {
+		respondWithReaderError(w, r, err, "Failed to get stream")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.ReaderStreamContentsResponse{
+		Direction:    "ltr",
+		ID:           query.StreamID,
+		Updated:      time.Now().Unix(),
+		Items:        dto.ReaderItemsToResponse(items),
+		Continuation: dto.ReaderContinuation(continuation),
+	})
+}
+
+func (h *ReaderHandler) StreamItemIDs(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	refs, continuation, err := h.readerService.GetStreamItemIDs(r.Context(), user.ID, readerStreamQuery(r))
+	if err != nil {
+		respondWithReaderError(w, r, err, "Failed to get item IDs")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.ReaderItemIDsResponse{
+		ItemRefs:     dto.ReaderItemRefsToResponse(refs),
+		Continuation: dto.ReaderContinuation(continuation),
+	})
+}
+
+// StreamItemContents returns the items listed in i, in any ID form.
+func (h *ReaderHandler) StreamItemContents(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	ids, ok := readerItemIDs(w, r)
+	if !ok {
+		return
+	}
+
+	items, err := h.readerService.GetItems(r.Context(), user.ID, ids)
+	if err != nil {
+		respondWithReaderError(w, r, err, "Failed to get items")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.ReaderStreamContentsResponse{
+		Direction: "ltr",
+		ID:        domain.ReaderStreamReadingList,
+		Updated:   time.Now().Unix(),
+		Items:     dto.ReaderItemsToResponse(items),
+	})
+}
+
+// EditTag adds (a) and removes (r) the read and starred states of the
+// items in i.
+func (h *ReaderHandler) EditTag(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	ids, ok := readerItemIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.readerService.EditTags(r.Context(), user.ID, ids, r.Form["a"], r.Form["r"]); err != nil {
+		respondWithReaderError(w, r, err, "Failed to edit tags")
+		return
+	}
+
+	respondWithText(w, "OK")
+}
+
+func readerStreamQuery(r *http.Request) domain.ReaderStreamQuery {
+	values := r.URL.Query()
+
+	query := domain.ReaderStreamQuery{
+		StreamID:    values.Get("s"),
+		Exclude:     values["xt"],
+		Include:     values["it"],
+		OldestFirst: values.Get("r") == "o",
+	}
+	if n, err := strconv.Atoi(values.Get("n")); err == nil {
+		query.Limit = n
+	}
+	if c, err := strconv.ParseInt(values.Get("c"), 10, 64); err == nil {
+		query.Continuation = &c
+	}
+	if ot, err := strconv.ParseInt(values.Get("ot"), 10, 64); err == nil {
+		newerThan := time.Unix(ot, 0).UTC()
+		query.NewerThan = &newerThan
+	}
+	if nt, err := strconv.ParseInt(values.Get("nt"), 10, 64); err == nil {
+		olderThan := time.Unix(nt, 0).UTC()
+		query.OlderThan = &olderThan
+	}
+	return query
+}
+
+func readerItemIDs(w http.ResponseWriter, r *http.Request) ([]int64, bool) {
+	if err := r.ParseForm(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid form")
+		return nil, false
+	}
+
+	ids := make([]int64, 0, len(r.Form["i"]))
+	for _, value := range r.Form["i"] {
+		id, err := domain.ParseReaderItemID(value)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid item ID")
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+	return ids, true
+}
+
+func respondWithText(w http.ResponseWriter, text string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(text))
+}
+
+func respondWithReaderError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidReaderStream):
+		respondWithError(w, http.StatusBadRequest, "Invalid stream ID")
+	case errors.Is(err, domain.ErrInvalidReaderItemID):
+		respondWithError(w, http.StatusBadRequest, "Invalid item ID")
+	case errors.Is(err, domain.ErrInvalidReaderAction):
+		respondWithError(w, http.StatusBadRequest, "Invalid action, expected one of: subscribe, unsubscribe, edit")
+	case errors.Is(err, domain.ErrReaderSubscriptionNotFound):
+		respondWithError(w, http.StatusNotFound, "Subscription not found")
+	case errors.Is(err, domain.ErrInvalidFeedURL), errors.Is(err, domain.ErrInvalidFeedName):
+		respondWithError(w, http.StatusBadRequest, "Invalid feed URL")
+	case errors.Is(err, domain.ErrInvalidFolderName):
+		respondWithError(w, http.StatusBadRequest, "Invalid label")
+	case errors.Is(err, domain.ErrFeedFollowTitleTooLong):
+		respondWithError(w, http.StatusBadRequest, "Title too long")
+	default:
+		respondWithServiceError(w, r, err, msg)
+	}
+}
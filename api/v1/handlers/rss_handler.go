@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -36,17 +36,17 @@ func (h *RSSHandler) FetchFeed(w http.ResponseWriter, r *http.Request, user *dom
 
 	feed, err := h.feedService.GetFeedByID(r.Context(), feedID)
 	if err != nil {
-		if err == domain.ErrFeedNotFound {
+		if errors.Is(err, domain.ErrFeedNotFound) {
 			respondWithError(w, http.StatusNotFound, "Feed not found")
 		} else {
-			respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get feed: %v", err))
+			respondWithServiceError(w, r, err, "Failed to get feed")
 		}
 		return
 	}
 
 	newPostCount, err := h.rssService.FetchSingleFeed(r.Context(), *feed)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to fetch feed: %v", err))
+		respondWithServiceError(w, r, err, "Failed to fetch feed")
 		return
 	}
 
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/api/v1/dto"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/service"
+	"github.com/hel1th/rssagg/internal/stream"
+)
+
+const (
+	streamHeartbeatInterval = 15 * time.Second
+	streamBatchSize         = 100
+	streamRetryMillis       = 5000
+)
+
+// StreamHandler pushes new timeline posts to clients as Server-Sent Events.
+type StreamHandler struct {
+	postService service.PostService
+	hub         *stream.Hub
+}
+
+func NewStreamHandler(postService service.PostService, hub *stream.Hub) *StreamHandler {
+	return &StreamHandler{
+		postService: postService,
+		hub:         hub,
+	}
+}
+
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+
+	var last domain.StreamPosition
+	if lastEventID != "" {
+		pos, err := domain.ParseStreamPosition(lastEventID)
+		if err != nil {
+			respondWithServiceError(w, r, err, "Invalid Last-Event-ID")
+			return
+		}
+		last = pos
+	}
+
+	// Subscribe before reading the starting point so that nothing inserted
+	// in between is missed.
+	wake, unsubscribe := h.hub.Subscribe()
+	defer unsubscribe()
+
+	if lastEventID == "" {
+		pos, err := h.postService.GetPostStreamStart(r.Context())
+		if err != nil {
+			respondWithServiceError(w, r, err, "Failed to open stream")
+			return
+		}
+		last = pos
+	}
+
+	rc := http.NewResponseController(w)
+	// The server's WriteTimeout would otherwise cut every stream short.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		slog.WarnContext(r.Context(), "Error clearing stream write deadline", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetryMillis)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ctx := r.Context()
+	last, err := h.sendSince(ctx, w, rc, user.ID, last)
+	if err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-wake:
+			last, err = h.sendSince(ctx, w, rc, user.ID, last)
+			if err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			// Posts held back by a transaction that was still running at
+			// the last read get no notification of their own.
+			last, err = h.sendSince(ctx, w, rc, user.ID, last)
+			if err != nil {
+				return
+			}
+			fmt.Fprint(w, ": heartbeat\n\n")
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// sendSince writes every post after last as a "post" event and returns the
+// position of the last one sent.
+func (h *StreamHandler) sendSince(ctx context.Context, w http.ResponseWriter, rc *http.ResponseController, userID uuid.UUID, last domain.StreamPosition) (domain.StreamPosition, error) {
+	for {
+		posts, err := h.postService.GetPostsForUserSince(ctx, userID, last, streamBatchSize)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error reading post stream", "user_id", userID, "error", err)
+			return last, err
+		}
+
+		for _, post := range posts {
+			data, err := json.Marshal(dto.TimelinePostToResponse(&post.TimelinePost))
+			if err != nil {
+				return last, err
+			}
+			fmt.Fprintf(w, "id: %s\nevent: post\ndata: %s\n\n", post.Position, data)
+			last = post.Position
+		}
+		if len(posts) > 0 {
+			if err := rc.Flush(); err != nil {
+				return last, err
+			}
+		}
+
+		if len(posts) < streamBatchSize {
+			return last, nil
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/api/v1/dto"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/service"
+)
+
+type TagHandler struct {
+	tagService         service.TagService
+	syndicationService service.SyndicationService
+}
+
+func NewTagHandler(tagService service.TagService, syndicationService service.SyndicationService) *TagHandler {
+	return &TagHandler{
+		tagService:         tagService,
+		syndicationService: syndicationService,
+	}
+}
+
+func (h *TagHandler) CreateTag(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	var req dto.CreateTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Error parsing JSON: %v", err))
+		return
+	}
+
+	tag, err := h.tagService.CreateTag(r.Context(), req.Name, user.ID)
+	if err != nil {
+		respondWithTagError(w, r, err, "Failed to create tag")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, dto.TagToResponse(tag))
+}
+
+func (h *TagHandler) GetUserTags(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	tags, err := h.tagService.GetUserTags(r.Context(), user.ID)
+	if err != nil {
+		respondWithServiceError(w, r, err, "Failed to get tags")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.TagsToResponse(tags))
+}
+
+func (h *TagHandler) RenameTag(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	tagID, ok := parseUUIDParam(w, r, "id", "Tag ID")
+	if !ok {
+		return
+	}
+
+	var req dto.CreateTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Error parsing JSON: %v", err))
+		return
+	}
+
+	tag, err := h.tagService.RenameTag(r.Context(), tagID, user.ID, req.Name)
+	if err != nil {
+		respondWithTagError(w, r, err, "Failed to rename tag")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.TagToResponse(tag))
+}
+
+func (h *TagHandler) DeleteTag(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	tagID, ok := parseUUIDParam(w, r, "id", "Tag ID")
+	if !ok {
+		return
+	}
+
+	if err := h.tagService.DeleteTag(r.Context(), tagID, user.ID); err != nil {
+		respondWithTagError(w, r, err, "Failed to delete tag")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Tag deleted"})
+}
+
+func (h *TagHandler) TagPost(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	var req dto.PostTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Error parsing JSON: %v", err))
+		return
+	}
+
+	if err := h.tagService.TagPost(r.Context(), req.TagID, req.PostID, user.ID); err != nil {
+		respondWithTagError(w, r, err, "Failed to tag post")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Post tagged"})
+}
+
+func (h *TagHandler) UntagPost(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	tagID, ok := parseUUIDParam(w, r, "tag_id", "Tag ID")
+	if !ok {
+		return
+	}
+	postID, ok := parseUUIDParam(w, r, "post_id", "Post ID")
+	if !ok {
+		return
+	}
+
+	if err := h.tagService.UntagPost(r.Context(), tagID, postID, user.ID); err != nil {
+		respondWithTagError(w, r, err, "Failed to untag post")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Post untagged"})
+}
+
+func (h *TagHandler) ExportTag(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	tagID, ok := parseUUIDParam(w, r, "id", "Tag ID")
+	if !ok {
+		return
+	}
+
+	feed, err := h.syndicationService.TagFeed(r.Context(), user.ID, tagID)
+	if err != nil {
+		respondWithTagError(w, r, err, "Failed to export tag")
+		return
+	}
+
+	respondWithFeed(w, r, feed)
+}
+
+func parseUUIDParam(w http.ResponseWriter, r *http.Request, param, label string) (uuid.UUID, bool) {
+	value := r.URL.Query().Get(param)
+	if value == "" {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("%s is required", label))
+		return uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(value)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Invalid %s format", label))
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+func respondWithTagError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrTagNotFound):
+		respondWithError(w, http.StatusNotFound, "Tag not found")
+	case errors.Is(err, domain.ErrPostNotFound):
+		respondWithError(w, http.StatusNotFound, "Post not found")
+	case errors.Is(err, domain.ErrInvalidTagName):
+		respondWithError(w, http.StatusBadRequest, "Invalid tag name")
+	case errors.Is(err, domain.ErrDuplicateTag):
+		respondWithError(w, http.StatusConflict, "Tag already exists")
+	default:
+		respondWithServiceError(w, r, err, msg)
+	}
+}
@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/hel1th/rssagg/api/v1/dto"
 	"github.com/hel1th/rssagg/internal/domain"
@@ -27,9 +30,18 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.userService.CreateUser(r.Context(), req.Name)
+	user, err := h.userService.CreateUser(r.Context(), domain.UserRegistration{
+		Name:     req.Name,
+		Username: req.Username,
+		Email:    req.Email,
+		Password: req.Password,
+	})
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Can't create user: %v", err))
+		if errors.Is(err, domain.ErrInvalidPassword) {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Password must be %d to %d bytes", domain.MinPasswordLength, domain.MaxPasswordLength))
+		} else {
+			respondWithServiceError(w, r, err, "Failed to create user")
+		}
 		return
 	}
 
@@ -46,6 +58,38 @@ func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	json.NewEncoder(w).Encode(payload)
 }
 
+// respondWithError responds with an RFC 7807 problem whose detail is msg.
 func respondWithError(w http.ResponseWriter, code int, msg string) {
-	respondWithJSON(w, code, map[string]string{"error": msg})
+	respondWithProblem(w, dto.NewProblem(code, msg))
+}
+
+// respondWithServiceError responds to an error a service returned. Domain
+// errors get the status of their code and their own message; anything
+// else is logged and answered with a 500 saying msg and no more, so that
+// database errors and the like never reach clients.
+func respondWithServiceError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	if domainErr := domain.AsError(err); domainErr != nil {
+		problem := dto.NewProblem(dto.ErrorStatus(domainErr.Code), capitalize(domainErr.Message))
+		problem.Code = domainErr.Code
+		respondWithProblem(w, problem)
+		return
+	}
+
+	slog.ErrorContext(r.Context(), msg, "error", err)
+	respondWithError(w, http.StatusInternalServerError, msg)
+}
+
+func respondWithProblem(w http.ResponseWriter, problem dto.Problem) {
+	w.Header().Set("Content-Type", dto.ProblemContentType)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// capitalize turns a domain error message into a sentence like the
+// messages handlers write.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
 }
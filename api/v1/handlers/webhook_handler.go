@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hel1th/rssagg/api/v1/dto"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/service"
+)
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	var req dto.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Error parsing JSON: %v", err))
+		return
+	}
+
+	hook, err := h.webhookService.CreateWebhook(r.Context(), req.ToDomain(user.ID))
+	if err != nil {
+		respondWithWebhookError(w, r, err, "Failed to create webhook")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, dto.CreatedWebhookResponse{
+		WebhookResponse: dto.WebhookToResponse(hook),
+		Secret:          hook.Secret,
+	})
+}
+
+func (h *WebhookHandler) GetUserWebhooks(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	hooks, err := h.webhookService.GetUserWebhooks(r.Context(), user.ID)
+	if err != nil {
+		respondWithServiceError(w, r, err, "Failed to get webhooks")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.WebhooksToResponse(hooks))
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	webhookID, ok := parseUUIDParam(w, r, "id", "Webhook ID")
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(r.Context(), webhookID, user.ID); err != nil {
+		respondWithWebhookError(w, r, err, "Failed to delete webhook")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Webhook deleted"})
+}
+
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	webhookID, ok := parseUUIDParam(w, r, "id", "Webhook ID")
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	deliveries, err := h.webhookService.GetDeliveries(r.Context(), webhookID, user.ID, limit, offset)
+	if err != nil {
+		respondWithWebhookError(w, r, err, "Failed to get webhook deliveries")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, dto.WebhookDeliveriesToResponse(deliveries))
+}
+
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request, user *domain.User) {
+	deliveryID, ok := parseUUIDParam(w, r, "id", "Delivery ID")
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.Redeliver(r.Context(), deliveryID, user.ID); err != nil {
+		respondWithWebhookError(w, r, err, "Failed to redeliver webhook")
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, map[string]string{"message": "Delivery queued"})
+}
+
+func respondWithWebhookError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch {
+	case errors.Is(err, domain.ErrWebhookNotFound):
+		respondWithError(w, http.StatusNotFound, "Webhook not found")
+	case errors.Is(err, domain.ErrWebhookDeliveryNotFound):
+		respondWithError(w, http.StatusNotFound, "Webhook delivery not found")
+	case errors.Is(err, domain.ErrInvalidWebhookName):
+		respondWithError(w, http.StatusBadRequest, "Invalid webhook name")
+	case errors.Is(err, domain.ErrInvalidWebhookURL):
+		respondWithError(w, http.StatusBadRequest, "Invalid webhook URL")
+	case errors.Is(err, domain.ErrWebhookURLNotPublic):
+		respondWithError(w, http.StatusBadRequest, "Webhook URL must point to a public address")
+	case errors.Is(err, domain.ErrFeedNotFound):
+		respondWithError(w, http.StatusNotFound, "Feed not found")
+	case errors.Is(err, domain.ErrFolderNotFound):
+		respondWithError(w, http.StatusNotFound, "Folder not found")
+	default:
+		respondWithServiceError(w, r, err, msg)
+	}
+}
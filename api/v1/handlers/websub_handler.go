@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/service"
+	"github.com/hel1th/rssagg/internal/websub"
+)
+
+// websubMaxContentSize caps the size of content a hub may push.
+const websubMaxContentSize = 5 << 20
+
+// WebSubHandler serves the callback WebSub hubs verify subscriptions at and
+// push new content to. It is called by hubs, not users, so it has no auth;
+// content is authenticated by its X-Hub-Signature instead.
+type WebSubHandler struct {
+	websubService service.WebSubService
+	rssService    service.RSSService
+}
+
+func NewWebSubHandler(websubService service.WebSubService, rssService service.RSSService) *WebSubHandler {
+	return &WebSubHandler{
+		websubService: websubService,
+		rssService:    rssService,
+	}
+}
+
+func (h *WebSubHandler) VerifyIntent(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	lease, _ := strconv.Atoi(query.Get("hub.lease_seconds"))
+	challenge, err := h.websubService.VerifyIntent(r.Context(), subscriptionID, domain.WebSubVerification{
+		Mode:         query.Get("hub.mode"),
+		Topic:        query.Get("hub.topic"),
+		Challenge:    query.Get("hub.challenge"),
+		LeaseSeconds: lease,
+		Reason:       query.Get("hub.reason"),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrWebSubSubscriptionNotFound), errors.Is(err, domain.ErrWebSubTopicMismatch), errors.Is(err, domain.ErrInvalidWebSubMode):
+			// A 404 tells the hub we don't agree with the request.
+			http.NotFound(w, r)
+		default:
+			slog.ErrorContext(r.Context(), "Error verifying WebSub intent", "subscription_id", subscriptionID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, challenge)
+}
+
+func (h *WebSubHandler) ReceiveContent(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Gone", http.StatusGone)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, websubMaxContentSize))
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	feed, err := h.websubService.VerifyContent(r.Context(), subscriptionID, r.Header.Get(websub.SignatureHeader), body)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrWebSubSubscriptionNotFound), errors.Is(err, domain.ErrFeedNotFound):
+			// 410 tells the hub to stop delivering to this callback.
+			http.Error(w, "Gone", http.StatusGone)
+		case errors.Is(err, domain.ErrInvalidWebSubSignature):
+			// The spec requires a 2xx even for bad signatures, so that a
+			// forger learns nothing; the content is dropped.
+			slog.WarnContext(r.Context(), "Dropping WebSub content with invalid signature", "subscription_id", subscriptionID)
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			slog.ErrorContext(r.Context(), "Error verifying WebSub content", "subscription_id", subscriptionID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	newPosts, err := h.rssService.IngestPushedContent(r.Context(), *feed, body)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Error ingesting WebSub content", "feed_id", feed.ID, "feed", feed.Name, "error", err)
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Feed pushed", "feed_id", feed.ID, "feed", feed.Name, "new_posts", newPosts)
+	w.WriteHeader(http.StatusAccepted)
+}
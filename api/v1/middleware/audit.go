@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/hel1th/rssagg/internal/audit"
+)
+
+// AuditRequest puts where the request came from in its context, so changes
+// made for it are recorded in the audit log with the client's IP and user
+// agent.
+func AuditRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(audit.WithRequest(r.Context(), audit.FromHTTP(r))))
+	})
+}
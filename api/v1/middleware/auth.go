@@ -2,9 +2,14 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strings"
 
+	"github.com/hel1th/rssagg/api/v1/dto"
 	"github.com/hel1th/rssagg/internal/auth"
 	"github.com/hel1th/rssagg/internal/domain"
 	"github.com/hel1th/rssagg/internal/service"
@@ -12,27 +17,103 @@ import (
 
 type contextKey string
 
-const userContextKey contextKey = "user"
+const (
+	userContextKey    contextKey = "user"
+	apiKeyContextKey  contextKey = "api_key"
+	sessionContextKey contextKey = "session"
+)
 
 type AuthMiddleware struct {
-	userService service.UserService
+	apiKeyService    service.APIKeyService
+	feedTokenService service.FeedTokenService
+	accountService   service.AccountService
 }
 
-func NewAuthMiddleware(userService service.UserService) *AuthMiddleware {
-	return &AuthMiddleware{userService: userService}
+func NewAuthMiddleware(apiKeyService service.APIKeyService, feedTokenService service.FeedTokenService, accountService service.AccountService) *AuthMiddleware {
+	return &AuthMiddleware{
+		apiKeyService:    apiKeyService,
+		feedTokenService: feedTokenService,
+		accountService:   accountService,
+	}
 }
 
-func (m *AuthMiddleware) Require(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		apiKey, err := auth.GetAPIKey(r.Header)
-		if err != nil {
-			respondWithError(w, http.StatusUnauthorized, "Unauthorized")
-			return
-		}
+// Require authenticates requests by API key, or by session cookie when
+// there is no Authorization header, and rejects keys whose scope doesn't
+// cover scope. Sessions can do anything their user can, but requests that
+// change something must echo the session's CSRF token in X-CSRF-Token.
+func (m *AuthMiddleware) Require(scope string) func(http.Handler) http.Handler {
+	return traceAuth("AuthMiddleware.Require", func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				if cookie, err := r.Cookie(domain.SessionCookieName); err == nil {
+					m.serveSession(w, r, next, cookie.Value)
+					return
+				}
+			}
+
+			apiKey, err := auth.GetAPIKey(r.Header)
+			if err != nil {
+				RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+
+			user, key, err := m.apiKeyService.Authenticate(r.Context(), apiKey)
+			if err != nil {
+				respondWithAuthError(w, r, err, "Invalid API key")
+				return
+			}
+			if !key.Allows(scope) {
+				RespondWithError(w, http.StatusForbidden, fmt.Sprintf("API key scope %q does not allow this, requires %q", key.Scope, scope))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(withCredentials(r.Context(), user, key)))
+		})
+	})
+}
+
+func (m *AuthMiddleware) serveSession(w http.ResponseWriter, r *http.Request, next http.Handler, token string) {
+	user, session, err := m.accountService.Authenticate(r.Context(), token)
+	if err != nil {
+		respondWithAuthError(w, r, err, "Invalid session")
+		return
+	}
+	if !isSafeMethod(r.Method) && !validCSRFToken(r.Header.Get(domain.CSRFHeader), session.CSRFToken) {
+		RespondWithError(w, http.StatusForbidden, "Missing or invalid CSRF token")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), userContextKey, user)
+	ctx = context.WithValue(ctx, sessionContextKey, session)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RequireRole rejects users without role. It goes after Require.
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := GetUserFromContext(r.Context())
+			if !ok || user.Role != role {
+				RespondWithError(w, http.StatusForbidden, fmt.Sprintf("Requires the %q role", role))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireFeedToken authenticates output feed requests by the secret "token"
+// query parameter, since feed readers can't send an Authorization header.
+func (m *AuthMiddleware) RequireFeedToken(next http.Handler) http.Handler {
+	return traceAuth("AuthMiddleware.RequireFeedToken", m.requireFeedToken)(next)
+}
 
-		user, err := m.userService.GetUserByAPIKey(r.Context(), apiKey)
+func (m *AuthMiddleware) requireFeedToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := m.feedTokenService.GetUserByToken(r.Context(), r.URL.Query().Get("token"))
 		if err != nil {
-			respondWithError(w, http.StatusUnauthorized, "Invalid API key")
+			respondWithAuthError(w, r, err, "Invalid feed token")
 			return
 		}
 
@@ -41,13 +122,86 @@ func (m *AuthMiddleware) Require(next http.Handler) http.Handler {
 	})
 }
 
+// RequireReaderToken authenticates Google Reader API requests by the token
+// ClientLogin handed out, sent as "Authorization: GoogleLogin auth=<token>",
+// and rejects keys whose scope doesn't cover scope.
+func (m *AuthMiddleware) RequireReaderToken(scope string) func(http.Handler) http.Handler {
+	return traceAuth("AuthMiddleware.RequireReaderToken", func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, err := auth.GetGoogleLoginToken(r.Header)
+			if err != nil {
+				RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+
+			user, key, err := m.apiKeyService.AuthenticateClientHash(r.Context(), strings.ToLower(token))
+			if err != nil {
+				respondWithAuthError(w, r, err, "Invalid GoogleLogin token")
+				return
+			}
+			if !key.Allows(scope) {
+				RespondWithError(w, http.StatusForbidden, fmt.Sprintf("API key scope %q does not allow this, requires %q", key.Scope, scope))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(withCredentials(r.Context(), user, key)))
+		})
+	})
+}
+
 func GetUserFromContext(ctx context.Context) (*domain.User, bool) {
 	user, ok := ctx.Value(userContextKey).(*domain.User)
 	return user, ok
 }
 
-func respondWithError(w http.ResponseWriter, code int, msg string) {
-	w.Header().Set("Content-Type", "application/json")
+// GetAPIKeyFromContext returns the API key the request was authenticated
+// with, if it was.
+func GetAPIKeyFromContext(ctx context.Context) (*domain.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyContextKey).(*domain.APIKey)
+	return key, ok
+}
+
+// GetSessionFromContext returns the session the request was authenticated
+// with, if it was.
+func GetSessionFromContext(ctx context.Context) (*domain.Session, bool) {
+	session, ok := ctx.Value(sessionContextKey).(*domain.Session)
+	return session, ok
+}
+
+func withCredentials(ctx context.Context, user *domain.User, key *domain.APIKey) context.Context {
+	ctx = context.WithValue(ctx, userContextKey, user)
+	return context.WithValue(ctx, apiKeyContextKey, key)
+}
+
+// RespondWithError responds with an RFC 7807 problem whose detail is msg.
+// It is exported for the handler adapters the router is wired up with.
+func RespondWithError(w http.ResponseWriter, code int, msg string) {
+	problem := dto.NewProblem(code, msg)
+	w.Header().Set("Content-Type", dto.ProblemContentType)
 	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+	json.NewEncoder(w).Encode(problem)
+}
+
+// respondWithAuthError responds to a failed authentication with msg if the
+// credentials were wrong, or logs why it couldn't be checked, such as the
+// database being down, and fails with a 500.
+func respondWithAuthError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	if domain.AsError(err) == nil {
+		slog.ErrorContext(r.Context(), "Failed to authenticate", "error", err)
+		RespondWithError(w, http.StatusInternalServerError, "Failed to authenticate")
+		return
+	}
+	RespondWithError(w, http.StatusUnauthorized, msg)
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func validCSRFToken(got, want string) bool {
+	return got != "" && subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
 }
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/hel1th/rssagg/internal/metrics"
+)
+
+// statusRecorder remembers the status code a handler responded with.
+// Unwrap lets handlers reach the underlying writer through
+// http.ResponseController, to flush streams and extend deadlines.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Metrics records each request's latency and status by the route it
+// matched. Requests that matched no route are recorded as "unmatched".
+func Metrics(m *metrics.Metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w}
+
+			next.ServeHTTP(recorder, r)
+
+			route := "unmatched"
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			m.ObserveRequest(r.Method, route, status, time.Since(start))
+		})
+	}
+}
+
+// RequireMetricsToken lets through only requests bearing token, so that
+// metrics aren't public. An empty token lets everything through.
+func RequireMetricsToken(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if token != "" && subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
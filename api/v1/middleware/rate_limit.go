@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hel1th/rssagg/internal/audit"
+	"github.com/hel1th/rssagg/internal/ratelimit"
+)
+
+// RateLimiter limits requests per route group, by client IP or by user,
+// with token buckets kept in a ratelimit.Store.
+type RateLimiter struct {
+	store  ratelimit.Store
+	limits map[string]ratelimit.Limit
+}
+
+// NewRateLimiter returns a limiter of the route groups in limits. Groups
+// missing from it, or with the zero Limit, aren't limited.
+func NewRateLimiter(store ratelimit.Store, limits map[string]ratelimit.Limit) *RateLimiter {
+	return &RateLimiter{
+		store:  store,
+		limits: limits,
+	}
+}
+
+// PerIP limits the requests of each client IP to group's limit.
+func (l *RateLimiter) PerIP(group string) func(http.Handler) http.Handler {
+	return l.limit(group, func(r *http.Request) string {
+		return "ip:" + audit.ClientIP(r)
+	})
+}
+
+// PerUser limits the requests of each user, across all their API keys and
+// sessions, to group's limit. It goes after authentication; requests
+// without a user are limited by IP instead.
+func (l *RateLimiter) PerUser(group string) func(http.Handler) http.Handler {
+	return l.limit(group, func(r *http.Request) string {
+		if user, ok := GetUserFromContext(r.Context()); ok {
+			return "user:" + user.ID.String()
+		}
+		return "ip:" + audit.ClientIP(r)
+	})
+}
+
+func (l *RateLimiter) limit(group string, key func(*http.Request) string) func(http.Handler) http.Handler {
+	limit := l.limits[group]
+	if !limit.Enabled() {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, err := l.store.Take(r.Context(), group+":"+key(r), limit)
+			if err != nil {
+				// Failing open keeps the API up when the store is down
+				slog.ErrorContext(r.Context(), "Error checking rate limit", "group", group, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			setRateLimitHeaders(w, limit, result)
+			if !result.Allowed {
+				retryAfter := ceilSeconds(result.RetryAfter)
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				RespondWithError(w, http.StatusTooManyRequests, fmt.Sprintf("Rate limit exceeded, retry in %d seconds", retryAfter))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// setRateLimitHeaders sets the RateLimit-* headers of the IETF draft. A
+// request can pass several limits; the one with the fewest remaining
+// requests is reported.
+func setRateLimitHeaders(w http.ResponseWriter, limit ratelimit.Limit, result ratelimit.Result) {
+	if current := w.Header().Get("RateLimit-Remaining"); current != "" {
+		if remaining, err := strconv.Atoi(current); err == nil && remaining <= result.Remaining {
+			return
+		}
+	}
+
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(limit.Requests))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+	w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, ceilSeconds(limit.Period)))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+// Trace starts a span for each request, continuing the trace of any W3C
+// trace context the client sent. Spans are named by the route matched,
+// once it is known.
+func Trace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.StartKind(ctx, r.Method, trace.SpanKindServer,
+			semconv.HTTPRequestMethodKey.String(r.Method),
+			semconv.URLPath(r.URL.Path),
+		)
+		defer span.End()
+
+		recorder := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			span.SetName(r.Method + " " + rctx.RoutePattern())
+			span.SetAttributes(semconv.HTTPRoute(rctx.RoutePattern()))
+		}
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
+
+type authSpanContextKey struct{}
+
+type authSpan struct {
+	parent trace.Span
+	span   trace.Span
+}
+
+// traceAuth runs an authenticating middleware in a span of its own, which
+// ends as soon as the middleware lets the request through or turns it
+// away, so that the time spent authenticating shows apart from the
+// handler's.
+func traceAuth(name string, authenticate func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		inner := authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			spans := r.Context().Value(authSpanContextKey{}).(*authSpan)
+			spans.span.End()
+			next.ServeHTTP(w, r.WithContext(trace.ContextWithSpan(r.Context(), spans.parent)))
+		}))
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			spans := &authSpan{parent: trace.SpanFromContext(r.Context())}
+			ctx := context.WithValue(r.Context(), authSpanContextKey{}, spans)
+			ctx, spans.span = tracing.Start(ctx, name)
+
+			inner.ServeHTTP(w, r.WithContext(ctx))
+
+			// Ending again is a no-op for requests let through
+			spans.span.End()
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+
+	"github.com/hel1th/rssagg/api/v1/dto"
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+)
+
+type adminCommand struct {
+	name    string
+	args    string
+	summary string
+	// needsAdmin commands act as the admin named by -as
+	needsAdmin bool
+	run        func(ctx context.Context, a *app, admin *domain.User, args []string) error
+}
+
+var adminCommands = []adminCommand{
+	{"users", "[search]", "List users, searching name, username and email", false, adminUsers},
+	{"set-role", "<user> <user|admin>", "Set a user's role, as when making the first admin", false, adminSetRole},
+	{"disable-user", "<user>", "Disable a user and end their sessions", true, adminSetUserDisabled(true)},
+	{"enable-user", "<user>", "Enable a disabled user", true, adminSetUserDisabled(false)},
+	{"reset-password", "<user>", "Issue a password reset token for a user", true, adminResetPassword},
+	{"disable-feed", "<feed>", "Stop fetching a feed", false, adminSetFeedDisabled(true)},
+	{"enable-feed", "<feed>", "Fetch a disabled feed again", false, adminSetFeedDisabled(false)},
+	{"scraper", "", "Print scraper heartbeats, feed counts and failing feeds as JSON", false, adminScraper},
+}
+
+// admin runs the actions of the admin API from the command line, for
+// operators with database access rather than an admin account. Users are
+// given by ID or username, feeds by ID or URL. Changes are recorded in the
+// audit log as made by the admin named by -as, or by no one.
+func admin(args []string) {
+	fs := flag.NewFlagSet("admin", flag.ExitOnError)
+	as := fs.String("as", "", "`user` the action is recorded as taken by, who must be an admin")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s admin [flags] <command> [arguments]\n\nCommands:\n", os.Args[0])
+		for _, cmd := range adminCommands {
+			fmt.Fprintf(fs.Output(), "  %-36s %s\n", cmd.name+" "+cmd.args, cmd.summary)
+		}
+		fmt.Fprintln(fs.Output(), "\nFlags:")
+		fs.PrintDefaults()
+	}
+	cfg, shutdown := start(fs, args, os.Stderr)
+	defer shutdown()
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	var cmd *adminCommand
+	for i := range adminCommands {
+		if adminCommands[i].name == fs.Arg(0) {
+			cmd = &adminCommands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(fs.Output(), "unknown admin command %q\n\n", fs.Arg(0))
+		fs.Usage()
+		os.Exit(2)
+	}
+	if cmd.needsAdmin && *as == "" {
+		fatal(cmd.name + " needs -as naming the admin it is done by")
+	}
+
+	a := newApp(cfg)
+	defer a.Close()
+
+	ctx := context.Background()
+	var actor *domain.User
+	if *as != "" {
+		user, err := findUser(ctx, a, *as)
+		if err != nil {
+			fatal("Failed to find admin", "user", *as, "error", err)
+		}
+		if !user.IsAdmin() {
+			fatal("-as must name an admin", "user", *as)
+		}
+		actor = user
+	}
+
+	if err := cmd.run(ctx, a, actor, fs.Args()[1:]); err != nil {
+		fatal("Failed to run "+cmd.name, "error", err)
+	}
+}
+
+func adminUsers(ctx context.Context, a *app, _ *domain.User, args []string) error {
+	users, total, err := a.adminService.SearchUsers(ctx, domain.AdminUserQuery{
+		Search: strings.Join(args, " "),
+		Limit:  200,
+	})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSERNAME\tNAME\tEMAIL\tROLE\tDISABLED")
+	for _, user := range users {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%t\n", user.ID, deref(user.Username), user.Name, deref(user.Email), user.Role, user.IsDisabled())
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if total > int64(len(users)) {
+		fmt.Fprintf(os.Stderr, "%d of %d users; search to narrow them down\n", len(users), total)
+	}
+	return nil
+}
+
+func adminSetRole(ctx context.Context, a *app, admin *domain.User, args []string) error {
+	if len(args) != 2 {
+		return errors.New("set-role needs a user and a role")
+	}
+	user, err := findUser(ctx, a, args[0])
+	if err != nil {
+		return err
+	}
+	return a.adminService.SetUserRole(ctx, admin, user.ID, args[1])
+}
+
+func adminSetUserDisabled(disabled bool) func(context.Context, *app, *domain.User, []string) error {
+	return func(ctx context.Context, a *app, admin *domain.User, args []string) error {
+		if len(args) != 1 {
+			return errors.New("a user is needed")
+		}
+		user, err := findUser(ctx, a, args[0])
+		if err != nil {
+			return err
+		}
+		return a.adminService.SetUserDisabled(ctx, admin, user.ID, disabled)
+	}
+}
+
+func adminResetPassword(ctx context.Context, a *app, admin *domain.User, args []string) error {
+	if len(args) != 1 {
+		return errors.New("reset-password needs a user")
+	}
+	user, err := findUser(ctx, a, args[0])
+	if err != nil {
+		return err
+	}
+	reset, err := a.adminService.IssuePasswordReset(ctx, admin, user.ID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\nexpires %s\n", reset.Token, reset.ExpiresAt.Format("2006-01-02 15:04:05 MST"))
+	return nil
+}
+
+func adminSetFeedDisabled(disabled bool) func(context.Context, *app, *domain.User, []string) error {
+	return func(ctx context.Context, a *app, admin *domain.User, args []string) error {
+		if len(args) != 1 {
+			return errors.New("a feed is needed")
+		}
+		feed, err := findFeed(ctx, a, args[0])
+		if err != nil {
+			return err
+		}
+		return a.adminService.SetFeedDisabled(ctx, admin, feed.ID, disabled)
+	}
+}
+
+func adminScraper(ctx context.Context, a *app, _ *domain.User, _ []string) error {
+	status, err := a.scraperService.Status(ctx)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(dto.ScraperStatusToResponse(status))
+}
+
+// findUser looks a user up by ID, or by username if ref isn't one.
+func findUser(ctx context.Context, a *app, ref string) (*domain.User, error) {
+	var dbUser database.User
+	id, err := uuid.Parse(ref)
+	if err == nil {
+		dbUser, err = a.userRepo.GetByID(ctx, id)
+	} else {
+		dbUser, err = a.userRepo.GetByUsername(ctx, strings.ToLower(ref))
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return domain.MapUserFromDB(dbUser), nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
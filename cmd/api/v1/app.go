@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/hel1th/rssagg/internal/config"
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/metrics"
+	"github.com/hel1th/rssagg/internal/migrate"
+	"github.com/hel1th/rssagg/internal/oidc"
+	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/rss"
+	"github.com/hel1th/rssagg/internal/service"
+	"github.com/hel1th/rssagg/internal/webhook"
+	"github.com/hel1th/rssagg/internal/websub"
+)
+
+// app holds the repositories and services every command shares, all on
+// one database connection pool. Services whose settings are missing, like
+// WebSub without a callback URL, are nil.
+type app struct {
+	cfg      *config.Config
+	conn     *sql.DB
+	migrator *migrate.Migrator
+	metrics  *metrics.Metrics
+
+	userRepo      repository.UserRepository
+	rateLimitRepo repository.RateLimitRepository
+
+	auditService       service.AuditService
+	apiKeyService      service.APIKeyService
+	userService        service.UserService
+	accountService     service.AccountService
+	feedService        service.FeedService
+	feedFollowService  service.FeedFollowService
+	postService        service.PostService
+	tagService         service.TagService
+	folderService      service.FolderService
+	syndicationService service.SyndicationService
+	feedTokenService   service.FeedTokenService
+	filterRuleService  service.FilterRuleService
+	webhookService     service.WebhookService
+	websubService      service.WebSubService
+	feverService       service.FeverService
+	oidcService        service.OIDCService
+	readerService      service.ReaderService
+	rssService         service.RSSService
+	scraperService     service.ScraperService
+	adminService       service.AdminService
+}
+
+// newApp connects to the database, checks its schema and wires up the
+// repositories and services on it.
+func newApp(cfg *config.Config) *app {
+	conn := openDB(cfg.Database)
+	migrator := checkSchema(conn, cfg.Database.AutoMigrate)
+
+	// Queries are traced, and logged with the request or scrape they ran for
+	db := database.New(repository.NewTracedDB(repository.NewLoggedDB(conn)))
+	appMetrics := metrics.New(conn)
+
+	// Initialize repositories
+	userRepo := repository.NewUserRepository(db)
+	feedRepo := repository.NewFeedRepository(db)
+	feedFollowRepo := repository.NewFeedFollowRepository(db)
+	postRepo := repository.NewPostRepository(db)
+	folderRepo := repository.NewFolderRepository(db)
+	filterRuleRepo := repository.NewFilterRuleRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	feedTokenRepo := repository.NewFeedTokenRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	websubRepo := repository.NewWebSubRepository(db)
+	feverRepo := repository.NewFeverRepository(db)
+	readerRepo := repository.NewReaderRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	userIdentityRepo := repository.NewUserIdentityRepository(db)
+	adminRepo := repository.NewAdminRepository(db, conn)
+	scraperRepo := repository.NewScraperRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	rateLimitRepo := repository.NewRateLimitRepository(db)
+
+	// Initialize services
+	auditService := service.NewAuditService(auditRepo)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, auditService)
+	userService := service.NewUserService(userRepo, apiKeyService, auditService)
+	accountService := service.NewAccountService(userRepo, sessionRepo, auditService)
+	feedService := service.NewFeedService(feedRepo, auditService)
+	feedFollowService := service.NewFeedFollowService(feedFollowRepo, folderRepo, auditService)
+	postService := service.NewPostService(postRepo, tagRepo)
+	tagService := service.NewTagService(tagRepo, postRepo)
+	folderService := service.NewFolderService(folderRepo)
+	syndicationService := service.NewSyndicationService(postService, tagService, folderService)
+	feedTokenService := service.NewFeedTokenService(feedTokenRepo)
+	filterRuleService := service.NewFilterRuleService(filterRuleRepo, feedFollowRepo, folderRepo, postRepo, tagRepo)
+	webhookService := service.NewWebhookService(webhookRepo, feedRepo, folderRepo, webhook.NewSender(cfg.Webhooks.AllowPrivateTargets))
+	var websubService service.WebSubService
+	if cfg.WebSub.CallbackURL != "" {
+		websubService = service.NewWebSubService(websubRepo, feedRepo, websub.NewSubscriber(nil), cfg.WebSub.CallbackURL)
+	}
+	feverService := service.NewFeverService(feverRepo, postRepo, apiKeyService)
+	var oidcService service.OIDCService
+	if cfg.OIDC.IssuerURL != "" {
+		oidcService = newOIDCService(cfg.OIDC, userRepo, userIdentityRepo, apiKeyRepo, accountService, auditService)
+	}
+	fetcher := newFetcher(cfg.Scraper)
+	readerService := service.NewReaderService(readerRepo, feedRepo, folderRepo, feedService, feedFollowService, folderService, apiKeyService, fetcher)
+	rssService := service.NewRSSServiceWithFetcher(postRepo, feedRepo, filterRuleService, webhookService, websubService, fetcher, appMetrics)
+	scraperService := service.NewScraperService(scraperRepo)
+	adminService := service.NewAdminService(adminRepo, userRepo, feedRepo, sessionRepo, accountService, rssService, auditService)
+
+	return &app{
+		cfg:      cfg,
+		conn:     conn,
+		migrator: migrator,
+		metrics:  appMetrics,
+
+		userRepo:      userRepo,
+		rateLimitRepo: rateLimitRepo,
+
+		auditService:       auditService,
+		apiKeyService:      apiKeyService,
+		userService:        userService,
+		accountService:     accountService,
+		feedService:        feedService,
+		feedFollowService:  feedFollowService,
+		postService:        postService,
+		tagService:         tagService,
+		folderService:      folderService,
+		syndicationService: syndicationService,
+		feedTokenService:   feedTokenService,
+		filterRuleService:  filterRuleService,
+		webhookService:     webhookService,
+		websubService:      websubService,
+		feverService:       feverService,
+		oidcService:        oidcService,
+		readerService:      readerService,
+		rssService:         rssService,
+		scraperService:     scraperService,
+		adminService:       adminService,
+	}
+}
+
+// healthService checks the database and the scraper for readiness probes.
+// runsScraper is whether this process runs the scraper, which then has to
+// be alive for the process to be ready.
+func (a *app) healthService(runsScraper bool) service.HealthService {
+	return service.NewHealthService(a.conn, a.migrator, a.scraperService, service.HealthOptions{
+		Timeout:         a.cfg.Health.Timeout,
+		MaxHeartbeatAge: a.cfg.Health.MaxHeartbeatAge,
+		MaxQueueLag:     a.cfg.Health.MaxQueueLag,
+		RunsScraper:     runsScraper,
+	})
+}
+
+func (a *app) Close() error {
+	return a.conn.Close()
+}
+
+// openDB opens the connection pool and checks that Postgres can be reached.
+func openDB(db config.Database) *sql.DB {
+	conn, err := sql.Open("postgres", db.URL)
+	if err != nil {
+		fatal("Failed to connect to database", "error", err)
+	}
+	conn.SetMaxOpenConns(db.MaxOpenConns)
+	conn.SetMaxIdleConns(db.MaxIdleConns)
+	conn.SetConnMaxLifetime(db.ConnMaxLifetime)
+
+	if err := conn.Ping(); err != nil {
+		fatal("Failed to ping database", "error", err)
+	}
+	slog.Info("Database connection established")
+
+	return conn
+}
+
+// checkSchema exits if the database schema is older than the code expects,
+// after applying pending migrations if autoMigrate is set. The returned
+// migrator checks it again for readiness probes.
+func checkSchema(conn *sql.DB, autoMigrate bool) *migrate.Migrator {
+	ctx := context.Background()
+	migrator, err := migrate.New(conn)
+	if err != nil {
+		fatal("Failed to set up migrations", "error", err)
+	}
+
+	if autoMigrate {
+		results, err := migrator.Up(ctx)
+		if err != nil {
+			fatal("Failed to migrate database", "error", err)
+		}
+		for _, result := range results {
+			slog.Info("Applied migration", "version", result.Source.Version, "file", result.Source.Path, "duration", result.Duration)
+		}
+	}
+
+	if err := migrator.Check(ctx); err != nil {
+		fatal("Refusing to start, run the migrate command", "error", err)
+	}
+	return migrator
+}
+
+// newFetcher builds the fetcher shared by everything that fetches feeds,
+// so that per-host limits hold across all of them.
+func newFetcher(scraper config.Scraper) rss.Fetcher {
+	slog.Info("Feed fetch limits", "concurrency", scraper.Concurrency, "host_concurrency", scraper.HostConcurrency, "host_delay", scraper.HostDelay)
+
+	return rss.NewFetcher(rss.FetcherConfig{
+		UserAgent: scraper.UserAgent,
+		Timeout:   scraper.FetchTimeout,
+		Scheduler: rss.NewScheduler(rss.SchedulerConfig{
+			Concurrency:     scraper.Concurrency,
+			HostConcurrency: scraper.HostConcurrency,
+			HostDelay:       scraper.HostDelay,
+			HostGroups:      scraper.HostGroups,
+		}),
+	})
+}
+
+// newOIDCService configures single sign-on.
+func newOIDCService(
+	oidcConfig config.OIDC,
+	userRepo repository.UserRepository,
+	userIdentityRepo repository.UserIdentityRepository,
+	apiKeyRepo repository.APIKeyRepository,
+	accountService service.AccountService,
+	auditService service.AuditService,
+) service.OIDCService {
+	roleMapping, err := domain.ParseOIDCRoleMapping(oidcConfig.RoleMapping)
+	if err != nil {
+		fatal("Invalid oidc.role_mapping", "error", err)
+	}
+
+	provider := oidc.NewProvider(oidc.Config{
+		IssuerURL:    oidcConfig.IssuerURL,
+		ClientID:     oidcConfig.ClientID,
+		ClientSecret: oidcConfig.ClientSecret,
+		RedirectURL:  oidcConfig.RedirectURL,
+		Scopes:       oidcConfig.Scopes,
+	}, nil)
+
+	return service.NewOIDCService(provider, userRepo, userIdentityRepo, apiKeyRepo, accountService, auditService, service.OIDCOptions{
+		RoleClaim:   oidcConfig.RoleClaim,
+		RoleMapping: roleMapping,
+		AllowSignup: oidcConfig.AllowSignup,
+	})
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+
+	"github.com/hel1th/rssagg/internal/domain"
+)
+
+// fetchOnce fetches one feed, given by ID or URL, the way the scraper
+// would, and prints how many new posts it had.
+func fetchOnce(args []string) {
+	fs := flag.NewFlagSet("fetch-once", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s fetch-once [flags] <feed ID or URL>\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	cfg, shutdown := start(fs, args, os.Stderr)
+	defer shutdown()
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	a := newApp(cfg)
+	defer a.Close()
+
+	ctx := context.Background()
+	feed, err := findFeed(ctx, a, fs.Arg(0))
+	if err != nil {
+		fatal("Failed to find feed", "feed", fs.Arg(0), "error", err)
+	}
+
+	newPosts, err := a.rssService.FetchSingleFeed(ctx, *feed)
+	if err != nil {
+		fatal("Failed to fetch feed", "feed_id", feed.ID, "error", err)
+	}
+	fmt.Printf("%s: %d new posts\n", feed.URL, newPosts)
+}
+
+// findFeed looks a feed up by ID, or by URL if ref isn't one.
+func findFeed(ctx context.Context, a *app, ref string) (*domain.Feed, error) {
+	if id, err := uuid.Parse(ref); err == nil {
+		return a.feedService.GetFeedByID(ctx, id)
+	}
+	return a.feedService.GetFeedByURL(ctx, ref)
+}
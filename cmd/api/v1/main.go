@@ -2,201 +2,109 @@ package main
 
 import (
 	"context"
-	"database/sql"
-	"log"
-	"net/http"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
-	"time"
 
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/cors"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 
-	"github.com/hel1th/rssagg/api/v1/handlers"
-	"github.com/hel1th/rssagg/api/v1/middleware"
-	"github.com/hel1th/rssagg/internal/database"
-	"github.com/hel1th/rssagg/internal/domain"
-	"github.com/hel1th/rssagg/internal/repository"
-	"github.com/hel1th/rssagg/internal/service"
+	"github.com/hel1th/rssagg/internal/config"
+	"github.com/hel1th/rssagg/internal/logging"
+	"github.com/hel1th/rssagg/internal/tracing"
 )
 
-func main() {
-	// Load environment variables
-	if err := godotenv.Load(".env"); err != nil {
-		log.Println("No .env file found, using system environment variables")
-	}
-
-	port := os.Getenv("PORT")
-	if port == "" {
-		log.Fatal("PORT environment variable is not set")
-	}
-
-	dbURL := os.Getenv("DB_URL")
-	if dbURL == "" {
-		log.Fatal("DB_URL environment variable is not set")
-	}
-
-	conn, err := sql.Open("postgres", dbURL)
-	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
-	}
-	defer conn.Close()
-
-	if err := conn.Ping(); err != nil {
-		log.Fatal("Failed to ping database:", err)
-	}
-	log.Println("Database connection established")
-
-	db := database.New(conn)
-
-	// Initialize repositories
-	userRepo := repository.NewUserRepository(db)
-	feedRepo := repository.NewFeedRepository(db)
-	feedFollowRepo := repository.NewFeedFollowRepository(db)
-	postRepo := repository.NewPostRepository(db)
-
-	// Initialize services
-	userService := service.NewUserService(userRepo)
-	feedService := service.NewFeedService(feedRepo)
-	feedFollowService := service.NewFeedFollowService(feedFollowRepo)
-	postService := service.NewPostService(postRepo)
-	rssService := service.NewRSSService(postRepo, feedRepo)
-
-	// Initialize handlers
-	userHandler := handlers.NewUserHandler(userService)
-	feedHandler := handlers.NewFeedHandler(feedService)
-	feedFollowHandler := handlers.NewFeedFollowHandler(feedFollowService)
-	postHandler := handlers.NewPostHandler(postService)
-	rssHandler := handlers.NewRSSHandler(rssService, feedService)
-
-	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(userService)
-
-	// Start background RSS scraper
-	go startScraper(db, feedService, rssService, 10, time.Minute)
-
-	router := setupRouter(
-		userHandler,
-		feedHandler,
-		feedFollowHandler,
-		postHandler,
-		rssHandler,
-		authMiddleware,
-	)
-
-	srv := &http.Server{
-		Handler:      router,
-		Addr:         ":" + port,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
-	}
-
-	log.Printf("Server starting on port %s", port)
-	if err := srv.ListenAndServe(); err != nil {
-		log.Fatal("Server failed to start:", err)
-	}
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(args []string)
 }
 
-func setupRouter(
-	userHandler *handlers.UserHandler,
-	feedHandler *handlers.FeedHandler,
-	feedFollowHandler *handlers.FeedFollowHandler,
-	postHandler *handlers.PostHandler,
-	rssHandler *handlers.RSSHandler,
-	authMiddleware *middleware.AuthMiddleware,
-) http.Handler {
-	router := chi.NewRouter()
-
-	router.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"https://*", "http://*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"*"},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: false,
-		MaxAge:           300,
-	}))
-
-	v1Router := chi.NewRouter()
-
-	v1Router.Get("/healthz", healthCheck)
-	v1Router.Get("/err", errorHandler)
-
-	v1Router.Post("/users", userHandler.CreateUser)
-	v1Router.With(authMiddleware.Require).Get("/users", adaptAuthHandler(userHandler.GetUser))
-
-	v1Router.With(authMiddleware.Require).Post("/feeds", adaptAuthHandler(feedHandler.CreateFeed))
-	v1Router.Get("/feeds", feedHandler.GetAllFeeds)
+// dotenvErr is why .env couldn't be loaded, logged once logging is set up.
+var dotenvErr error
 
-	v1Router.With(authMiddleware.Require).Post("/feed_follows", adaptAuthHandler(feedFollowHandler.FollowFeed))
-	v1Router.With(authMiddleware.Require).Get("/feed_follows", adaptAuthHandler(feedFollowHandler.GetUserFeedFollows))
-	v1Router.With(authMiddleware.Require).Delete("/feed_follows", adaptAuthHandler(feedFollowHandler.UnfollowFeed))
-
-	v1Router.With(authMiddleware.Require).Get("/posts", adaptAuthHandler(postHandler.GetPostsForUser))
-
-	v1Router.With(authMiddleware.Require).Post("/rss/fetch", adaptAuthHandler(rssHandler.FetchFeed))
+var commands = []command{
+	{"serve", "[-worker]", "Serve the HTTP API", serve},
+	{"worker", "", "Run the scraper, webhook deliveries and WebSub renewals", worker},
+	{"migrate", "[up|down|redo|status]", "Migrate the database schema, by default to the latest version", migrateDB},
+	{"fetch-once", "<feed ID or URL>", "Fetch one feed now and exit", fetchOnce},
+	{"admin", "<command> [arguments]", "Manage users and feeds", admin},
+}
 
-	router.Mount("/v1", v1Router)
+func main() {
+	// Load environment variables
+	dotenvErr = godotenv.Load(".env")
 
-	return router
-}
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
 
-func adaptAuthHandler(handler func(http.ResponseWriter, *http.Request, *domain.User)) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		user, ok := middleware.GetUserFromContext(r.Context())
-		if !ok {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	name, args := os.Args[1], os.Args[2:]
+	for _, cmd := range commands {
+		if cmd.name == name {
+			cmd.run(args)
 			return
 		}
-		handler(w, r, user)
 	}
-}
 
-func healthCheck(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status":"ok"}`))
+	if name != "help" && name != "-h" && name != "-help" {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	}
+	usage()
+	os.Exit(2)
 }
 
-func errorHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusInternalServerError)
-	w.Write([]byte(`{"error":"Internal Server Error"}`))
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [flags] [arguments]\n\nCommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-36s %s\n", cmd.name+" "+cmd.args, cmd.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nEvery command takes the configuration flags; see %s <command> -h.\n", os.Args[0])
 }
 
-func startScraper(
-	db *database.Queries,
-	feedService service.FeedService,
-	rssService service.RSSService,
-	concurrency int,
-	interval time.Duration,
-) {
-	log.Printf("Starting RSS scraper: interval=%v, concurrency=%d", interval, concurrency)
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		ctx := context.Background()
-		feeds, err := feedService.GetNextFeedsToFetch(ctx, concurrency)
-		if err != nil {
-			log.Printf("Error fetching feeds to scrape: %v", err)
-			continue
+// start loads the configuration from fs's flags, the file they name and the
+// environment, and sets up logging to logs and tracing. With -print-config
+// it prints the configuration and exits instead. The returned function
+// flushes spans not yet exported.
+func start(fs *flag.FlagSet, args []string, logs io.Writer) (*config.Config, func()) {
+	printConfig := fs.Bool("print-config", false, "print the effective config, secrets redacted, and exit")
+	cfg, err := config.Load(fs, args)
+	if err != nil {
+		fatal("Invalid configuration", "error", err)
+	}
+	if *printConfig {
+		if err := cfg.Write(os.Stdout); err != nil {
+			fatal("Failed to print configuration", "error", err)
 		}
+		os.Exit(0)
+	}
 
-		if len(feeds) == 0 {
-			continue
-		}
+	// Validated by config.Load
+	logLevel, _ := logging.ParseLevel(cfg.Log.Level)
+	slog.SetDefault(logging.New(logs, logLevel))
 
-		log.Printf("Scraping %d feeds", len(feeds))
+	if dotenvErr != nil {
+		slog.Info("No .env file found, using system environment variables")
+	}
 
-		feedValues := make([]domain.Feed, len(feeds))
-		for i, feed := range feeds {
-			feedValues[i] = *feed
-		}
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.Endpoint)
+	if err != nil {
+		fatal("Failed to set up tracing", "error", err)
+	}
 
-		if err := rssService.FetchAndStoreFeeds(ctx, feedValues); err != nil {
-			log.Printf("Error during feed scraping: %v", err)
+	return cfg, func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			slog.Error("Failed to flush spans", "error", err)
 		}
 	}
 }
+
+// fatal logs msg as an error and exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/pressly/goose/v3"
+
+	"github.com/hel1th/rssagg/internal/migrate"
+)
+
+var migrateCommands = []struct {
+	name    string
+	summary string
+}{
+	{"up", "Apply every migration not yet applied (the default)"},
+	{"down", "Roll the latest applied migration back"},
+	{"redo", "Roll the latest applied migration back and apply it again"},
+	{"status", "List the migrations and when they were applied"},
+}
+
+// migrateDB runs the migrations embedded in the binary against the database.
+func migrateDB(args []string) {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s migrate [flags] [command]\n\nCommands:\n", os.Args[0])
+		for _, cmd := range migrateCommands {
+			fmt.Fprintf(fs.Output(), "  %-8s %s\n", cmd.name, cmd.summary)
+		}
+		fmt.Fprintln(fs.Output(), "\nFlags:")
+		fs.PrintDefaults()
+	}
+	cfg, shutdown := start(fs, args, os.Stderr)
+	defer shutdown()
+
+	name := "up"
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		name = fs.Arg(0)
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+	known := false
+	for _, cmd := range migrateCommands {
+		known = known || cmd.name == name
+	}
+	if !known {
+		fmt.Fprintf(fs.Output(), "unknown migrate command %q\n\n", name)
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	conn := openDB(cfg.Database)
+	defer conn.Close()
+
+	migrator, err := migrate.New(conn)
+	if err != nil {
+		fatal("Failed to set up migrations", "error", err)
+	}
+
+	ctx := context.Background()
+	var results []*goose.MigrationResult
+	switch name {
+	case "up":
+		results, err = migrator.Up(ctx)
+	case "down":
+		var result *goose.MigrationResult
+		result, err = migrator.Down(ctx)
+		if result != nil {
+			results = append(results, result)
+		}
+	case "redo":
+		results, err = migrator.Redo(ctx)
+	case "status":
+		err = migrateStatus(ctx, migrator)
+	}
+
+	for _, result := range results {
+		fmt.Println(result)
+	}
+	if err != nil {
+		fatal("Failed to migrate database", "error", err)
+	}
+	if name != "status" && len(results) == 0 {
+		fmt.Println("no migrations to run")
+	}
+}
+
+func migrateStatus(ctx context.Context, migrator *migrate.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tMIGRATION\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.State == goose.StateApplied {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", status.Source.Version, filepath.Base(status.Source.Path), appliedAt)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/cors"
+
+	"github.com/hel1th/rssagg/api/v1/handlers"
+	"github.com/hel1th/rssagg/api/v1/middleware"
+	"github.com/hel1th/rssagg/internal/config"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/metrics"
+	"github.com/hel1th/rssagg/internal/ratelimit"
+	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/service"
+	"github.com/hel1th/rssagg/internal/stream"
+)
+
+// Rate limited route groups, configured by rate_limit.<group>
+const (
+	rateLimitIP     = "ip"
+	rateLimitSignup = "signup"
+	rateLimitLogin  = "login"
+	rateLimitUser   = "user"
+	rateLimitFetch  = "fetch"
+)
+
+// serve runs the HTTP API. Background jobs only run with -worker, so that
+// API replicas can be scaled apart from the worker.
+func serve(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	withWorker := fs.Bool("worker", false, "also run the scraper and other background jobs, as the worker command does")
+	cfg, shutdown := start(fs, args, os.Stdout)
+	defer shutdown()
+
+	a := newApp(cfg)
+	defer a.Close()
+
+	// Initialize handlers
+	userHandler := handlers.NewUserHandler(a.userService)
+	accountHandler := handlers.NewAccountHandler(a.accountService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(a.apiKeyService)
+	feedHandler := handlers.NewFeedHandler(a.feedService)
+	feedFollowHandler := handlers.NewFeedFollowHandler(a.feedFollowService)
+	postHandler := handlers.NewPostHandler(a.postService)
+	rssHandler := handlers.NewRSSHandler(a.rssService, a.feedService)
+	folderHandler := handlers.NewFolderHandler(a.folderService)
+	filterRuleHandler := handlers.NewFilterRuleHandler(a.filterRuleService)
+	tagHandler := handlers.NewTagHandler(a.tagService, a.syndicationService)
+	feedTokenHandler := handlers.NewFeedTokenHandler(a.feedTokenService)
+	outputHandler := handlers.NewOutputHandler(a.syndicationService)
+	webhookHandler := handlers.NewWebhookHandler(a.webhookService)
+	feverHandler := handlers.NewFeverHandler(a.feverService)
+	readerHandler := handlers.NewReaderHandler(a.readerService)
+	adminHandler := handlers.NewAdminHandler(a.adminService, a.scraperService)
+	auditHandler := handlers.NewAuditHandler(a.auditService)
+	healthHandler := handlers.NewHealthHandler(a.healthService(*withWorker))
+
+	// Fan new post notifications out to stream clients
+	postHub := stream.NewHub()
+	go func() {
+		if err := postHub.Listen(context.Background(), cfg.Database.URL); err != nil {
+			slog.Error("Post stream listener stopped", "error", err)
+		}
+	}()
+	streamHandler := handlers.NewStreamHandler(a.postService, postHub)
+	var oidcHandler *handlers.OIDCHandler
+	if a.oidcService != nil {
+		oidcHandler = handlers.NewOIDCHandler(a.oidcService, cfg.OIDC.PostLoginURL)
+	} else {
+		slog.Info("oidc.issuer_url is not set, single sign-on is disabled")
+	}
+	var websubHandler *handlers.WebSubHandler
+	if a.websubService != nil {
+		websubHandler = handlers.NewWebSubHandler(a.websubService, a.rssService)
+	}
+
+	// Initialize middleware
+	authMiddleware := middleware.NewAuthMiddleware(a.apiKeyService, a.feedTokenService, a.accountService)
+	rateLimiter := newRateLimiter(cfg.RateLimit, a.rateLimitRepo)
+
+	if *withWorker {
+		startWorker(a)
+	}
+
+	router := setupRouter(
+		userHandler,
+		accountHandler,
+		oidcHandler,
+		apiKeyHandler,
+		feedHandler,
+		feedFollowHandler,
+		postHandler,
+		rssHandler,
+		folderHandler,
+		filterRuleHandler,
+		tagHandler,
+		feedTokenHandler,
+		outputHandler,
+		webhookHandler,
+		streamHandler,
+		websubHandler,
+		feverHandler,
+		readerHandler,
+		adminHandler,
+		auditHandler,
+		healthHandler,
+		authMiddleware,
+		rateLimiter,
+		a.metrics,
+		cfg.Server.CORS,
+		cfg.Metrics.Token,
+	)
+
+	srv := &http.Server{
+		Handler:      router,
+		Addr:         ":" + strconv.Itoa(cfg.Server.Port),
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+
+	slog.Info("Server starting", "port", cfg.Server.Port)
+	if err := srv.ListenAndServe(); err != nil {
+		fatal("Server failed to start", "error", err)
+	}
+}
+
+func setupRouter(
+	userHandler *handlers.UserHandler,
+	accountHandler *handlers.AccountHandler,
+	oidcHandler *handlers.OIDCHandler,
+	apiKeyHandler *handlers.APIKeyHandler,
+	feedHandler *handlers.FeedHandler,
+	feedFollowHandler *handlers.FeedFollowHandler,
+	postHandler *handlers.PostHandler,
+	rssHandler *handlers.RSSHandler,
+	folderHandler *handlers.FolderHandler,
+	filterRuleHandler *handlers.FilterRuleHandler,
+	tagHandler *handlers.TagHandler,
+	feedTokenHandler *handlers.FeedTokenHandler,
+	outputHandler *handlers.OutputHandler,
+	webhookHandler *handlers.WebhookHandler,
+	streamHandler *handlers.StreamHandler,
+	websubHandler *handlers.WebSubHandler,
+	feverHandler *handlers.FeverHandler,
+	readerHandler *handlers.ReaderHandler,
+	adminHandler *handlers.AdminHandler,
+	auditHandler *handlers.AuditHandler,
+	healthHandler *handlers.HealthHandler,
+	authMiddleware *middleware.AuthMiddleware,
+	rateLimiter *middleware.RateLimiter,
+	appMetrics *metrics.Metrics,
+	corsConfig config.CORS,
+	metricsToken string,
+) http.Handler {
+	router := chi.NewRouter()
+
+	// Trace, measure and log first, so requests turned away by later
+	// middleware count too
+	router.Use(middleware.Trace)
+	router.Use(middleware.RequestID)
+	router.Use(middleware.LogRequests)
+	router.Use(middleware.Metrics(appMetrics))
+
+	router.Use(cors.Handler(cors.Options{
+		AllowedOrigins:   corsConfig.AllowedOrigins,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"*"},
+		ExposedHeaders:   []string{"Link", "X-Total-Count", "X-Request-ID", "Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy"},
+		AllowCredentials: false,
+		MaxAge:           corsConfig.MaxAge,
+	}))
+	router.Use(middleware.AuditRequest)
+	router.Use(rateLimiter.PerIP(rateLimitIP))
+	router.NotFound(handlers.NotFound)
+
+	router.With(middleware.RequireMetricsToken(metricsToken)).Handle("/metrics", appMetrics.Handler())
+
+	// API keys only reach routes their scope covers, sessions reach all.
+	// Authenticated requests are also limited per user.
+	perUser := rateLimiter.PerUser(rateLimitUser)
+	read := chi.Chain(authMiddleware.Require(domain.APIKeyScopeRead), perUser).Handler
+	write := chi.Chain(authMiddleware.Require(domain.APIKeyScopeWrite), perUser).Handler
+	admin := chi.Chain(authMiddleware.Require(domain.APIKeyScopeAdmin), perUser).Handler
+
+	// Unauthenticated routes that create accounts or check credentials,
+	// and routes that make us fetch someone else's site, have their own
+	// tighter limits
+	signupLimit := rateLimiter.PerIP(rateLimitSignup)
+	loginLimit := rateLimiter.PerIP(rateLimitLogin)
+	fetchLimit := rateLimiter.PerUser(rateLimitFetch)
+
+	v1Router := chi.NewRouter()
+
+	// Probes, unauthenticated. /healthz predates the split and is liveness
+	v1Router.Get("/livez", healthHandler.Live)
+	v1Router.Get("/healthz", healthHandler.Live)
+	v1Router.Get("/readyz", healthHandler.Ready)
+
+	v1Router.With(signupLimit).Post("/users", userHandler.CreateUser)
+	v1Router.With(read).Get("/users", adaptAuthHandler(userHandler.GetUser))
+	v1Router.With(admin).Post("/users/password", adaptAuthHandler(accountHandler.ChangePassword))
+
+	v1Router.With(loginLimit).Post("/login", accountHandler.Login)
+	v1Router.With(read).Post("/logout", adaptAuthHandler(accountHandler.Logout))
+	v1Router.With(read).Get("/session", adaptAuthHandler(accountHandler.GetSession))
+	v1Router.With(loginLimit).Post("/password_reset", accountHandler.ResetPassword)
+
+	if oidcHandler != nil {
+		v1Router.With(loginLimit).Get("/oidc/login", oidcHandler.Login)
+		v1Router.With(loginLimit).Get("/oidc/callback", oidcHandler.Callback)
+	}
+
+	v1Router.With(admin).Post("/api_keys", adaptAuthHandler(apiKeyHandler.CreateAPIKey))
+	v1Router.With(admin).Get("/api_keys", adaptAuthHandler(apiKeyHandler.GetUserAPIKeys))
+	v1Router.With(admin).Delete("/api_keys", adaptAuthHandler(apiKeyHandler.RevokeAPIKey))
+
+	v1Router.With(read).Get("/audit_log", adaptAuthHandler(auditHandler.GetUserLog))
+	v1Router.With(read).Get("/audit_log/export", adaptAuthHandler(auditHandler.ExportUserLog))
+
+	v1Router.With(write).Post("/feeds", adaptAuthHandler(feedHandler.CreateFeed))
+	v1Router.Get("/feeds", feedHandler.GetFeedDirectory)
+
+	v1Router.With(write).Post("/feed_follows", adaptAuthHandler(feedFollowHandler.FollowFeed))
+	v1Router.With(read).Get("/feed_follows", adaptAuthHandler(feedFollowHandler.GetUserFeedFollows))
+	v1Router.With(write).Patch("/feed_follows", adaptAuthHandler(feedFollowHandler.UpdateFeedFollow))
+	v1Router.With(write).Delete("/feed_follows", adaptAuthHandler(feedFollowHandler.UnfollowFeed))
+
+	v1Router.With(read).Get("/posts", adaptAuthHandler(postHandler.GetPostsForUser))
+	v1Router.With(read).Get("/stream", adaptAuthHandler(streamHandler.Stream))
+	v1Router.With(write).Post("/posts/tags", adaptAuthHandler(tagHandler.TagPost))
+	v1Router.With(write).Delete("/posts/tags", adaptAuthHandler(tagHandler.UntagPost))
+
+	v1Router.With(write, fetchLimit).Post("/rss/fetch", adaptAuthHandler(rssHandler.FetchFeed))
+
+	v1Router.With(write).Post("/folders", adaptAuthHandler(folderHandler.CreateFolder))
+	v1Router.With(read).Get("/folders", adaptAuthHandler(folderHandler.GetUserFolders))
+	v1Router.With(write).Delete("/folders", adaptAuthHandler(folderHandler.DeleteFolder))
+
+	v1Router.With(write).Post("/filter_rules", adaptAuthHandler(filterRuleHandler.CreateRule))
+	v1Router.With(read).Get("/filter_rules", adaptAuthHandler(filterRuleHandler.GetUserRules))
+	v1Router.With(write).Delete("/filter_rules", adaptAuthHandler(filterRuleHandler.DeleteRule))
+	v1Router.With(write).Post("/filter_rules/apply", adaptAuthHandler(filterRuleHandler.ApplyRule))
+	v1Router.With(read).Post("/filter_rules/dry_run", adaptAuthHandler(filterRuleHandler.DryRun))
+
+	v1Router.With(write).Post("/tags", adaptAuthHandler(tagHandler.CreateTag))
+	v1Router.With(read).Get("/tags", adaptAuthHandler(tagHandler.GetUserTags))
+	v1Router.With(write).Patch("/tags", adaptAuthHandler(tagHandler.RenameTag))
+	v1Router.With(write).Delete("/tags", adaptAuthHandler(tagHandler.DeleteTag))
+	v1Router.With(read).Get("/tags/feed", adaptAuthHandler(tagHandler.ExportTag))
+
+	v1Router.With(write).Post("/feed_tokens", adaptAuthHandler(feedTokenHandler.CreateFeedToken))
+	v1Router.With(read).Get("/feed_tokens", adaptAuthHandler(feedTokenHandler.GetUserFeedTokens))
+	v1Router.With(write).Delete("/feed_tokens", adaptAuthHandler(feedTokenHandler.DeleteFeedToken))
+
+	v1Router.With(authMiddleware.RequireFeedToken).Get("/output/timeline", adaptAuthHandler(outputHandler.TimelineFeed))
+	v1Router.With(authMiddleware.RequireFeedToken).Get("/output/folder", adaptAuthHandler(outputHandler.FolderFeed))
+	v1Router.With(authMiddleware.RequireFeedToken).Get("/output/tag", adaptAuthHandler(outputHandler.TagFeed))
+	v1Router.With(authMiddleware.RequireFeedToken).Get("/output/starred", adaptAuthHandler(outputHandler.StarredFeed))
+
+	v1Router.With(write).Post("/webhooks", adaptAuthHandler(webhookHandler.CreateWebhook))
+	v1Router.With(read).Get("/webhooks", adaptAuthHandler(webhookHandler.GetUserWebhooks))
+	v1Router.With(write).Delete("/webhooks", adaptAuthHandler(webhookHandler.DeleteWebhook))
+	v1Router.With(read).Get("/webhooks/deliveries", adaptAuthHandler(webhookHandler.GetDeliveries))
+	v1Router.With(write).Post("/webhooks/deliveries/redeliver", adaptAuthHandler(webhookHandler.Redeliver))
+
+	if websubHandler != nil {
+		v1Router.Get("/websub/callback", websubHandler.VerifyIntent)
+		v1Router.Post("/websub/callback", websubHandler.ReceiveContent)
+	}
+
+	// Admin routes need both an admin scoped credential and the admin role
+	adminRouter := chi.NewRouter()
+	adminRouter.Use(admin, middleware.RequireRole(domain.UserRoleAdmin))
+	adminRouter.Get("/users", adaptAuthHandler(adminHandler.SearchUsers))
+	adminRouter.Delete("/users", adaptAuthHandler(adminHandler.DeleteUser))
+	adminRouter.Post("/users/disable", adaptAuthHandler(adminHandler.DisableUser))
+	adminRouter.Post("/users/enable", adaptAuthHandler(adminHandler.EnableUser))
+	adminRouter.Post("/password_resets", adaptAuthHandler(adminHandler.IssuePasswordReset))
+	adminRouter.Post("/feeds/refresh", adaptAuthHandler(adminHandler.RefreshFeed))
+	adminRouter.Post("/feeds/disable", adaptAuthHandler(adminHandler.DisableFeed))
+	adminRouter.Post("/feeds/enable", adaptAuthHandler(adminHandler.EnableFeed))
+	adminRouter.Patch("/feeds/owner", adaptAuthHandler(adminHandler.ReassignFeed))
+	adminRouter.Get("/scraper", adaptAuthHandler(adminHandler.GetScraperStatus))
+	adminRouter.Delete("/posts", adaptAuthHandler(adminHandler.PurgePosts))
+	adminRouter.Get("/audit_log", adaptAuthHandler(auditHandler.GetLog))
+	adminRouter.Get("/audit_log/export", adaptAuthHandler(auditHandler.ExportLog))
+	v1Router.Mount("/admin", adminRouter)
+
+	router.Mount("/v1", v1Router)
+
+	// Fever clients authenticate with their own api_key, not our header
+	router.HandleFunc("/fever", feverHandler.Handle)
+	router.HandleFunc("/fever/", feverHandler.Handle)
+
+	// Google Reader API clients log in with ClientLogin and then send its token
+	router.With(loginLimit).Post("/accounts/ClientLogin", readerHandler.ClientLogin)
+	router.With(loginLimit).Get("/accounts/ClientLogin", readerHandler.ClientLogin)
+	readerRead := chi.Chain(authMiddleware.RequireReaderToken(domain.APIKeyScopeRead), perUser).Handler
+	readerWrite := chi.Chain(authMiddleware.RequireReaderToken(domain.APIKeyScopeWrite), perUser).Handler
+	readerRouter := chi.NewRouter()
+	readerRouter.With(readerRead).Get("/token", adaptAuthHandler(readerHandler.Token))
+	readerRouter.With(readerRead).Get("/user-info", adaptAuthHandler(readerHandler.UserInfo))
+	readerRouter.With(readerRead).Get("/subscription/list", adaptAuthHandler(readerHandler.SubscriptionList))
+	readerRouter.With(readerWrite).Post("/subscription/edit", adaptAuthHandler(readerHandler.SubscriptionEdit))
+	readerRouter.With(readerWrite, fetchLimit).Post("/subscription/quickadd", adaptAuthHandler(readerHandler.QuickAdd))
+	readerRouter.With(readerRead).Get("/tag/list", adaptAuthHandler(readerHandler.TagList))
+	readerRouter.With(readerRead).Get("/stream/contents", adaptAuthHandler(readerHandler.StreamContents))
+	readerRouter.With(readerRead).Get("/stream/contents/*", adaptAuthHandler(readerHandler.StreamContents))
+	readerRouter.With(readerRead).Get("/stream/items/ids", adaptAuthHandler(readerHandler.StreamItemIDs))
+	readerRouter.With(readerRead).Post("/stream/items/contents", adaptAuthHandler(readerHandler.StreamItemContents))
+	readerRouter.With(readerWrite).Post("/edit-tag", adaptAuthHandler(readerHandler.EditTag))
+	router.Mount("/reader/api/0", readerRouter)
+
+	return router
+}
+
+// newRateLimiter configures rate limits by route group. Buckets are kept
+// in memory, per instance, unless the store is postgres, where the worker
+// deletes idle ones.
+func newRateLimiter(rateLimit config.RateLimit, rateLimitRepo repository.RateLimitRepository) *middleware.RateLimiter {
+	limits, _ := rateLimits(rateLimit)
+
+	var store ratelimit.Store
+	switch rateLimit.Store {
+	case config.RateLimitStorePostgres:
+		store = service.NewRateLimitService(rateLimitRepo)
+	default:
+		store = ratelimit.NewMemoryStore()
+	}
+
+	return middleware.NewRateLimiter(store, limits)
+}
+
+// rateLimits parses the limit of each route group. It also returns the
+// longest period, after which an untouched bucket is full again.
+func rateLimits(rateLimit config.RateLimit) (map[string]ratelimit.Limit, time.Duration) {
+	groups := map[string]string{
+		rateLimitIP:     rateLimit.IP,
+		rateLimitSignup: rateLimit.Signup,
+		rateLimitLogin:  rateLimit.Login,
+		rateLimitUser:   rateLimit.User,
+		rateLimitFetch:  rateLimit.Fetch,
+	}
+
+	limits := make(map[string]ratelimit.Limit, len(groups))
+	var longest time.Duration
+	for group, value := range groups {
+		limit, err := ratelimit.ParseLimit(value)
+		if err != nil {
+			fatal("Invalid rate_limit."+group, "error", err)
+		}
+		limits[group] = limit
+		longest = max(longest, limit.Period)
+	}
+	return limits, longest
+}
+
+func adaptAuthHandler(handler func(http.ResponseWriter, *http.Request, *domain.User)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			middleware.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		handler(w, r, user)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/hel1th/rssagg/api/v1/handlers"
+	"github.com/hel1th/rssagg/api/v1/middleware"
+	"github.com/hel1th/rssagg/internal/config"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/logging"
+	"github.com/hel1th/rssagg/internal/metrics"
+	"github.com/hel1th/rssagg/internal/service"
+)
+
+// worker runs the background jobs without the API, serving only its
+// health checks and metrics. Feeds aren't claimed before they're fetched, so
+// only one worker should run at a time.
+func worker(args []string) {
+	fs := flag.NewFlagSet("worker", flag.ExitOnError)
+	cfg, shutdown := start(fs, args, os.Stdout)
+	defer shutdown()
+
+	a := newApp(cfg)
+	defer a.Close()
+
+	startWorker(a)
+
+	if cfg.Worker.Port == 0 {
+		slog.Info("worker.port is 0, not serving health or metrics")
+		select {}
+	}
+
+	healthHandler := handlers.NewHealthHandler(a.healthService(true))
+
+	router := chi.NewRouter()
+	router.NotFound(handlers.NotFound)
+	router.Get("/livez", healthHandler.Live)
+	router.Get("/healthz", healthHandler.Live)
+	router.Get("/readyz", healthHandler.Ready)
+	router.With(middleware.RequireMetricsToken(cfg.Metrics.Token)).Handle("/metrics", a.metrics.Handler())
+
+	srv := &http.Server{
+		Handler:      router,
+		Addr:         ":" + strconv.Itoa(cfg.Worker.Port),
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+
+	slog.Info("Worker serving health and metrics", "port", cfg.Worker.Port)
+	if err := srv.ListenAndServe(); err != nil {
+		fatal("Worker server failed to start", "error", err)
+	}
+}
+
+// startWorker starts the scraper and the other background jobs.
+func startWorker(a *app) {
+	cfg := a.cfg
+
+	// Start background RSS scraper
+	go startScraper(a.feedService, a.rssService, a.scraperService, a.metrics, cfg.Scraper.Concurrency, cfg.Scraper.Interval)
+
+	// Start background webhook delivery worker
+	go startWebhookWorker(a.webhookService, cfg.Webhooks.BatchSize, cfg.Webhooks.Interval, cfg.Webhooks.DigestInterval)
+
+	// Renew WebSub leases before they expire
+	if a.websubService != nil {
+		go startWebSubRenewer(a.websubService, cfg.WebSub.RenewBatchSize, cfg.WebSub.RenewInterval)
+	} else {
+		slog.Info("websub.callback_url is not set, WebSub push is disabled")
+	}
+
+	// Buckets kept in Postgres outlive the API instances that filled them
+	if cfg.RateLimit.Store == config.RateLimitStorePostgres {
+		_, longest := rateLimits(cfg.RateLimit)
+		go startRateLimitSweeper(service.NewRateLimitService(a.rateLimitRepo), longest, cfg.RateLimit.SweepInterval)
+	}
+}
+
+func startScraper(
+	feedService service.FeedService,
+	rssService service.RSSService,
+	scraperService service.ScraperService,
+	appMetrics *metrics.Metrics,
+	concurrency int,
+	interval time.Duration,
+) {
+	slog.Info("Starting RSS scraper", "interval", interval, "concurrency", concurrency)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		// Everything logged for this run, down to its queries, carries
+		// the batch ID
+		ctx := logging.With(context.Background(), "batch_id", uuid.NewString())
+		feeds, err := feedService.GetNextFeedsToFetch(ctx, concurrency)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error fetching feeds to scrape", "error", err)
+			continue
+		}
+
+		// Beat even when there is nothing to fetch, so admins can tell an
+		// idle scraper from a dead one
+		if err := scraperService.RunStarted(ctx, len(feeds)); err != nil {
+			slog.ErrorContext(ctx, "Error recording scraper heartbeat", "error", err)
+		}
+		appMetrics.SetBatchFeeds(len(feeds))
+		if lag, err := scraperService.QueueLag(ctx); err != nil {
+			slog.ErrorContext(ctx, "Error measuring scraper queue lag", "error", err)
+		} else {
+			appMetrics.SetQueueLag(lag)
+		}
+
+		if len(feeds) > 0 {
+			slog.InfoContext(ctx, "Scraping feeds", "feeds", len(feeds))
+
+			feedValues := make([]domain.Feed, len(feeds))
+			for i, feed := range feeds {
+				feedValues[i] = *feed
+			}
+
+			if err := rssService.FetchAndStoreFeeds(ctx, feedValues); err != nil {
+				slog.ErrorContext(ctx, "Error during feed scraping", "error", err)
+			}
+		}
+
+		if err := scraperService.RunFinished(ctx); err != nil {
+			slog.ErrorContext(ctx, "Error recording scraper heartbeat", "error", err)
+		}
+	}
+}
+
+func startWebhookWorker(webhookService service.WebhookService, batchSize int, interval, digestInterval time.Duration) {
+	slog.Info("Starting webhook worker", "interval", interval, "batch_size", batchSize, "digest_interval", digestInterval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx := context.Background()
+		// Digests queued here go out with the deliveries below. A digest
+		// that fails to queue stays due, so one batch a tick is enough.
+		if _, err := webhookService.QueueDueDigests(ctx, digestInterval, batchSize); err != nil {
+			slog.Error("Error queueing webhook digests", "error", err)
+		}
+
+		// Keep draining while full batches come back so a backlog clears
+		// without waiting a tick per batch.
+		for {
+			sent, err := webhookService.DeliverDue(ctx, batchSize)
+			if err != nil {
+				slog.Error("Error delivering webhooks", "error", err)
+				break
+			}
+			if sent < batchSize {
+				break
+			}
+		}
+	}
+}
+
+func startWebSubRenewer(websubService service.WebSubService, batchSize int, interval time.Duration) {
+	slog.Info("Starting WebSub renewer", "interval", interval, "batch_size", batchSize)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		renewed, err := websubService.RenewSubscriptions(context.Background(), batchSize)
+		if err != nil {
+			slog.Error("Error renewing WebSub subscriptions", "error", err)
+			continue
+		}
+		if renewed > 0 {
+			slog.Info("Sent WebSub subscription requests", "requests", renewed)
+		}
+	}
+}
+
+func startRateLimitSweeper(rateLimitService service.RateLimitService, idle, interval time.Duration) {
+	slog.Info("Starting rate limit sweeper", "interval", interval, "idle", idle)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := rateLimitService.DeleteIdle(context.Background(), idle); err != nil {
+			slog.Error("Error deleting idle rate limit buckets", "error", err)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Command fakehub is a minimal WebSub hub and publisher for trying out push
+// ingestion locally. It serves a feed at /feed.xml that advertises the hub
+// at /hub, accepts and verifies subscriptions, and pushes the signed feed to
+// every subscriber when an item is published:
+//
+//	go run ./cmd/fakehub -addr :8090
+//	# add http://localhost:8090/feed.xml as a feed, follow it, and run the
+//	# API with WEBSUB_CALLBACK_URL=http://localhost:8080
+//	curl -X POST 'http://localhost:8090/publish?title=Hello'
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type subscription struct {
+	Callback string
+	Secret   string
+	Lease    int
+}
+
+type item struct {
+	Title   string
+	Link    string
+	PubDate time.Time
+}
+
+type hub struct {
+	baseURL string
+	client  http.Client
+
+	mu            sync.Mutex
+	subscriptions map[string]subscription
+	items         []item
+}
+
+func main() {
+	addr := flag.String("addr", ":8090", "address to listen on")
+	baseURL := flag.String("base", "http://localhost:8090", "public base URL of this hub")
+	flag.Parse()
+
+	h := &hub{
+		baseURL:       strings.TrimRight(*baseURL, "/"),
+		client:        http.Client{Timeout: 10 * time.Second},
+		subscriptions: make(map[string]subscription),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/feed.xml", h.serveFeed)
+	mux.HandleFunc("/hub", h.subscribe)
+	mux.HandleFunc("/publish", h.publish)
+
+	log.Printf("Fake hub listening on %s, feed at %s/feed.xml", *addr, h.baseURL)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+func (h *hub) topicURL() string {
+	return h.baseURL + "/feed.xml"
+}
+
+func (h *hub) serveFeed(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/rss+xml")
+	w.Write(h.renderFeed())
+}
+
+func (h *hub) renderFeed() []byte {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom"><channel>`)
+	b.WriteString(`<title>Fake hub feed</title>`)
+	fmt.Fprintf(&b, `<link>%s</link>`, h.baseURL)
+	fmt.Fprintf(&b, `<atom:link rel="hub" href="%s/hub"/>`, h.baseURL)
+	fmt.Fprintf(&b, `<atom:link rel="self" href="%s"/>`, h.topicURL())
+	for i := len(h.items) - 1; i >= 0; i-- {
+		it := h.items[i]
+		b.WriteString(`<item><title>`)
+		xml.EscapeText(&b, []byte(it.Title))
+		fmt.Fprintf(&b, `</title><link>%s</link><pubDate>%s</pubDate></item>`, it.Link, it.PubDate.Format(time.RFC1123Z))
+	}
+	b.WriteString(`</channel></rss>`)
+	return []byte(b.String())
+}
+
+func (h *hub) subscribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	mode := r.PostForm.Get("hub.mode")
+	topic := r.PostForm.Get("hub.topic")
+	callback := r.PostForm.Get("hub.callback")
+	if topic != h.topicURL() {
+		http.Error(w, "unknown topic", http.StatusBadRequest)
+		return
+	}
+	if mode != "subscribe" && mode != "unsubscribe" {
+		http.Error(w, "unsupported mode", http.StatusBadRequest)
+		return
+	}
+
+	sub := subscription{
+		Callback: callback,
+		Secret:   r.PostForm.Get("hub.secret"),
+		Lease:    3600,
+	}
+	w.WriteHeader(http.StatusAccepted)
+
+	go h.verify(mode, topic, sub)
+}
+
+func (h *hub) verify(mode, topic string, sub subscription) {
+	challenge := randomHex(16)
+	callback, err := url.Parse(sub.Callback)
+	if err != nil {
+		log.Printf("Invalid callback %q: %v", sub.Callback, err)
+		return
+	}
+	query := callback.Query()
+	query.Set("hub.mode", mode)
+	query.Set("hub.topic", topic)
+	query.Set("hub.challenge", challenge)
+	query.Set("hub.lease_seconds", fmt.Sprint(sub.Lease))
+	callback.RawQuery = query.Encode()
+
+	resp, err := h.client.Get(callback.String())
+	if err != nil {
+		log.Printf("Verification of %s failed: %v", sub.Callback, err)
+		return
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode/100 != 2 || string(body) != challenge {
+		log.Printf("Subscriber %s refused %s (%s)", sub.Callback, mode, resp.Status)
+		return
+	}
+
+	h.mu.Lock()
+	if mode == "subscribe" {
+		h.subscriptions[sub.Callback] = sub
+	} else {
+		delete(h.subscriptions, sub.Callback)
+	}
+	h.mu.Unlock()
+	log.Printf("Verified %s for %s", mode, sub.Callback)
+}
+
+func (h *hub) publish(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	title := r.URL.Query().Get("title")
+	if title == "" {
+		title = "Post " + time.Now().Format(time.RFC3339)
+	}
+
+	h.mu.Lock()
+	h.items = append(h.items, item{
+		Title:   title,
+		Link:    fmt.Sprintf("%s/posts/%s", h.baseURL, randomHex(8)),
+		PubDate: time.Now().UTC(),
+	})
+	subs := make([]subscription, 0, len(h.subscriptions))
+	for _, sub := range h.subscriptions {
+		subs = append(subs, sub)
+	}
+	h.mu.Unlock()
+
+	feed := h.renderFeed()
+	for _, sub := range subs {
+		h.push(sub, feed)
+	}
+
+	fmt.Fprintf(w, "published %q to %d subscribers\n", title, len(subs))
+}
+
+func (h *hub) push(sub subscription, feed []byte) {
+	req, err := http.NewRequest(http.MethodPost, sub.Callback, strings.NewReader(string(feed)))
+	if err != nil {
+		log.Printf("Push to %s failed: %v", sub.Callback, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/rss+xml")
+	req.Header.Set("Link", fmt.Sprintf(`<%s/hub>; rel="hub", <%s>; rel="self"`, h.baseURL, h.topicURL()))
+	if sub.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(sub.Secret))
+		mac.Write(feed)
+		req.Header.Set("X-Hub-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		log.Printf("Push to %s failed: %v", sub.Callback, err)
+		return
+	}
+	resp.Body.Close()
+	log.Printf("Pushed to %s: %s", sub.Callback, resp.Status)
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+type contextKey struct{}
+
+// Request is where a request came from, as recorded in the audit log.
+type Request struct {
+	IP        string
+	UserAgent string
+}
+
+// FromHTTP returns where r came from.
+func FromHTTP(r *http.Request) Request {
+	return Request{
+		IP:        ClientIP(r),
+		UserAgent: r.UserAgent(),
+	}
+}
+
+// WithRequest returns a copy of ctx that carries req, for services to
+// record changes they make with.
+func WithRequest(ctx context.Context, req Request) context.Context {
+	return context.WithValue(ctx, contextKey{}, req)
+}
+
+// RequestFromContext returns the request ctx carries. Work that isn't done
+// for a request, like the scraper's, has none.
+func RequestFromContext(ctx context.Context) Request {
+	req, _ := ctx.Value(contextKey{}).(Request)
+	return req
+}
+
+// ClientIP returns the address r came from, without its port.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -25,3 +25,20 @@ func GetAPIKey(headers http.Header) (string, error) {
 
 	return parts[1], nil
 }
+
+// GetGoogleLoginToken extracts the Google Reader API auth token from the
+// Authorization header
+// Expected format: "GoogleLogin auth=<token>"
+func GetGoogleLoginToken(headers http.Header) (string, error) {
+	authHeader := headers.Get("Authorization")
+	if authHeader == "" {
+		return "", errors.New("no authorization header included")
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "GoogleLogin" || !strings.HasPrefix(parts[1], "auth=") {
+		return "", errors.New("malformed authorization header")
+	}
+
+	return strings.TrimPrefix(parts[1], "auth="), nil
+}
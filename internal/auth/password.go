@@ -0,0 +1,27 @@
+package auth
+
+import "golang.org/x/crypto/bcrypt"
+
+// HashPassword returns the bcrypt hash a password is stored under.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches hash. An empty hash never
+// matches, but still takes as long to check as a real one so callers don't
+// reveal which users exist.
+func CheckPassword(hash, password string) bool {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// dummyPasswordHash is the hash of a random password, compared against when
+// there is no real hash to check.
+var dummyPasswordHash = []byte("$2a$10$X/ux7mnsMnH1NgPUN3REw.dsydRdW6UEPtQkyxFWOzltC37wjSUy2")
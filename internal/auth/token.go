@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// GenerateToken returns a random 256-bit secret encoded as hex.
+func GenerateToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// HashToken returns the hex-encoded SHA-256 digest under which a secret is stored.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ClientHash returns md5("<name>:<key>") hex-encoded, which Fever and Google
+// Reader API clients authenticate with instead of the key itself.
+func ClientHash(name, key string) string {
+	sum := md5.Sum([]byte(name + ":" + key))
+	return hex.EncodeToString(sum[:])
+}
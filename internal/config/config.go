@@ -0,0 +1,294 @@
+// Package config holds the server's settings, loaded by Load from built-in
+// defaults, a YAML or TOML file, environment variables and command line
+// flags, each overriding the ones before.
+//
+// Every setting has a key in the file, such as scraper.concurrency, which
+// is also the name of its flag (-scraper.concurrency), and an environment
+// variable (SCRAPER_CONCURRENCY). Settings marked secret are redacted when
+// the config is printed.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/logging"
+	"github.com/hel1th/rssagg/internal/oidc"
+	"github.com/hel1th/rssagg/internal/ratelimit"
+	"github.com/hel1th/rssagg/internal/rss"
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+// Rate limit stores
+const (
+	RateLimitStoreMemory   = "memory"
+	RateLimitStorePostgres = "postgres"
+)
+
+// Off in a list setting given as a string, such as SCRAPER_HOST_GROUPS=off,
+// empties the list rather than falling back to its default.
+const Off = "off"
+
+type Config struct {
+	Server    Server    `yaml:"server"`
+	Database  Database  `yaml:"database"`
+	Log       Log       `yaml:"log"`
+	Worker    Worker    `yaml:"worker"`
+	Scraper   Scraper   `yaml:"scraper"`
+	Webhooks  Webhooks  `yaml:"webhooks"`
+	WebSub    WebSub    `yaml:"websub"`
+	OIDC      OIDC      `yaml:"oidc"`
+	RateLimit RateLimit `yaml:"rate_limit"`
+	Metrics   Metrics   `yaml:"metrics"`
+	Health    Health    `yaml:"health"`
+	Tracing   Tracing   `yaml:"tracing"`
+}
+
+type Server struct {
+	Port         int           `yaml:"port" env:"PORT" help:"port to listen on"`
+	ReadTimeout  time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT" help:"time to read a request, body included"`
+	WriteTimeout time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT" help:"time to write a response; streams and exports are exempt"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" help:"time to keep idle connections open"`
+	CORS         CORS          `yaml:"cors"`
+}
+
+type CORS struct {
+	AllowedOrigins []string `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS" help:"origins browsers may call the API from, with * wildcards"`
+	MaxAge         int      `yaml:"max_age" env:"CORS_MAX_AGE" help:"seconds browsers may cache preflight responses"`
+}
+
+type Database struct {
+	URL             string        `yaml:"url" env:"DB_URL" secret:"url" help:"Postgres connection URL"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" help:"open connections at most, 0 for no limit"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" help:"idle connections kept open"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" help:"time before connections are closed, 0 for no limit"`
+	AutoMigrate     bool          `yaml:"auto_migrate" env:"DB_AUTO_MIGRATE" help:"apply pending migrations on startup"`
+}
+
+type Log struct {
+	Level string `yaml:"level" env:"LOG_LEVEL" help:"debug, info, warn or error"`
+}
+
+// Worker is the process running the scraper and other background jobs.
+type Worker struct {
+	Port int `yaml:"port" env:"WORKER_PORT" help:"port the worker serves its health checks and /metrics on, 0 for none"`
+}
+
+type Scraper struct {
+	Interval        time.Duration `yaml:"interval" env:"SCRAPER_INTERVAL" help:"time between scraper runs"`
+	Concurrency     int           `yaml:"concurrency" env:"SCRAPER_CONCURRENCY" help:"requests in flight across all hosts, and feeds per scraper run"`
+	HostConcurrency int           `yaml:"host_concurrency" env:"SCRAPER_HOST_CONCURRENCY" help:"requests in flight to one host"`
+	HostDelay       time.Duration `yaml:"host_delay" env:"SCRAPER_HOST_DELAY" help:"least time between starting two requests to one host"`
+	HostGroups      []string      `yaml:"host_groups" env:"SCRAPER_HOST_GROUPS" help:"domains whose subdomains count as one host, or off"`
+	FetchTimeout    time.Duration `yaml:"fetch_timeout" env:"SCRAPER_FETCH_TIMEOUT" help:"time to fetch a feed or favicon, waiting for the host's turn aside"`
+	UserAgent       string        `yaml:"user_agent" env:"SCRAPER_USER_AGENT" help:"User-Agent sent with every fetch, {subscribers} replaced by the follower count"`
+}
+
+type Webhooks struct {
+	BatchSize           int           `yaml:"batch_size" env:"WEBHOOK_BATCH_SIZE" help:"deliveries sent at a time"`
+	Interval            time.Duration `yaml:"interval" env:"WEBHOOK_INTERVAL" help:"time between looking for due deliveries"`
+	DigestInterval      time.Duration `yaml:"digest_interval" env:"WEBHOOK_DIGEST_INTERVAL" help:"time a post waits for a digest to go out, for follows in digest mode"`
+	AllowPrivateTargets bool          `yaml:"allow_private_targets" env:"WEBHOOK_ALLOW_PRIVATE_TARGETS" help:"allow webhooks to loopback, private and link-local addresses"`
+}
+
+type WebSub struct {
+	CallbackURL    string        `yaml:"callback_url" env:"WEBSUB_CALLBACK_URL" help:"public base URL hubs can reach the API at; WebSub is off if empty"`
+	RenewBatchSize int           `yaml:"renew_batch_size" env:"WEBSUB_RENEW_BATCH_SIZE" help:"subscriptions renewed at a time"`
+	RenewInterval  time.Duration `yaml:"renew_interval" env:"WEBSUB_RENEW_INTERVAL" help:"time between looking for leases to renew"`
+}
+
+type OIDC struct {
+	IssuerURL    string   `yaml:"issuer_url" env:"OIDC_ISSUER_URL" help:"OpenID Connect issuer; single sign-on is off if empty"`
+	ClientID     string   `yaml:"client_id" env:"OIDC_CLIENT_ID" help:"client ID registered with the issuer"`
+	ClientSecret string   `yaml:"client_secret" env:"OIDC_CLIENT_SECRET" secret:"true" help:"client secret registered with the issuer"`
+	RedirectURL  string   `yaml:"redirect_url" env:"OIDC_REDIRECT_URL" help:"URL of /v1/oidc/callback as the issuer redirects to it"`
+	PostLoginURL string   `yaml:"post_login_url" env:"OIDC_POST_LOGIN_URL" help:"where browsers go once logged in"`
+	Scopes       []string `yaml:"scopes" env:"OIDC_SCOPES" help:"scopes asked for"`
+	RoleClaim    string   `yaml:"role_claim" env:"OIDC_ROLE_CLAIM" help:"ID token claim roles are mapped from"`
+	RoleMapping  string   `yaml:"role_mapping" env:"OIDC_ROLE_MAPPING" help:"claim value=role pairs, comma separated; roles are left alone if empty"`
+	AllowSignup  bool     `yaml:"allow_signup" env:"OIDC_ALLOW_SIGNUP" help:"create users on their first login"`
+}
+
+// RateLimit holds each route group's limit as <requests>/<period>, or off.
+type RateLimit struct {
+	Store         string        `yaml:"store" env:"RATE_LIMIT_STORE" help:"memory keeps buckets per instance, postgres shares them"`
+	SweepInterval time.Duration `yaml:"sweep_interval" env:"RATE_LIMIT_SWEEP_INTERVAL" help:"time between deleting idle buckets from Postgres"`
+	IP            string        `yaml:"ip" env:"RATE_LIMIT_IP" help:"limit per client IP on every route"`
+	Signup        string        `yaml:"signup" env:"RATE_LIMIT_SIGNUP" help:"limit per client IP on signup"`
+	Login         string        `yaml:"login" env:"RATE_LIMIT_LOGIN" help:"limit per client IP on login routes"`
+	User          string        `yaml:"user" env:"RATE_LIMIT_USER" help:"limit per user on authenticated routes"`
+	Fetch         string        `yaml:"fetch" env:"RATE_LIMIT_FETCH" help:"limit per user on routes fetching feeds"`
+}
+
+type Metrics struct {
+	Token string `yaml:"token" env:"METRICS_TOKEN" secret:"true" help:"bearer token needed to scrape /metrics; public if empty"`
+}
+
+// Health is how far the scraper may fall behind before readiness checks
+// report it.
+type Health struct {
+	Timeout         time.Duration `yaml:"timeout" env:"HEALTH_TIMEOUT" help:"time the readiness checks may take"`
+	MaxHeartbeatAge time.Duration `yaml:"max_heartbeat_age" env:"HEALTH_MAX_HEARTBEAT_AGE" help:"age of the latest scraper heartbeat before the scraper counts as down"`
+	MaxQueueLag     time.Duration `yaml:"max_queue_lag" env:"HEALTH_MAX_QUEUE_LAG" help:"time the next feed due may have waited to be fetched, 0 for no limit"`
+}
+
+type Tracing struct {
+	Exporter string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER" help:"otlp or none"`
+	Endpoint string `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT" help:"OTLP/HTTP collector URL"`
+}
+
+// Default returns the settings used where nothing overrides them.
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			Port:         8080,
+			ReadTimeout:  15 * time.Second,
+			WriteTimeout: 15 * time.Second,
+			IdleTimeout:  60 * time.Second,
+			CORS: CORS{
+				AllowedOrigins: []string{"https://*", "http://*"},
+				MaxAge:         300,
+			},
+		},
+		Database: Database{
+			MaxIdleConns: 2,
+		},
+		Log: Log{
+			Level: "info",
+		},
+		Worker: Worker{
+			Port: 8081,
+		},
+		Scraper: Scraper{
+			Interval:        time.Minute,
+			Concurrency:     rss.DefaultConcurrency,
+			HostConcurrency: rss.DefaultHostConcurrency,
+			HostDelay:       rss.DefaultHostDelay,
+			HostGroups:      append([]string(nil), rss.DefaultHostGroups...),
+			FetchTimeout:    rss.DefaultTimeout,
+			UserAgent:       rss.DefaultUserAgent,
+		},
+		Webhooks: Webhooks{
+			BatchSize:      20,
+			Interval:       5 * time.Second,
+			DigestInterval: 24 * time.Hour,
+		},
+		WebSub: WebSub{
+			RenewBatchSize: 50,
+			RenewInterval:  10 * time.Minute,
+		},
+		OIDC: OIDC{
+			Scopes:      append([]string(nil), oidc.DefaultScopes...),
+			RoleClaim:   domain.DefaultOIDCRoleClaim,
+			AllowSignup: true,
+		},
+		RateLimit: RateLimit{
+			Store:         RateLimitStoreMemory,
+			SweepInterval: time.Minute,
+			IP:            "600/m",
+			Signup:        "5/h",
+			Login:         "10/m",
+			User:          "600/m",
+			Fetch:         "10/m",
+		},
+		Health: Health{
+			Timeout:         2 * time.Second,
+			MaxHeartbeatAge: 10 * time.Minute,
+			MaxQueueLag:     time.Hour,
+		},
+		Tracing: Tracing{
+			Exporter: tracing.ExporterNone,
+		},
+	}
+}
+
+// Validate reports every setting that is missing or out of range.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port < 1<<16, "server.port must be between 1 and 65535")
+	check(c.Server.ReadTimeout >= 0, "server.read_timeout must not be negative")
+	check(c.Server.WriteTimeout >= 0, "server.write_timeout must not be negative")
+	check(c.Server.IdleTimeout >= 0, "server.idle_timeout must not be negative")
+	// The CORS middleware allows every origin when given none
+	check(len(c.Server.CORS.AllowedOrigins) > 0, "server.cors.allowed_origins must not be empty, use * to allow any origin")
+	check(c.Server.CORS.MaxAge >= 0, "server.cors.max_age must not be negative")
+
+	check(c.Database.URL != "", "database.url is required")
+	check(c.Database.MaxOpenConns >= 0, "database.max_open_conns must not be negative")
+	check(c.Database.MaxIdleConns >= 0, "database.max_idle_conns must not be negative")
+	check(c.Database.ConnMaxLifetime >= 0, "database.conn_max_lifetime must not be negative")
+
+	_, err := logging.ParseLevel(c.Log.Level)
+	check(err == nil, "log.level must be debug, info, warn or error")
+
+	check(c.Worker.Port >= 0 && c.Worker.Port < 1<<16, "worker.port must be between 0 and 65535")
+
+	check(c.Scraper.Interval > 0, "scraper.interval must be positive")
+	check(c.Scraper.Concurrency > 0, "scraper.concurrency must be positive")
+	check(c.Scraper.HostConcurrency > 0, "scraper.host_concurrency must be positive")
+	check(c.Scraper.HostDelay >= 0, "scraper.host_delay must not be negative")
+	check(c.Scraper.FetchTimeout > 0, "scraper.fetch_timeout must be positive")
+
+	check(c.Webhooks.BatchSize > 0, "webhooks.batch_size must be positive")
+	check(c.Webhooks.Interval > 0, "webhooks.interval must be positive")
+	check(c.Webhooks.DigestInterval > 0, "webhooks.digest_interval must be positive")
+
+	if c.WebSub.CallbackURL != "" {
+		check(isAbsoluteURL(c.WebSub.CallbackURL), "websub.callback_url must be an absolute http or https URL")
+	}
+	check(c.WebSub.RenewBatchSize > 0, "websub.renew_batch_size must be positive")
+	check(c.WebSub.RenewInterval > 0, "websub.renew_interval must be positive")
+
+	if c.OIDC.IssuerURL != "" {
+		check(isAbsoluteURL(c.OIDC.IssuerURL), "oidc.issuer_url must be an absolute http or https URL")
+		check(c.OIDC.RedirectURL != "", "oidc.redirect_url is required with oidc.issuer_url")
+		if _, err := domain.ParseOIDCRoleMapping(c.OIDC.RoleMapping); err != nil {
+			errs = append(errs, fmt.Errorf("oidc.role_mapping: %w", err))
+		}
+	}
+
+	for _, limit := range []struct{ key, value string }{
+		{"ip", c.RateLimit.IP},
+		{"signup", c.RateLimit.Signup},
+		{"login", c.RateLimit.Login},
+		{"user", c.RateLimit.User},
+		{"fetch", c.RateLimit.Fetch},
+	} {
+		if _, err := ratelimit.ParseLimit(limit.value); err != nil {
+			errs = append(errs, fmt.Errorf("rate_limit.%s: %w", limit.key, err))
+		}
+	}
+	check(c.RateLimit.Store == RateLimitStoreMemory || c.RateLimit.Store == RateLimitStorePostgres,
+		"rate_limit.store must be %s or %s", RateLimitStoreMemory, RateLimitStorePostgres)
+	check(c.RateLimit.SweepInterval > 0, "rate_limit.sweep_interval must be positive")
+
+	check(c.Health.Timeout > 0, "health.timeout must be positive")
+	check(c.Health.MaxHeartbeatAge > 0, "health.max_heartbeat_age must be positive")
+	check(c.Health.MaxQueueLag >= 0, "health.max_queue_lag must not be negative")
+
+	switch c.Tracing.Exporter {
+	case "", tracing.ExporterNone:
+	case tracing.ExporterOTLP:
+		if c.Tracing.Endpoint != "" {
+			check(isAbsoluteURL(c.Tracing.Endpoint), "tracing.endpoint must be an absolute http or https URL")
+		}
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter must be %s or %s", tracing.ExporterOTLP, tracing.ExporterNone))
+	}
+
+	return errors.Join(errs...)
+}
+
+func isAbsoluteURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,256 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the config file when the -config flag doesn't.
+const FileEnv = "CONFIG_FILE"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Load registers a flag for every setting on fs, parses args with it and
+// returns the defaults overridden by the config file given by -config or
+// CONFIG_FILE, then by environment variables, then by the flags set.
+// Empty environment variables count as unset. The config is validated.
+func Load(fs *flag.FlagSet, args []string) (*Config, error) {
+	config := Default()
+
+	file := fs.String("config", os.Getenv(FileEnv), "YAML or TOML config `file`, or "+FileEnv)
+	flags := map[string]string{}
+	for _, s := range settings(config) {
+		fs.Var(&flagValue{setting: s, flags: flags, value: s.String()}, s.key, s.help+", or "+s.env)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *file != "" {
+		if err := loadFile(config, *file); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range settings(config) {
+		if value := os.Getenv(s.env); s.env != "" && value != "" {
+			if err := s.Set(value); err != nil {
+				return nil, fmt.Errorf("%s: %w", s.env, err)
+			}
+		}
+	}
+
+	for _, s := range settings(config) {
+		if value, ok := flags[s.key]; ok {
+			// Checked when parsed
+			_ = s.Set(value)
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// loadFile overrides config with the settings in a .yaml, .yml or .toml
+// file. Keys that aren't settings are errors, to catch typos.
+func loadFile(config *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	values := map[string]any{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.NewDecoder(bytes.NewReader(data)).Decode(&values)
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	case ".toml":
+		_, err = toml.Decode(string(data), &values)
+	default:
+		return fmt.Errorf("config file %s must be .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	byKey := map[string]setting{}
+	for _, s := range settings(config) {
+		byKey[s.key] = s
+	}
+	return setFromFile(byKey, "", values)
+}
+
+func setFromFile(byKey map[string]setting, prefix string, values map[string]any) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := prefix + key
+		switch value := values[key].(type) {
+		case nil:
+		case map[string]any:
+			if err := setFromFile(byKey, path+".", value); err != nil {
+				return err
+			}
+		default:
+			s, ok := byKey[path]
+			if !ok {
+				return fmt.Errorf("unknown config key %s", path)
+			}
+			if err := s.setValue(value); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// setting is a field of Config that isn't a section of others.
+type setting struct {
+	key    string
+	env    string
+	help   string
+	secret string
+	field  reflect.Value
+}
+
+// settings lists the settings of config in the order they're declared,
+// keyed by their path of yaml tags.
+func settings(config *Config) []setting {
+	var all []setting
+	var walk func(prefix string, section reflect.Value)
+	walk = func(prefix string, section reflect.Value) {
+		for i := 0; i < section.NumField(); i++ {
+			field := section.Type().Field(i)
+			key := prefix + field.Tag.Get("yaml")
+			if field.Type.Kind() == reflect.Struct {
+				walk(key+".", section.Field(i))
+				continue
+			}
+			all = append(all, setting{
+				key:    key,
+				env:    field.Tag.Get("env"),
+				help:   field.Tag.Get("help"),
+				secret: field.Tag.Get("secret"),
+				field:  section.Field(i),
+			})
+		}
+	}
+	walk("", reflect.ValueOf(config).Elem())
+	return all
+}
+
+// Set parses value into the setting. Lists are separated by commas or spaces,
+// and a list of just Off is empty.
+func (s setting) Set(value string) error {
+	field := s.field
+	switch {
+	case field.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration such as 30s or 5m", value)
+		}
+		field.SetInt(int64(d))
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+	case field.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", value)
+		}
+		field.SetInt(int64(n))
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+		field.SetBool(b)
+	case field.Kind() == reflect.Slice:
+		items := strings.FieldsFunc(value, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t' || r == '\n'
+		})
+		if len(items) == 1 && items[0] == Off {
+			items = nil
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		panic("config: unsupported setting type " + field.Type().String())
+	}
+	return nil
+}
+
+// setValue sets a value decoded from a file, where lists are lists.
+func (s setting) setValue(value any) error {
+	if list, ok := value.([]any); ok && s.field.Kind() == reflect.Slice {
+		items := make([]string, len(list))
+		for i, item := range list {
+			items[i] = fmt.Sprint(item)
+		}
+		s.field.Set(reflect.ValueOf(items))
+		return nil
+	}
+	return s.Set(fmt.Sprint(value))
+}
+
+// String formats the setting the way Set parses it.
+func (s setting) String() string {
+	switch {
+	case s.field.Type() == durationType:
+		return time.Duration(s.field.Int()).String()
+	case s.field.Kind() == reflect.Slice:
+		return strings.Join(s.field.Interface().([]string), ",")
+	default:
+		return fmt.Sprint(s.field.Interface())
+	}
+}
+
+// flagValue keeps a setting's flag until environment variables have been
+// applied, so that the flag wins over them.
+type flagValue struct {
+	setting setting
+	flags   map[string]string
+	value   string
+}
+
+func (f *flagValue) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+func (f *flagValue) Set(value string) error {
+	// Parse into a scratch setting so bad values fail with the flag's name
+	scratch := f.setting
+	scratch.field = reflect.New(f.setting.field.Type()).Elem()
+	if err := scratch.Set(value); err != nil {
+		return err
+	}
+	f.flags[f.setting.key] = value
+	f.value = value
+	return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+	return f.setting.field.Kind() == reflect.Bool
+}
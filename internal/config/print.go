@@ -0,0 +1,79 @@
+package config
+
+import (
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/hel1th/rssagg/internal/logging"
+)
+
+// Write writes the config to w as YAML a config file could hold, with
+// secrets redacted.
+func (c *Config) Write(w io.Writer) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	sections := map[string]*yaml.Node{"": root}
+
+	for _, s := range settings(c) {
+		parent := ""
+		section := root
+		keys := strings.Split(s.key, ".")
+		for _, key := range keys[:len(keys)-1] {
+			path := parent + key + "."
+			if sections[path] == nil {
+				sections[path] = &yaml.Node{Kind: yaml.MappingNode}
+				section.Content = append(section.Content, scalar(key), sections[path])
+			}
+			parent, section = path, sections[path]
+		}
+
+		var value *yaml.Node
+		switch {
+		case s.field.Kind() == reflect.Slice:
+			value = &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+			for _, item := range s.field.Interface().([]string) {
+				value.Content = append(value.Content, scalar(item))
+			}
+		case s.field.Kind() == reflect.String || s.field.Type() == durationType:
+			value = scalar(s.redacted())
+		default:
+			// Numbers and booleans
+			value = &yaml.Node{Kind: yaml.ScalarNode, Value: s.String()}
+		}
+		section.Content = append(section.Content, scalar(keys[len(keys)-1]), value)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// redacted formats the setting, hiding secrets that are set. Only the
+// password of URLs is hidden, the way url.URL.Redacted hides it.
+func (s setting) redacted() string {
+	value := s.String()
+	switch {
+	case value == "" || s.secret == "":
+		return value
+	case s.secret == "url":
+		if u, err := url.Parse(value); err == nil && u.Scheme != "" {
+			query := u.Query()
+			if query.Has("password") {
+				query.Set("password", "xxxxx")
+				u.RawQuery = query.Encode()
+			}
+			return u.Redacted()
+		}
+	}
+	return logging.Redacted
+}
+
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
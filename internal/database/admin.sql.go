@@ -0,0 +1,213 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: admin.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const countSearchUsers = `-- name: CountSearchUsers :one
+SELECT COUNT(*) FROM users
+WHERE $1::text = ''
+   OR users.name ILIKE '%' || $1::text || '%' ESCAPE '\'
+   OR users.username ILIKE '%' || $1::text || '%' ESCAPE '\'
+   OR users.email ILIKE '%' || $1::text || '%' ESCAPE '\'
+`
+
+func (q *Queries) CountSearchUsers(ctx context.Context, search string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchUsers, search)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteUser = `-- name: DeleteUser :execrows
+DELETE FROM users WHERE id = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const handOverSharedFeeds = `-- name: HandOverSharedFeeds :execrows
+UPDATE feeds
+SET user_id = $1, updated_at = NOW()
+WHERE feeds.user_id = $2
+  AND EXISTS (
+        SELECT 1 FROM feed_follows
+        WHERE feed_follows.feed_id = feeds.id
+          AND feed_follows.user_id <> $2
+  )
+`
+
+type HandOverSharedFeedsParams struct {
+	NewOwnerID uuid.UUID
+	UserID     uuid.UUID
+}
+
+// Feeds other users follow would go with their creator, so they are handed
+// to new_owner_id first.
+func (q *Queries) HandOverSharedFeeds(ctx context.Context, arg HandOverSharedFeedsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, handOverSharedFeeds, arg.NewOwnerID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgePosts = `-- name: PurgePosts :execrows
+DELETE FROM posts
+WHERE ($1::uuid IS NULL OR feed_id = $1::uuid)
+  AND ($2::timestamp IS NULL OR published_at < $2::timestamp)
+`
+
+type PurgePostsParams struct {
+	FeedID uuid.NullUUID
+	Before sql.NullTime
+}
+
+func (q *Queries) PurgePosts(ctx context.Context, arg PurgePostsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgePosts, arg.FeedID, arg.Before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT users.id, users.created_at, users.updated_at, users.name, users.username, users.email, users.password_hash, users.password_changed_at, users.role, users.disabled_at,
+       (SELECT COUNT(*) FROM feed_follows WHERE feed_follows.user_id = users.id) AS follow_count,
+       (SELECT COUNT(*) FROM feeds WHERE feeds.user_id = users.id) AS feed_count
+FROM users
+WHERE $1::text = ''
+   OR users.name ILIKE '%' || $1::text || '%' ESCAPE '\'
+   OR users.username ILIKE '%' || $1::text || '%' ESCAPE '\'
+   OR users.email ILIKE '%' || $1::text || '%' ESCAPE '\'
+ORDER BY users.created_at DESC
+LIMIT $3 OFFSET $2
+`
+
+type SearchUsersParams struct {
+	Search     string
+	PageOffset int32
+	PageLimit  int32
+}
+
+type SearchUsersRow struct {
+	ID                uuid.UUID
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	Name              string
+	Username          sql.NullString
+	Email             sql.NullString
+	PasswordHash      sql.NullString
+	PasswordChangedAt sql.NullTime
+	Role              string
+	DisabledAt        sql.NullTime
+	FollowCount       int64
+	FeedCount         int64
+}
+
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchUsers, arg.Search, arg.PageOffset, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchUsersRow
+	for rows.Next() {
+		var i SearchUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Username,
+			&i.Email,
+			&i.PasswordHash,
+			&i.PasswordChangedAt,
+			&i.Role,
+			&i.DisabledAt,
+			&i.FollowCount,
+			&i.FeedCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setFeedDisabled = `-- name: SetFeedDisabled :execrows
+UPDATE feeds
+SET disabled_at = CASE WHEN $1::boolean THEN NOW() END, updated_at = NOW()
+WHERE id = $2
+`
+
+type SetFeedDisabledParams struct {
+	Disabled bool
+	ID       uuid.UUID
+}
+
+func (q *Queries) SetFeedDisabled(ctx context.Context, arg SetFeedDisabledParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFeedDisabled, arg.Disabled, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setFeedOwner = `-- name: SetFeedOwner :execrows
+UPDATE feeds
+SET user_id = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type SetFeedOwnerParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) SetFeedOwner(ctx context.Context, arg SetFeedOwnerParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFeedOwner, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setUserDisabled = `-- name: SetUserDisabled :execrows
+UPDATE users
+SET disabled_at = CASE WHEN $1::boolean THEN NOW() END, updated_at = NOW()
+WHERE id = $2
+`
+
+type SetUserDisabledParams struct {
+	Disabled bool
+	ID       uuid.UUID
+}
+
+func (q *Queries) SetUserDisabled(ctx context.Context, arg SetUserDisabledParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setUserDisabled, arg.Disabled, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
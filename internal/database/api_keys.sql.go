@@ -0,0 +1,241 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: api_keys.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createAPIKey = `-- name: CreateAPIKey :one
+INSERT INTO api_keys(id, created_at, updated_at, user_id, name, prefix, key_hash, client_hash, scope, expires_at)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, created_at, updated_at, user_id, name, prefix, key_hash, client_hash, scope, last_used_at, expires_at, revoked_at
+`
+
+type CreateAPIKeyParams struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uuid.UUID
+	Name       string
+	Prefix     string
+	KeyHash    string
+	ClientHash string
+	Scope      string
+	ExpiresAt  sql.NullTime
+}
+
+func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error) {
+	row := q.db.QueryRowContext(ctx, createAPIKey,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UserID,
+		arg.Name,
+		arg.Prefix,
+		arg.KeyHash,
+		arg.ClientHash,
+		arg.Scope,
+		arg.ExpiresAt,
+	)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		&i.ClientHash,
+		&i.Scope,
+		&i.LastUsedAt,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const getAPIKeysForUser = `-- name: GetAPIKeysForUser :many
+SELECT id, created_at, updated_at, user_id, name, prefix, key_hash, client_hash, scope, last_used_at, expires_at, revoked_at FROM api_keys WHERE user_id = $1 ORDER BY created_at
+`
+
+func (q *Queries) GetAPIKeysForUser(ctx context.Context, userID uuid.UUID) ([]ApiKey, error) {
+	rows, err := q.db.QueryContext(ctx, getAPIKeysForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ApiKey
+	for rows.Next() {
+		var i ApiKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.Name,
+			&i.Prefix,
+			&i.KeyHash,
+			&i.ClientHash,
+			&i.Scope,
+			&i.LastUsedAt,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActiveAPIKeyByClientHash = `-- name: GetActiveAPIKeyByClientHash :one
+SELECT api_keys.id, api_keys.created_at, api_keys.updated_at, api_keys.user_id, api_keys.name, api_keys.prefix, api_keys.key_hash, api_keys.client_hash, api_keys.scope, api_keys.last_used_at, api_keys.expires_at, api_keys.revoked_at, users.id, users.created_at, users.updated_at, users.name, users.username, users.email, users.password_hash, users.password_changed_at, users.role, users.disabled_at
+FROM api_keys
+JOIN users ON users.id = api_keys.user_id
+WHERE api_keys.client_hash = $1
+  AND users.disabled_at IS NULL
+  AND api_keys.revoked_at IS NULL
+  AND (api_keys.expires_at IS NULL OR api_keys.expires_at > NOW())
+LIMIT 1
+`
+
+type GetActiveAPIKeyByClientHashRow struct {
+	ApiKey ApiKey
+	User   User
+}
+
+func (q *Queries) GetActiveAPIKeyByClientHash(ctx context.Context, clientHash string) (GetActiveAPIKeyByClientHashRow, error) {
+	row := q.db.QueryRowContext(ctx, getActiveAPIKeyByClientHash, clientHash)
+	var i GetActiveAPIKeyByClientHashRow
+	err := row.Scan(
+		&i.ApiKey.ID,
+		&i.ApiKey.CreatedAt,
+		&i.ApiKey.UpdatedAt,
+		&i.ApiKey.UserID,
+		&i.ApiKey.Name,
+		&i.ApiKey.Prefix,
+		&i.ApiKey.KeyHash,
+		&i.ApiKey.ClientHash,
+		&i.ApiKey.Scope,
+		&i.ApiKey.LastUsedAt,
+		&i.ApiKey.ExpiresAt,
+		&i.ApiKey.RevokedAt,
+		&i.User.ID,
+		&i.User.CreatedAt,
+		&i.User.UpdatedAt,
+		&i.User.Name,
+		&i.User.Username,
+		&i.User.Email,
+		&i.User.PasswordHash,
+		&i.User.PasswordChangedAt,
+		&i.User.Role,
+		&i.User.DisabledAt,
+	)
+	return i, err
+}
+
+const getActiveAPIKeyByHash = `-- name: GetActiveAPIKeyByHash :one
+SELECT api_keys.id, api_keys.created_at, api_keys.updated_at, api_keys.user_id, api_keys.name, api_keys.prefix, api_keys.key_hash, api_keys.client_hash, api_keys.scope, api_keys.last_used_at, api_keys.expires_at, api_keys.revoked_at, users.id, users.created_at, users.updated_at, users.name, users.username, users.email, users.password_hash, users.password_changed_at, users.role, users.disabled_at
+FROM api_keys
+JOIN users ON users.id = api_keys.user_id
+WHERE api_keys.key_hash = $1
+  AND users.disabled_at IS NULL
+  AND api_keys.revoked_at IS NULL
+  AND (api_keys.expires_at IS NULL OR api_keys.expires_at > NOW())
+`
+
+type GetActiveAPIKeyByHashRow struct {
+	ApiKey ApiKey
+	User   User
+}
+
+func (q *Queries) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (GetActiveAPIKeyByHashRow, error) {
+	row := q.db.QueryRowContext(ctx, getActiveAPIKeyByHash, keyHash)
+	var i GetActiveAPIKeyByHashRow
+	err := row.Scan(
+		&i.ApiKey.ID,
+		&i.ApiKey.CreatedAt,
+		&i.ApiKey.UpdatedAt,
+		&i.ApiKey.UserID,
+		&i.ApiKey.Name,
+		&i.ApiKey.Prefix,
+		&i.ApiKey.KeyHash,
+		&i.ApiKey.ClientHash,
+		&i.ApiKey.Scope,
+		&i.ApiKey.LastUsedAt,
+		&i.ApiKey.ExpiresAt,
+		&i.ApiKey.RevokedAt,
+		&i.User.ID,
+		&i.User.CreatedAt,
+		&i.User.UpdatedAt,
+		&i.User.Name,
+		&i.User.Username,
+		&i.User.Email,
+		&i.User.PasswordHash,
+		&i.User.PasswordChangedAt,
+		&i.User.Role,
+		&i.User.DisabledAt,
+	)
+	return i, err
+}
+
+const revokeAPIKey = `-- name: RevokeAPIKey :one
+UPDATE api_keys
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+RETURNING id, created_at, updated_at, user_id, name, prefix, key_hash, client_hash, scope, last_used_at, expires_at, revoked_at
+`
+
+type RevokeAPIKeyParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (ApiKey, error) {
+	row := q.db.QueryRowContext(ctx, revokeAPIKey, arg.ID, arg.UserID)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		&i.ClientHash,
+		&i.Scope,
+		&i.LastUsedAt,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const touchAPIKey = `-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = NOW()
+WHERE id = $1
+  AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+`
+
+// Recording every request would write on every read, so last_used_at is
+// only kept to the minute.
+func (q *Queries) TouchAPIKey(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, touchAPIKey, id)
+	return err
+}
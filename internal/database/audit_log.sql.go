@@ -0,0 +1,162 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: audit_log.sql
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createAuditEntry = `-- name: CreateAuditEntry :exec
+INSERT INTO audit_log(id, created_at, actor_id, user_id, action, target_type, target_id, ip, user_agent, before, after)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+`
+
+type CreateAuditEntryParams struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	ActorID    uuid.NullUUID
+	UserID     uuid.NullUUID
+	Action     string
+	TargetType string
+	TargetID   uuid.NullUUID
+	Ip         string
+	UserAgent  string
+	Before     json.RawMessage
+	After      json.RawMessage
+}
+
+func (q *Queries) CreateAuditEntry(ctx context.Context, arg CreateAuditEntryParams) error {
+	_, err := q.db.ExecContext(ctx, createAuditEntry,
+		arg.ID,
+		arg.CreatedAt,
+		arg.ActorID,
+		arg.UserID,
+		arg.Action,
+		arg.TargetType,
+		arg.TargetID,
+		arg.Ip,
+		arg.UserAgent,
+		arg.Before,
+		arg.After,
+	)
+	return err
+}
+
+const exportAuditLog = `-- name: ExportAuditLog :many
+SELECT id, created_at, actor_id, user_id, action, target_type, target_id, ip, user_agent, before, after FROM audit_log
+WHERE ($1::uuid IS NULL OR user_id = $1 OR actor_id = $1)
+  AND ($2::text = '' OR action = $2)
+ORDER BY created_at, id
+LIMIT $4 OFFSET $3
+`
+
+type ExportAuditLogParams struct {
+	UserID     uuid.NullUUID
+	Action     string
+	PageOffset int32
+	PageLimit  int32
+}
+
+// Oldest first, so appends don't shift the pages of an export in progress.
+func (q *Queries) ExportAuditLog(ctx context.Context, arg ExportAuditLogParams) ([]AuditLog, error) {
+	rows, err := q.db.QueryContext(ctx, exportAuditLog,
+		arg.UserID,
+		arg.Action,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.ActorID,
+			&i.UserID,
+			&i.Action,
+			&i.TargetType,
+			&i.TargetID,
+			&i.Ip,
+			&i.UserAgent,
+			&i.Before,
+			&i.After,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAuditLog = `-- name: GetAuditLog :many
+SELECT id, created_at, actor_id, user_id, action, target_type, target_id, ip, user_agent, before, after FROM audit_log
+WHERE ($1::uuid IS NULL OR user_id = $1 OR actor_id = $1)
+  AND ($2::text = '' OR action = $2)
+ORDER BY created_at DESC, id
+LIMIT $4 OFFSET $3
+`
+
+type GetAuditLogParams struct {
+	UserID     uuid.NullUUID
+	Action     string
+	PageOffset int32
+	PageLimit  int32
+}
+
+// Newest first. A user's log is what they did and what was done to them.
+func (q *Queries) GetAuditLog(ctx context.Context, arg GetAuditLogParams) ([]AuditLog, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditLog,
+		arg.UserID,
+		arg.Action,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.ActorID,
+			&i.UserID,
+			&i.Action,
+			&i.TargetType,
+			&i.TargetID,
+			&i.Ip,
+			&i.UserAgent,
+			&i.Before,
+			&i.After,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,7 +16,7 @@ import (
 const createFeedFollow = `-- name: CreateFeedFollow :one
 INSERT INTO feed_follows(id, created_at, updated_at, user_id, feed_id)
 VALUES($1, $2, $3, $4, $5)
-RETURNING id, created_at, updated_at, user_id, feed_id
+RETURNING id, created_at, updated_at, user_id, feed_id, title, muted, priority, notification_mode, folder_id
 `
 
 type CreateFeedFollowParams struct {
@@ -41,6 +42,11 @@ func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowPara
 		&i.UpdatedAt,
 		&i.UserID,
 		&i.FeedID,
+		&i.Title,
+		&i.Muted,
+		&i.Priority,
+		&i.NotificationMode,
+		&i.FolderID,
 	)
 	return i, err
 }
@@ -59,25 +65,122 @@ func (q *Queries) DeleteFeedFollow(ctx context.Context, arg DeleteFeedFollowPara
 	return err
 }
 
+const getFeedFollow = `-- name: GetFeedFollow :one
+SELECT feed_follows.id, feed_follows.created_at, feed_follows.updated_at, feed_follows.user_id, feed_follows.feed_id, feed_follows.title, feed_follows.muted, feed_follows.priority, feed_follows.notification_mode, feed_follows.folder_id, feeds.name AS feed_name
+FROM feed_follows
+JOIN feeds ON feeds.id = feed_follows.feed_id
+WHERE feed_follows.id = $1 AND feed_follows.user_id = $2
+`
+
+type GetFeedFollowParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+type GetFeedFollowRow struct {
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	UserID           uuid.UUID
+	FeedID           uuid.UUID
+	Title            sql.NullString
+	Muted            bool
+	Priority         int32
+	NotificationMode string
+	FolderID         uuid.NullUUID
+	FeedName         string
+}
+
+func (q *Queries) GetFeedFollow(ctx context.Context, arg GetFeedFollowParams) (GetFeedFollowRow, error) {
+	row := q.db.QueryRowContext(ctx, getFeedFollow, arg.ID, arg.UserID)
+	var i GetFeedFollowRow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.Title,
+		&i.Muted,
+		&i.Priority,
+		&i.NotificationMode,
+		&i.FolderID,
+		&i.FeedName,
+	)
+	return i, err
+}
+
+const getFeedFollowByFeed = `-- name: GetFeedFollowByFeed :one
+SELECT id, created_at, updated_at, user_id, feed_id, title, muted, priority, notification_mode, folder_id FROM feed_follows
+WHERE feed_id = $1 AND user_id = $2
+`
+
+type GetFeedFollowByFeedParams struct {
+	FeedID uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetFeedFollowByFeed(ctx context.Context, arg GetFeedFollowByFeedParams) (FeedFollow, error) {
+	row := q.db.QueryRowContext(ctx, getFeedFollowByFeed, arg.FeedID, arg.UserID)
+	var i FeedFollow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.Title,
+		&i.Muted,
+		&i.Priority,
+		&i.NotificationMode,
+		&i.FolderID,
+	)
+	return i, err
+}
+
 const getFeedFollows = `-- name: GetFeedFollows :many
-SELECT id, created_at, updated_at, user_id, feed_id FROM feed_follows WHERE user_id = $1
+SELECT feed_follows.id, feed_follows.created_at, feed_follows.updated_at, feed_follows.user_id, feed_follows.feed_id, feed_follows.title, feed_follows.muted, feed_follows.priority, feed_follows.notification_mode, feed_follows.folder_id, feeds.name AS feed_name
+FROM feed_follows
+JOIN feeds ON feeds.id = feed_follows.feed_id
+WHERE feed_follows.user_id = $1
+ORDER BY feed_follows.priority DESC, feed_follows.created_at
 `
 
-func (q *Queries) GetFeedFollows(ctx context.Context, userID uuid.UUID) ([]FeedFollow, error) {
+type GetFeedFollowsRow struct {
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	UserID           uuid.UUID
+	FeedID           uuid.UUID
+	Title            sql.NullString
+	Muted            bool
+	Priority         int32
+	NotificationMode string
+	FolderID         uuid.NullUUID
+	FeedName         string
+}
+
+func (q *Queries) GetFeedFollows(ctx context.Context, userID uuid.UUID) ([]GetFeedFollowsRow, error) {
 	rows, err := q.db.QueryContext(ctx, getFeedFollows, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []FeedFollow
+	var items []GetFeedFollowsRow
 	for rows.Next() {
-		var i FeedFollow
+		var i GetFeedFollowsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.UserID,
 			&i.FeedID,
+			&i.Title,
+			&i.Muted,
+			&i.Priority,
+			&i.NotificationMode,
+			&i.FolderID,
+			&i.FeedName,
 		); err != nil {
 			return nil, err
 		}
@@ -91,3 +194,37 @@ func (q *Queries) GetFeedFollows(ctx context.Context, userID uuid.UUID) ([]FeedF
 	}
 	return items, nil
 }
+
+const updateFeedFollowPreferences = `-- name: UpdateFeedFollowPreferences :exec
+UPDATE feed_follows
+SET title = $3,
+    muted = $4,
+    priority = $5,
+    notification_mode = $6,
+    folder_id = $7,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+`
+
+type UpdateFeedFollowPreferencesParams struct {
+	ID               uuid.UUID
+	UserID           uuid.UUID
+	Title            sql.NullString
+	Muted            bool
+	Priority         int32
+	NotificationMode string
+	FolderID         uuid.NullUUID
+}
+
+func (q *Queries) UpdateFeedFollowPreferences(ctx context.Context, arg UpdateFeedFollowPreferencesParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedFollowPreferences,
+		arg.ID,
+		arg.UserID,
+		arg.Title,
+		arg.Muted,
+		arg.Priority,
+		arg.NotificationMode,
+		arg.FolderID,
+	)
+	return err
+}
@@ -0,0 +1,126 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: feed_tokens.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createFeedToken = `-- name: CreateFeedToken :one
+INSERT INTO feed_tokens(id, created_at, user_id, name, token_hash)
+VALUES($1, $2, $3, $4, $5)
+RETURNING id, created_at, user_id, name, token_hash, last_used_at
+`
+
+type CreateFeedTokenParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UserID    uuid.UUID
+	Name      string
+	TokenHash string
+}
+
+func (q *Queries) CreateFeedToken(ctx context.Context, arg CreateFeedTokenParams) (FeedToken, error) {
+	row := q.db.QueryRowContext(ctx, createFeedToken,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UserID,
+		arg.Name,
+		arg.TokenHash,
+	)
+	var i FeedToken
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const deleteFeedToken = `-- name: DeleteFeedToken :execrows
+DELETE FROM feed_tokens WHERE id = $1 AND user_id = $2
+`
+
+type DeleteFeedTokenParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteFeedToken(ctx context.Context, arg DeleteFeedTokenParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFeedToken, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getFeedTokens = `-- name: GetFeedTokens :many
+SELECT id, created_at, user_id, name, token_hash, last_used_at FROM feed_tokens WHERE user_id = $1 ORDER BY created_at
+`
+
+func (q *Queries) GetFeedTokens(ctx context.Context, userID uuid.UUID) ([]FeedToken, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedTokens, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FeedToken
+	for rows.Next() {
+		var i FeedToken
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UserID,
+			&i.Name,
+			&i.TokenHash,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserByFeedToken = `-- name: GetUserByFeedToken :one
+UPDATE feed_tokens
+SET last_used_at = NOW()
+FROM users
+WHERE feed_tokens.token_hash = $1
+  AND users.id = feed_tokens.user_id
+  AND users.disabled_at IS NULL
+RETURNING users.id, users.created_at, users.updated_at, users.name, users.username, users.email, users.password_hash, users.password_changed_at, users.role, users.disabled_at
+`
+
+func (q *Queries) GetUserByFeedToken(ctx context.Context, tokenHash string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByFeedToken, tokenHash)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Username,
+		&i.Email,
+		&i.PasswordHash,
+		&i.PasswordChangedAt,
+		&i.Role,
+		&i.DisabledAt,
+	)
+	return i, err
+}
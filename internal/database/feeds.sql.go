@@ -7,15 +7,45 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const countFeedDirectory = `-- name: CountFeedDirectory :one
+SELECT COUNT(*) FROM feeds
+WHERE feeds.disabled_at IS NULL
+  AND (
+        $1::text = ''
+     OR feeds.name ILIKE '%' || $1::text || '%' ESCAPE '\'
+     OR feeds.url ILIKE '%' || $1::text || '%' ESCAPE '\'
+     OR feeds.site_title ILIKE '%' || $1::text || '%' ESCAPE '\'
+  )
+`
+
+func (q *Queries) CountFeedDirectory(ctx context.Context, search string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFeedDirectory, search)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countFeedFollowers = `-- name: CountFeedFollowers :one
+SELECT COUNT(*) FROM feed_follows WHERE feed_id = $1
+`
+
+func (q *Queries) CountFeedFollowers(ctx context.Context, feedID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFeedFollowers, feedID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createFeed = `-- name: CreateFeed :one
 INSERT INTO feeds(id, created_at, updated_at, name, url, user_id)
 VALUES($1, $2, $3, $4, $5, $6)
-RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, site_title, seq, site_url, favicon, favicon_checked_at, disabled_at, last_fetch_error
 `
 
 type CreateFeedParams struct {
@@ -45,12 +75,19 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.SiteTitle,
+		&i.Seq,
+		&i.SiteUrl,
+		&i.Favicon,
+		&i.FaviconCheckedAt,
+		&i.DisabledAt,
+		&i.LastFetchError,
 	)
 	return i, err
 }
 
 const getFeedByID = `-- name: GetFeedByID :one
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds WHERE id = $1
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, site_title, seq, site_url, favicon, favicon_checked_at, disabled_at, last_fetch_error FROM feeds WHERE id = $1
 `
 
 func (q *Queries) GetFeedByID(ctx context.Context, id uuid.UUID) (Feed, error) {
@@ -64,16 +101,63 @@ func (q *Queries) GetFeedByID(ctx context.Context, id uuid.UUID) (Feed, error) {
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.SiteTitle,
+		&i.Seq,
+		&i.SiteUrl,
+		&i.Favicon,
+		&i.FaviconCheckedAt,
+		&i.DisabledAt,
+		&i.LastFetchError,
+	)
+	return i, err
+}
+
+const getFeedByURL = `-- name: GetFeedByURL :one
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, site_title, seq, site_url, favicon, favicon_checked_at, disabled_at, last_fetch_error FROM feeds WHERE url = $1
+`
+
+func (q *Queries) GetFeedByURL(ctx context.Context, url string) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeedByURL, url)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.SiteTitle,
+		&i.Seq,
+		&i.SiteUrl,
+		&i.Favicon,
+		&i.FaviconCheckedAt,
+		&i.DisabledAt,
+		&i.LastFetchError,
 	)
 	return i, err
 }
 
-const getFeeds = `-- name: GetFeeds :many
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds
+const getNextFeedsToFetch = `-- name: GetNextFeedsToFetch :many
+SELECT feeds.id, feeds.created_at, feeds.updated_at, feeds.name, feeds.url, feeds.user_id, feeds.last_fetched_at, feeds.site_title, feeds.seq, feeds.site_url, feeds.favicon, feeds.favicon_checked_at, feeds.disabled_at, feeds.last_fetch_error FROM feeds
+LEFT JOIN websub_subscriptions
+  ON websub_subscriptions.feed_id = feeds.id
+ AND websub_subscriptions.state = 'active'
+ AND websub_subscriptions.expires_at > NOW()
+WHERE feeds.disabled_at IS NULL
+  AND (
+        websub_subscriptions.id IS NULL
+     OR feeds.last_fetched_at IS NULL
+     OR feeds.last_fetched_at < NOW() - INTERVAL '6 hours'
+  )
+ORDER BY feeds.last_fetched_at NULLS FIRST
+LIMIT $1
 `
 
-func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
-	rows, err := q.db.QueryContext(ctx, getFeeds)
+// Feeds with a live WebSub lease get their content pushed, so they are only
+// polled as a safety net every few hours.
+func (q *Queries) GetNextFeedsToFetch(ctx context.Context, limit int32) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getNextFeedsToFetch, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -89,6 +173,13 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
 			&i.Url,
 			&i.UserID,
 			&i.LastFetchedAt,
+			&i.SiteTitle,
+			&i.Seq,
+			&i.SiteUrl,
+			&i.Favicon,
+			&i.FaviconCheckedAt,
+			&i.DisabledAt,
+			&i.LastFetchError,
 		); err != nil {
 			return nil, err
 		}
@@ -103,21 +194,71 @@ func (q *Queries) GetFeeds(ctx context.Context) ([]Feed, error) {
 	return items, nil
 }
 
-const getNextFeedsToFetch = `-- name: GetNextFeedsToFetch :many
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds
-ORDER BY last_fetched_at NULLS FIRST
-LIMIT $1
+const listFeedDirectory = `-- name: ListFeedDirectory :many
+SELECT feeds.id, feeds.created_at, feeds.updated_at, feeds.name, feeds.url, feeds.user_id, feeds.last_fetched_at, feeds.site_title, feeds.seq, feeds.site_url, feeds.favicon, feeds.favicon_checked_at, feeds.disabled_at, feeds.last_fetch_error,
+       (SELECT COUNT(*) FROM feed_follows WHERE feed_follows.feed_id = feeds.id) AS follower_count,
+       COUNT(posts.id) FILTER (WHERE posts.published_at > NOW() - INTERVAL '30 days') AS recent_post_count,
+       MAX(posts.published_at) AS last_post_at
+FROM feeds
+LEFT JOIN posts ON posts.feed_id = feeds.id
+WHERE feeds.disabled_at IS NULL
+  AND (
+        $1::text = ''
+     OR feeds.name ILIKE '%' || $1::text || '%' ESCAPE '\'
+     OR feeds.url ILIKE '%' || $1::text || '%' ESCAPE '\'
+     OR feeds.site_title ILIKE '%' || $1::text || '%' ESCAPE '\'
+  )
+GROUP BY feeds.id
+ORDER BY
+    CASE WHEN $2::text = 'followers'
+        THEN (SELECT COUNT(*) FROM feed_follows WHERE feed_follows.feed_id = feeds.id)
+    END DESC,
+    CASE WHEN $2::text = 'activity' THEN MAX(posts.published_at) END DESC NULLS LAST,
+    feeds.created_at DESC
+LIMIT $4 OFFSET $3
 `
 
-func (q *Queries) GetNextFeedsToFetch(ctx context.Context, limit int32) ([]Feed, error) {
-	rows, err := q.db.QueryContext(ctx, getNextFeedsToFetch, limit)
+type ListFeedDirectoryParams struct {
+	Search     string
+	Sort       string
+	PageOffset int32
+	PageLimit  int32
+}
+
+type ListFeedDirectoryRow struct {
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Name             string
+	Url              string
+	UserID           uuid.UUID
+	LastFetchedAt    sql.NullTime
+	SiteTitle        sql.NullString
+	Seq              int64
+	SiteUrl          sql.NullString
+	Favicon          sql.NullString
+	FaviconCheckedAt sql.NullTime
+	DisabledAt       sql.NullTime
+	LastFetchError   sql.NullString
+	FollowerCount    int64
+	RecentPostCount  int64
+	LastPostAt       interface{}
+}
+
+func (q *Queries) ListFeedDirectory(ctx context.Context, arg ListFeedDirectoryParams) ([]ListFeedDirectoryRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFeedDirectory,
+		arg.Search,
+		arg.Sort,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Feed
+	var items []ListFeedDirectoryRow
 	for rows.Next() {
-		var i Feed
+		var i ListFeedDirectoryRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
@@ -126,6 +267,16 @@ func (q *Queries) GetNextFeedsToFetch(ctx context.Context, limit int32) ([]Feed,
 			&i.Url,
 			&i.UserID,
 			&i.LastFetchedAt,
+			&i.SiteTitle,
+			&i.Seq,
+			&i.SiteUrl,
+			&i.Favicon,
+			&i.FaviconCheckedAt,
+			&i.DisabledAt,
+			&i.LastFetchError,
+			&i.FollowerCount,
+			&i.RecentPostCount,
+			&i.LastPostAt,
 		); err != nil {
 			return nil, err
 		}
@@ -144,7 +295,7 @@ const markFeedAsFetched = `-- name: MarkFeedAsFetched :one
 UPDATE feeds
 SET last_fetched_at = NOW(), updated_at = NOW()
 WHERE id = $1
-RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, site_title, seq, site_url, favicon, favicon_checked_at, disabled_at, last_fetch_error
 `
 
 func (q *Queries) MarkFeedAsFetched(ctx context.Context, id uuid.UUID) (Feed, error) {
@@ -158,6 +309,63 @@ func (q *Queries) MarkFeedAsFetched(ctx context.Context, id uuid.UUID) (Feed, er
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.SiteTitle,
+		&i.Seq,
+		&i.SiteUrl,
+		&i.Favicon,
+		&i.FaviconCheckedAt,
+		&i.DisabledAt,
+		&i.LastFetchError,
 	)
 	return i, err
 }
+
+const setFeedFetchError = `-- name: SetFeedFetchError :exec
+UPDATE feeds
+SET last_fetch_error = $2
+WHERE id = $1
+`
+
+type SetFeedFetchErrorParams struct {
+	ID             uuid.UUID
+	LastFetchError sql.NullString
+}
+
+// A NULL error marks the last fetch as successful.
+func (q *Queries) SetFeedFetchError(ctx context.Context, arg SetFeedFetchErrorParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedFetchError, arg.ID, arg.LastFetchError)
+	return err
+}
+
+const updateFeedFavicon = `-- name: UpdateFeedFavicon :exec
+UPDATE feeds
+SET favicon = $2, favicon_checked_at = NOW()
+WHERE id = $1
+`
+
+type UpdateFeedFaviconParams struct {
+	ID      uuid.UUID
+	Favicon sql.NullString
+}
+
+func (q *Queries) UpdateFeedFavicon(ctx context.Context, arg UpdateFeedFaviconParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedFavicon, arg.ID, arg.Favicon)
+	return err
+}
+
+const updateFeedSiteInfo = `-- name: UpdateFeedSiteInfo :exec
+UPDATE feeds
+SET site_title = $2, site_url = $3, updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateFeedSiteInfoParams struct {
+	ID        uuid.UUID
+	SiteTitle sql.NullString
+	SiteUrl   sql.NullString
+}
+
+func (q *Queries) UpdateFeedSiteInfo(ctx context.Context, arg UpdateFeedSiteInfoParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedSiteInfo, arg.ID, arg.SiteTitle, arg.SiteUrl)
+	return err
+}
@@ -0,0 +1,403 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: fever.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const countFeverItems = `-- name: CountFeverItems :one
+SELECT COUNT(*)
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+LEFT JOIN post_states
+  ON post_states.post_id = posts.id
+ AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = $1
+  AND post_states.hidden_at IS NULL
+`
+
+func (q *Queries) CountFeverItems(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFeverItems, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getFeverFavicons = `-- name: GetFeverFavicons :many
+SELECT feeds.seq, feeds.favicon
+FROM feed_follows
+JOIN feeds ON feeds.id = feed_follows.feed_id
+WHERE feed_follows.user_id = $1 AND feeds.favicon IS NOT NULL
+ORDER BY feeds.seq
+`
+
+type GetFeverFaviconsRow struct {
+	Seq     int64
+	Favicon sql.NullString
+}
+
+func (q *Queries) GetFeverFavicons(ctx context.Context, userID uuid.UUID) ([]GetFeverFaviconsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeverFavicons, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeverFaviconsRow
+	for rows.Next() {
+		var i GetFeverFaviconsRow
+		if err := rows.Scan(&i.Seq, &i.Favicon); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeverFeeds = `-- name: GetFeverFeeds :many
+SELECT feeds.seq,
+       COALESCE(feed_follows.title, feeds.name)::text AS title,
+       feeds.url,
+       feeds.site_url,
+       feeds.last_fetched_at,
+       folders.seq AS folder_seq
+FROM feed_follows
+JOIN feeds ON feeds.id = feed_follows.feed_id
+LEFT JOIN folders ON folders.id = feed_follows.folder_id
+WHERE feed_follows.user_id = $1
+ORDER BY feeds.seq
+`
+
+type GetFeverFeedsRow struct {
+	Seq           int64
+	Title         string
+	Url           string
+	SiteUrl       sql.NullString
+	LastFetchedAt sql.NullTime
+	FolderSeq     sql.NullInt64
+}
+
+func (q *Queries) GetFeverFeeds(ctx context.Context, userID uuid.UUID) ([]GetFeverFeedsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeverFeeds, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeverFeedsRow
+	for rows.Next() {
+		var i GetFeverFeedsRow
+		if err := rows.Scan(
+			&i.Seq,
+			&i.Title,
+			&i.Url,
+			&i.SiteUrl,
+			&i.LastFetchedAt,
+			&i.FolderSeq,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeverGroups = `-- name: GetFeverGroups :many
+SELECT seq, name FROM folders WHERE user_id = $1 ORDER BY name
+`
+
+type GetFeverGroupsRow struct {
+	Seq  int64
+	Name string
+}
+
+func (q *Queries) GetFeverGroups(ctx context.Context, userID uuid.UUID) ([]GetFeverGroupsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeverGroups, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeverGroupsRow
+	for rows.Next() {
+		var i GetFeverGroupsRow
+		if err := rows.Scan(&i.Seq, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeverItems = `-- name: GetFeverItems :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.description, posts.published_at, posts.url, posts.feed_id, posts.author, posts.seq, posts.stream_xid,
+       feeds.seq AS feed_seq,
+       (post_states.read_at IS NOT NULL)::boolean AS is_read,
+       (post_states.starred_at IS NOT NULL)::boolean AS is_starred
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+JOIN feeds ON feeds.id = posts.feed_id
+LEFT JOIN post_states
+  ON post_states.post_id = posts.id
+ AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = $1
+  AND post_states.hidden_at IS NULL
+  AND ($2::bigint IS NULL OR posts.seq > $2::bigint)
+  AND ($3::bigint IS NULL OR posts.seq < $3::bigint)
+  AND (NOT $4::boolean OR posts.seq = ANY($5::bigint[]))
+ORDER BY
+    CASE WHEN $3::bigint IS NOT NULL THEN posts.seq END DESC,
+    posts.seq
+LIMIT $6
+`
+
+type GetFeverItemsParams struct {
+	UserID    uuid.UUID
+	SinceID   sql.NullInt64
+	MaxID     sql.NullInt64
+	FilterIds bool
+	Ids       []int64
+	PageLimit int32
+}
+
+type GetFeverItemsRow struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Title       string
+	Description sql.NullString
+	PublishedAt time.Time
+	Url         string
+	FeedID      uuid.UUID
+	Author      sql.NullString
+	Seq         int64
+	StreamXid   sql.NullInt64
+	FeedSeq     int64
+	IsRead      bool
+	IsStarred   bool
+}
+
+// Items after since_id (oldest first), before max_id (newest first), or
+// with the given IDs. Hidden posts are left out like in the timeline.
+func (q *Queries) GetFeverItems(ctx context.Context, arg GetFeverItemsParams) ([]GetFeverItemsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeverItems,
+		arg.UserID,
+		arg.SinceID,
+		arg.MaxID,
+		arg.FilterIds,
+		pq.Array(arg.Ids),
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeverItemsRow
+	for rows.Next() {
+		var i GetFeverItemsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Description,
+			&i.PublishedAt,
+			&i.Url,
+			&i.FeedID,
+			&i.Author,
+			&i.Seq,
+			&i.StreamXid,
+			&i.FeedSeq,
+			&i.IsRead,
+			&i.IsStarred,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeverSavedItemIDs = `-- name: GetFeverSavedItemIDs :many
+SELECT posts.seq
+FROM posts
+JOIN post_states ON post_states.post_id = posts.id
+WHERE post_states.user_id = $1
+  AND post_states.starred_at IS NOT NULL
+ORDER BY posts.seq
+`
+
+func (q *Queries) GetFeverSavedItemIDs(ctx context.Context, userID uuid.UUID) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getFeverSavedItemIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var seq int64
+		if err := rows.Scan(&seq); err != nil {
+			return nil, err
+		}
+		items = append(items, seq)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeverUnreadItemIDs = `-- name: GetFeverUnreadItemIDs :many
+SELECT posts.seq
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+LEFT JOIN post_states
+  ON post_states.post_id = posts.id
+ AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = $1
+  AND post_states.hidden_at IS NULL
+  AND post_states.read_at IS NULL
+ORDER BY posts.seq
+`
+
+func (q *Queries) GetFeverUnreadItemIDs(ctx context.Context, userID uuid.UUID) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getFeverUnreadItemIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var seq int64
+		if err := rows.Scan(&seq); err != nil {
+			return nil, err
+		}
+		items = append(items, seq)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLastRefreshedAt = `-- name: GetLastRefreshedAt :one
+SELECT COALESCE(MAX(feeds.last_fetched_at), TIMESTAMP 'epoch')::timestamp AS last_refreshed_at
+FROM feed_follows
+JOIN feeds ON feeds.id = feed_follows.feed_id
+WHERE feed_follows.user_id = $1
+`
+
+func (q *Queries) GetLastRefreshedAt(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, getLastRefreshedAt, userID)
+	var last_refreshed_at time.Time
+	err := row.Scan(&last_refreshed_at)
+	return last_refreshed_at, err
+}
+
+const getPostIDForFeverItem = `-- name: GetPostIDForFeverItem :one
+SELECT posts.id
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+WHERE posts.seq = $1 AND feed_follows.user_id = $2
+`
+
+type GetPostIDForFeverItemParams struct {
+	Seq    int64
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetPostIDForFeverItem(ctx context.Context, arg GetPostIDForFeverItemParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, getPostIDForFeverItem, arg.Seq, arg.UserID)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const markFeverFeedRead = `-- name: MarkFeverFeedRead :exec
+INSERT INTO post_states(user_id, post_id, created_at, updated_at, read_at)
+SELECT feed_follows.user_id, posts.id, NOW(), NOW(), NOW()
+FROM posts
+JOIN feeds ON feeds.id = posts.feed_id
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+WHERE feed_follows.user_id = $1
+  AND feeds.seq = $2
+  AND posts.created_at <= $3
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read_at = COALESCE(post_states.read_at, NOW()),
+    updated_at = NOW()
+`
+
+type MarkFeverFeedReadParams struct {
+	UserID  uuid.UUID
+	FeedSeq int64
+	Before  time.Time
+}
+
+// Marks a feed's posts read up to "before", as Fever clients do when the
+// user catches up on a feed.
+func (q *Queries) MarkFeverFeedRead(ctx context.Context, arg MarkFeverFeedReadParams) error {
+	_, err := q.db.ExecContext(ctx, markFeverFeedRead, arg.UserID, arg.FeedSeq, arg.Before)
+	return err
+}
+
+const markFeverGroupRead = `-- name: MarkFeverGroupRead :exec
+INSERT INTO post_states(user_id, post_id, created_at, updated_at, read_at)
+SELECT feed_follows.user_id, posts.id, NOW(), NOW(), NOW()
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+LEFT JOIN folders ON folders.id = feed_follows.folder_id
+WHERE feed_follows.user_id = $1
+  AND ($2::bigint = 0 OR folders.seq = $2::bigint)
+  AND posts.created_at <= $3
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read_at = COALESCE(post_states.read_at, NOW()),
+    updated_at = NOW()
+`
+
+type MarkFeverGroupReadParams struct {
+	UserID   uuid.UUID
+	GroupSeq int64
+	Before   time.Time
+}
+
+// Group 0 is Fever's "Kindling" super group of all feeds.
+func (q *Queries) MarkFeverGroupRead(ctx context.Context, arg MarkFeverGroupReadParams) error {
+	_, err := q.db.ExecContext(ctx, markFeverGroupRead, arg.UserID, arg.GroupSeq, arg.Before)
+	return err
+}
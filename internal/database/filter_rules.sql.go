@@ -0,0 +1,285 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: filter_rules.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createFilterRule = `-- name: CreateFilterRule :one
+INSERT INTO filter_rules(
+    id, created_at, updated_at, user_id, name, scope, folder_id, feed_id,
+    field, match_type, pattern, action, tag_name, enabled
+)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+RETURNING id, created_at, updated_at, user_id, name, scope, folder_id, feed_id, field, match_type, pattern, action, tag_name, enabled
+`
+
+type CreateFilterRuleParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	Name      string
+	Scope     string
+	FolderID  uuid.NullUUID
+	FeedID    uuid.NullUUID
+	Field     string
+	MatchType string
+	Pattern   string
+	Action    string
+	TagName   sql.NullString
+	Enabled   bool
+}
+
+func (q *Queries) CreateFilterRule(ctx context.Context, arg CreateFilterRuleParams) (FilterRule, error) {
+	row := q.db.QueryRowContext(ctx, createFilterRule,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UserID,
+		arg.Name,
+		arg.Scope,
+		arg.FolderID,
+		arg.FeedID,
+		arg.Field,
+		arg.MatchType,
+		arg.Pattern,
+		arg.Action,
+		arg.TagName,
+		arg.Enabled,
+	)
+	var i FilterRule
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+		&i.Scope,
+		&i.FolderID,
+		&i.FeedID,
+		&i.Field,
+		&i.MatchType,
+		&i.Pattern,
+		&i.Action,
+		&i.TagName,
+		&i.Enabled,
+	)
+	return i, err
+}
+
+const deleteFilterRule = `-- name: DeleteFilterRule :execrows
+DELETE FROM filter_rules WHERE id = $1 AND user_id = $2
+`
+
+type DeleteFilterRuleParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteFilterRule(ctx context.Context, arg DeleteFilterRuleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFilterRule, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getFilterRule = `-- name: GetFilterRule :one
+SELECT id, created_at, updated_at, user_id, name, scope, folder_id, feed_id, field, match_type, pattern, action, tag_name, enabled FROM filter_rules WHERE id = $1 AND user_id = $2
+`
+
+type GetFilterRuleParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetFilterRule(ctx context.Context, arg GetFilterRuleParams) (FilterRule, error) {
+	row := q.db.QueryRowContext(ctx, getFilterRule, arg.ID, arg.UserID)
+	var i FilterRule
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+		&i.Scope,
+		&i.FolderID,
+		&i.FeedID,
+		&i.Field,
+		&i.MatchType,
+		&i.Pattern,
+		&i.Action,
+		&i.TagName,
+		&i.Enabled,
+	)
+	return i, err
+}
+
+const getFilterRules = `-- name: GetFilterRules :many
+SELECT id, created_at, updated_at, user_id, name, scope, folder_id, feed_id, field, match_type, pattern, action, tag_name, enabled FROM filter_rules WHERE user_id = $1 ORDER BY created_at
+`
+
+func (q *Queries) GetFilterRules(ctx context.Context, userID uuid.UUID) ([]FilterRule, error) {
+	rows, err := q.db.QueryContext(ctx, getFilterRules, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FilterRule
+	for rows.Next() {
+		var i FilterRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.Name,
+			&i.Scope,
+			&i.FolderID,
+			&i.FeedID,
+			&i.Field,
+			&i.MatchType,
+			&i.Pattern,
+			&i.Action,
+			&i.TagName,
+			&i.Enabled,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFilterRulesForFeed = `-- name: GetFilterRulesForFeed :many
+SELECT filter_rules.id, filter_rules.created_at, filter_rules.updated_at, filter_rules.user_id, filter_rules.name, filter_rules.scope, filter_rules.folder_id, filter_rules.feed_id, filter_rules.field, filter_rules.match_type, filter_rules.pattern, filter_rules.action, filter_rules.tag_name, filter_rules.enabled
+FROM filter_rules
+JOIN feed_follows
+  ON feed_follows.user_id = filter_rules.user_id
+ AND feed_follows.feed_id = $1
+WHERE filter_rules.enabled
+  AND (
+        filter_rules.scope = 'global'
+     OR (filter_rules.scope = 'feed' AND filter_rules.feed_id = $1)
+     OR (filter_rules.scope = 'folder' AND filter_rules.folder_id = feed_follows.folder_id)
+  )
+ORDER BY filter_rules.created_at
+`
+
+func (q *Queries) GetFilterRulesForFeed(ctx context.Context, feedID uuid.UUID) ([]FilterRule, error) {
+	rows, err := q.db.QueryContext(ctx, getFilterRulesForFeed, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FilterRule
+	for rows.Next() {
+		var i FilterRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.Name,
+			&i.Scope,
+			&i.FolderID,
+			&i.FeedID,
+			&i.Field,
+			&i.MatchType,
+			&i.Pattern,
+			&i.Action,
+			&i.TagName,
+			&i.Enabled,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsInRuleScope = `-- name: GetPostsInRuleScope :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.description, posts.published_at, posts.url, posts.feed_id, posts.author, posts.seq, posts.stream_xid
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+WHERE feed_follows.user_id = $1
+  AND (
+        $2::text = 'global'
+     OR ($2::text = 'feed' AND posts.feed_id = $3)
+     OR ($2::text = 'folder' AND feed_follows.folder_id = $4)
+  )
+ORDER BY posts.published_at DESC
+LIMIT $6 OFFSET $5
+`
+
+type GetPostsInRuleScopeParams struct {
+	UserID     uuid.UUID
+	Scope      string
+	FeedID     uuid.NullUUID
+	FolderID   uuid.NullUUID
+	PageOffset int32
+	PageLimit  int32
+}
+
+func (q *Queries) GetPostsInRuleScope(ctx context.Context, arg GetPostsInRuleScopeParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsInRuleScope,
+		arg.UserID,
+		arg.Scope,
+		arg.FeedID,
+		arg.FolderID,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Post
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Description,
+			&i.PublishedAt,
+			&i.Url,
+			&i.FeedID,
+			&i.Author,
+			&i.Seq,
+			&i.StreamXid,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
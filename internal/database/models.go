@@ -6,27 +6,114 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type ApiKey struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uuid.UUID
+	Name       string
+	Prefix     string
+	KeyHash    string
+	ClientHash string
+	Scope      string
+	LastUsedAt sql.NullTime
+	ExpiresAt  sql.NullTime
+	RevokedAt  sql.NullTime
+}
+
+type AuditLog struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	ActorID    uuid.NullUUID
+	UserID     uuid.NullUUID
+	Action     string
+	TargetType string
+	TargetID   uuid.NullUUID
+	Ip         string
+	UserAgent  string
+	Before     json.RawMessage
+	After      json.RawMessage
+}
+
 type Feed struct {
-	ID            uuid.UUID
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Name          string
-	Url           string
-	UserID        uuid.UUID
-	LastFetchedAt sql.NullTime
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Name             string
+	Url              string
+	UserID           uuid.UUID
+	LastFetchedAt    sql.NullTime
+	SiteTitle        sql.NullString
+	Seq              int64
+	SiteUrl          sql.NullString
+	Favicon          sql.NullString
+	FaviconCheckedAt sql.NullTime
+	DisabledAt       sql.NullTime
+	LastFetchError   sql.NullString
 }
 
 type FeedFollow struct {
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	UserID           uuid.UUID
+	FeedID           uuid.UUID
+	Title            sql.NullString
+	Muted            bool
+	Priority         int32
+	NotificationMode string
+	FolderID         uuid.NullUUID
+}
+
+type FeedToken struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UserID     uuid.UUID
+	Name       string
+	TokenHash  string
+	LastUsedAt sql.NullTime
+}
+
+type FilterRule struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	UserID    uuid.UUID
-	FeedID    uuid.UUID
+	Name      string
+	Scope     string
+	FolderID  uuid.NullUUID
+	FeedID    uuid.NullUUID
+	Field     string
+	MatchType string
+	Pattern   string
+	Action    string
+	TagName   sql.NullString
+	Enabled   bool
+}
+
+type Folder struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	Name      string
+	Seq       int64
+}
+
+type PasswordReset struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UserID    uuid.UUID
+	CreatedBy uuid.NullUUID
+	TokenHash string
+	UsedAt    sql.NullTime
 }
 
 type Post struct {
@@ -38,12 +125,132 @@ type Post struct {
 	PublishedAt time.Time
 	Url         string
 	FeedID      uuid.UUID
+	Author      sql.NullString
+	Seq         int64
+	StreamXid   sql.NullInt64
+}
+
+type PostState struct {
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ReadAt    sql.NullTime
+	StarredAt sql.NullTime
+	HiddenAt  sql.NullTime
+}
+
+type PostTag struct {
+	TagID     uuid.UUID
+	PostID    uuid.UUID
+	CreatedAt time.Time
+}
+
+type RateLimitBucket struct {
+	Key       string
+	Tokens    float64
+	Allowed   bool
+	UpdatedAt time.Time
+}
+
+type ScraperHeartbeat struct {
+	WorkerID          string
+	StartedAt         time.Time
+	LastBeatAt        time.Time
+	LastRunStartedAt  sql.NullTime
+	LastRunFinishedAt sql.NullTime
+	LastRunFeeds      int32
+}
+
+type Session struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	UserID     uuid.UUID
+	TokenHash  string
+	CsrfToken  string
+	UserAgent  string
+	Ip         string
+}
+
+type Tag struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	Name      string
 }
 
 type User struct {
+	ID                uuid.UUID
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	Name              string
+	Username          sql.NullString
+	Email             sql.NullString
+	PasswordHash      sql.NullString
+	PasswordChangedAt sql.NullTime
+	Role              string
+	DisabledAt        sql.NullTime
+}
+
+type UserIdentity struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	LastLoginAt time.Time
+	UserID      uuid.UUID
+	Issuer      string
+	Subject     string
+	Email       sql.NullString
+}
+
+type Webhook struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	UserID    uuid.UUID
 	Name      string
-	ApiKey    string
+	Url       string
+	Secret    string
+	FeedID    uuid.NullUUID
+	FolderID  uuid.NullUUID
+	Keyword   sql.NullString
+	Enabled   bool
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	WebhookID      uuid.UUID
+	PostID         uuid.NullUUID
+	Event          string
+	Payload        string
+	Status         string
+	Attempts       int32
+	NextAttemptAt  time.Time
+	LastAttemptAt  sql.NullTime
+	ResponseStatus sql.NullInt32
+	LastError      sql.NullString
+}
+
+type WebhookDigestPost struct {
+	WebhookID uuid.UUID
+	PostID    uuid.UUID
+	CreatedAt time.Time
+}
+
+type WebsubSubscription struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	FeedID       uuid.UUID
+	HubUrl       string
+	TopicUrl     string
+	Secret       string
+	State        string
+	LeaseSeconds sql.NullInt32
+	ExpiresAt    sql.NullTime
+	LastError    sql.NullString
 }
@@ -0,0 +1,99 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: post_states.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const applyPostState = `-- name: ApplyPostState :exec
+INSERT INTO post_states(user_id, post_id, created_at, updated_at, read_at, starred_at, hidden_at)
+VALUES(
+    $1,
+    $2,
+    NOW(),
+    NOW(),
+    CASE WHEN $3::boolean THEN NOW() END,
+    CASE WHEN $4::boolean THEN NOW() END,
+    CASE WHEN $5::boolean THEN NOW() END
+)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read_at = COALESCE(post_states.read_at, EXCLUDED.read_at),
+    starred_at = COALESCE(post_states.starred_at, EXCLUDED.starred_at),
+    hidden_at = COALESCE(post_states.hidden_at, EXCLUDED.hidden_at),
+    updated_at = NOW()
+`
+
+type ApplyPostStateParams struct {
+	UserID   uuid.UUID
+	PostID   uuid.UUID
+	MarkRead bool
+	Star     bool
+	Hide     bool
+}
+
+func (q *Queries) ApplyPostState(ctx context.Context, arg ApplyPostStateParams) error {
+	_, err := q.db.ExecContext(ctx, applyPostState,
+		arg.UserID,
+		arg.PostID,
+		arg.MarkRead,
+		arg.Star,
+		arg.Hide,
+	)
+	return err
+}
+
+const setPostRead = `-- name: SetPostRead :exec
+INSERT INTO post_states(user_id, post_id, created_at, updated_at, read_at)
+VALUES(
+    $1,
+    $2,
+    NOW(),
+    NOW(),
+    CASE WHEN $3::boolean THEN NOW() END
+)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read_at = CASE WHEN $3::boolean THEN COALESCE(post_states.read_at, NOW()) END,
+    updated_at = NOW()
+`
+
+type SetPostReadParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+	Read   bool
+}
+
+func (q *Queries) SetPostRead(ctx context.Context, arg SetPostReadParams) error {
+	_, err := q.db.ExecContext(ctx, setPostRead, arg.UserID, arg.PostID, arg.Read)
+	return err
+}
+
+const setPostStarred = `-- name: SetPostStarred :exec
+INSERT INTO post_states(user_id, post_id, created_at, updated_at, starred_at)
+VALUES(
+    $1,
+    $2,
+    NOW(),
+    NOW(),
+    CASE WHEN $3::boolean THEN NOW() END
+)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET starred_at = CASE WHEN $3::boolean THEN COALESCE(post_states.starred_at, NOW()) END,
+    updated_at = NOW()
+`
+
+type SetPostStarredParams struct {
+	UserID  uuid.UUID
+	PostID  uuid.UUID
+	Starred bool
+}
+
+func (q *Queries) SetPostStarred(ctx context.Context, arg SetPostStarredParams) error {
+	_, err := q.db.ExecContext(ctx, setPostStarred, arg.UserID, arg.PostID, arg.Starred)
+	return err
+}
@@ -11,9 +11,10 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
-const createPost = `-- name: CreatePost :exec
+const createPost = `-- name: CreatePost :execrows
 INSERT INTO posts (
                   id,
                   created_at,
@@ -22,9 +23,10 @@ INSERT INTO posts (
                   description,
                   url,
                   feed_id,
-                  published_at
+                  published_at,
+                  author
                 )
-VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
 ON CONFLICT (url) DO NOTHING
 `
 
@@ -37,10 +39,11 @@ type CreatePostParams struct {
 	Url         string
 	FeedID      uuid.UUID
 	PublishedAt time.Time
+	Author      sql.NullString
 }
 
-func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) error {
-	_, err := q.db.ExecContext(ctx, createPost,
+func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createPost,
 		arg.ID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
@@ -49,36 +52,238 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) error {
 		arg.Url,
 		arg.FeedID,
 		arg.PublishedAt,
+		arg.Author,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getPostForUser = `-- name: GetPostForUser :one
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.description, posts.published_at, posts.url, posts.feed_id, posts.author, posts.seq, posts.stream_xid
+FROM posts
+JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE posts.id = $1 AND feed_follows.user_id = $2
+`
+
+type GetPostForUserParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetPostForUser(ctx context.Context, arg GetPostForUserParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostForUser, arg.ID, arg.UserID)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Description,
+		&i.PublishedAt,
+		&i.Url,
+		&i.FeedID,
+		&i.Author,
+		&i.Seq,
+		&i.StreamXid,
+	)
+	return i, err
+}
+
+const getPostStreamHorizon = `-- name: GetPostStreamHorizon :one
+SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint AS xid
+`
+
+// The oldest transaction still running. Every post written from here on
+// has a stream_xid at least this large.
+func (q *Queries) GetPostStreamHorizon(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getPostStreamHorizon)
+	var xid int64
+	err := row.Scan(&xid)
+	return xid, err
 }
 
 const getPostsForUser = `-- name: GetPostsForUser :many
 
-SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.description, posts.published_at, posts.url, posts.feed_id
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.description, posts.published_at, posts.url, posts.feed_id, posts.author, posts.seq, posts.stream_xid,
+       COALESCE(feed_follows.title, feeds.name)::text AS feed_title,
+       feed_follows.priority AS feed_priority,
+       (post_states.read_at IS NOT NULL)::boolean AS is_read,
+       (post_states.starred_at IS NOT NULL)::boolean AS is_starred
 FROM posts
 JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+JOIN feeds ON feeds.id = posts.feed_id
+LEFT JOIN post_states
+  ON post_states.post_id = posts.id
+ AND post_states.user_id = feed_follows.user_id
 WHERE feed_follows.user_id = $1
-ORDER BY posts.published_at DESC
-LIMIT $2 OFFSET $3
+  AND (
+        $2::uuid IS NULL
+     OR EXISTS (
+            SELECT 1 FROM post_tags
+            WHERE post_tags.post_id = posts.id
+              AND post_tags.tag_id = $2::uuid
+        )
+  )
+  AND (
+        $3::uuid IS NULL
+     OR feed_follows.folder_id = $3::uuid
+  )
+  AND (NOT $4::boolean OR post_states.starred_at IS NOT NULL)
+  -- Muted feeds and hidden posts only drop out of the main and folder
+  -- timelines; tagged and starred posts were picked out by the user.
+  AND (
+        $2::uuid IS NOT NULL
+     OR $4::boolean
+     OR (NOT feed_follows.muted AND post_states.hidden_at IS NULL)
+  )
+ORDER BY
+    CASE WHEN $5::text = 'priority' THEN feed_follows.priority END DESC,
+    posts.published_at DESC
+LIMIT $7 OFFSET $6
 `
 
 type GetPostsForUserParams struct {
-	UserID uuid.UUID
-	Limit  int32
-	Offset int32
+	UserID      uuid.UUID
+	TagID       uuid.NullUUID
+	FolderID    uuid.NullUUID
+	StarredOnly bool
+	Sort        string
+	PageOffset  int32
+	PageLimit   int32
+}
+
+type GetPostsForUserRow struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Description  sql.NullString
+	PublishedAt  time.Time
+	Url          string
+	FeedID       uuid.UUID
+	Author       sql.NullString
+	Seq          int64
+	StreamXid    sql.NullInt64
+	FeedTitle    string
+	FeedPriority int32
+	IsRead       bool
+	IsStarred    bool
 }
 
 // RETURNING *;
-func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]Post, error) {
-	rows, err := q.db.QueryContext(ctx, getPostsForUser, arg.UserID, arg.Limit, arg.Offset)
+func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUser,
+		arg.UserID,
+		arg.TagID,
+		arg.FolderID,
+		arg.StarredOnly,
+		arg.Sort,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsForUserRow
+	for rows.Next() {
+		var i GetPostsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Description,
+			&i.PublishedAt,
+			&i.Url,
+			&i.FeedID,
+			&i.Author,
+			&i.Seq,
+			&i.StreamXid,
+			&i.FeedTitle,
+			&i.FeedPriority,
+			&i.IsRead,
+			&i.IsStarred,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsForUserSince = `-- name: GetPostsForUserSince :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.description, posts.published_at, posts.url, posts.feed_id, posts.author, posts.seq, posts.stream_xid,
+       COALESCE(feed_follows.title, feeds.name)::text AS feed_title,
+       feed_follows.priority AS feed_priority,
+       (post_states.read_at IS NOT NULL)::boolean AS is_read,
+       (post_states.starred_at IS NOT NULL)::boolean AS is_starred
+FROM posts
+JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+JOIN feeds ON feeds.id = posts.feed_id
+LEFT JOIN post_states
+  ON post_states.post_id = posts.id
+ AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = $1
+  AND (posts.stream_xid, posts.seq) > ($2::bigint, $3::bigint)
+  AND posts.stream_xid < pg_snapshot_xmin(pg_current_snapshot())::text::bigint
+  AND NOT feed_follows.muted
+  AND post_states.hidden_at IS NULL
+ORDER BY posts.stream_xid, posts.seq
+LIMIT $4
+`
+
+type GetPostsForUserSinceParams struct {
+	UserID    uuid.UUID
+	AfterXid  int64
+	AfterSeq  int64
+	PageLimit int32
+}
+
+type GetPostsForUserSinceRow struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Description  sql.NullString
+	PublishedAt  time.Time
+	Url          string
+	FeedID       uuid.UUID
+	Author       sql.NullString
+	Seq          int64
+	StreamXid    sql.NullInt64
+	FeedTitle    string
+	FeedPriority int32
+	IsRead       bool
+	IsStarred    bool
+}
+
+// Same shape as GetPostsForUser, for streaming: posts after a given stream
+// position, oldest first, without muted feeds or hidden posts. Posts not
+// published yet, or published by transactions that may still be running,
+// are left for a later read.
+func (q *Queries) GetPostsForUserSince(ctx context.Context, arg GetPostsForUserSinceParams) ([]GetPostsForUserSinceRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUserSince,
+		arg.UserID,
+		arg.AfterXid,
+		arg.AfterSeq,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Post
+	var items []GetPostsForUserSinceRow
 	for rows.Next() {
-		var i Post
+		var i GetPostsForUserSinceRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.CreatedAt,
@@ -88,6 +293,13 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 			&i.PublishedAt,
 			&i.Url,
 			&i.FeedID,
+			&i.Author,
+			&i.Seq,
+			&i.StreamXid,
+			&i.FeedTitle,
+			&i.FeedPriority,
+			&i.IsRead,
+			&i.IsStarred,
 		); err != nil {
 			return nil, err
 		}
@@ -101,3 +313,29 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 	}
 	return items, nil
 }
+
+const publishPosts = `-- name: PublishPosts :exec
+WITH published AS (
+    UPDATE posts
+    SET stream_xid = pg_current_xact_id()::text::bigint
+    WHERE id = ANY($2::uuid[]) AND stream_xid IS NULL
+    RETURNING id
+)
+SELECT pg_notify(
+    'new_posts',
+    json_build_object('feed_id', $1::uuid, 'posts', COUNT(*))::text
+)
+FROM published
+`
+
+type PublishPostsParams struct {
+	FeedID uuid.UUID
+	Ids    []uuid.UUID
+}
+
+// Releases new posts to the stream once filter rules have run on them, and
+// wakes the streams when that commits.
+func (q *Queries) PublishPosts(ctx context.Context, arg PublishPostsParams) error {
+	_, err := q.db.ExecContext(ctx, publishPosts, arg.FeedID, pq.Array(arg.Ids))
+	return err
+}
@@ -0,0 +1,440 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: reader.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const getReaderItemIDs = `-- name: GetReaderItemIDs :many
+SELECT posts.seq, posts.created_at
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+JOIN feeds ON feeds.id = posts.feed_id
+LEFT JOIN folders ON folders.id = feed_follows.folder_id
+LEFT JOIN post_states
+  ON post_states.post_id = posts.id
+ AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = $1
+  AND post_states.hidden_at IS NULL
+  AND ($2::bigint IS NULL OR feeds.seq = $2::bigint)
+  AND ($3::text IS NULL OR folders.name = $3::text)
+  AND (NOT $4::boolean OR post_states.read_at IS NOT NULL)
+  AND (NOT $5::boolean OR post_states.read_at IS NULL)
+  AND (NOT $6::boolean OR post_states.starred_at IS NOT NULL)
+  AND (NOT $7::boolean OR post_states.starred_at IS NULL)
+  AND ($8::timestamp IS NULL OR posts.created_at >= $8::timestamp)
+  AND ($9::timestamp IS NULL OR posts.created_at < $9::timestamp)
+  AND ($10::bigint IS NULL
+       OR ($11::boolean AND posts.seq > $10::bigint)
+       OR (NOT $11::boolean AND posts.seq < $10::bigint))
+ORDER BY
+    CASE WHEN $11::boolean THEN posts.seq END,
+    posts.seq DESC
+LIMIT $12
+`
+
+type GetReaderItemIDsParams struct {
+	UserID         uuid.UUID
+	FeedSeq        sql.NullInt64
+	FolderName     sql.NullString
+	ReadOnly       bool
+	ExcludeRead    bool
+	StarredOnly    bool
+	ExcludeStarred bool
+	NewerThan      sql.NullTime
+	OlderThan      sql.NullTime
+	Continuation   sql.NullInt64
+	OldestFirst    bool
+	PageLimit      int32
+}
+
+type GetReaderItemIDsRow struct {
+	Seq       int64
+	CreatedAt time.Time
+}
+
+// Same stream filters as GetReaderItems, without the content.
+func (q *Queries) GetReaderItemIDs(ctx context.Context, arg GetReaderItemIDsParams) ([]GetReaderItemIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReaderItemIDs,
+		arg.UserID,
+		arg.FeedSeq,
+		arg.FolderName,
+		arg.ReadOnly,
+		arg.ExcludeRead,
+		arg.StarredOnly,
+		arg.ExcludeStarred,
+		arg.NewerThan,
+		arg.OlderThan,
+		arg.Continuation,
+		arg.OldestFirst,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReaderItemIDsRow
+	for rows.Next() {
+		var i GetReaderItemIDsRow
+		if err := rows.Scan(&i.Seq, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReaderItems = `-- name: GetReaderItems :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.description, posts.published_at, posts.url, posts.feed_id, posts.author, posts.seq, posts.stream_xid,
+       feeds.seq AS feed_seq,
+       COALESCE(feed_follows.title, feeds.name)::text AS feed_title,
+       feeds.site_url AS feed_site_url,
+       folders.name AS folder_name,
+       (post_states.read_at IS NOT NULL)::boolean AS is_read,
+       (post_states.starred_at IS NOT NULL)::boolean AS is_starred
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+JOIN feeds ON feeds.id = posts.feed_id
+LEFT JOIN folders ON folders.id = feed_follows.folder_id
+LEFT JOIN post_states
+  ON post_states.post_id = posts.id
+ AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = $1
+  AND post_states.hidden_at IS NULL
+  AND ($2::bigint IS NULL OR feeds.seq = $2::bigint)
+  AND ($3::text IS NULL OR folders.name = $3::text)
+  AND (NOT $4::boolean OR post_states.read_at IS NOT NULL)
+  AND (NOT $5::boolean OR post_states.read_at IS NULL)
+  AND (NOT $6::boolean OR post_states.starred_at IS NOT NULL)
+  AND (NOT $7::boolean OR post_states.starred_at IS NULL)
+  AND ($8::timestamp IS NULL OR posts.created_at >= $8::timestamp)
+  AND ($9::timestamp IS NULL OR posts.created_at < $9::timestamp)
+  AND ($10::bigint IS NULL
+       OR ($11::boolean AND posts.seq > $10::bigint)
+       OR (NOT $11::boolean AND posts.seq < $10::bigint))
+ORDER BY
+    CASE WHEN $11::boolean THEN posts.seq END,
+    posts.seq DESC
+LIMIT $12
+`
+
+type GetReaderItemsParams struct {
+	UserID         uuid.UUID
+	FeedSeq        sql.NullInt64
+	FolderName     sql.NullString
+	ReadOnly       bool
+	ExcludeRead    bool
+	StarredOnly    bool
+	ExcludeStarred bool
+	NewerThan      sql.NullTime
+	OlderThan      sql.NullTime
+	Continuation   sql.NullInt64
+	OldestFirst    bool
+	PageLimit      int32
+}
+
+type GetReaderItemsRow struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Title       string
+	Description sql.NullString
+	PublishedAt time.Time
+	Url         string
+	FeedID      uuid.UUID
+	Author      sql.NullString
+	Seq         int64
+	StreamXid   sql.NullInt64
+	FeedSeq     int64
+	FeedTitle   string
+	FeedSiteUrl sql.NullString
+	FolderName  sql.NullString
+	IsRead      bool
+	IsStarred   bool
+}
+
+// A page of a stream, newest first unless oldest_first. The continuation
+// is the seq of the last item of the previous page.
+func (q *Queries) GetReaderItems(ctx context.Context, arg GetReaderItemsParams) ([]GetReaderItemsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReaderItems,
+		arg.UserID,
+		arg.FeedSeq,
+		arg.FolderName,
+		arg.ReadOnly,
+		arg.ExcludeRead,
+		arg.StarredOnly,
+		arg.ExcludeStarred,
+		arg.NewerThan,
+		arg.OlderThan,
+		arg.Continuation,
+		arg.OldestFirst,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReaderItemsRow
+	for rows.Next() {
+		var i GetReaderItemsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Description,
+			&i.PublishedAt,
+			&i.Url,
+			&i.FeedID,
+			&i.Author,
+			&i.Seq,
+			&i.StreamXid,
+			&i.FeedSeq,
+			&i.FeedTitle,
+			&i.FeedSiteUrl,
+			&i.FolderName,
+			&i.IsRead,
+			&i.IsStarred,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReaderItemsByIDs = `-- name: GetReaderItemsByIDs :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.description, posts.published_at, posts.url, posts.feed_id, posts.author, posts.seq, posts.stream_xid,
+       feeds.seq AS feed_seq,
+       COALESCE(feed_follows.title, feeds.name)::text AS feed_title,
+       feeds.site_url AS feed_site_url,
+       folders.name AS folder_name,
+       (post_states.read_at IS NOT NULL)::boolean AS is_read,
+       (post_states.starred_at IS NOT NULL)::boolean AS is_starred
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+JOIN feeds ON feeds.id = posts.feed_id
+LEFT JOIN folders ON folders.id = feed_follows.folder_id
+LEFT JOIN post_states
+  ON post_states.post_id = posts.id
+ AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = $1
+  AND posts.seq = ANY($2::bigint[])
+ORDER BY posts.seq DESC
+`
+
+type GetReaderItemsByIDsParams struct {
+	UserID uuid.UUID
+	Ids    []int64
+}
+
+type GetReaderItemsByIDsRow struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Title       string
+	Description sql.NullString
+	PublishedAt time.Time
+	Url         string
+	FeedID      uuid.UUID
+	Author      sql.NullString
+	Seq         int64
+	StreamXid   sql.NullInt64
+	FeedSeq     int64
+	FeedTitle   string
+	FeedSiteUrl sql.NullString
+	FolderName  sql.NullString
+	IsRead      bool
+	IsStarred   bool
+}
+
+func (q *Queries) GetReaderItemsByIDs(ctx context.Context, arg GetReaderItemsByIDsParams) ([]GetReaderItemsByIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReaderItemsByIDs, arg.UserID, pq.Array(arg.Ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReaderItemsByIDsRow
+	for rows.Next() {
+		var i GetReaderItemsByIDsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Description,
+			&i.PublishedAt,
+			&i.Url,
+			&i.FeedID,
+			&i.Author,
+			&i.Seq,
+			&i.StreamXid,
+			&i.FeedSeq,
+			&i.FeedTitle,
+			&i.FeedSiteUrl,
+			&i.FolderName,
+			&i.IsRead,
+			&i.IsStarred,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReaderSubscription = `-- name: GetReaderSubscription :one
+SELECT feed_follows.id AS feed_follow_id, folders.name AS folder_name
+FROM feed_follows
+JOIN feeds ON feeds.id = feed_follows.feed_id
+LEFT JOIN folders ON folders.id = feed_follows.folder_id
+WHERE feed_follows.user_id = $1 AND feeds.seq = $2
+`
+
+type GetReaderSubscriptionParams struct {
+	UserID uuid.UUID
+	Seq    int64
+}
+
+type GetReaderSubscriptionRow struct {
+	FeedFollowID uuid.UUID
+	FolderName   sql.NullString
+}
+
+func (q *Queries) GetReaderSubscription(ctx context.Context, arg GetReaderSubscriptionParams) (GetReaderSubscriptionRow, error) {
+	row := q.db.QueryRowContext(ctx, getReaderSubscription, arg.UserID, arg.Seq)
+	var i GetReaderSubscriptionRow
+	err := row.Scan(&i.FeedFollowID, &i.FolderName)
+	return i, err
+}
+
+const getReaderSubscriptions = `-- name: GetReaderSubscriptions :many
+SELECT feed_follows.id AS feed_follow_id,
+       feeds.seq,
+       COALESCE(feed_follows.title, feeds.name)::text AS title,
+       feeds.url,
+       feeds.site_url,
+       folders.name AS folder_name,
+       feed_follows.created_at
+FROM feed_follows
+JOIN feeds ON feeds.id = feed_follows.feed_id
+LEFT JOIN folders ON folders.id = feed_follows.folder_id
+WHERE feed_follows.user_id = $1
+ORDER BY title
+`
+
+type GetReaderSubscriptionsRow struct {
+	FeedFollowID uuid.UUID
+	Seq          int64
+	Title        string
+	Url          string
+	SiteUrl      sql.NullString
+	FolderName   sql.NullString
+	CreatedAt    time.Time
+}
+
+func (q *Queries) GetReaderSubscriptions(ctx context.Context, userID uuid.UUID) ([]GetReaderSubscriptionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReaderSubscriptions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReaderSubscriptionsRow
+	for rows.Next() {
+		var i GetReaderSubscriptionsRow
+		if err := rows.Scan(
+			&i.FeedFollowID,
+			&i.Seq,
+			&i.Title,
+			&i.Url,
+			&i.SiteUrl,
+			&i.FolderName,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setReaderItemsRead = `-- name: SetReaderItemsRead :exec
+INSERT INTO post_states(user_id, post_id, created_at, updated_at, read_at)
+SELECT feed_follows.user_id, posts.id, NOW(), NOW(),
+       CASE WHEN $1::boolean THEN NOW() END
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+WHERE feed_follows.user_id = $2
+  AND posts.seq = ANY($3::bigint[])
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read_at = CASE WHEN $1::boolean THEN COALESCE(post_states.read_at, NOW()) END,
+    updated_at = NOW()
+`
+
+type SetReaderItemsReadParams struct {
+	Read   bool
+	UserID uuid.UUID
+	Ids    []int64
+}
+
+func (q *Queries) SetReaderItemsRead(ctx context.Context, arg SetReaderItemsReadParams) error {
+	_, err := q.db.ExecContext(ctx, setReaderItemsRead, arg.Read, arg.UserID, pq.Array(arg.Ids))
+	return err
+}
+
+const setReaderItemsStarred = `-- name: SetReaderItemsStarred :exec
+INSERT INTO post_states(user_id, post_id, created_at, updated_at, starred_at)
+SELECT feed_follows.user_id, posts.id, NOW(), NOW(),
+       CASE WHEN $1::boolean THEN NOW() END
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+WHERE feed_follows.user_id = $2
+  AND posts.seq = ANY($3::bigint[])
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET starred_at = CASE WHEN $1::boolean THEN COALESCE(post_states.starred_at, NOW()) END,
+    updated_at = NOW()
+`
+
+type SetReaderItemsStarredParams struct {
+	Starred bool
+	UserID  uuid.UUID
+	Ids     []int64
+}
+
+func (q *Queries) SetReaderItemsStarred(ctx context.Context, arg SetReaderItemsStarredParams) error {
+	_, err := q.db.ExecContext(ctx, setReaderItemsStarred, arg.Starred, arg.UserID, pq.Array(arg.Ids))
+	return err
+}
@@ -0,0 +1,193 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: scraper.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const beginScraperRun = `-- name: BeginScraperRun :exec
+INSERT INTO scraper_heartbeats(worker_id, started_at, last_beat_at, last_run_started_at, last_run_feeds)
+VALUES($1, $2, NOW(), NOW(), $3)
+ON CONFLICT (worker_id) DO UPDATE
+SET started_at = EXCLUDED.started_at,
+    last_beat_at = NOW(),
+    last_run_started_at = NOW(),
+    last_run_feeds = EXCLUDED.last_run_feeds
+`
+
+type BeginScraperRunParams struct {
+	WorkerID     string
+	StartedAt    time.Time
+	LastRunFeeds int32
+}
+
+func (q *Queries) BeginScraperRun(ctx context.Context, arg BeginScraperRunParams) error {
+	_, err := q.db.ExecContext(ctx, beginScraperRun, arg.WorkerID, arg.StartedAt, arg.LastRunFeeds)
+	return err
+}
+
+const finishScraperRun = `-- name: FinishScraperRun :exec
+UPDATE scraper_heartbeats
+SET last_beat_at = NOW(), last_run_finished_at = NOW()
+WHERE worker_id = $1
+`
+
+func (q *Queries) FinishScraperRun(ctx context.Context, workerID string) error {
+	_, err := q.db.ExecContext(ctx, finishScraperRun, workerID)
+	return err
+}
+
+const getFailingFeeds = `-- name: GetFailingFeeds :many
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, site_title, seq, site_url, favicon, favicon_checked_at, disabled_at, last_fetch_error FROM feeds
+WHERE disabled_at IS NULL AND last_fetch_error IS NOT NULL
+ORDER BY last_fetched_at DESC
+LIMIT $1
+`
+
+func (q *Queries) GetFailingFeeds(ctx context.Context, limit int32) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getFailingFeeds, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.LastFetchedAt,
+			&i.SiteTitle,
+			&i.Seq,
+			&i.SiteUrl,
+			&i.Favicon,
+			&i.FaviconCheckedAt,
+			&i.DisabledAt,
+			&i.LastFetchError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNextDueSince = `-- name: GetNextDueSince :one
+SELECT COALESCE(feeds.last_fetched_at, feeds.created_at)::timestamp AS due_since
+FROM feeds
+LEFT JOIN websub_subscriptions
+  ON websub_subscriptions.feed_id = feeds.id
+ AND websub_subscriptions.state = 'active'
+ AND websub_subscriptions.expires_at > NOW()
+WHERE feeds.disabled_at IS NULL
+  AND (
+        websub_subscriptions.id IS NULL
+     OR feeds.last_fetched_at IS NULL
+     OR feeds.last_fetched_at < NOW() - INTERVAL '6 hours'
+  )
+ORDER BY feeds.last_fetched_at NULLS FIRST
+LIMIT 1
+`
+
+// Since when the feed next in line to be fetched has waited: its last
+// fetch, or its creation if it was never fetched. Mirrors
+// GetNextFeedsToFetch.
+func (q *Queries) GetNextDueSince(ctx context.Context) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, getNextDueSince)
+	var due_since time.Time
+	err := row.Scan(&due_since)
+	return due_since, err
+}
+
+const getOldestFetchedAt = `-- name: GetOldestFetchedAt :one
+SELECT last_fetched_at FROM feeds
+WHERE disabled_at IS NULL AND last_fetched_at IS NOT NULL
+ORDER BY last_fetched_at
+LIMIT 1
+`
+
+// How far behind the scraper is: the longest any enabled feed has gone
+// without a fetch.
+func (q *Queries) GetOldestFetchedAt(ctx context.Context) (sql.NullTime, error) {
+	row := q.db.QueryRowContext(ctx, getOldestFetchedAt)
+	var last_fetched_at sql.NullTime
+	err := row.Scan(&last_fetched_at)
+	return last_fetched_at, err
+}
+
+const getScraperFeedStats = `-- name: GetScraperFeedStats :one
+SELECT COUNT(*) AS total,
+       COUNT(*) FILTER (WHERE disabled_at IS NOT NULL) AS disabled,
+       COUNT(*) FILTER (WHERE disabled_at IS NULL AND last_fetched_at IS NULL) AS never_fetched,
+       COUNT(*) FILTER (WHERE disabled_at IS NULL AND last_fetch_error IS NOT NULL) AS failing
+FROM feeds
+`
+
+type GetScraperFeedStatsRow struct {
+	Total        int64
+	Disabled     int64
+	NeverFetched int64
+	Failing      int64
+}
+
+func (q *Queries) GetScraperFeedStats(ctx context.Context) (GetScraperFeedStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getScraperFeedStats)
+	var i GetScraperFeedStatsRow
+	err := row.Scan(
+		&i.Total,
+		&i.Disabled,
+		&i.NeverFetched,
+		&i.Failing,
+	)
+	return i, err
+}
+
+const getScraperHeartbeats = `-- name: GetScraperHeartbeats :many
+SELECT worker_id, started_at, last_beat_at, last_run_started_at, last_run_finished_at, last_run_feeds FROM scraper_heartbeats ORDER BY last_beat_at DESC
+`
+
+func (q *Queries) GetScraperHeartbeats(ctx context.Context) ([]ScraperHeartbeat, error) {
+	rows, err := q.db.QueryContext(ctx, getScraperHeartbeats)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ScraperHeartbeat
+	for rows.Next() {
+		var i ScraperHeartbeat
+		if err := rows.Scan(
+			&i.WorkerID,
+			&i.StartedAt,
+			&i.LastBeatAt,
+			&i.LastRunStartedAt,
+			&i.LastRunFinishedAt,
+			&i.LastRunFeeds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
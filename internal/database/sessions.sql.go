@@ -0,0 +1,196 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: sessions.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const consumePasswordReset = `-- name: ConsumePasswordReset :one
+UPDATE password_resets
+SET used_at = NOW()
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING id, created_at, expires_at, user_id, created_by, token_hash, used_at
+`
+
+func (q *Queries) ConsumePasswordReset(ctx context.Context, tokenHash string) (PasswordReset, error) {
+	row := q.db.QueryRowContext(ctx, consumePasswordReset, tokenHash)
+	var i PasswordReset
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.UserID,
+		&i.CreatedBy,
+		&i.TokenHash,
+		&i.UsedAt,
+	)
+	return i, err
+}
+
+const createPasswordReset = `-- name: CreatePasswordReset :one
+INSERT INTO password_resets(id, created_at, expires_at, user_id, created_by, token_hash)
+VALUES($1, $2, $3, $4, $5, $6)
+RETURNING id, created_at, expires_at, user_id, created_by, token_hash, used_at
+`
+
+type CreatePasswordResetParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UserID    uuid.UUID
+	CreatedBy uuid.NullUUID
+	TokenHash string
+}
+
+func (q *Queries) CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error) {
+	row := q.db.QueryRowContext(ctx, createPasswordReset,
+		arg.ID,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+		arg.UserID,
+		arg.CreatedBy,
+		arg.TokenHash,
+	)
+	var i PasswordReset
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.UserID,
+		&i.CreatedBy,
+		&i.TokenHash,
+		&i.UsedAt,
+	)
+	return i, err
+}
+
+const createSession = `-- name: CreateSession :one
+INSERT INTO sessions(id, created_at, last_seen_at, expires_at, user_id, token_hash, csrf_token, user_agent, ip)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, created_at, last_seen_at, expires_at, user_id, token_hash, csrf_token, user_agent, ip
+`
+
+type CreateSessionParams struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	UserID     uuid.UUID
+	TokenHash  string
+	CsrfToken  string
+	UserAgent  string
+	Ip         string
+}
+
+func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
+	row := q.db.QueryRowContext(ctx, createSession,
+		arg.ID,
+		arg.CreatedAt,
+		arg.LastSeenAt,
+		arg.ExpiresAt,
+		arg.UserID,
+		arg.TokenHash,
+		arg.CsrfToken,
+		arg.UserAgent,
+		arg.Ip,
+	)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.LastSeenAt,
+		&i.ExpiresAt,
+		&i.UserID,
+		&i.TokenHash,
+		&i.CsrfToken,
+		&i.UserAgent,
+		&i.Ip,
+	)
+	return i, err
+}
+
+const deleteSession = `-- name: DeleteSession :exec
+DELETE FROM sessions WHERE id = $1
+`
+
+func (q *Queries) DeleteSession(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteSession, id)
+	return err
+}
+
+const deleteUserSessions = `-- name: DeleteUserSessions :exec
+DELETE FROM sessions
+WHERE user_id = $1
+  AND ($2::uuid IS NULL OR id <> $2::uuid)
+`
+
+type DeleteUserSessionsParams struct {
+	UserID uuid.UUID
+	KeepID uuid.NullUUID
+}
+
+// Signs a user out everywhere except, if given, the session keep_id.
+func (q *Queries) DeleteUserSessions(ctx context.Context, arg DeleteUserSessionsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteUserSessions, arg.UserID, arg.KeepID)
+	return err
+}
+
+const getActiveSessionByHash = `-- name: GetActiveSessionByHash :one
+SELECT sessions.id, sessions.created_at, sessions.last_seen_at, sessions.expires_at, sessions.user_id, sessions.token_hash, sessions.csrf_token, sessions.user_agent, sessions.ip, users.id, users.created_at, users.updated_at, users.name, users.username, users.email, users.password_hash, users.password_changed_at, users.role, users.disabled_at
+FROM sessions
+JOIN users ON users.id = sessions.user_id
+WHERE sessions.token_hash = $1
+  AND sessions.expires_at > NOW()
+  AND users.disabled_at IS NULL
+`
+
+type GetActiveSessionByHashRow struct {
+	Session Session
+	User    User
+}
+
+func (q *Queries) GetActiveSessionByHash(ctx context.Context, tokenHash string) (GetActiveSessionByHashRow, error) {
+	row := q.db.QueryRowContext(ctx, getActiveSessionByHash, tokenHash)
+	var i GetActiveSessionByHashRow
+	err := row.Scan(
+		&i.Session.ID,
+		&i.Session.CreatedAt,
+		&i.Session.LastSeenAt,
+		&i.Session.ExpiresAt,
+		&i.Session.UserID,
+		&i.Session.TokenHash,
+		&i.Session.CsrfToken,
+		&i.Session.UserAgent,
+		&i.Session.Ip,
+		&i.User.ID,
+		&i.User.CreatedAt,
+		&i.User.UpdatedAt,
+		&i.User.Name,
+		&i.User.Username,
+		&i.User.Email,
+		&i.User.PasswordHash,
+		&i.User.PasswordChangedAt,
+		&i.User.Role,
+		&i.User.DisabledAt,
+	)
+	return i, err
+}
+
+const touchSession = `-- name: TouchSession :exec
+UPDATE sessions
+SET last_seen_at = NOW()
+WHERE id = $1 AND last_seen_at < NOW() - INTERVAL '1 minute'
+`
+
+// Like API keys, last_seen_at is only kept to the minute.
+func (q *Queries) TouchSession(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, touchSession, id)
+	return err
+}
@@ -0,0 +1,248 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: tags.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const addPostTag = `-- name: AddPostTag :exec
+INSERT INTO post_tags(tag_id, post_id, created_at)
+VALUES($1, $2, NOW())
+ON CONFLICT (tag_id, post_id) DO NOTHING
+`
+
+type AddPostTagParams struct {
+	TagID  uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) AddPostTag(ctx context.Context, arg AddPostTagParams) error {
+	_, err := q.db.ExecContext(ctx, addPostTag, arg.TagID, arg.PostID)
+	return err
+}
+
+const createTag = `-- name: CreateTag :one
+INSERT INTO tags(id, created_at, updated_at, user_id, name)
+VALUES($1, $2, $3, $4, $5)
+RETURNING id, created_at, updated_at, user_id, name
+`
+
+type CreateTagParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	Name      string
+}
+
+func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, createTag,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UserID,
+		arg.Name,
+	)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+	)
+	return i, err
+}
+
+const deleteTag = `-- name: DeleteTag :execrows
+DELETE FROM tags WHERE id = $1 AND user_id = $2
+`
+
+type DeleteTagParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteTag(ctx context.Context, arg DeleteTagParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTag, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getTag = `-- name: GetTag :one
+SELECT id, created_at, updated_at, user_id, name FROM tags WHERE id = $1 AND user_id = $2
+`
+
+type GetTagParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetTag(ctx context.Context, arg GetTagParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, getTag, arg.ID, arg.UserID)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+	)
+	return i, err
+}
+
+const getTagByName = `-- name: GetTagByName :one
+SELECT id, created_at, updated_at, user_id, name FROM tags WHERE user_id = $1 AND name = $2
+`
+
+type GetTagByNameParams struct {
+	UserID uuid.UUID
+	Name   string
+}
+
+func (q *Queries) GetTagByName(ctx context.Context, arg GetTagByNameParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, getTagByName, arg.UserID, arg.Name)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+	)
+	return i, err
+}
+
+const getTags = `-- name: GetTags :many
+SELECT tags.id, tags.created_at, tags.updated_at, tags.user_id, tags.name, COUNT(post_tags.post_id) AS post_count
+FROM tags
+LEFT JOIN post_tags ON post_tags.tag_id = tags.id
+WHERE tags.user_id = $1
+GROUP BY tags.id
+ORDER BY tags.name
+`
+
+type GetTagsRow struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	Name      string
+	PostCount int64
+}
+
+func (q *Queries) GetTags(ctx context.Context, userID uuid.UUID) ([]GetTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTags, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTagsRow
+	for rows.Next() {
+		var i GetTagsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.Name,
+			&i.PostCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removePostTag = `-- name: RemovePostTag :execrows
+DELETE FROM post_tags WHERE tag_id = $1 AND post_id = $2
+`
+
+type RemovePostTagParams struct {
+	TagID  uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) RemovePostTag(ctx context.Context, arg RemovePostTagParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removePostTag, arg.TagID, arg.PostID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const renameTag = `-- name: RenameTag :one
+UPDATE tags
+SET name = $3, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING id, created_at, updated_at, user_id, name
+`
+
+type RenameTagParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	Name   string
+}
+
+func (q *Queries) RenameTag(ctx context.Context, arg RenameTagParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, renameTag, arg.ID, arg.UserID, arg.Name)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+	)
+	return i, err
+}
+
+const upsertTag = `-- name: UpsertTag :one
+INSERT INTO tags(id, created_at, updated_at, user_id, name)
+VALUES($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name
+RETURNING id, created_at, updated_at, user_id, name
+`
+
+type UpsertTagParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	Name      string
+}
+
+func (q *Queries) UpsertTag(ctx context.Context, arg UpsertTagParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, upsertTag,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UserID,
+		arg.Name,
+	)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+	)
+	return i, err
+}
@@ -0,0 +1,111 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: user_identities.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createUserIdentity = `-- name: CreateUserIdentity :one
+INSERT INTO user_identities(id, created_at, last_login_at, user_id, issuer, subject, email)
+VALUES($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, created_at, last_login_at, user_id, issuer, subject, email
+`
+
+type CreateUserIdentityParams struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	LastLoginAt time.Time
+	UserID      uuid.UUID
+	Issuer      string
+	Subject     string
+	Email       sql.NullString
+}
+
+func (q *Queries) CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error) {
+	row := q.db.QueryRowContext(ctx, createUserIdentity,
+		arg.ID,
+		arg.CreatedAt,
+		arg.LastLoginAt,
+		arg.UserID,
+		arg.Issuer,
+		arg.Subject,
+		arg.Email,
+	)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.LastLoginAt,
+		&i.UserID,
+		&i.Issuer,
+		&i.Subject,
+		&i.Email,
+	)
+	return i, err
+}
+
+const getUserByIdentity = `-- name: GetUserByIdentity :one
+SELECT user_identities.id, user_identities.created_at, user_identities.last_login_at, user_identities.user_id, user_identities.issuer, user_identities.subject, user_identities.email, users.id, users.created_at, users.updated_at, users.name, users.username, users.email, users.password_hash, users.password_changed_at, users.role, users.disabled_at
+FROM user_identities
+JOIN users ON users.id = user_identities.user_id
+WHERE user_identities.issuer = $1 AND user_identities.subject = $2
+`
+
+type GetUserByIdentityParams struct {
+	Issuer  string
+	Subject string
+}
+
+type GetUserByIdentityRow struct {
+	UserIdentity UserIdentity
+	User         User
+}
+
+func (q *Queries) GetUserByIdentity(ctx context.Context, arg GetUserByIdentityParams) (GetUserByIdentityRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserByIdentity, arg.Issuer, arg.Subject)
+	var i GetUserByIdentityRow
+	err := row.Scan(
+		&i.UserIdentity.ID,
+		&i.UserIdentity.CreatedAt,
+		&i.UserIdentity.LastLoginAt,
+		&i.UserIdentity.UserID,
+		&i.UserIdentity.Issuer,
+		&i.UserIdentity.Subject,
+		&i.UserIdentity.Email,
+		&i.User.ID,
+		&i.User.CreatedAt,
+		&i.User.UpdatedAt,
+		&i.User.Name,
+		&i.User.Username,
+		&i.User.Email,
+		&i.User.PasswordHash,
+		&i.User.PasswordChangedAt,
+		&i.User.Role,
+		&i.User.DisabledAt,
+	)
+	return i, err
+}
+
+const touchUserIdentity = `-- name: TouchUserIdentity :exec
+UPDATE user_identities
+SET last_login_at = NOW(), email = $2
+WHERE id = $1
+`
+
+type TouchUserIdentityParams struct {
+	ID    uuid.UUID
+	Email sql.NullString
+}
+
+func (q *Queries) TouchUserIdentity(ctx context.Context, arg TouchUserIdentityParams) error {
+	_, err := q.db.ExecContext(ctx, touchUserIdentity, arg.ID, arg.Email)
+	return err
+}
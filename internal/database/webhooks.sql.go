@@ -0,0 +1,565 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: webhooks.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const addWebhookDigestPost = `-- name: AddWebhookDigestPost :exec
+INSERT INTO webhook_digest_posts(webhook_id, post_id, created_at)
+VALUES($1, $2, $3)
+ON CONFLICT DO NOTHING
+`
+
+type AddWebhookDigestPostParams struct {
+	WebhookID uuid.UUID
+	PostID    uuid.UUID
+	CreatedAt time.Time
+}
+
+func (q *Queries) AddWebhookDigestPost(ctx context.Context, arg AddWebhookDigestPostParams) error {
+	_, err := q.db.ExecContext(ctx, addWebhookDigestPost, arg.WebhookID, arg.PostID, arg.CreatedAt)
+	return err
+}
+
+const claimDueWebhookDeliveries = `-- name: ClaimDueWebhookDeliveries :many
+UPDATE webhook_deliveries
+SET next_attempt_at = NOW() + $1::int * INTERVAL '1 second'
+WHERE id IN (
+    SELECT id FROM webhook_deliveries
+    WHERE status = 'pending' AND next_attempt_at <= NOW()
+    ORDER BY next_attempt_at
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, created_at, updated_at, webhook_id, post_id, event, payload, status, attempts, next_attempt_at, last_attempt_at, response_status, last_error
+`
+
+type ClaimDueWebhookDeliveriesParams struct {
+	LeaseSeconds int32
+	BatchSize    int32
+}
+
+// Leases due deliveries by pushing next_attempt_at forward, so concurrent
+// workers skip them; the lease lapses on its own if a worker dies mid-send.
+func (q *Queries) ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, claimDueWebhookDeliveries, arg.LeaseSeconds, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WebhookID,
+			&i.PostID,
+			&i.Event,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastAttemptAt,
+			&i.ResponseStatus,
+			&i.LastError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const claimWebhookDigestPosts = `-- name: ClaimWebhookDigestPosts :many
+WITH claimed AS (
+    DELETE FROM webhook_digest_posts
+    WHERE webhook_id = $1
+    RETURNING post_id, created_at
+)
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.description, posts.published_at, posts.url, posts.feed_id, posts.author, posts.seq, posts.stream_xid, feeds.id, feeds.created_at, feeds.updated_at, feeds.name, feeds.url, feeds.user_id, feeds.last_fetched_at, feeds.site_title, feeds.seq, feeds.site_url, feeds.favicon, feeds.favicon_checked_at, feeds.disabled_at, feeds.last_fetch_error, claimed.created_at AS queued_at
+FROM claimed
+JOIN posts ON posts.id = claimed.post_id
+JOIN feeds ON feeds.id = posts.feed_id
+ORDER BY posts.published_at
+`
+
+type ClaimWebhookDigestPostsRow struct {
+	Post     Post
+	Feed     Feed
+	QueuedAt time.Time
+}
+
+// Takes a webhook's waiting posts out of the digest, so a concurrent worker
+// can't send them again.
+func (q *Queries) ClaimWebhookDigestPosts(ctx context.Context, webhookID uuid.UUID) ([]ClaimWebhookDigestPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, claimWebhookDigestPosts, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimWebhookDigestPostsRow
+	for rows.Next() {
+		var i ClaimWebhookDigestPostsRow
+		if err := rows.Scan(
+			&i.Post.ID,
+			&i.Post.CreatedAt,
+			&i.Post.UpdatedAt,
+			&i.Post.Title,
+			&i.Post.Description,
+			&i.Post.PublishedAt,
+			&i.Post.Url,
+			&i.Post.FeedID,
+			&i.Post.Author,
+			&i.Post.Seq,
+			&i.Post.StreamXid,
+			&i.Feed.ID,
+			&i.Feed.CreatedAt,
+			&i.Feed.UpdatedAt,
+			&i.Feed.Name,
+			&i.Feed.Url,
+			&i.Feed.UserID,
+			&i.Feed.LastFetchedAt,
+			&i.Feed.SiteTitle,
+			&i.Feed.Seq,
+			&i.Feed.SiteUrl,
+			&i.Feed.Favicon,
+			&i.Feed.FaviconCheckedAt,
+			&i.Feed.DisabledAt,
+			&i.Feed.LastFetchError,
+			&i.QueuedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createWebhook = `-- name: CreateWebhook :one
+INSERT INTO webhooks(
+    id, created_at, updated_at, user_id, name, url, secret, feed_id, folder_id, keyword, enabled
+)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id, created_at, updated_at, user_id, name, url, secret, feed_id, folder_id, keyword, enabled
+`
+
+type CreateWebhookParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	Name      string
+	Url       string
+	Secret    string
+	FeedID    uuid.NullUUID
+	FolderID  uuid.NullUUID
+	Keyword   sql.NullString
+	Enabled   bool
+}
+
+func (q *Queries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, createWebhook,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UserID,
+		arg.Name,
+		arg.Url,
+		arg.Secret,
+		arg.FeedID,
+		arg.FolderID,
+		arg.Keyword,
+		arg.Enabled,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+		&i.Url,
+		&i.Secret,
+		&i.FeedID,
+		&i.FolderID,
+		&i.Keyword,
+		&i.Enabled,
+	)
+	return i, err
+}
+
+const createWebhookDelivery = `-- name: CreateWebhookDelivery :execrows
+INSERT INTO webhook_deliveries(
+    id, created_at, updated_at, webhook_id, post_id, event, payload, next_attempt_at
+)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (webhook_id, post_id, event) DO NOTHING
+`
+
+type CreateWebhookDeliveryParams struct {
+	ID            uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	WebhookID     uuid.UUID
+	PostID        uuid.NullUUID
+	Event         string
+	Payload       string
+	NextAttemptAt time.Time
+}
+
+func (q *Queries) CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createWebhookDelivery,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.WebhookID,
+		arg.PostID,
+		arg.Event,
+		arg.Payload,
+		arg.NextAttemptAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteWebhook = `-- name: DeleteWebhook :execrows
+DELETE FROM webhooks WHERE id = $1 AND user_id = $2
+`
+
+type DeleteWebhookParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteWebhook(ctx context.Context, arg DeleteWebhookParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteWebhook, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getDueWebhookDigests = `-- name: GetDueWebhookDigests :many
+SELECT webhook_id
+FROM webhook_digest_posts
+GROUP BY webhook_id
+HAVING MIN(created_at) <= $1::timestamp
+LIMIT $2
+`
+
+type GetDueWebhookDigestsParams struct {
+	DueBefore time.Time
+	BatchSize int32
+}
+
+// Webhooks whose oldest waiting post has waited a whole digest interval.
+func (q *Queries) GetDueWebhookDigests(ctx context.Context, arg GetDueWebhookDigestsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getDueWebhookDigests, arg.DueBefore, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var webhook_id uuid.UUID
+		if err := rows.Scan(&webhook_id); err != nil {
+			return nil, err
+		}
+		items = append(items, webhook_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWebhook = `-- name: GetWebhook :one
+SELECT id, created_at, updated_at, user_id, name, url, secret, feed_id, folder_id, keyword, enabled FROM webhooks WHERE id = $1 AND user_id = $2
+`
+
+type GetWebhookParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetWebhook(ctx context.Context, arg GetWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, getWebhook, arg.ID, arg.UserID)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+		&i.Url,
+		&i.Secret,
+		&i.FeedID,
+		&i.FolderID,
+		&i.Keyword,
+		&i.Enabled,
+	)
+	return i, err
+}
+
+const getWebhookByID = `-- name: GetWebhookByID :one
+SELECT id, created_at, updated_at, user_id, name, url, secret, feed_id, folder_id, keyword, enabled FROM webhooks WHERE id = $1
+`
+
+func (q *Queries) GetWebhookByID(ctx context.Context, id uuid.UUID) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookByID, id)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+		&i.Url,
+		&i.Secret,
+		&i.FeedID,
+		&i.FolderID,
+		&i.Keyword,
+		&i.Enabled,
+	)
+	return i, err
+}
+
+const getWebhookDeliveries = `-- name: GetWebhookDeliveries :many
+SELECT webhook_deliveries.id, webhook_deliveries.created_at, webhook_deliveries.updated_at, webhook_deliveries.webhook_id, webhook_deliveries.post_id, webhook_deliveries.event, webhook_deliveries.payload, webhook_deliveries.status, webhook_deliveries.attempts, webhook_deliveries.next_attempt_at, webhook_deliveries.last_attempt_at, webhook_deliveries.response_status, webhook_deliveries.last_error
+FROM webhook_deliveries
+JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+WHERE webhook_deliveries.webhook_id = $1
+  AND webhooks.user_id = $2
+ORDER BY webhook_deliveries.created_at DESC
+LIMIT $4 OFFSET $3
+`
+
+type GetWebhookDeliveriesParams struct {
+	WebhookID  uuid.UUID
+	UserID     uuid.UUID
+	PageOffset int32
+	PageLimit  int32
+}
+
+func (q *Queries) GetWebhookDeliveries(ctx context.Context, arg GetWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhookDeliveries,
+		arg.WebhookID,
+		arg.UserID,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WebhookID,
+			&i.PostID,
+			&i.Event,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastAttemptAt,
+			&i.ResponseStatus,
+			&i.LastError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWebhooks = `-- name: GetWebhooks :many
+SELECT id, created_at, updated_at, user_id, name, url, secret, feed_id, folder_id, keyword, enabled FROM webhooks WHERE user_id = $1 ORDER BY created_at
+`
+
+func (q *Queries) GetWebhooks(ctx context.Context, userID uuid.UUID) ([]Webhook, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhooks, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.Name,
+			&i.Url,
+			&i.Secret,
+			&i.FeedID,
+			&i.FolderID,
+			&i.Keyword,
+			&i.Enabled,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWebhooksForFeed = `-- name: GetWebhooksForFeed :many
+SELECT webhooks.id, webhooks.created_at, webhooks.updated_at, webhooks.user_id, webhooks.name, webhooks.url, webhooks.secret, webhooks.feed_id, webhooks.folder_id, webhooks.keyword, webhooks.enabled, feed_follows.notification_mode
+FROM webhooks
+JOIN feed_follows
+  ON feed_follows.user_id = webhooks.user_id
+ AND feed_follows.feed_id = $1
+WHERE webhooks.enabled
+  AND feed_follows.notification_mode <> 'off'
+  AND (webhooks.feed_id IS NULL OR webhooks.feed_id = $1)
+  AND (webhooks.folder_id IS NULL OR webhooks.folder_id = feed_follows.folder_id)
+ORDER BY webhooks.created_at
+`
+
+type GetWebhooksForFeedRow struct {
+	Webhook          Webhook
+	NotificationMode string
+}
+
+// Along with each webhook, how its owner wants to hear about the feed.
+func (q *Queries) GetWebhooksForFeed(ctx context.Context, feedID uuid.UUID) ([]GetWebhooksForFeedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhooksForFeed, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWebhooksForFeedRow
+	for rows.Next() {
+		var i GetWebhooksForFeedRow
+		if err := rows.Scan(
+			&i.Webhook.ID,
+			&i.Webhook.CreatedAt,
+			&i.Webhook.UpdatedAt,
+			&i.Webhook.UserID,
+			&i.Webhook.Name,
+			&i.Webhook.Url,
+			&i.Webhook.Secret,
+			&i.Webhook.FeedID,
+			&i.Webhook.FolderID,
+			&i.Webhook.Keyword,
+			&i.Webhook.Enabled,
+			&i.NotificationMode,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordWebhookDeliveryAttempt = `-- name: RecordWebhookDeliveryAttempt :exec
+UPDATE webhook_deliveries
+SET status = $2,
+    attempts = attempts + 1,
+    next_attempt_at = $3,
+    last_attempt_at = $4,
+    response_status = $5,
+    last_error = $6,
+    updated_at = $4
+WHERE id = $1
+`
+
+type RecordWebhookDeliveryAttemptParams struct {
+	ID             uuid.UUID
+	Status         string
+	NextAttemptAt  time.Time
+	LastAttemptAt  sql.NullTime
+	ResponseStatus sql.NullInt32
+	LastError      sql.NullString
+}
+
+func (q *Queries) RecordWebhookDeliveryAttempt(ctx context.Context, arg RecordWebhookDeliveryAttemptParams) error {
+	_, err := q.db.ExecContext(ctx, recordWebhookDeliveryAttempt,
+		arg.ID,
+		arg.Status,
+		arg.NextAttemptAt,
+		arg.LastAttemptAt,
+		arg.ResponseStatus,
+		arg.LastError,
+	)
+	return err
+}
+
+const requeueWebhookDelivery = `-- name: RequeueWebhookDelivery :execrows
+UPDATE webhook_deliveries
+SET status = 'pending',
+    attempts = 0,
+    next_attempt_at = NOW(),
+    last_error = NULL,
+    updated_at = NOW()
+FROM webhooks
+WHERE webhook_deliveries.id = $1
+  AND webhooks.id = webhook_deliveries.webhook_id
+  AND webhooks.user_id = $2
+`
+
+type RequeueWebhookDeliveryParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) RequeueWebhookDelivery(ctx context.Context, arg RequeueWebhookDeliveryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, requeueWebhookDelivery, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
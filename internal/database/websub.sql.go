@@ -0,0 +1,220 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: websub.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const activateWebSubSubscription = `-- name: ActivateWebSubSubscription :exec
+UPDATE websub_subscriptions
+SET state = 'active',
+    lease_seconds = $2,
+    expires_at = $3,
+    last_error = NULL,
+    updated_at = NOW()
+WHERE id = $1
+`
+
+type ActivateWebSubSubscriptionParams struct {
+	ID           uuid.UUID
+	LeaseSeconds sql.NullInt32
+	ExpiresAt    sql.NullTime
+}
+
+func (q *Queries) ActivateWebSubSubscription(ctx context.Context, arg ActivateWebSubSubscriptionParams) error {
+	_, err := q.db.ExecContext(ctx, activateWebSubSubscription, arg.ID, arg.LeaseSeconds, arg.ExpiresAt)
+	return err
+}
+
+const denyWebSubSubscription = `-- name: DenyWebSubSubscription :exec
+UPDATE websub_subscriptions
+SET state = 'denied', last_error = $2, expires_at = NULL, updated_at = NOW()
+WHERE id = $1
+`
+
+type DenyWebSubSubscriptionParams struct {
+	ID        uuid.UUID
+	LastError sql.NullString
+}
+
+func (q *Queries) DenyWebSubSubscription(ctx context.Context, arg DenyWebSubSubscriptionParams) error {
+	_, err := q.db.ExecContext(ctx, denyWebSubSubscription, arg.ID, arg.LastError)
+	return err
+}
+
+const getWebSubSubscription = `-- name: GetWebSubSubscription :one
+SELECT id, created_at, updated_at, feed_id, hub_url, topic_url, secret, state, lease_seconds, expires_at, last_error FROM websub_subscriptions WHERE id = $1
+`
+
+func (q *Queries) GetWebSubSubscription(ctx context.Context, id uuid.UUID) (WebsubSubscription, error) {
+	row := q.db.QueryRowContext(ctx, getWebSubSubscription, id)
+	var i WebsubSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FeedID,
+		&i.HubUrl,
+		&i.TopicUrl,
+		&i.Secret,
+		&i.State,
+		&i.LeaseSeconds,
+		&i.ExpiresAt,
+		&i.LastError,
+	)
+	return i, err
+}
+
+const getWebSubSubscriptionForFeed = `-- name: GetWebSubSubscriptionForFeed :one
+SELECT id, created_at, updated_at, feed_id, hub_url, topic_url, secret, state, lease_seconds, expires_at, last_error FROM websub_subscriptions WHERE feed_id = $1
+`
+
+func (q *Queries) GetWebSubSubscriptionForFeed(ctx context.Context, feedID uuid.UUID) (WebsubSubscription, error) {
+	row := q.db.QueryRowContext(ctx, getWebSubSubscriptionForFeed, feedID)
+	var i WebsubSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FeedID,
+		&i.HubUrl,
+		&i.TopicUrl,
+		&i.Secret,
+		&i.State,
+		&i.LeaseSeconds,
+		&i.ExpiresAt,
+		&i.LastError,
+	)
+	return i, err
+}
+
+const getWebSubSubscriptionsToRenew = `-- name: GetWebSubSubscriptionsToRenew :many
+SELECT id, created_at, updated_at, feed_id, hub_url, topic_url, secret, state, lease_seconds, expires_at, last_error FROM websub_subscriptions
+WHERE updated_at < $1::timestamp
+  AND (
+        (state = 'active'
+         AND expires_at - COALESCE(lease_seconds, 0) * INTERVAL '1 second' / 10 < NOW())
+     OR state = 'pending'
+  )
+ORDER BY updated_at
+LIMIT $2
+`
+
+type GetWebSubSubscriptionsToRenewParams struct {
+	RetryBefore time.Time
+	BatchSize   int32
+}
+
+// Active leases with less than a tenth of their time left, and requests the
+// hub hasn't verified yet, unless one was sent since retry_before.
+func (q *Queries) GetWebSubSubscriptionsToRenew(ctx context.Context, arg GetWebSubSubscriptionsToRenewParams) ([]WebsubSubscription, error) {
+	rows, err := q.db.QueryContext(ctx, getWebSubSubscriptionsToRenew, arg.RetryBefore, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebsubSubscription
+	for rows.Next() {
+		var i WebsubSubscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FeedID,
+			&i.HubUrl,
+			&i.TopicUrl,
+			&i.Secret,
+			&i.State,
+			&i.LeaseSeconds,
+			&i.ExpiresAt,
+			&i.LastError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordWebSubRequest = `-- name: RecordWebSubRequest :exec
+UPDATE websub_subscriptions
+SET last_error = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type RecordWebSubRequestParams struct {
+	ID        uuid.UUID
+	LastError sql.NullString
+}
+
+// Notes a (re)subscription request sent to the hub and its error, if any.
+func (q *Queries) RecordWebSubRequest(ctx context.Context, arg RecordWebSubRequestParams) error {
+	_, err := q.db.ExecContext(ctx, recordWebSubRequest, arg.ID, arg.LastError)
+	return err
+}
+
+const upsertWebSubSubscription = `-- name: UpsertWebSubSubscription :one
+INSERT INTO websub_subscriptions(id, created_at, updated_at, feed_id, hub_url, topic_url, secret)
+VALUES($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (feed_id) DO UPDATE
+SET updated_at = EXCLUDED.updated_at,
+    hub_url = EXCLUDED.hub_url,
+    topic_url = EXCLUDED.topic_url,
+    secret = EXCLUDED.secret,
+    state = 'pending',
+    last_error = NULL
+RETURNING id, created_at, updated_at, feed_id, hub_url, topic_url, secret, state, lease_seconds, expires_at, last_error
+`
+
+type UpsertWebSubSubscriptionParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	FeedID    uuid.UUID
+	HubUrl    string
+	TopicUrl  string
+	Secret    string
+}
+
+// Starts a new subscription attempt for a feed, replacing the hub, topic and
+// secret of any previous one.
+func (q *Queries) UpsertWebSubSubscription(ctx context.Context, arg UpsertWebSubSubscriptionParams) (WebsubSubscription, error) {
+	row := q.db.QueryRowContext(ctx, upsertWebSubSubscription,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.FeedID,
+		arg.HubUrl,
+		arg.TopicUrl,
+		arg.Secret,
+	)
+	var i WebsubSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FeedID,
+		&i.HubUrl,
+		&i.TopicUrl,
+		&i.Secret,
+		&i.State,
+		&i.LeaseSeconds,
+		&i.ExpiresAt,
+		&i.LastError,
+	)
+	return i, err
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScraperFailingFeedsLimit caps how many failing feeds the scraper status
+// lists.
+const ScraperFailingFeedsLimit = 20
+
+type AdminUserQuery struct {
+	Search string
+	Limit  int
+	Offset int
+}
+
+// SearchPattern returns Search escaped for the user search's ILIKE match,
+// so that %, _ and \ in it match themselves.
+func (q *AdminUserQuery) SearchPattern() string {
+	return escapeLike(q.Search)
+}
+
+func (q *AdminUserQuery) Validate() {
+	if q.Limit <= 0 {
+		q.Limit = 50
+	}
+	if q.Limit > 200 {
+		q.Limit = 200
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+}
+
+// AdminUser is a user as admins see them, with how much they follow and
+// have created.
+type AdminUser struct {
+	User
+	FollowCount int64
+	FeedCount   int64
+}
+
+// PurgePostsQuery picks the posts to purge: those of a feed, those
+// published before a time, or both. It can't pick every post.
+type PurgePostsQuery struct {
+	FeedID *uuid.UUID
+	Before *time.Time
+}
+
+func (q PurgePostsQuery) Validate() error {
+	if q.FeedID == nil && q.Before == nil {
+		return ErrInvalidPurgeQuery
+	}
+	return nil
+}
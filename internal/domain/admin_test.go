@@ -0,0 +1,21 @@
+package domain
+
+import "testing"
+
+func TestAdminUserQuerySearchPattern(t *testing.T) {
+	tests := []struct {
+		search string
+		want   string
+	}{
+		{search: "jane", want: "jane"},
+		{search: "jane_doe", want: `jane\_doe`},
+		{search: "100%", want: `100\%`},
+		{search: `back\slash`, want: `back\\slash`},
+	}
+	for _, tt := range tests {
+		query := AdminUserQuery{Search: tt.search}
+		if got := query.SearchPattern(); got != tt.want {
+			t.Errorf("SearchPattern() for %q = %q, want %q", tt.search, got, tt.want)
+		}
+	}
+}
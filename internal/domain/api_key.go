@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// API key scopes, each allowing everything the ones before it do: read
+// only reads, write also changes the user's data, and admin also manages
+// the user's API keys.
+const (
+	APIKeyScopeRead  = "read"
+	APIKeyScopeWrite = "write"
+	APIKeyScopeAdmin = "admin"
+)
+
+var apiKeyScopeRanks = map[string]int{
+	APIKeyScopeRead:  1,
+	APIKeyScopeWrite: 2,
+	APIKeyScopeAdmin: 3,
+}
+
+// APIKeyPrefixLength is how much of a key is kept in the clear so users
+// can tell their keys apart.
+const APIKeyPrefixLength = 8
+
+// DefaultAPIKeyName names the key every user gets on sign up.
+const DefaultAPIKeyName = "default"
+
+type APIKey struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uuid.UUID
+	Name       string
+	Prefix     string
+	Scope      string
+	LastUsedAt *time.Time
+	ExpiresAt  *time.Time
+	RevokedAt  *time.Time
+
+	// Key is the plaintext secret. It is only set when the key is created.
+	Key string
+}
+
+func NewAPIKey(name, scope string, expiresAt *time.Time, userID uuid.UUID) *APIKey {
+	now := time.Now().UTC()
+	return &APIKey{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		UserID:    userID,
+		Name:      name,
+		Scope:     scope,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (k *APIKey) Validate() error {
+	if k.Name == "" || len(k.Name) > 255 {
+		return ErrInvalidAPIKeyName
+	}
+	if _, ok := apiKeyScopeRanks[k.Scope]; !ok {
+		return ErrInvalidAPIKeyScope
+	}
+	if k.ExpiresAt != nil && !k.ExpiresAt.After(k.CreatedAt) {
+		return ErrInvalidAPIKeyExpiry
+	}
+	if k.UserID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	return nil
+}
+
+// IsActive reports whether the key still authenticates at now.
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || k.ExpiresAt.After(now))
+}
+
+// Allows reports whether the key's scope covers scope.
+func (k *APIKey) Allows(scope string) bool {
+	return apiKeyScopeRanks[k.Scope] >= apiKeyScopeRanks[scope]
+}
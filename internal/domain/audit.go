@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit log actions.
+const (
+	AuditUserCreate         = "user.create"
+	AuditUserChangePassword = "user.password_change"
+	AuditUserRedeemReset    = "user.password_reset_redeem"
+	AuditUserChangeRole     = "user.role_change"
+	AuditUserLinkIdentity   = "user.identity_link"
+	AuditAPIKeyCreate       = "api_key.create"
+	AuditAPIKeyRevoke       = "api_key.revoke"
+	AuditFeedCreate         = "feed.create"
+	AuditFeedFollowCreate   = "feed_follow.create"
+	AuditFeedFollowUpdate   = "feed_follow.update"
+	AuditFeedFollowDelete   = "feed_follow.delete"
+	AuditUserDisable        = "user.disable"
+	AuditUserEnable         = "user.enable"
+	AuditUserDelete         = "user.delete"
+	AuditUserIssueReset     = "user.password_reset"
+	AuditFeedRefresh        = "feed.refresh"
+	AuditFeedDisable        = "feed.disable"
+	AuditFeedEnable         = "feed.enable"
+	AuditFeedReassign       = "feed.reassign"
+	AuditPostsPurge         = "posts.purge"
+)
+
+// Kinds of things audit log entries are about.
+const (
+	AuditTargetUser       = "user"
+	AuditTargetAPIKey     = "api_key"
+	AuditTargetFeed       = "feed"
+	AuditTargetFeedFollow = "feed_follow"
+	AuditTargetPosts      = "posts"
+)
+
+// AuditExportBatchSize is how many entries an export reads at a time.
+const AuditExportBatchSize = 500
+
+// AuditEntry records a change: who made it, from where, to whose account
+// or subscriptions, and what the target looked like before and after.
+// Before is nil for creations and After for deletions.
+type AuditEntry struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	ActorID    *uuid.UUID
+	UserID     *uuid.UUID
+	Action     string
+	TargetType string
+	TargetID   *uuid.UUID
+	IP         string
+	UserAgent  string
+	Before     any
+	After      any
+}
+
+// NewAuditEntry returns an entry of a change made by actorID, or by no one
+// in particular when it is nil, to userID's account or subscriptions.
+func NewAuditEntry(actorID, userID *uuid.UUID, action, targetType string, targetID *uuid.UUID, before, after any) *AuditEntry {
+	return &AuditEntry{
+		ID:         uuid.New(),
+		CreatedAt:  time.Now().UTC(),
+		ActorID:    actorID,
+		UserID:     userID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     before,
+		After:      after,
+	}
+}
+
+// RedactFor hides where a change came from unless userID made it, so users
+// reading their own log don't learn admins' addresses.
+func (e *AuditEntry) RedactFor(userID uuid.UUID) {
+	if e.ActorID == nil || *e.ActorID != userID {
+		e.IP = ""
+		e.UserAgent = ""
+	}
+}
+
+type AuditQuery struct {
+	UserID *uuid.UUID
+	Action string
+	Limit  int
+	Offset int
+}
+
+func (q *AuditQuery) Validate() {
+	if q.Limit <= 0 {
+		q.Limit = 50
+	}
+	if q.Limit > 200 {
+		q.Limit = 200
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+}
+
+// AuditUser is what the audit log keeps of a user. Password hashes and
+// keys are left out.
+func AuditUser(user *User) map[string]any {
+	return map[string]any{
+		"name":        user.Name,
+		"username":    user.Username,
+		"email":       user.Email,
+		"role":        user.Role,
+		"disabled_at": user.DisabledAt,
+	}
+}
+
+// AuditAPIKey is what the audit log keeps of an API key, which is never
+// the key itself.
+func AuditAPIKey(key *APIKey) map[string]any {
+	return map[string]any{
+		"name":       key.Name,
+		"prefix":     key.Prefix,
+		"scope":      key.Scope,
+		"expires_at": key.ExpiresAt,
+	}
+}
+
+func AuditFeed(feed *Feed) map[string]any {
+	return map[string]any{
+		"name":        feed.Name,
+		"url":         feed.URL,
+		"user_id":     feed.UserID,
+		"disabled_at": feed.DisabledAt,
+	}
+}
+
+func AuditFeedFollow(feedFollow *FeedFollow) map[string]any {
+	return map[string]any{
+		"feed_id":           feedFollow.FeedID,
+		"feed_name":         feedFollow.FeedName,
+		"folder_id":         feedFollow.FolderID,
+		"title":             feedFollow.Title,
+		"muted":             feedFollow.Muted,
+		"priority":          feedFollow.Priority,
+		"notification_mode": feedFollow.NotificationMode,
+	}
+}
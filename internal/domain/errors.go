@@ -2,38 +2,179 @@ package domain
 
 import "errors"
 
+// ErrorCode is the kind of failure an Error is, which decides the HTTP
+// status it is served with.
+type ErrorCode string
+
+const (
+	ErrorCodeInvalid      ErrorCode = "invalid"
+	ErrorCodeUnauthorized ErrorCode = "unauthorized"
+	ErrorCodeForbidden    ErrorCode = "forbidden"
+	ErrorCodeNotFound     ErrorCode = "not_found"
+	ErrorCodeConflict     ErrorCode = "conflict"
+	ErrorCodeRateLimited  ErrorCode = "rate_limited"
+	ErrorCodeUnavailable  ErrorCode = "unavailable"
+	ErrorCodeInternal     ErrorCode = "internal"
+)
+
+// Error is a failure clients can be told about; its message never holds
+// internals such as database errors. Services return the sentinels below,
+// wrapped or not, and callers match them with errors.Is.
+type Error struct {
+	Code    ErrorCode
+	Message string
+}
+
+func NewError(code ErrorCode, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// AsError returns the Error in err's chain, or nil if err isn't one, as
+// with errors from the database.
+func AsError(err error) *Error {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+	return nil
+}
+
+var (
+	ErrInternalServer = NewError(ErrorCodeInternal, "internal server error")
+	ErrUnauthorized   = NewError(ErrorCodeUnauthorized, "unauthorized")
+	ErrForbidden      = NewError(ErrorCodeForbidden, "forbidden")
+)
+
+var (
+	ErrInvalidUserName = NewError(ErrorCodeInvalid, "invalid user name")
+	ErrUserNameTooLong = NewError(ErrorCodeInvalid, "user name is too long")
+	ErrUserNotFound    = NewError(ErrorCodeNotFound, "user not found")
+	ErrInvalidAPIKey   = NewError(ErrorCodeUnauthorized, "invalid API key")
+	ErrInvalidUserID   = NewError(ErrorCodeInvalid, "invalid user ID")
+)
+
+var (
+	ErrInvalidUsername      = NewError(ErrorCodeInvalid, "invalid username")
+	ErrInvalidEmail         = NewError(ErrorCodeInvalid, "invalid email")
+	ErrInvalidPassword      = NewError(ErrorCodeInvalid, "invalid password")
+	ErrUsernameRequired     = NewError(ErrorCodeInvalid, "a password needs a username")
+	ErrDuplicateUsername    = NewError(ErrorCodeConflict, "username is taken")
+	ErrDuplicateEmail       = NewError(ErrorCodeConflict, "email is taken")
+	ErrInvalidCredentials   = NewError(ErrorCodeUnauthorized, "invalid username or password")
+	ErrInvalidSession       = NewError(ErrorCodeUnauthorized, "invalid session")
+	ErrInvalidCSRFToken     = NewError(ErrorCodeForbidden, "invalid CSRF token")
+	ErrInvalidPasswordReset = NewError(ErrorCodeInvalid, "invalid or expired password reset token")
+	ErrAdminRequired        = NewError(ErrorCodeForbidden, "admin role required")
+)
+
+var (
+	ErrUserDisabled      = NewError(ErrorCodeForbidden, "user is disabled")
+	ErrCannotModifySelf  = NewError(ErrorCodeInvalid, "admins cannot disable, delete or demote themselves")
+	ErrInvalidPurgeQuery = NewError(ErrorCodeInvalid, "purging posts needs a feed, a cutoff time or both")
+	ErrInvalidUserRole   = NewError(ErrorCodeInvalid, "role must be user or admin")
+)
+
+var (
+	ErrOIDCLoginFailed    = NewError(ErrorCodeUnauthorized, "single sign-on login failed")
+	ErrOIDCSignupDisabled = NewError(ErrorCodeForbidden, "no user is linked to this identity and sign-up is disabled")
+	ErrOIDCEmailTaken     = NewError(ErrorCodeConflict, "a user with this email already has a password or API key, so the identity can't be linked to it")
+)
+
+var (
+	ErrInvalidAPIKeyName   = NewError(ErrorCodeInvalid, "invalid API key name")
+	ErrInvalidAPIKeyScope  = NewError(ErrorCodeInvalid, "invalid API key scope")
+	ErrInvalidAPIKeyExpiry = NewError(ErrorCodeInvalid, "API key expiry must be in the future")
+	ErrAPIKeyNotFound      = NewError(ErrorCodeNotFound, "API key not found")
+	ErrInsufficientScope   = NewError(ErrorCodeForbidden, "API key scope does not allow this")
+)
+
+var (
+	ErrInvalidFeedName   = NewError(ErrorCodeInvalid, "invalid feed name")
+	ErrInvalidFeedURL    = NewError(ErrorCodeInvalid, "invalid feed URL")
+	ErrFeedNotFound      = NewError(ErrorCodeNotFound, "feed not found")
+	ErrInvalidFeedID     = NewError(ErrorCodeInvalid, "invalid feed ID")
+	ErrDuplicateFeed     = NewError(ErrorCodeConflict, "feed already exists")
+	ErrInvalidFeedSort   = NewError(ErrorCodeInvalid, "invalid feed sort")
+	ErrInvalidFeedOffset = NewError(ErrorCodeInvalid, "invalid feed directory offset")
+)
+
+var (
+	ErrFeedFollowNotFound  = NewError(ErrorCodeNotFound, "feed follow not found")
+	ErrDuplicateFeedFollow = NewError(ErrorCodeConflict, "already following this feed")
+
+	ErrFeedFollowTitleTooLong    = NewError(ErrorCodeInvalid, "feed follow title is too long")
+	ErrInvalidFeedFollowPriority = NewError(ErrorCodeInvalid, "invalid feed follow priority")
+	ErrInvalidNotificationMode   = NewError(ErrorCodeInvalid, "invalid notification mode")
+)
+
+var (
+	ErrInvalidPostTitle    = NewError(ErrorCodeInvalid, "invalid post title")
+	ErrInvalidPostURL      = NewError(ErrorCodeInvalid, "invalid post URL")
+	ErrInvalidPublishedAt  = NewError(ErrorCodeInvalid, "invalid published date")
+	ErrPostNotFound        = NewError(ErrorCodeNotFound, "post not found")
+	ErrDuplicatePost       = NewError(ErrorCodeConflict, "post already exists")
+	ErrInvalidTimelineSort = NewError(ErrorCodeInvalid, "invalid timeline sort")
+
+	ErrInvalidStreamPosition = NewError(ErrorCodeInvalid, "invalid Last-Event-ID")
+)
+
+var (
+	ErrInvalidFolderName = NewError(ErrorCodeInvalid, "invalid folder name")
+	ErrFolderNotFound    = NewError(ErrorCodeNotFound, "folder not found")
+	ErrDuplicateFolder   = NewError(ErrorCodeConflict, "folder already exists")
+)
+
+var (
+	ErrInvalidFilterRuleName  = NewError(ErrorCodeInvalid, "invalid filter rule name")
+	ErrInvalidFilterScope     = NewError(ErrorCodeInvalid, "invalid filter rule scope")
+	ErrInvalidFilterField     = NewError(ErrorCodeInvalid, "invalid filter rule field")
+	ErrInvalidFilterMatchType = NewError(ErrorCodeInvalid, "invalid filter rule match type")
+	ErrInvalidFilterPattern   = NewError(ErrorCodeInvalid, "invalid filter rule pattern")
+	ErrInvalidFilterAction    = NewError(ErrorCodeInvalid, "invalid filter rule action")
+	ErrFilterRuleNotFound     = NewError(ErrorCodeNotFound, "filter rule not found")
+)
+
+var (
+	ErrInvalidTagName = NewError(ErrorCodeInvalid, "invalid tag name")
+	ErrTagNotFound    = NewError(ErrorCodeNotFound, "tag not found")
+	ErrDuplicateTag   = NewError(ErrorCodeConflict, "tag already exists")
+)
+
 var (
-	ErrInternalServer = errors.New("internal server error")
-	ErrUnauthorized   = errors.New("unauthorized")
-	ErrForbidden      = errors.New("forbidden")
+	ErrInvalidFeedTokenName = NewError(ErrorCodeInvalid, "invalid feed token name")
+	ErrFeedTokenNotFound    = NewError(ErrorCodeNotFound, "feed token not found")
+	ErrInvalidFeedToken     = NewError(ErrorCodeUnauthorized, "invalid feed token")
 )
 
 var (
-	ErrInvalidUserName = errors.New("invalid user name")
-	ErrUserNameTooLong = errors.New("user name is too long")
-	ErrUserNotFound    = errors.New("user not found")
-	ErrInvalidAPIKey   = errors.New("invalid API key")
-	ErrInvalidUserID   = errors.New("invalid user ID")
+	ErrInvalidWebhookName      = NewError(ErrorCodeInvalid, "invalid webhook name")
+	ErrInvalidWebhookURL       = NewError(ErrorCodeInvalid, "invalid webhook URL")
+	ErrWebhookURLNotPublic     = NewError(ErrorCodeInvalid, "webhook URL is not a public address")
+	ErrWebhookNotFound         = NewError(ErrorCodeNotFound, "webhook not found")
+	ErrWebhookDeliveryNotFound = NewError(ErrorCodeNotFound, "webhook delivery not found")
 )
 
 var (
-	ErrInvalidFeedName = errors.New("invalid feed name")
-	ErrInvalidFeedURL  = errors.New("invalid feed URL")
-	ErrFeedNotFound    = errors.New("feed not found")
-	ErrInvalidFeedID   = errors.New("invalid feed ID")
-	ErrDuplicateFeed   = errors.New("feed already exists")
+	ErrWebSubSubscriptionNotFound = NewError(ErrorCodeNotFound, "websub subscription not found")
+	ErrWebSubTopicMismatch        = NewError(ErrorCodeInvalid, "websub topic does not match subscription")
+	ErrInvalidWebSubMode          = NewError(ErrorCodeInvalid, "invalid websub mode")
+	ErrInvalidWebSubSignature     = NewError(ErrorCodeForbidden, "invalid websub signature")
 )
 
 var (
-	ErrFeedFollowNotFound  = errors.New("feed follow not found")
-	ErrDuplicateFeedFollow = errors.New("already following this feed")
-	ErrCannotUnfollowFeed  = errors.New("cannot unfollow feed")
+	ErrInvalidFeverAPIKey = NewError(ErrorCodeUnauthorized, "invalid fever api key")
+	ErrInvalidFeverMark   = NewError(ErrorCodeInvalid, "invalid fever mark")
+	ErrFeverItemNotFound  = NewError(ErrorCodeNotFound, "fever item not found")
 )
 
 var (
-	ErrInvalidPostTitle   = errors.New("invalid post title")
-	ErrInvalidPostURL     = errors.New("invalid post URL")
-	ErrInvalidPublishedAt = errors.New("invalid published date")
-	ErrPostNotFound       = errors.New("post not found")
-	ErrDuplicatePost      = errors.New("post already exists")
+	ErrInvalidReaderCredentials   = NewError(ErrorCodeUnauthorized, "invalid reader credentials")
+	ErrInvalidReaderStream        = NewError(ErrorCodeInvalid, "invalid reader stream")
+	ErrInvalidReaderItemID        = NewError(ErrorCodeInvalid, "invalid reader item id")
+	ErrInvalidReaderAction        = NewError(ErrorCodeInvalid, "invalid reader subscription action")
+	ErrReaderSubscriptionNotFound = NewError(ErrorCodeNotFound, "reader subscription not found")
 )
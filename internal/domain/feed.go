@@ -8,13 +8,23 @@ import (
 )
 
 type Feed struct {
-	ID            uuid.UUID
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	Name          string
-	URL           string
-	UserID        uuid.UUID
-	LastFetchedAt *time.Time
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Name             string
+	URL              string
+	UserID           uuid.UUID
+	LastFetchedAt    *time.Time
+	SiteTitle        *string
+	SiteURL          *string
+	FaviconCheckedAt *time.Time
+
+	// DisabledAt is set while an admin has disabled the feed, which isn't
+	// fetched then.
+	DisabledAt *time.Time
+
+	// LastFetchError is why the last fetch failed, nil if it succeeded.
+	LastFetchError *string
 }
 
 func NewFeed(name, feedURL string, userID uuid.UUID) *Feed {
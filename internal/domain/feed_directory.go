@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	FeedSortFollowers = "followers"
+	FeedSortActivity  = "activity"
+	FeedSortNewest    = "newest"
+)
+
+// recentPostWindow is the period used to compute a feed's post frequency.
+const recentPostWindow = 30 * 24 * time.Hour
+
+type FeedDirectoryQuery struct {
+	Search string
+	Sort   string
+	Limit  int
+	Offset int
+}
+
+// SearchPattern returns Search escaped for the directory's ILIKE match, so
+// that %, _ and \ in it match themselves.
+func (q *FeedDirectoryQuery) SearchPattern() string {
+	return escapeLike(q.Search)
+}
+
+func (q *FeedDirectoryQuery) Validate() error {
+	switch q.Sort {
+	case "":
+		q.Sort = FeedSortFollowers
+	case FeedSortFollowers, FeedSortActivity, FeedSortNewest:
+	default:
+		return ErrInvalidFeedSort
+	}
+
+	if q.Limit <= 0 {
+		q.Limit = 20
+	}
+	if q.Limit > 100 {
+		q.Limit = 100
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	if q.Offset > math.MaxInt32 {
+		return ErrInvalidFeedOffset
+	}
+	return nil
+}
+
+type FeedDirectoryEntry struct {
+	Feed
+	FollowerCount   int64
+	RecentPostCount int64
+	LastPostAt      *time.Time
+}
+
+// PostsPerWeek returns the average number of posts per week published
+// by the feed over the recent post window.
+func (e *FeedDirectoryEntry) PostsPerWeek() float64 {
+	weeks := recentPostWindow.Hours() / (7 * 24)
+	return float64(e.RecentPostCount) / weeks
+}
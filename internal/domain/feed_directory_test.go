@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFeedDirectoryQueryValidateOffset(t *testing.T) {
+	query := FeedDirectoryQuery{Offset: math.MaxInt32}
+	if err := query.Validate(); err != nil {
+		t.Fatalf("Validate() with offset %d error = %v", query.Offset, err)
+	}
+
+	query = FeedDirectoryQuery{Offset: math.MaxInt32 + 1}
+	if err := query.Validate(); !errors.Is(err, ErrInvalidFeedOffset) {
+		t.Fatalf("Validate() with offset %d error = %v, want ErrInvalidFeedOffset", query.Offset, err)
+	}
+
+	query = FeedDirectoryQuery{Offset: -1}
+	if err := query.Validate(); err != nil {
+		t.Fatalf("Validate() with offset -1 error = %v", err)
+	}
+	if query.Offset != 0 {
+		t.Errorf("Validate() offset = %d, want 0", query.Offset)
+	}
+}
+
+func TestFeedDirectoryQuerySearchPattern(t *testing.T) {
+	query := FeedDirectoryQuery{Search: `50%_off\`}
+	if got, want := query.SearchPattern(), `50\%\_off\\`; got != want {
+		t.Errorf("SearchPattern() = %q, want %q", got, want)
+	}
+}
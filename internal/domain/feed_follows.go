@@ -6,22 +6,51 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	NotificationModeOff     = "off"
+	NotificationModeInstant = "instant"
+	NotificationModeDigest  = "digest"
+)
+
+const (
+	MinFeedFollowPriority = -100
+	MaxFeedFollowPriority = 100
+)
+
 type FeedFollow struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	UserID    uuid.UUID
-	FeedID    uuid.UUID
+	ID               uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	UserID           uuid.UUID
+	FeedID           uuid.UUID
+	FeedName         string
+	FolderID         *uuid.UUID
+	Title            *string
+	Muted            bool
+	Priority         int32
+	NotificationMode string
+}
+
+// FeedFollowUpdate holds the per-follow preferences a user can change.
+// Nil fields are left untouched; an empty Title clears the override and
+// a nil UUID FolderID moves the follow out of its folder.
+type FeedFollowUpdate struct {
+	FolderID         *uuid.UUID
+	Title            *string
+	Muted            *bool
+	Priority         *int32
+	NotificationMode *string
 }
 
 func NewFeedFollow(userID, feedID uuid.UUID) *FeedFollow {
 	now := time.Now().UTC()
 	return &FeedFollow{
-		ID:        uuid.New(),
-		CreatedAt: now,
-		UpdatedAt: now,
-		UserID:    userID,
-		FeedID:    feedID,
+		ID:               uuid.New(),
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		UserID:           userID,
+		FeedID:           feedID,
+		NotificationMode: NotificationModeInstant,
 	}
 }
 
@@ -32,5 +61,54 @@ func (ff *FeedFollow) Validate() error {
 	if ff.FeedID == uuid.Nil {
 		return ErrInvalidFeedID
 	}
+	if ff.Title != nil && len(*ff.Title) > 255 {
+		return ErrFeedFollowTitleTooLong
+	}
+	if ff.Priority < MinFeedFollowPriority || ff.Priority > MaxFeedFollowPriority {
+		return ErrInvalidFeedFollowPriority
+	}
+	switch ff.NotificationMode {
+	case NotificationModeOff, NotificationModeInstant, NotificationModeDigest:
+	default:
+		return ErrInvalidNotificationMode
+	}
 	return nil
 }
+
+func (ff *FeedFollow) Apply(update FeedFollowUpdate) {
+	if update.FolderID != nil {
+		if *update.FolderID == uuid.Nil {
+			ff.FolderID = nil
+		} else {
+			folderID := *update.FolderID
+			ff.FolderID = &folderID
+		}
+	}
+	if update.Title != nil {
+		if *update.Title == "" {
+			ff.Title = nil
+		} else {
+			title := *update.Title
+			ff.Title = &title
+		}
+	}
+	if update.Muted != nil {
+		ff.Muted = *update.Muted
+	}
+	if update.Priority != nil {
+		ff.Priority = *update.Priority
+	}
+	if update.NotificationMode != nil {
+		ff.NotificationMode = *update.NotificationMode
+	}
+	ff.UpdatedAt = time.Now().UTC()
+}
+
+// DisplayTitle returns the follower's own title for the feed, falling back
+// to the feed name chosen by its creator.
+func (ff *FeedFollow) DisplayTitle() string {
+	if ff.Title != nil && *ff.Title != "" {
+		return *ff.Title
+	}
+	return ff.FeedName
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeedToken is a per-user secret embedded in output feed URLs, for feed
+// readers that cannot send an Authorization header.
+type FeedToken struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UserID     uuid.UUID
+	Name       string
+	LastUsedAt *time.Time
+
+	// Token is the plaintext secret. It is only set when the token is created.
+	Token string
+}
+
+func NewFeedToken(name string, userID uuid.UUID) *FeedToken {
+	return &FeedToken{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UserID:    userID,
+		Name:      name,
+	}
+}
+
+func (t *FeedToken) Validate() error {
+	if t.Name == "" || len(t.Name) > 255 {
+		return ErrInvalidFeedTokenName
+	}
+	if t.UserID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// Fever identifies everything by integer. Groups are folders, feeds are
+// followed feeds and items are posts, each by its seq. A feed's favicon
+// shares the feed's ID.
+
+const (
+	FeverMarkRead    = "read"
+	FeverMarkUnread  = "unread"
+	FeverMarkSaved   = "saved"
+	FeverMarkUnsaved = "unsaved"
+)
+
+// FeverItemLimit is the number of items Fever returns per request.
+const FeverItemLimit = 50
+
+type FeverGroup struct {
+	ID    int64
+	Title string
+}
+
+type FeverFeed struct {
+	ID          int64
+	Title       string
+	URL         string
+	SiteURL     string
+	LastUpdated *time.Time
+	GroupID     *int64
+}
+
+type FeverFavicon struct {
+	ID   int64
+	Data string
+}
+
+type FeverItem struct {
+	ID        int64
+	FeedID    int64
+	Title     string
+	Author    string
+	HTML      string
+	URL       string
+	Saved     bool
+	Read      bool
+	CreatedOn time.Time
+}
+
+// FeverItemQuery selects items after SinceID, before MaxID, or by ID.
+type FeverItemQuery struct {
+	SinceID *int64
+	MaxID   *int64
+	WithIDs []int64
+}
@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	FilterScopeGlobal = "global"
+	FilterScopeFolder = "folder"
+	FilterScopeFeed   = "feed"
+)
+
+const (
+	FilterFieldAny     = "any"
+	FilterFieldTitle   = "title"
+	FilterFieldContent = "content"
+	FilterFieldAuthor  = "author"
+	FilterFieldURL     = "url"
+)
+
+const (
+	FilterMatchSubstring = "substring"
+	FilterMatchRegex     = "regex"
+)
+
+const (
+	FilterActionHide     = "hide"
+	FilterActionMarkRead = "mark_read"
+	FilterActionStar     = "star"
+	FilterActionTag      = "tag"
+)
+
+type FilterRule struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	Name      string
+	Scope     string
+	FolderID  *uuid.UUID
+	FeedID    *uuid.UUID
+	Field     string
+	MatchType string
+	Pattern   string
+	Action    string
+	TagName   *string
+	Enabled   bool
+
+	compiled *regexp.Regexp
+}
+
+// FilterDryRun reports which posts a rule would act on without applying it.
+type FilterDryRun struct {
+	Scanned int
+	Matched int
+	Posts   []*Post
+}
+
+func NewFilterRule(userID uuid.UUID) *FilterRule {
+	now := time.Now().UTC()
+	return &FilterRule{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		UserID:    userID,
+		Scope:     FilterScopeGlobal,
+		Field:     FilterFieldAny,
+		MatchType: FilterMatchSubstring,
+		Enabled:   true,
+	}
+}
+
+func (r *FilterRule) Validate() error {
+	if r.UserID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	if r.Name == "" || len(r.Name) > 255 {
+		return ErrInvalidFilterRuleName
+	}
+
+	switch r.Scope {
+	case FilterScopeGlobal:
+		r.FolderID, r.FeedID = nil, nil
+	case FilterScopeFolder:
+		if r.FolderID == nil {
+			return ErrInvalidFilterScope
+		}
+		r.FeedID = nil
+	case FilterScopeFeed:
+		if r.FeedID == nil {
+			return ErrInvalidFilterScope
+		}
+		r.FolderID = nil
+	default:
+		return ErrInvalidFilterScope
+	}
+
+	switch r.Field {
+	case FilterFieldAny, FilterFieldTitle, FilterFieldContent, FilterFieldAuthor, FilterFieldURL:
+	default:
+		return ErrInvalidFilterField
+	}
+
+	if r.Pattern == "" {
+		return ErrInvalidFilterPattern
+	}
+	switch r.MatchType {
+	case FilterMatchSubstring:
+	case FilterMatchRegex:
+		compiled, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return ErrInvalidFilterPattern
+		}
+		r.compiled = compiled
+	default:
+		return ErrInvalidFilterMatchType
+	}
+
+	switch r.Action {
+	case FilterActionHide, FilterActionMarkRead, FilterActionStar:
+		r.TagName = nil
+	case FilterActionTag:
+		if r.TagName == nil || *r.TagName == "" {
+			return ErrInvalidFilterAction
+		}
+		if err := NewTag(*r.TagName, r.UserID).Validate(); err != nil {
+			return err
+		}
+	default:
+		return ErrInvalidFilterAction
+	}
+
+	return nil
+}
+
+// Matches reports whether the post satisfies the rule's pattern.
+// Substring matches are case-insensitive.
+func (r *FilterRule) Matches(post *Post) bool {
+	for _, value := range r.fieldValues(post) {
+		if value == "" {
+			continue
+		}
+		if r.MatchType == FilterMatchRegex {
+			if r.compiled == nil {
+				compiled, err := regexp.Compile(r.Pattern)
+				if err != nil {
+					return false
+				}
+				r.compiled = compiled
+			}
+			if r.compiled.MatchString(value) {
+				return true
+			}
+			continue
+		}
+		if strings.Contains(strings.ToLower(value), strings.ToLower(r.Pattern)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *FilterRule) fieldValues(post *Post) []string {
+	var content, author string
+	if post.Description != nil {
+		content = *post.Description
+	}
+	if post.Author != nil {
+		author = *post.Author
+	}
+
+	switch r.Field {
+	case FilterFieldTitle:
+		return []string{post.Title}
+	case FilterFieldContent:
+		return []string{content}
+	case FilterFieldAuthor:
+		return []string{author}
+	case FilterFieldURL:
+		return []string{post.URL}
+	default:
+		return []string{post.Title, content, author, post.URL}
+	}
+}
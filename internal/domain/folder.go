@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Folder struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	Name      string
+}
+
+func NewFolder(name string, userID uuid.UUID) *Folder {
+	now := time.Now().UTC()
+	return &Folder{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		UserID:    userID,
+		Name:      name,
+	}
+}
+
+func (f *Folder) Validate() error {
+	if f.Name == "" {
+		return ErrInvalidFolderName
+	}
+	if len(f.Name) > 255 {
+		return ErrInvalidFolderName
+	}
+	if f.UserID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+type HealthStatus string
+
+const (
+	HealthOK HealthStatus = "ok"
+	// HealthDegraded means only checks that aren't critical failed, so the
+	// process can still serve.
+	HealthDegraded HealthStatus = "degraded"
+	HealthDown     HealthStatus = "down"
+)
+
+// Names of the readiness checks
+const (
+	HealthCheckDatabase   = "database"
+	HealthCheckMigrations = "migrations"
+	HealthCheckScraper    = "scraper"
+	HealthCheckQueue      = "queue"
+)
+
+// HealthCheck is the result of checking one thing the process depends on.
+// Its Status is HealthOK or HealthDown.
+type HealthCheck struct {
+	Name     string
+	Status   HealthStatus
+	Critical bool
+	Latency  time.Duration
+	// Message says what is wrong, or what was observed, without internals
+	// like addresses that errors would leak.
+	Message string
+}
+
+// Health is the result of all readiness checks.
+type Health struct {
+	Status HealthStatus
+	Checks []*HealthCheck
+}
+
+// NewHealth sums checks up: down if a critical check failed, degraded if
+// another did.
+func NewHealth(checks []*HealthCheck) *Health {
+	health := &Health{Status: HealthOK, Checks: checks}
+	for _, check := range checks {
+		if check.Status == HealthOK {
+			continue
+		}
+		if check.Critical {
+			health.Status = HealthDown
+		} else if health.Status == HealthOK {
+			health.Status = HealthDegraded
+		}
+	}
+	return health
+}
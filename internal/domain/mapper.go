@@ -1,17 +1,32 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/hel1th/rssagg/internal/database"
 )
 
 func MapUserFromDB(dbUser database.User) *User {
-	return &User{
-		ID:        dbUser.ID,
-		CreatedAt: dbUser.CreatedAt,
-		UpdatedAt: dbUser.UpdatedAt,
-		Name:      dbUser.Name,
-		APIKey:    dbUser.ApiKey,
+	user := &User{
+		ID:           dbUser.ID,
+		CreatedAt:    dbUser.CreatedAt,
+		UpdatedAt:    dbUser.UpdatedAt,
+		Name:         dbUser.Name,
+		Role:         dbUser.Role,
+		PasswordHash: dbUser.PasswordHash.String,
+	}
+
+	if dbUser.Username.Valid {
+		user.Username = &dbUser.Username.String
 	}
+	if dbUser.Email.Valid {
+		user.Email = &dbUser.Email.String
+	}
+	if dbUser.DisabledAt.Valid {
+		user.DisabledAt = &dbUser.DisabledAt.Time
+	}
+
+	return user
 }
 
 func MapFeedFromDB(dbFeed database.Feed) *Feed {
@@ -27,6 +42,21 @@ func MapFeedFromDB(dbFeed database.Feed) *Feed {
 	if dbFeed.LastFetchedAt.Valid {
 		feed.LastFetchedAt = &dbFeed.LastFetchedAt.Time
 	}
+	if dbFeed.SiteTitle.Valid {
+		feed.SiteTitle = &dbFeed.SiteTitle.String
+	}
+	if dbFeed.SiteUrl.Valid {
+		feed.SiteURL = &dbFeed.SiteUrl.String
+	}
+	if dbFeed.FaviconCheckedAt.Valid {
+		feed.FaviconCheckedAt = &dbFeed.FaviconCheckedAt.Time
+	}
+	if dbFeed.DisabledAt.Valid {
+		feed.DisabledAt = &dbFeed.DisabledAt.Time
+	}
+	if dbFeed.LastFetchError.Valid {
+		feed.LastFetchError = &dbFeed.LastFetchError.String
+	}
 
 	return feed
 }
@@ -39,20 +69,86 @@ func MapFeedsFromDB(dbFeeds []database.Feed) []*Feed {
 	return feeds
 }
 
+func MapFeedDirectoryEntryFromDB(row database.ListFeedDirectoryRow) *FeedDirectoryEntry {
+	entry := &FeedDirectoryEntry{
+		Feed: *MapFeedFromDB(database.Feed{
+			ID:               row.ID,
+			CreatedAt:        row.CreatedAt,
+			UpdatedAt:        row.UpdatedAt,
+			Name:             row.Name,
+			Url:              row.Url,
+			UserID:           row.UserID,
+			LastFetchedAt:    row.LastFetchedAt,
+			SiteTitle:        row.SiteTitle,
+			Seq:              row.Seq,
+			SiteUrl:          row.SiteUrl,
+			Favicon:          row.Favicon,
+			FaviconCheckedAt: row.FaviconCheckedAt,
+			DisabledAt:       row.DisabledAt,
+			LastFetchError:   row.LastFetchError,
+		}),
+		FollowerCount:   row.FollowerCount,
+		RecentPostCount: row.RecentPostCount,
+	}
+
+	if lastPostAt, ok := row.LastPostAt.(time.Time); ok {
+		entry.LastPostAt = &lastPostAt
+	}
+
+	return entry
+}
+
+func MapFeedDirectoryFromDB(rows []database.ListFeedDirectoryRow) []*FeedDirectoryEntry {
+	entries := make([]*FeedDirectoryEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = MapFeedDirectoryEntryFromDB(row)
+	}
+	return entries
+}
+
 func MapFeedFollowFromDB(dbFeedFollow database.FeedFollow) *FeedFollow {
-	return &FeedFollow{
-		ID:        dbFeedFollow.ID,
-		CreatedAt: dbFeedFollow.CreatedAt,
-		UpdatedAt: dbFeedFollow.UpdatedAt,
-		UserID:    dbFeedFollow.UserID,
-		FeedID:    dbFeedFollow.FeedID,
+	feedFollow := &FeedFollow{
+		ID:               dbFeedFollow.ID,
+		CreatedAt:        dbFeedFollow.CreatedAt,
+		UpdatedAt:        dbFeedFollow.UpdatedAt,
+		UserID:           dbFeedFollow.UserID,
+		FeedID:           dbFeedFollow.FeedID,
+		Muted:            dbFeedFollow.Muted,
+		Priority:         dbFeedFollow.Priority,
+		NotificationMode: dbFeedFollow.NotificationMode,
+	}
+
+	if dbFeedFollow.Title.Valid {
+		feedFollow.Title = &dbFeedFollow.Title.String
+	}
+	if dbFeedFollow.FolderID.Valid {
+		feedFollow.FolderID = &dbFeedFollow.FolderID.UUID
 	}
+
+	return feedFollow
+}
+
+func MapFeedFollowRowFromDB(row database.GetFeedFollowRow) *FeedFollow {
+	feedFollow := MapFeedFollowFromDB(database.FeedFollow{
+		ID:               row.ID,
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        row.UpdatedAt,
+		UserID:           row.UserID,
+		FeedID:           row.FeedID,
+		Title:            row.Title,
+		Muted:            row.Muted,
+		Priority:         row.Priority,
+		NotificationMode: row.NotificationMode,
+		FolderID:         row.FolderID,
+	})
+	feedFollow.FeedName = row.FeedName
+	return feedFollow
 }
 
-func MapFeedFollowsFromDB(dbFeedFollows []database.FeedFollow) []*FeedFollow {
-	feedFollows := make([]*FeedFollow, len(dbFeedFollows))
-	for i, dbFeedFollow := range dbFeedFollows {
-		feedFollows[i] = MapFeedFollowFromDB(dbFeedFollow)
+func MapFeedFollowsFromDB(rows []database.GetFeedFollowsRow) []*FeedFollow {
+	feedFollows := make([]*FeedFollow, len(rows))
+	for i, row := range rows {
+		feedFollows[i] = MapFeedFollowRowFromDB(database.GetFeedFollowRow(row))
 	}
 	return feedFollows
 }
@@ -66,11 +162,15 @@ func MapPostFromDB(dbPost database.Post) *Post {
 		PublishedAt: dbPost.PublishedAt,
 		URL:         dbPost.Url,
 		FeedID:      dbPost.FeedID,
+		Seq:         dbPost.Seq,
 	}
 
 	if dbPost.Description.Valid {
 		post.Description = &dbPost.Description.String
 	}
+	if dbPost.Author.Valid {
+		post.Author = &dbPost.Author.String
+	}
 
 	return post
 }
@@ -82,3 +182,525 @@ func MapPostsFromDB(dbPosts []database.Post) []*Post {
 	}
 	return posts
 }
+
+func MapTimelinePostsFromDB(rows []database.GetPostsForUserRow) []*TimelinePost {
+	posts := make([]*TimelinePost, len(rows))
+	for i, row := range rows {
+		posts[i] = &TimelinePost{
+			Post: *MapPostFromDB(database.Post{
+				ID:          row.ID,
+				CreatedAt:   row.CreatedAt,
+				UpdatedAt:   row.UpdatedAt,
+				Title:       row.Title,
+				Description: row.Description,
+				PublishedAt: row.PublishedAt,
+				Url:         row.Url,
+				FeedID:      row.FeedID,
+				Author:      row.Author,
+				Seq:         row.Seq,
+			}),
+			FeedTitle:    row.FeedTitle,
+			FeedPriority: row.FeedPriority,
+			Read:         row.IsRead,
+			Starred:      row.IsStarred,
+		}
+	}
+	return posts
+}
+
+func MapStreamPostsFromDB(rows []database.GetPostsForUserSinceRow) []*StreamPost {
+	timelineRows := make([]database.GetPostsForUserRow, len(rows))
+	for i, row := range rows {
+		timelineRows[i] = database.GetPostsForUserRow(row)
+	}
+
+	posts := make([]*StreamPost, len(rows))
+	for i, post := range MapTimelinePostsFromDB(timelineRows) {
+		posts[i] = &StreamPost{
+			TimelinePost: *post,
+			Position:     StreamPosition{XID: rows[i].StreamXid.Int64, Seq: rows[i].Seq},
+		}
+	}
+	return posts
+}
+
+func MapFolderFromDB(dbFolder database.Folder) *Folder {
+	return &Folder{
+		ID:        dbFolder.ID,
+		CreatedAt: dbFolder.CreatedAt,
+		UpdatedAt: dbFolder.UpdatedAt,
+		UserID:    dbFolder.UserID,
+		Name:      dbFolder.Name,
+	}
+}
+
+func MapFoldersFromDB(dbFolders []database.Folder) []*Folder {
+	folders := make([]*Folder, len(dbFolders))
+	for i, dbFolder := range dbFolders {
+		folders[i] = MapFolderFromDB(dbFolder)
+	}
+	return folders
+}
+
+func MapFilterRuleFromDB(dbRule database.FilterRule) *FilterRule {
+	rule := &FilterRule{
+		ID:        dbRule.ID,
+		CreatedAt: dbRule.CreatedAt,
+		UpdatedAt: dbRule.UpdatedAt,
+		UserID:    dbRule.UserID,
+		Name:      dbRule.Name,
+		Scope:     dbRule.Scope,
+		Field:     dbRule.Field,
+		MatchType: dbRule.MatchType,
+		Pattern:   dbRule.Pattern,
+		Action:    dbRule.Action,
+		Enabled:   dbRule.Enabled,
+	}
+
+	if dbRule.FolderID.Valid {
+		rule.FolderID = &dbRule.FolderID.UUID
+	}
+	if dbRule.FeedID.Valid {
+		rule.FeedID = &dbRule.FeedID.UUID
+	}
+	if dbRule.TagName.Valid {
+		rule.TagName = &dbRule.TagName.String
+	}
+
+	return rule
+}
+
+func MapFilterRulesFromDB(dbRules []database.FilterRule) []*FilterRule {
+	rules := make([]*FilterRule, len(dbRules))
+	for i, dbRule := range dbRules {
+		rules[i] = MapFilterRuleFromDB(dbRule)
+	}
+	return rules
+}
+
+func MapTagFromDB(dbTag database.Tag) *Tag {
+	return &Tag{
+		ID:        dbTag.ID,
+		CreatedAt: dbTag.CreatedAt,
+		UpdatedAt: dbTag.UpdatedAt,
+		UserID:    dbTag.UserID,
+		Name:      dbTag.Name,
+	}
+}
+
+func MapTagsFromDB(rows []database.GetTagsRow) []*Tag {
+	tags := make([]*Tag, len(rows))
+	for i, row := range rows {
+		tags[i] = &Tag{
+			ID:        row.ID,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+			UserID:    row.UserID,
+			Name:      row.Name,
+			PostCount: row.PostCount,
+		}
+	}
+	return tags
+}
+
+func MapFeedTokenFromDB(dbToken database.FeedToken) *FeedToken {
+	token := &FeedToken{
+		ID:        dbToken.ID,
+		CreatedAt: dbToken.CreatedAt,
+		UserID:    dbToken.UserID,
+		Name:      dbToken.Name,
+	}
+
+	if dbToken.LastUsedAt.Valid {
+		token.LastUsedAt = &dbToken.LastUsedAt.Time
+	}
+
+	return token
+}
+
+func MapFeedTokensFromDB(dbTokens []database.FeedToken) []*FeedToken {
+	tokens := make([]*FeedToken, len(dbTokens))
+	for i, dbToken := range dbTokens {
+		tokens[i] = MapFeedTokenFromDB(dbToken)
+	}
+	return tokens
+}
+
+func MapWebhookFromDB(dbWebhook database.Webhook) *Webhook {
+	webhook := &Webhook{
+		ID:        dbWebhook.ID,
+		CreatedAt: dbWebhook.CreatedAt,
+		UpdatedAt: dbWebhook.UpdatedAt,
+		UserID:    dbWebhook.UserID,
+		Name:      dbWebhook.Name,
+		URL:       dbWebhook.Url,
+		Enabled:   dbWebhook.Enabled,
+		Secret:    dbWebhook.Secret,
+	}
+
+	if dbWebhook.FeedID.Valid {
+		webhook.FeedID = &dbWebhook.FeedID.UUID
+	}
+	if dbWebhook.FolderID.Valid {
+		webhook.FolderID = &dbWebhook.FolderID.UUID
+	}
+	if dbWebhook.Keyword.Valid {
+		webhook.Keyword = &dbWebhook.Keyword.String
+	}
+
+	return webhook
+}
+
+func MapWebhooksFromDB(dbWebhooks []database.Webhook) []*Webhook {
+	webhooks := make([]*Webhook, len(dbWebhooks))
+	for i, dbWebhook := range dbWebhooks {
+		webhooks[i] = MapWebhookFromDB(dbWebhook)
+	}
+	return webhooks
+}
+
+func MapWebhookDeliveryFromDB(dbDelivery database.WebhookDelivery) *WebhookDelivery {
+	delivery := &WebhookDelivery{
+		ID:            dbDelivery.ID,
+		CreatedAt:     dbDelivery.CreatedAt,
+		UpdatedAt:     dbDelivery.UpdatedAt,
+		WebhookID:     dbDelivery.WebhookID,
+		Event:         dbDelivery.Event,
+		Payload:       dbDelivery.Payload,
+		Status:        dbDelivery.Status,
+		Attempts:      int(dbDelivery.Attempts),
+		NextAttemptAt: dbDelivery.NextAttemptAt,
+	}
+
+	if dbDelivery.PostID.Valid {
+		delivery.PostID = &dbDelivery.PostID.UUID
+	}
+	if dbDelivery.LastAttemptAt.Valid {
+		delivery.LastAttemptAt = &dbDelivery.LastAttemptAt.Time
+	}
+	if dbDelivery.ResponseStatus.Valid {
+		status := int(dbDelivery.ResponseStatus.Int32)
+		delivery.ResponseStatus = &status
+	}
+	if dbDelivery.LastError.Valid {
+		delivery.LastError = &dbDelivery.LastError.String
+	}
+
+	return delivery
+}
+
+func MapWebhookDeliveriesFromDB(dbDeliveries []database.WebhookDelivery) []*WebhookDelivery {
+	deliveries := make([]*WebhookDelivery, len(dbDeliveries))
+	for i, dbDelivery := range dbDeliveries {
+		deliveries[i] = MapWebhookDeliveryFromDB(dbDelivery)
+	}
+	return deliveries
+}
+
+func MapWebSubSubscriptionFromDB(dbSub database.WebsubSubscription) *WebSubSubscription {
+	sub := &WebSubSubscription{
+		ID:        dbSub.ID,
+		CreatedAt: dbSub.CreatedAt,
+		UpdatedAt: dbSub.UpdatedAt,
+		FeedID:    dbSub.FeedID,
+		HubURL:    dbSub.HubUrl,
+		TopicURL:  dbSub.TopicUrl,
+		Secret:    dbSub.Secret,
+		State:     dbSub.State,
+	}
+
+	if dbSub.LeaseSeconds.Valid {
+		lease := int(dbSub.LeaseSeconds.Int32)
+		sub.LeaseSeconds = &lease
+	}
+	if dbSub.ExpiresAt.Valid {
+		sub.ExpiresAt = &dbSub.ExpiresAt.Time
+	}
+	if dbSub.LastError.Valid {
+		sub.LastError = &dbSub.LastError.String
+	}
+
+	return sub
+}
+
+func MapFeverGroupsFromDB(rows []database.GetFeverGroupsRow) []*FeverGroup {
+	groups := make([]*FeverGroup, len(rows))
+	for i, row := range rows {
+		groups[i] = &FeverGroup{
+			ID:    row.Seq,
+			Title: row.Name,
+		}
+	}
+	return groups
+}
+
+func MapFeverFeedsFromDB(rows []database.GetFeverFeedsRow) []*FeverFeed {
+	feeds := make([]*FeverFeed, len(rows))
+	for i, row := range rows {
+		feed := &FeverFeed{
+			ID:      row.Seq,
+			Title:   row.Title,
+			URL:     row.Url,
+			SiteURL: row.SiteUrl.String,
+		}
+		if row.LastFetchedAt.Valid {
+			feed.LastUpdated = &row.LastFetchedAt.Time
+		}
+		if row.FolderSeq.Valid {
+			feed.GroupID = &row.FolderSeq.Int64
+		}
+		feeds[i] = feed
+	}
+	return feeds
+}
+
+func MapFeverFaviconsFromDB(rows []database.GetFeverFaviconsRow) []*FeverFavicon {
+	favicons := make([]*FeverFavicon, len(rows))
+	for i, row := range rows {
+		favicons[i] = &FeverFavicon{
+			ID:   row.Seq,
+			Data: row.Favicon.String,
+		}
+	}
+	return favicons
+}
+
+func MapFeverItemsFromDB(rows []database.GetFeverItemsRow) []*FeverItem {
+	items := make([]*FeverItem, len(rows))
+	for i, row := range rows {
+		items[i] = &FeverItem{
+			ID:        row.Seq,
+			FeedID:    row.FeedSeq,
+			Title:     row.Title,
+			Author:    row.Author.String,
+			HTML:      row.Description.String,
+			URL:       row.Url,
+			Saved:     row.IsStarred,
+			Read:      row.IsRead,
+			CreatedOn: row.PublishedAt,
+		}
+	}
+	return items
+}
+
+func MapReaderSubscriptionsFromDB(rows []database.GetReaderSubscriptionsRow) []*ReaderSubscription {
+	subscriptions := make([]*ReaderSubscription, len(rows))
+	for i, row := range rows {
+		subscription := &ReaderSubscription{
+			FeedFollowID: row.FeedFollowID,
+			FeedID:       row.Seq,
+			Title:        row.Title,
+			URL:          row.Url,
+			SiteURL:      row.SiteUrl.String,
+			CreatedAt:    row.CreatedAt,
+		}
+		if row.FolderName.Valid {
+			subscription.Folder = &row.FolderName.String
+		}
+		subscriptions[i] = subscription
+	}
+	return subscriptions
+}
+
+func MapReaderItemsFromDB(rows []database.GetReaderItemsRow) []*ReaderItem {
+	items := make([]*ReaderItem, len(rows))
+	for i, row := range rows {
+		item := &ReaderItem{
+			ID:          row.Seq,
+			FeedID:      row.FeedSeq,
+			FeedTitle:   row.FeedTitle,
+			FeedSiteURL: row.FeedSiteUrl.String,
+			Title:       row.Title,
+			Author:      row.Author.String,
+			Content:     row.Description.String,
+			URL:         row.Url,
+			PublishedAt: row.PublishedAt,
+			CrawledAt:   row.CreatedAt,
+			Read:        row.IsRead,
+			Starred:     row.IsStarred,
+		}
+		if row.FolderName.Valid {
+			item.Folder = &row.FolderName.String
+		}
+		items[i] = item
+	}
+	return items
+}
+
+func MapReaderItemsByIDsFromDB(rows []database.GetReaderItemsByIDsRow) []*ReaderItem {
+	converted := make([]database.GetReaderItemsRow, len(rows))
+	for i, row := range rows {
+		converted[i] = database.GetReaderItemsRow(row)
+	}
+	return MapReaderItemsFromDB(converted)
+}
+
+func MapReaderItemRefsFromDB(rows []database.GetReaderItemIDsRow) []*ReaderItemRef {
+	refs := make([]*ReaderItemRef, len(rows))
+	for i, row := range rows {
+		refs[i] = &ReaderItemRef{
+			ID:        row.Seq,
+			CrawledAt: row.CreatedAt,
+		}
+	}
+	return refs
+}
+
+func MapAPIKeyFromDB(dbKey database.ApiKey) *APIKey {
+	key := &APIKey{
+		ID:        dbKey.ID,
+		CreatedAt: dbKey.CreatedAt,
+		UpdatedAt: dbKey.UpdatedAt,
+		UserID:    dbKey.UserID,
+		Name:      dbKey.Name,
+		Prefix:    dbKey.Prefix,
+		Scope:     dbKey.Scope,
+	}
+
+	if dbKey.LastUsedAt.Valid {
+		key.LastUsedAt = &dbKey.LastUsedAt.Time
+	}
+	if dbKey.ExpiresAt.Valid {
+		key.ExpiresAt = &dbKey.ExpiresAt.Time
+	}
+	if dbKey.RevokedAt.Valid {
+		key.RevokedAt = &dbKey.RevokedAt.Time
+	}
+
+	return key
+}
+
+func MapAPIKeysFromDB(dbKeys []database.ApiKey) []*APIKey {
+	keys := make([]*APIKey, len(dbKeys))
+	for i, dbKey := range dbKeys {
+		keys[i] = MapAPIKeyFromDB(dbKey)
+	}
+	return keys
+}
+
+func MapSessionFromDB(dbSession database.Session) *Session {
+	return &Session{
+		ID:         dbSession.ID,
+		CreatedAt:  dbSession.CreatedAt,
+		LastSeenAt: dbSession.LastSeenAt,
+		ExpiresAt:  dbSession.ExpiresAt,
+		UserID:     dbSession.UserID,
+		CSRFToken:  dbSession.CsrfToken,
+		UserAgent:  dbSession.UserAgent,
+		IP:         dbSession.Ip,
+	}
+}
+
+func MapPasswordResetFromDB(dbReset database.PasswordReset) *PasswordReset {
+	reset := &PasswordReset{
+		ID:        dbReset.ID,
+		CreatedAt: dbReset.CreatedAt,
+		ExpiresAt: dbReset.ExpiresAt,
+		UserID:    dbReset.UserID,
+	}
+
+	if dbReset.CreatedBy.Valid {
+		reset.CreatedBy = &dbReset.CreatedBy.UUID
+	}
+
+	return reset
+}
+
+func MapUserIdentityFromDB(dbIdentity database.UserIdentity) *UserIdentity {
+	identity := &UserIdentity{
+		ID:          dbIdentity.ID,
+		CreatedAt:   dbIdentity.CreatedAt,
+		LastLoginAt: dbIdentity.LastLoginAt,
+		UserID:      dbIdentity.UserID,
+		Issuer:      dbIdentity.Issuer,
+		Subject:     dbIdentity.Subject,
+	}
+
+	if dbIdentity.Email.Valid {
+		identity.Email = &dbIdentity.Email.String
+	}
+
+	return identity
+}
+
+func MapAdminUserFromDB(row database.SearchUsersRow) *AdminUser {
+	return &AdminUser{
+		User: *MapUserFromDB(database.User{
+			ID:                row.ID,
+			CreatedAt:         row.CreatedAt,
+			UpdatedAt:         row.UpdatedAt,
+			Name:              row.Name,
+			Username:          row.Username,
+			Email:             row.Email,
+			PasswordHash:      row.PasswordHash,
+			PasswordChangedAt: row.PasswordChangedAt,
+			Role:              row.Role,
+			DisabledAt:        row.DisabledAt,
+		}),
+		FollowCount: row.FollowCount,
+		FeedCount:   row.FeedCount,
+	}
+}
+
+func MapAdminUsersFromDB(rows []database.SearchUsersRow) []*AdminUser {
+	users := make([]*AdminUser, len(rows))
+	for i, row := range rows {
+		users[i] = MapAdminUserFromDB(row)
+	}
+	return users
+}
+
+func MapAuditEntryFromDB(dbEntry database.AuditLog) *AuditEntry {
+	entry := &AuditEntry{
+		ID:         dbEntry.ID,
+		CreatedAt:  dbEntry.CreatedAt,
+		Action:     dbEntry.Action,
+		TargetType: dbEntry.TargetType,
+		IP:         dbEntry.Ip,
+		UserAgent:  dbEntry.UserAgent,
+	}
+
+	if dbEntry.ActorID.Valid {
+		entry.ActorID = &dbEntry.ActorID.UUID
+	}
+	if dbEntry.UserID.Valid {
+		entry.UserID = &dbEntry.UserID.UUID
+	}
+	if dbEntry.TargetID.Valid {
+		entry.TargetID = &dbEntry.TargetID.UUID
+	}
+	if string(dbEntry.Before) != "null" {
+		entry.Before = dbEntry.Before
+	}
+	if string(dbEntry.After) != "null" {
+		entry.After = dbEntry.After
+	}
+
+	return entry
+}
+
+func MapAuditEntriesFromDB(dbEntries []database.AuditLog) []*AuditEntry {
+	entries := make([]*AuditEntry, len(dbEntries))
+	for i, dbEntry := range dbEntries {
+		entries[i] = MapAuditEntryFromDB(dbEntry)
+	}
+	return entries
+}
+
+func MapScraperWorkerFromDB(dbHeartbeat database.ScraperHeartbeat) *ScraperWorker {
+	worker := &ScraperWorker{
+		ID:           dbHeartbeat.WorkerID,
+		StartedAt:    dbHeartbeat.StartedAt,
+		LastBeatAt:   dbHeartbeat.LastBeatAt,
+		LastRunFeeds: int(dbHeartbeat.LastRunFeeds),
+	}
+
+	if dbHeartbeat.LastRunStartedAt.Valid {
+		worker.LastRunStartedAt = &dbHeartbeat.LastRunStartedAt.Time
+	}
+	if dbHeartbeat.LastRunFinishedAt.Valid {
+		worker.LastRunFinishedAt = &dbHeartbeat.LastRunFinishedAt.Time
+	}
+
+	return worker
+}
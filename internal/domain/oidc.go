@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// OIDCStateCookieName holds the state, nonce and PKCE verifier of a
+	// login in progress, tying the provider's redirect to the browser that
+	// started it.
+	OIDCStateCookieName = "rssagg_oidc"
+
+	// OIDCLoginTTL is how long a user has to finish logging in at the
+	// provider.
+	OIDCLoginTTL = 10 * time.Minute
+
+	DefaultOIDCRoleClaim = "groups"
+)
+
+// OIDCLogin is a login started at the identity provider. Its secrets stay
+// with the browser until the provider redirects back.
+type OIDCLogin struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+	AuthURL      string
+}
+
+// UserIdentity links an account at an identity provider, identified by
+// issuer and subject, to a user.
+type UserIdentity struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	LastLoginAt time.Time
+	UserID      uuid.UUID
+	Issuer      string
+	Subject     string
+	Email       *string
+}
+
+func NewUserIdentity(userID uuid.UUID, issuer, subject string, email *string) *UserIdentity {
+	now := time.Now().UTC()
+	return &UserIdentity{
+		ID:          uuid.New(),
+		CreatedAt:   now,
+		LastLoginAt: now,
+		UserID:      userID,
+		Issuer:      issuer,
+		Subject:     subject,
+		Email:       email,
+	}
+}
+
+// OIDCRoleMapping maps values of the role claim, like group names, to user
+// roles.
+type OIDCRoleMapping map[string]string
+
+// ParseOIDCRoleMapping parses "value=role" pairs separated by commas, as in
+// "rss-admins=admin,staff=user".
+func ParseOIDCRoleMapping(s string) (OIDCRoleMapping, error) {
+	mapping := make(OIDCRoleMapping)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		value, role, ok := strings.Cut(pair, "=")
+		value, role = strings.TrimSpace(value), strings.TrimSpace(role)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid role mapping %q, expected value=role", pair)
+		}
+		if !IsValidUserRole(role) {
+			return nil, fmt.Errorf("invalid role %q in role mapping, expected user or admin", role)
+		}
+		mapping[value] = role
+	}
+	return mapping, nil
+}
+
+// Role returns the highest role any of values maps to, or UserRoleUser if
+// none do.
+func (m OIDCRoleMapping) Role(values []string) string {
+	role := UserRoleUser
+	for _, value := range values {
+		if m[value] == UserRoleAdmin {
+			role = UserRoleAdmin
+		}
+	}
+	return role
+}
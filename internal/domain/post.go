@@ -1,7 +1,10 @@
 package domain
 
 import (
+	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +19,91 @@ type Post struct {
 	PublishedAt time.Time
 	URL         string
 	FeedID      uuid.UUID
+	Author      *string
+	Seq         int64
+}
+
+// TimelinePost is a post as seen in a user's timeline, carrying the
+// follower's own title and priority for the feed it came from.
+type TimelinePost struct {
+	Post
+	FeedTitle    string
+	FeedPriority int32
+	Read         bool
+	Starred      bool
+}
+
+// StreamPosition is where a post sits in the post stream: the transaction
+// that wrote it, then its seq. Unlike seq alone, positions only ever appear
+// in increasing order.
+type StreamPosition struct {
+	XID int64
+	Seq int64
+}
+
+// String is the SSE event ID for the position.
+func (p StreamPosition) String() string {
+	return fmt.Sprintf("%d-%d", p.XID, p.Seq)
+}
+
+// ParseStreamPosition accepts an event ID from String, or a bare seq as sent
+// before positions carried a transaction.
+func ParseStreamPosition(id string) (StreamPosition, error) {
+	xid, seq, found := strings.Cut(id, "-")
+	if !found {
+		xid, seq = "0", id
+	}
+
+	var pos StreamPosition
+	var err error
+	if pos.XID, err = strconv.ParseInt(xid, 10, 64); err != nil || pos.XID < 0 {
+		return StreamPosition{}, ErrInvalidStreamPosition
+	}
+	if pos.Seq, err = strconv.ParseInt(seq, 10, 64); err != nil || pos.Seq < 0 {
+		return StreamPosition{}, ErrInvalidStreamPosition
+	}
+	return pos, nil
+}
+
+// StreamPost is a timeline post read from the post stream.
+type StreamPost struct {
+	TimelinePost
+	Position StreamPosition
+}
+
+const (
+	TimelineSortLatest   = "latest"
+	TimelineSortPriority = "priority"
+)
+
+type TimelineQuery struct {
+	Tag      string
+	FolderID *uuid.UUID
+	Starred  bool
+	Sort     string
+	Limit    int
+	Offset   int
+}
+
+func (q *TimelineQuery) Validate() error {
+	switch q.Sort {
+	case "":
+		q.Sort = TimelineSortLatest
+	case TimelineSortLatest, TimelineSortPriority:
+	default:
+		return ErrInvalidTimelineSort
+	}
+
+	if q.Limit <= 0 {
+		q.Limit = 10
+	}
+	if q.Limit > 100 {
+		q.Limit = 100
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	return nil
 }
 
 func NewPost(title, postURL string, publishedAt time.Time, feedID uuid.UUID, description *string) *Post {
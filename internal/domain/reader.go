@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Google Reader stream and tag IDs. Clients may name the user by ID instead
+// of "-", NormalizeReaderStreamID maps those to the "-" form.
+const (
+	ReaderStreamReadingList = "user/-/state/com.google/reading-list"
+	ReaderStreamRead        = "user/-/state/com.google/read"
+	ReaderStreamStarred     = "user/-/state/com.google/starred"
+	ReaderLabelPrefix       = "user/-/label/"
+	ReaderFeedPrefix        = "feed/"
+	ReaderItemIDPrefix      = "tag:google.com,2005:reader/item/"
+)
+
+const (
+	ReaderDefaultItemLimit = 20
+	ReaderMaxItemLimit     = 1000
+	ReaderMaxIDLimit       = 10000
+)
+
+// ReaderSubscription is a followed feed. Its stream ID is "feed/<seq>".
+type ReaderSubscription struct {
+	FeedFollowID uuid.UUID
+	FeedID       int64
+	Title        string
+	URL          string
+	SiteURL      string
+	Folder       *string
+	CreatedAt    time.Time
+}
+
+type ReaderItem struct {
+	ID          int64
+	FeedID      int64
+	FeedTitle   string
+	FeedSiteURL string
+	Folder      *string
+	Title       string
+	Author      string
+	Content     string
+	URL         string
+	PublishedAt time.Time
+	CrawledAt   time.Time
+	Read        bool
+	Starred     bool
+}
+
+type ReaderItemRef struct {
+	ID        int64
+	CrawledAt time.Time
+}
+
+// ReaderStreamQuery selects a page of a stream: s, xt, it, ot, nt, c, r
+// and n in the Reader API.
+type ReaderStreamQuery struct {
+	StreamID     string
+	Exclude      []string
+	Include      []string
+	NewerThan    *time.Time
+	OlderThan    *time.Time
+	Continuation *int64
+	OldestFirst  bool
+	Limit        int
+}
+
+// ReaderStreamFilter is a stream query broken down into what to match.
+type ReaderStreamFilter struct {
+	FeedID         *int64
+	Folder         *string
+	ReadOnly       bool
+	ExcludeRead    bool
+	StarredOnly    bool
+	ExcludeStarred bool
+}
+
+// Filter resolves the stream, include and exclude targets of the query.
+func (q ReaderStreamQuery) Filter() (ReaderStreamFilter, error) {
+	var filter ReaderStreamFilter
+
+	streamID := NormalizeReaderStreamID(q.StreamID)
+	switch {
+	case streamID == "" || streamID == ReaderStreamReadingList:
+	case streamID == ReaderStreamRead:
+		filter.ReadOnly = true
+	case streamID == ReaderStreamStarred:
+		filter.StarredOnly = true
+	case strings.HasPrefix(streamID, ReaderLabelPrefix):
+		folder := strings.TrimPrefix(streamID, ReaderLabelPrefix)
+		filter.Folder = &folder
+	case strings.HasPrefix(streamID, ReaderFeedPrefix):
+		feedID, err := strconv.ParseInt(strings.TrimPrefix(streamID, ReaderFeedPrefix), 10, 64)
+		if err != nil {
+			return filter, ErrInvalidReaderStream
+		}
+		filter.FeedID = &feedID
+	default:
+		return filter, ErrInvalidReaderStream
+	}
+
+	for _, target := range q.Include {
+		switch NormalizeReaderStreamID(target) {
+		case ReaderStreamRead:
+			filter.ReadOnly = true
+		case ReaderStreamStarred:
+			filter.StarredOnly = true
+		}
+	}
+	for _, target := range q.Exclude {
+		switch NormalizeReaderStreamID(target) {
+		case ReaderStreamRead:
+			filter.ExcludeRead = true
+		case ReaderStreamStarred:
+			filter.ExcludeStarred = true
+		}
+	}
+
+	return filter, nil
+}
+
+// NormalizeReaderStreamID turns "user/<id>/..." into "user/-/...".
+func NormalizeReaderStreamID(streamID string) string {
+	if !strings.HasPrefix(streamID, "user/") {
+		return streamID
+	}
+	rest := strings.TrimPrefix(streamID, "user/")
+	if i := strings.Index(rest, "/"); i >= 0 {
+		return "user/-/" + rest[i+1:]
+	}
+	return streamID
+}
+
+// ReaderItemID is the long form of an item ID: 16 hex digits of its seq.
+func ReaderItemID(seq int64) string {
+	return fmt.Sprintf("%s%016x", ReaderItemIDPrefix, uint64(seq))
+}
+
+// ParseReaderItemID accepts the long form, bare hex after the prefix, or the
+// decimal short form returned by stream/items/ids.
+func ParseReaderItemID(id string) (int64, error) {
+	id = strings.TrimSpace(id)
+	if strings.HasPrefix(id, ReaderItemIDPrefix) {
+		seq, err := strconv.ParseUint(strings.TrimPrefix(id, ReaderItemIDPrefix), 16, 64)
+		if err != nil {
+			return 0, ErrInvalidReaderItemID
+		}
+		return int64(seq), nil
+	}
+
+	seq, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidReaderItemID
+	}
+	return seq, nil
+}
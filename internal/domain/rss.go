@@ -1,10 +1,20 @@
 package domain
 
+import "time"
+
 type RSSFeedData struct {
 	Title       string
 	Description string
 	Link        string
 	Items       []RSSItemData
+
+	// HubURL and SelfURL are the WebSub hub and canonical topic URL the
+	// feed advertises, if any.
+	HubURL  string
+	SelfURL string
+
+	// Size is the size of the document in bytes.
+	Size int
 }
 
 type RSSItemData struct {
@@ -12,4 +22,25 @@ type RSSItemData struct {
 	Description string
 	Link        string
 	PubDate     string
+	Author      string
+}
+
+// OutputFeed is a stream of posts republished by rssagg as RSS or JSON Feed.
+type OutputFeed struct {
+	Title       string
+	Description string
+	Link        string
+	FeedURL     string
+	Updated     time.Time
+	Items       []OutputItem
+}
+
+type OutputItem struct {
+	ID          string
+	Title       string
+	Description string
+	Link        string
+	Author      string
+	Source      string
+	PublishedAt time.Time
 }
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// ScraperWorker is the heartbeat of a scraper process.
+type ScraperWorker struct {
+	ID                string
+	StartedAt         time.Time
+	LastBeatAt        time.Time
+	LastRunStartedAt  *time.Time
+	LastRunFinishedAt *time.Time
+	LastRunFeeds      int
+}
+
+// ScraperStatus is how the scraper and the feeds it fetches are doing.
+type ScraperStatus struct {
+	Workers           []*ScraperWorker
+	TotalFeeds        int64
+	DisabledFeeds     int64
+	NeverFetchedFeeds int64
+	FailingFeedCount  int64
+
+	// OldestFetchedAt is the last fetch of the enabled feed fetched longest
+	// ago, which is how far behind the scraper is.
+	OldestFetchedAt *time.Time
+
+	// FailingFeeds are the most recently fetched feeds whose last fetch
+	// failed.
+	FailingFeeds []*Feed
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "strings"
+
+// likeEscaper escapes the characters that are special in a LIKE pattern
+// whose ESCAPE character is a backslash.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike returns s for use inside a LIKE or ILIKE pattern with
+// ESCAPE '\', so that it only matches itself.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// SessionTTL is how long a login lasts.
+	SessionTTL = 30 * 24 * time.Hour
+
+	SessionCookieName = "rssagg_session"
+
+	// CSRFHeader carries the session's CSRF token on requests that change
+	// something, since the browser attaches the session cookie by itself.
+	CSRFHeader = "X-CSRF-Token"
+
+	// PasswordResetTTL is how long a password reset token can be redeemed.
+	PasswordResetTTL = 24 * time.Hour
+)
+
+// Session is a password login, identified by a secret in a cookie.
+type Session struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	UserID     uuid.UUID
+	CSRFToken  string
+	UserAgent  string
+	IP         string
+
+	// Token is the plaintext session secret. It is only set when the
+	// session is created.
+	Token string
+}
+
+func NewSession(userID uuid.UUID, userAgent, ip string) *Session {
+	now := time.Now().UTC()
+	return &Session{
+		ID:         uuid.New(),
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(SessionTTL),
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IP:         ip,
+	}
+}
+
+// PasswordReset is a one-time token an admin issues so a user can set a
+// new password.
+type PasswordReset struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UserID    uuid.UUID
+	CreatedBy *uuid.UUID
+
+	// Token is the plaintext reset secret. It is only set when the reset
+	// is created.
+	Token string
+}
+
+func NewPasswordReset(userID, createdBy uuid.UUID) *PasswordReset {
+	now := time.Now().UTC()
+	return &PasswordReset{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		ExpiresAt: now.Add(PasswordResetTTL),
+		UserID:    userID,
+		CreatedBy: &createdBy,
+	}
+}
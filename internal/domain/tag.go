@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Tag struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	Name      string
+	PostCount int64
+}
+
+func NewTag(name string, userID uuid.UUID) *Tag {
+	now := time.Now().UTC()
+	return &Tag{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+	}
+}
+
+func (t *Tag) Validate() error {
+	if t.Name == "" || len(t.Name) > 64 {
+		return ErrInvalidTagName
+	}
+	if t.UserID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	return nil
+}
@@ -1,17 +1,55 @@
 package domain
 
 import (
+	"net/mail"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	UserRoleUser  = "user"
+	UserRoleAdmin = "admin"
+)
+
+// Passwords are hashed with bcrypt, which only looks at the first 72 bytes.
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+var usernamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{2,31}$`)
+
 type User struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	Name      string
-	APIKey    string
+	Username  *string
+	Email     *string
+	Role      string
+
+	// DisabledAt is set while an admin has disabled the user, who can't
+	// authenticate then.
+	DisabledAt *time.Time
+
+	// PasswordHash is empty for users who only log in with API keys.
+	PasswordHash string
+
+	// APIKey is the plaintext default API key. It is only set when the
+	// user is created.
+	APIKey string
+}
+
+// UserRegistration is what a new user signs up with. Only Name is
+// required; a password needs a username to log in with.
+type UserRegistration struct {
+	Name     string
+	Username string
+	Email    string
+	Password string
 }
 
 func NewUser(name string) *User {
@@ -21,6 +59,7 @@ func NewUser(name string) *User {
 		CreatedAt: now,
 		UpdatedAt: now,
 		Name:      name,
+		Role:      UserRoleUser,
 	}
 }
 
@@ -33,3 +72,55 @@ func (u *User) Validate() error {
 	}
 	return nil
 }
+
+func (u *User) HasPassword() bool {
+	return u.PasswordHash != ""
+}
+
+// IsValidUserRole reports whether role is UserRoleUser or UserRoleAdmin.
+func IsValidUserRole(role string) bool {
+	return role == UserRoleUser || role == UserRoleAdmin
+}
+
+func (u *User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin
+}
+
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+// Normalize trims the registration and lowercases username and email, which
+// are unique regardless of case.
+func (r *UserRegistration) Normalize() {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Username = strings.ToLower(strings.TrimSpace(r.Username))
+	r.Email = strings.ToLower(strings.TrimSpace(r.Email))
+}
+
+func (r *UserRegistration) Validate() error {
+	if r.Username != "" && !usernamePattern.MatchString(r.Username) {
+		return ErrInvalidUsername
+	}
+	if r.Email != "" {
+		if _, err := mail.ParseAddress(r.Email); err != nil {
+			return ErrInvalidEmail
+		}
+	}
+	if r.Password != "" {
+		if r.Username == "" {
+			return ErrUsernameRequired
+		}
+		if err := ValidatePassword(r.Password); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func ValidatePassword(password string) error {
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return ErrInvalidPassword
+	}
+	return nil
+}
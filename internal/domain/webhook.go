@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// WebhookEventPostCreated is sent once for every post newly ingested
+	// from a feed the webhook's owner follows with notifications instant.
+	WebhookEventPostCreated = "post.created"
+
+	// WebhookEventPostsDigest carries the posts from feeds followed with
+	// notifications in digest mode, once the oldest has waited a digest
+	// interval.
+	WebhookEventPostsDigest = "posts.digest"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+type Webhook struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	Name      string
+	URL       string
+	FeedID    *uuid.UUID
+	FolderID  *uuid.UUID
+	Keyword   *string
+	Enabled   bool
+
+	// Secret signs every delivery. It is only exposed when the webhook is created.
+	Secret string
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	WebhookID      uuid.UUID
+	PostID         *uuid.UUID // nil for digests
+	Event          string
+	Payload        string
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastAttemptAt  *time.Time
+	ResponseStatus *int
+	LastError      *string
+}
+
+func NewWebhook(userID uuid.UUID) *Webhook {
+	now := time.Now().UTC()
+	return &Webhook{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		UserID:    userID,
+		Enabled:   true,
+	}
+}
+
+func (w *Webhook) Validate() error {
+	if w.UserID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	if w.Name == "" || len(w.Name) > 255 {
+		return ErrInvalidWebhookName
+	}
+
+	parsedURL, err := url.ParseRequestURI(w.URL)
+	if err != nil || parsedURL.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return ErrInvalidWebhookURL
+	}
+
+	if w.Keyword != nil && strings.TrimSpace(*w.Keyword) == "" {
+		w.Keyword = nil
+	}
+
+	return nil
+}
+
+// Matches reports whether the post passes the webhook's keyword filter.
+// The keyword is matched case-insensitively against the title and content.
+func (w *Webhook) Matches(post *Post) bool {
+	if w.Keyword == nil {
+		return true
+	}
+
+	keyword := strings.ToLower(*w.Keyword)
+	if strings.Contains(strings.ToLower(post.Title), keyword) {
+		return true
+	}
+	return post.Description != nil && strings.Contains(strings.ToLower(*post.Description), keyword)
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	WebSubStatePending = "pending"
+	WebSubStateActive  = "active"
+	WebSubStateDenied  = "denied"
+)
+
+const (
+	WebSubModeSubscribe   = "subscribe"
+	WebSubModeUnsubscribe = "unsubscribe"
+	WebSubModeDenied      = "denied"
+)
+
+// WebSubSubscription is the push subscription held with a feed's hub.
+type WebSubSubscription struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	FeedID       uuid.UUID
+	HubURL       string
+	TopicURL     string
+	Secret       string
+	State        string
+	LeaseSeconds *int
+	ExpiresAt    *time.Time
+	LastError    *string
+}
+
+// WebSubVerification is a hub's request to confirm a subscription intent,
+// or to report that it denied one.
+type WebSubVerification struct {
+	Mode         string
+	Topic        string
+	Challenge    string
+	LeaseSeconds int
+	Reason       string
+}
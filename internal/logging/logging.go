@@ -0,0 +1,117 @@
+// Package logging sets up structured JSON logging with log/slog.
+//
+// Attributes added to a context with With, such as the request ID or the
+// scrape batch, are logged with every record logged with that context, so
+// services and repositories need only pass their context along.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Redacted replaces the values of attributes that may hold secrets.
+const Redacted = "[REDACTED]"
+
+// secretKeys are attribute keys whose values are never logged.
+var secretKeys = map[string]bool{
+	"api_key":       true,
+	"authorization": true,
+	"cookie":        true,
+	"password":      true,
+	"secret":        true,
+	"token":         true,
+}
+
+type contextKey string
+
+const (
+	attrsContextKey     contextKey = "log_attrs"
+	requestIDContextKey contextKey = "request_id"
+)
+
+// New returns a logger writing JSON lines to w at level and above.
+func New(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(&contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	})})
+}
+
+// ParseLevel parses debug, info, warn or error, optionally with an offset
+// as in "debug+2". The empty string parses to info.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if s == "" {
+		return level, nil
+	}
+	err := level.UnmarshalText([]byte(s))
+	return level, err
+}
+
+// With returns a context whose records are logged with args, given as to
+// slog.Logger.With.
+func With(ctx context.Context, args ...any) context.Context {
+	attrs, _ := ctx.Value(attrsContextKey).([]slog.Attr)
+	record := slog.Record{}
+	record.Add(args...)
+
+	merged := make([]slog.Attr, len(attrs), len(attrs)+record.NumAttrs())
+	copy(merged, attrs)
+	record.Attrs(func(attr slog.Attr) bool {
+		merged = append(merged, attr)
+		return true
+	})
+
+	return context.WithValue(ctx, attrsContextKey, merged)
+}
+
+// WithRequestID returns a context whose records are logged with the ID of
+// the request they were logged for.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return With(context.WithValue(ctx, requestIDContextKey, id), "request_id", id)
+}
+
+// RequestID returns the ID of the request ctx is for, if any.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
+// contextHandler adds the attributes of each record's context, and the
+// trace and span it was logged in.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs, ok := ctx.Value(attrsContextKey).([]slog.Attr); ok {
+		record.AddAttrs(attrs...)
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", span.TraceID().String()),
+			slog.String("span_id", span.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{h.Handler.WithGroup(name)}
+}
+
+func redact(_ []string, attr slog.Attr) slog.Attr {
+	if secretKeys[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, Redacted)
+	}
+	return attr
+}
@@ -0,0 +1,179 @@
+// Package metrics exposes Prometheus metrics for the API, the scraper and
+// the database pool.
+//
+// All methods are safe to call on a nil *Metrics, which records nothing,
+// so that services can be built without metrics.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "rssagg"
+
+// Feed fetch outcomes
+const (
+	FetchSuccess = "success"
+	FetchError   = "error"
+)
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	fetches       *prometheus.CounterVec
+	fetchDuration *prometheus.HistogramVec
+	fetchBytes    prometheus.Histogram
+	posts         *prometheus.CounterVec
+
+	queueLag      prometheus.Gauge
+	batchFeeds    prometheus.Gauge
+	activeFetches prometheus.Gauge
+}
+
+// New registers the metrics, along with Go runtime, process and db pool
+// metrics, on a registry of their own.
+func New(db *sql.DB) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method and route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+
+		fetches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "feed_fetches_total",
+			Help:      "Feed fetches by outcome.",
+		}, []string{"outcome"}),
+		fetchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "feed_fetch_duration_seconds",
+			Help:      "Feed fetch duration by outcome, including the wait for the host's turn.",
+			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		}, []string{"outcome"}),
+		fetchBytes: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "feed_fetch_bytes",
+			Help:      "Size of fetched feed documents.",
+			Buckets:   prometheus.ExponentialBuckets(1<<10, 4, 8),
+		}),
+		posts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "posts_total",
+			Help:      "Feed items stored, by whether they were new posts or duplicates.",
+		}, []string{"result"}),
+
+		queueLag: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "scraper_queue_lag_seconds",
+			Help:      "How long the feed next in line to be fetched has waited.",
+		}),
+		batchFeeds: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "scraper_batch_feeds",
+			Help:      "Feeds in the scraper's last batch.",
+		}),
+		activeFetches: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "scraper_active_fetches",
+			Help:      "Goroutines fetching feeds of the current scrape batch.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(db, namespace),
+		m.httpRequests,
+		m.httpDuration,
+		m.fetches,
+		m.fetchDuration,
+		m.fetchBytes,
+		m.posts,
+		m.queueLag,
+		m.batchFeeds,
+		m.activeFetches,
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest records a served request. route is the route pattern,
+// not the path, so that IDs in paths don't make a series each.
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// ObserveFetch records a feed fetch. size is only recorded for successful
+// fetches.
+func (m *Metrics) ObserveFetch(outcome string, duration time.Duration, size int) {
+	if m == nil {
+		return
+	}
+	m.fetches.WithLabelValues(outcome).Inc()
+	m.fetchDuration.WithLabelValues(outcome).Observe(duration.Seconds())
+	if outcome == FetchSuccess {
+		m.fetchBytes.Observe(float64(size))
+	}
+}
+
+// CountPosts records the items of a fetched or pushed feed that were
+// stored as new posts and those that were already stored.
+func (m *Metrics) CountPosts(inserted, duplicates int) {
+	if m == nil {
+		return
+	}
+	m.posts.WithLabelValues("inserted").Add(float64(inserted))
+	m.posts.WithLabelValues("duplicate").Add(float64(duplicates))
+}
+
+func (m *Metrics) SetQueueLag(lag time.Duration) {
+	if m == nil {
+		return
+	}
+	m.queueLag.Set(lag.Seconds())
+}
+
+func (m *Metrics) SetBatchFeeds(feeds int) {
+	if m == nil {
+		return
+	}
+	m.batchFeeds.Set(float64(feeds))
+}
+
+// FetchStarted counts a goroutine fetching a feed of a scrape batch until
+// the returned function is called.
+func (m *Metrics) FetchStarted() func() {
+	if m == nil {
+		return func() {}
+	}
+	m.activeFetches.Inc()
+	return m.activeFetches.Dec
+}
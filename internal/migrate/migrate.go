@@ -0,0 +1,93 @@
+// Package migrate applies the embedded schema migrations and checks that
+// the database is at the version the code expects.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+
+	"github.com/hel1th/rssagg/migrations"
+)
+
+// ErrSchemaBehind is returned by Check when migrations the code needs
+// haven't been applied.
+var ErrSchemaBehind = errors.New("database schema is behind")
+
+// Migrator migrates one database. Migrations run under a Postgres advisory
+// lock, so that instances starting together apply them once.
+type Migrator struct {
+	provider *goose.Provider
+}
+
+func New(db *sql.DB) (*Migrator, error) {
+	schema, err := fs.Sub(migrations.Schema, "schema")
+	if err != nil {
+		return nil, err
+	}
+
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration lock: %w", err)
+	}
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, schema, goose.WithSessionLocker(locker))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	return &Migrator{provider: provider}, nil
+}
+
+// Up applies every migration not yet applied.
+func (m *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	return m.provider.Up(ctx)
+}
+
+// Down rolls the latest applied migration back.
+func (m *Migrator) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	return m.provider.Down(ctx)
+}
+
+// Redo rolls the latest applied migration back and applies it again.
+func (m *Migrator) Redo(ctx context.Context) ([]*goose.MigrationResult, error) {
+	down, err := m.provider.Down(ctx)
+	if err != nil {
+		return nil, err
+	}
+	up, err := m.provider.UpByOne(ctx)
+	if err != nil {
+		return []*goose.MigrationResult{down}, err
+	}
+	return []*goose.MigrationResult{down, up}, nil
+}
+
+// Status lists every migration, applied or pending, oldest first.
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	return m.provider.Status(ctx)
+}
+
+// Versions returns the version of the database schema, and the version
+// of the latest migration, which the code expects.
+func (m *Migrator) Versions(ctx context.Context) (current, expected int64, err error) {
+	return m.provider.GetVersions(ctx)
+}
+
+// Check returns ErrSchemaBehind if the database is at an older version
+// than the code expects. A newer database is fine, as during a rolling
+// deploy whose first instances have migrated it.
+func (m *Migrator) Check(ctx context.Context) error {
+	current, expected, err := m.Versions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get schema version: %w", err)
+	}
+	if current < expected {
+		return fmt.Errorf("%w: at version %d, expected %d", ErrSchemaBehind, current, expected)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// clockSkew is how far the provider's clock may be off from ours.
+const clockSkew = time.Minute
+
+var ErrInvalidIDToken = errors.New("invalid ID token")
+
+// IDToken is a verified ID token.
+type IDToken struct {
+	Issuer            string
+	Subject           string
+	Audience          []string
+	Expiry            time.Time
+	IssuedAt          time.Time
+	Nonce             string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+
+	// Claims holds every claim, for mapping roles from.
+	Claims map[string]any
+}
+
+// VerifyIDToken checks an ID token's signature against the provider's keys,
+// then that it was issued by the provider, for this client, for the login
+// started with nonce, and hasn't expired.
+func (p *Provider) VerifyIDToken(ctx context.Context, raw, nonce string) (*IDToken, error) {
+	metadata, err := p.Metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: not a JWS compact serialization", ErrInvalidIDToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidIDToken, err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature: %v", ErrInvalidIDToken, err)
+	}
+
+	key, err := p.keys.key(ctx, header.Kid)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	claims := make(map[string]any)
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrInvalidIDToken, err)
+	}
+
+	token := &IDToken{
+		Issuer:            stringClaim(claims, "iss"),
+		Subject:           stringClaim(claims, "sub"),
+		Audience:          stringsClaim(claims, "aud"),
+		Expiry:            timeClaim(claims, "exp"),
+		IssuedAt:          timeClaim(claims, "iat"),
+		Nonce:             stringClaim(claims, "nonce"),
+		Email:             stringClaim(claims, "email"),
+		EmailVerified:     boolClaim(claims, "email_verified"),
+		Name:              stringClaim(claims, "name"),
+		PreferredUsername: stringClaim(claims, "preferred_username"),
+		Claims:            claims,
+	}
+
+	if err := p.validate(token, metadata.Issuer, nonce); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	return token, nil
+}
+
+func (p *Provider) validate(token *IDToken, issuer, nonce string) error {
+	if token.Issuer != issuer {
+		return fmt.Errorf("issuer %q is not %q", token.Issuer, issuer)
+	}
+	if token.Subject == "" {
+		return fmt.Errorf("no subject")
+	}
+
+	audience := false
+	for _, aud := range token.Audience {
+		if aud == p.config.ClientID {
+			audience = true
+		}
+	}
+	if !audience {
+		return fmt.Errorf("not issued for this client")
+	}
+	if azp := stringClaim(token.Claims, "azp"); len(token.Audience) > 1 && azp != p.config.ClientID {
+		return fmt.Errorf("authorized party %q is not this client", azp)
+	}
+
+	now := time.Now()
+	if token.Expiry.IsZero() || now.After(token.Expiry.Add(clockSkew)) {
+		return fmt.Errorf("expired")
+	}
+	if token.IssuedAt.After(now.Add(clockSkew)) {
+		return fmt.Errorf("issued in the future")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token.Nonce), []byte(nonce)) != 1 {
+		return fmt.Errorf("nonce does not match")
+	}
+
+	return nil
+}
+
+// ClaimValues returns a claim as a list of strings, for claims like groups
+// or roles that may be a string or an array. Dots in name reach into
+// nested objects, as in "realm_access.roles".
+func (t *IDToken) ClaimValues(name string) []string {
+	object := t.Claims
+	parts := strings.Split(name, ".")
+	for _, part := range parts[:len(parts)-1] {
+		nested, ok := object[part].(map[string]any)
+		if !ok {
+			return nil
+		}
+		object = nested
+	}
+	return stringsClaim(object, parts[len(parts)-1])
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signed string, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+
+	digest := digest(hash, signed)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return fmt.Errorf("algorithm %s does not match RSA key", alg)
+		}
+		return rsa.VerifyPKCS1v15(key, hash, digest, signature)
+
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			return fmt.Errorf("algorithm %s does not match EC key", alg)
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return fmt.Errorf("invalid EC signature length")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unsupported key type %T", key)
+}
+
+func digest(hash crypto.Hash, signed string) []byte {
+	switch hash {
+	case crypto.SHA384:
+		sum := sha512.Sum384([]byte(signed))
+		return sum[:]
+	case crypto.SHA512:
+		sum := sha512.Sum512([]byte(signed))
+		return sum[:]
+	}
+	sum := sha256.Sum256([]byte(signed))
+	return sum[:]
+}
+
+func decodeSegment(segment string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func stringClaim(claims map[string]any, name string) string {
+	s, _ := claims[name].(string)
+	return s
+}
+
+// stringsClaim reads a claim that is a string or an array of them, like aud.
+func stringsClaim(claims map[string]any, name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func timeClaim(claims map[string]any, name string) time.Time {
+	seconds, ok := claims[name].(float64)
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(int64(seconds), 0)
+}
+
+// boolClaim also accepts "true", which some providers send for
+// email_verified.
+func boolClaim(claims map[string]any, name string) bool {
+	switch v := claims[name].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
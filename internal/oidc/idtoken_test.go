@@ -0,0 +1,424 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testClientID = "rssagg"
+	testNonce    = "nonce-1"
+)
+
+// testProvider is an OpenID provider serving a discovery document and a key
+// set that tests can swap out.
+type testProvider struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	keys    []jsonWebKey
+	fetches int
+}
+
+func newTestProvider(t *testing.T, keys ...jsonWebKey) *testProvider {
+	t.Helper()
+
+	tp := &testProvider{keys: keys}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Metadata{
+			Issuer:                tp.URL,
+			AuthorizationEndpoint: tp.URL + "/authorize",
+			TokenEndpoint:         tp.URL + "/token",
+			JWKSURI:               tp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		tp.mu.Lock()
+		defer tp.mu.Unlock()
+		tp.fetches++
+		json.NewEncoder(w).Encode(map[string]any{"keys": tp.keys})
+	})
+	tp.Server = httptest.NewServer(mux)
+	t.Cleanup(tp.Close)
+	return tp
+}
+
+func (tp *testProvider) setKeys(keys ...jsonWebKey) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	tp.keys = keys
+}
+
+func (tp *testProvider) fetchCount() int {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	return tp.fetches
+}
+
+func (tp *testProvider) provider() *Provider {
+	return NewProvider(Config{IssuerURL: tp.URL, ClientID: testClientID}, tp.Client())
+}
+
+func (tp *testProvider) claims() map[string]any {
+	now := time.Now()
+	return map[string]any{
+		"iss":   tp.URL,
+		"sub":   "user-1",
+		"aud":   testClientID,
+		"exp":   now.Add(time.Hour).Unix(),
+		"iat":   now.Unix(),
+		"nonce": testNonce,
+	}
+}
+
+type testKey struct {
+	kid    string
+	signer crypto.Signer
+}
+
+func newRSAKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKey{kid: kid, signer: key}
+}
+
+func newECKey(t *testing.T, kid string) testKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKey{kid: kid, signer: key}
+}
+
+func (k testKey) jwk() jsonWebKey {
+	switch pub := k.signer.Public().(type) {
+	case *rsa.PublicKey:
+		return jsonWebKey{
+			Kty: "RSA",
+			Kid: k.kid,
+			Use: "sig",
+			N:   encodeSegment(pub.N.Bytes()),
+			E:   encodeSegment(big.NewInt(int64(pub.E)).Bytes()),
+		}
+	case *ecdsa.PublicKey:
+		return jsonWebKey{
+			Kty: "EC",
+			Kid: k.kid,
+			Use: "sig",
+			Crv: "P-256",
+			X:   encodeSegment(pub.X.FillBytes(make([]byte, 32))),
+			Y:   encodeSegment(pub.Y.FillBytes(make([]byte, 32))),
+		}
+	}
+	panic("unsupported key type")
+}
+
+// sign returns claims as a compact JWS signed with k and labelled alg, which
+// need not match the key.
+func (k testKey) sign(t *testing.T, alg string, claims map[string]any) string {
+	t.Helper()
+
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": k.kid, "typ": "JWT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := encodeSegment(header) + "." + encodeSegment(payload)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch key := k.signer.(type) {
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, key, digest[:])
+		if err == nil {
+			signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+		}
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed + "." + encodeSegment(signature)
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	rsaKey := newRSAKey(t, "rsa-1")
+	ecKey := newECKey(t, "ec-1")
+	tp := newTestProvider(t, rsaKey.jwk(), ecKey.jwk())
+
+	tests := []struct {
+		name    string
+		token   func() string
+		nonce   string
+		wantErr string
+	}{
+		{
+			name:  "RSA",
+			token: func() string { return rsaKey.sign(t, "RS256", tp.claims()) },
+		},
+		{
+			name:  "EC",
+			token: func() string { return ecKey.sign(t, "ES256", tp.claims()) },
+		},
+		{
+			name: "several audiences with azp",
+			token: func() string {
+				claims := tp.claims()
+				claims["aud"] = []string{"other", testClientID}
+				claims["azp"] = testClientID
+				return rsaKey.sign(t, "RS256", claims)
+			},
+		},
+		{
+			name: "bad signature",
+			token: func() string {
+				parts := strings.Split(rsaKey.sign(t, "RS256", tp.claims()), ".")
+				other := tp.claims()
+				other["sub"] = "user-2"
+				payload, _ := json.Marshal(other)
+				return parts[0] + "." + encodeSegment(payload) + "." + parts[2]
+			},
+			wantErr: "verification error",
+		},
+		{
+			name: "signed by another key",
+			token: func() string {
+				return newRSAKey(t, rsaKey.kid).sign(t, "RS256", tp.claims())
+			},
+			wantErr: "verification error",
+		},
+		{
+			name:    "RSA key with EC algorithm",
+			token:   func() string { return rsaKey.sign(t, "ES256", tp.claims()) },
+			wantErr: "does not match RSA key",
+		},
+		{
+			name:    "EC key with RSA algorithm",
+			token:   func() string { return ecKey.sign(t, "RS256", tp.claims()) },
+			wantErr: "does not match EC key",
+		},
+		{
+			name:    "unsigned",
+			token:   func() string { return rsaKey.sign(t, "none", tp.claims()) },
+			wantErr: "unsupported signing algorithm",
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				claims := tp.claims()
+				claims["iss"] = "https://evil.example.com"
+				return rsaKey.sign(t, "RS256", claims)
+			},
+			wantErr: "issuer",
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				claims := tp.claims()
+				claims["aud"] = "other"
+				return rsaKey.sign(t, "RS256", claims)
+			},
+			wantErr: "not issued for this client",
+		},
+		{
+			name: "several audiences without azp",
+			token: func() string {
+				claims := tp.claims()
+				claims["aud"] = []string{testClientID, "other"}
+				return rsaKey.sign(t, "RS256", claims)
+			},
+			wantErr: "authorized party",
+		},
+		{
+			name: "wrong azp",
+			token: func() string {
+				claims := tp.claims()
+				claims["aud"] = []string{testClientID, "other"}
+				claims["azp"] = "other"
+				return rsaKey.sign(t, "RS256", claims)
+			},
+			wantErr: "authorized party",
+		},
+		{
+			name: "expired",
+			token: func() string {
+				claims := tp.claims()
+				claims["exp"] = time.Now().Add(-2 * clockSkew).Unix()
+				return rsaKey.sign(t, "RS256", claims)
+			},
+			wantErr: "expired",
+		},
+		{
+			name: "expired within clock skew",
+			token: func() string {
+				claims := tp.claims()
+				claims["exp"] = time.Now().Add(-clockSkew / 2).Unix()
+				return rsaKey.sign(t, "RS256", claims)
+			},
+		},
+		{
+			name: "no expiry",
+			token: func() string {
+				claims := tp.claims()
+				delete(claims, "exp")
+				return rsaKey.sign(t, "RS256", claims)
+			},
+			wantErr: "expired",
+		},
+		{
+			name: "issued in the future",
+			token: func() string {
+				claims := tp.claims()
+				claims["iat"] = time.Now().Add(2 * clockSkew).Unix()
+				return rsaKey.sign(t, "RS256", claims)
+			},
+			wantErr: "issued in the future",
+		},
+		{
+			name:    "nonce mismatch",
+			token:   func() string { return rsaKey.sign(t, "RS256", tp.claims()) },
+			nonce:   "nonce-2",
+			wantErr: "nonce does not match",
+		},
+		{
+			name: "no nonce",
+			token: func() string {
+				claims := tp.claims()
+				delete(claims, "nonce")
+				return rsaKey.sign(t, "RS256", claims)
+			},
+			wantErr: "nonce does not match",
+		},
+		{
+			name:    "malformed",
+			token:   func() string { return "not.a-token" },
+			wantErr: "not a JWS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nonce := tt.nonce
+			if nonce == "" {
+				nonce = testNonce
+			}
+
+			token, err := tp.provider().VerifyIDToken(context.Background(), tt.token(), nonce)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("VerifyIDToken() error = %v", err)
+				}
+				if token.Subject != "user-1" {
+					t.Errorf("Subject = %q, want %q", token.Subject, "user-1")
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("VerifyIDToken() succeeded, want error containing %q", tt.wantErr)
+			}
+			if !errors.Is(err, ErrInvalidIDToken) {
+				t.Errorf("VerifyIDToken() error = %v, want ErrInvalidIDToken", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("VerifyIDToken() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenKeyRollover(t *testing.T) {
+	oldKey := newRSAKey(t, "old")
+	newKey := newECKey(t, "new")
+	tp := newTestProvider(t, oldKey.jwk())
+	p := tp.provider()
+	ctx := context.Background()
+
+	if _, err := p.VerifyIDToken(ctx, oldKey.sign(t, "RS256", tp.claims()), testNonce); err != nil {
+		t.Fatalf("VerifyIDToken() with the old key: %v", err)
+	}
+	if got := tp.fetchCount(); got != 1 {
+		t.Fatalf("key set fetched %d times, want 1", got)
+	}
+
+	tp.setKeys(oldKey.jwk(), newKey.jwk())
+
+	// Right after a fetch, an unknown key ID doesn't refetch, so bogus
+	// tokens can't hammer the provider.
+	if _, err := p.VerifyIDToken(ctx, newKey.sign(t, "ES256", tp.claims()), testNonce); err == nil || !strings.Contains(err.Error(), "unknown signing key") {
+		t.Fatalf("VerifyIDToken() with the new key before refresh: error = %v, want unknown signing key", err)
+	}
+	if got := tp.fetchCount(); got != 1 {
+		t.Fatalf("key set fetched %d times, want 1", got)
+	}
+
+	p.keys.mu.Lock()
+	p.keys.fetchedAt = time.Now().Add(-2 * keySetMinRefresh)
+	p.keys.mu.Unlock()
+
+	if _, err := p.VerifyIDToken(ctx, newKey.sign(t, "ES256", tp.claims()), testNonce); err != nil {
+		t.Fatalf("VerifyIDToken() with the new key: %v", err)
+	}
+	if got := tp.fetchCount(); got != 2 {
+		t.Fatalf("key set fetched %d times, want 2", got)
+	}
+
+	// Known keys are served from the cache.
+	if _, err := p.VerifyIDToken(ctx, oldKey.sign(t, "RS256", tp.claims()), testNonce); err != nil {
+		t.Fatalf("VerifyIDToken() with the old key after refresh: %v", err)
+	}
+	if got := tp.fetchCount(); got != 2 {
+		t.Fatalf("key set fetched %d times, want 2", got)
+	}
+}
+
+func TestVerifyIDTokenKeySetUnavailable(t *testing.T) {
+	key := newRSAKey(t, "rsa-1")
+	tp := newTestProvider(t, key.jwk())
+	p := tp.provider()
+	ctx := context.Background()
+
+	if _, err := p.VerifyIDToken(ctx, key.sign(t, "RS256", tp.claims()), testNonce); err != nil {
+		t.Fatalf("VerifyIDToken(): %v", err)
+	}
+
+	// A stale key set keeps being trusted while the provider is down.
+	tp.Close()
+	p.keys.mu.Lock()
+	p.keys.fetchedAt = time.Now().Add(-2 * keySetTTL)
+	p.keys.mu.Unlock()
+
+	if _, err := p.VerifyIDToken(ctx, key.sign(t, "RS256", tp.claims()), testNonce); err != nil {
+		t.Fatalf("VerifyIDToken() with the provider down: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryStore forgets full buckets.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	limit     Limit
+	tokens    float64
+	updatedAt time.Time
+}
+
+// MemoryStore keeps buckets in this process, so each replica enforces its
+// own limits.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{limit: limit, tokens: float64(limit.Requests), updatedAt: now}
+		s.buckets[key] = b
+	}
+
+	b.limit = limit
+	b.tokens = refill(limit, b.tokens, now.Sub(b.updatedAt))
+	b.updatedAt = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	return NewResult(limit, b.tokens, allowed), nil
+}
+
+// sweep forgets buckets that have refilled, which are no different from
+// ones that were never used.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if refill(b.limit, b.tokens, now.Sub(b.updatedAt)) >= float64(b.limit.Requests) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,123 @@
+// Package ratelimit implements token bucket rate limits.
+//
+// A bucket holds up to Limit.Requests tokens and refills at
+// Requests/Period. Each request takes a token; requests that find the
+// bucket empty are rejected until it has refilled enough for one.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests per Period, all at once or spread out. The zero
+// Limit allows everything.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit parses "<requests>/<period>", where period is a Go duration
+// or one of s, m, h and d, as in "10/m" or "300/5m". "off" and "" parse to
+// the zero Limit, which allows everything.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "off" {
+		return Limit{}, nil
+	}
+
+	requestsStr, periodStr, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("rate limit %q is not <requests>/<period>", s)
+	}
+
+	requests, err := strconv.Atoi(strings.TrimSpace(requestsStr))
+	if err != nil || requests <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q needs a positive number of requests", s)
+	}
+
+	periodStr = strings.TrimSpace(periodStr)
+	var period time.Duration
+	switch periodStr {
+	case "s":
+		period = time.Second
+	case "m":
+		period = time.Minute
+	case "h":
+		period = time.Hour
+	case "d":
+		period = 24 * time.Hour
+	default:
+		if period, err = time.ParseDuration(periodStr); err != nil || period <= 0 {
+			return Limit{}, fmt.Errorf("rate limit %q needs a positive period", s)
+		}
+	}
+
+	return Limit{Requests: requests, Period: period}, nil
+}
+
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// Rate is how many tokens the bucket regains per second.
+func (l Limit) Rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Period)
+}
+
+// Result is what taking a token from a bucket found.
+type Result struct {
+	Allowed bool
+
+	// Remaining is how many whole tokens are left.
+	Remaining int
+
+	// RetryAfter is how long until a rejected request would be allowed.
+	RetryAfter time.Duration
+
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+}
+
+// NewResult describes a bucket left with tokens after a request was
+// allowed or not.
+func NewResult(limit Limit, tokens float64, allowed bool) Result {
+	rate := limit.Rate()
+	result := Result{
+		Allowed:   allowed,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     secondsToDuration((float64(limit.Requests) - tokens) / rate),
+	}
+	if !allowed {
+		result.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+	return result
+}
+
+// Store keeps buckets, by key, and takes tokens from them.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// refill returns the tokens a bucket that had tokens elapsed ago has now.
+func refill(limit Limit, tokens float64, elapsed time.Duration) float64 {
+	return math.Min(float64(limit.Requests), tokens+elapsed.Seconds()*limit.Rate())
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
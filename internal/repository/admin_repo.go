@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+)
+
+type AdminRepository interface {
+	SearchUsers(ctx context.Context, params database.SearchUsersParams) ([]database.SearchUsersRow, error)
+	CountUsers(ctx context.Context, search string) (int64, error)
+	SetUserDisabled(ctx context.Context, params database.SetUserDisabledParams) (int64, error)
+	// DeleteUser hands the user's feeds that others follow to newOwnerID
+	// and deletes the user, in one transaction. It returns how many feeds
+	// were handed over and how many users were deleted.
+	DeleteUser(ctx context.Context, id, newOwnerID uuid.UUID) (handedOver int64, deleted int64, err error)
+	SetFeedDisabled(ctx context.Context, params database.SetFeedDisabledParams) (int64, error)
+	SetFeedOwner(ctx context.Context, params database.SetFeedOwnerParams) (int64, error)
+	PurgePosts(ctx context.Context, params database.PurgePostsParams) (int64, error)
+}
+
+type adminRepository struct {
+	db   *database.Queries
+	conn *sql.DB
+}
+
+func NewAdminRepository(db *database.Queries, conn *sql.DB) AdminRepository {
+	return &adminRepository{
+		db:   db,
+		conn: conn,
+	}
+}
+
+func (r *adminRepository) SearchUsers(ctx context.Context, params database.SearchUsersParams) ([]database.SearchUsersRow, error) {
+	return r.db.SearchUsers(ctx, params)
+}
+
+func (r *adminRepository) CountUsers(ctx context.Context, search string) (int64, error) {
+	return r.db.CountSearchUsers(ctx, search)
+}
+
+func (r *adminRepository) SetUserDisabled(ctx context.Context, params database.SetUserDisabledParams) (int64, error) {
+	return r.db.SetUserDisabled(ctx, params)
+}
+
+func (r *adminRepository) DeleteUser(ctx context.Context, id, newOwnerID uuid.UUID) (handedOver int64, deleted int64, err error) {
+	err = inTx(ctx, r.conn, func(q *database.Queries) error {
+		handedOver, err = q.HandOverSharedFeeds(ctx, database.HandOverSharedFeedsParams{
+			UserID:     id,
+			NewOwnerID: newOwnerID,
+		})
+		if err != nil {
+			return err
+		}
+		deleted, err = q.DeleteUser(ctx, id)
+		return err
+	})
+	return handedOver, deleted, err
+}
+
+func (r *adminRepository) SetFeedDisabled(ctx context.Context, params database.SetFeedDisabledParams) (int64, error) {
+	return r.db.SetFeedDisabled(ctx, params)
+}
+
+func (r *adminRepository) SetFeedOwner(ctx context.Context, params database.SetFeedOwnerParams) (int64, error) {
+	return r.db.SetFeedOwner(ctx, params)
+}
+
+func (r *adminRepository) PurgePosts(ctx context.Context, params database.PurgePostsParams) (int64, error) {
+	return r.db.PurgePosts(ctx, params)
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, params database.CreateAPIKeyParams) (database.ApiKey, error)
+	GetByUser(ctx context.Context, userID uuid.UUID) ([]database.ApiKey, error)
+	GetActiveByHash(ctx context.Context, keyHash string) (database.GetActiveAPIKeyByHashRow, error)
+	GetActiveByClientHash(ctx context.Context, clientHash string) (database.GetActiveAPIKeyByClientHashRow, error)
+	Touch(ctx context.Context, id uuid.UUID) error
+	Revoke(ctx context.Context, params database.RevokeAPIKeyParams) (database.ApiKey, error)
+}
+
+type apiKeyRepository struct {
+	db *database.Queries
+}
+
+func NewAPIKeyRepository(db *database.Queries) APIKeyRepository {
+	return &apiKeyRepository{
+		db: db,
+	}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, params database.CreateAPIKeyParams) (database.ApiKey, error) {
+	return r.db.CreateAPIKey(ctx, params)
+}
+
+func (r *apiKeyRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]database.ApiKey, error) {
+	return r.db.GetAPIKeysForUser(ctx, userID)
+}
+
+func (r *apiKeyRepository) GetActiveByHash(ctx context.Context, keyHash string) (database.GetActiveAPIKeyByHashRow, error) {
+	return r.db.GetActiveAPIKeyByHash(ctx, keyHash)
+}
+
+func (r *apiKeyRepository) GetActiveByClientHash(ctx context.Context, clientHash string) (database.GetActiveAPIKeyByClientHashRow, error) {
+	return r.db.GetActiveAPIKeyByClientHash(ctx, clientHash)
+}
+
+func (r *apiKeyRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	return r.db.TouchAPIKey(ctx, id)
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, params database.RevokeAPIKeyParams) (database.ApiKey, error) {
+	return r.db.RevokeAPIKey(ctx, params)
+}
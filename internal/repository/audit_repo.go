@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/hel1th/rssagg/internal/database"
+)
+
+// AuditRepository only appends to and reads the audit log; the table
+// rejects updates and deletes.
+type AuditRepository interface {
+	Create(ctx context.Context, params database.CreateAuditEntryParams) error
+	List(ctx context.Context, params database.GetAuditLogParams) ([]database.AuditLog, error)
+	ListOldestFirst(ctx context.Context, params database.ExportAuditLogParams) ([]database.AuditLog, error)
+}
+
+type auditRepository struct {
+	db *database.Queries
+}
+
+func NewAuditRepository(db *database.Queries) AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+func (r *auditRepository) Create(ctx context.Context, params database.CreateAuditEntryParams) error {
+	return r.db.CreateAuditEntry(ctx, params)
+}
+
+func (r *auditRepository) List(ctx context.Context, params database.GetAuditLogParams) ([]database.AuditLog, error) {
+	return r.db.GetAuditLog(ctx, params)
+}
+
+func (r *auditRepository) ListOldestFirst(ctx context.Context, params database.ExportAuditLogParams) ([]database.AuditLog, error) {
+	return r.db.ExportAuditLog(ctx, params)
+}
@@ -9,7 +9,10 @@ import (
 
 type FeedFollowRepository interface {
 	Create(ctx context.Context, params database.CreateFeedFollowParams) (database.FeedFollow, error)
-	GetByUser(ctx context.Context, userID uuid.UUID) ([]database.FeedFollow, error)
+	GetByUser(ctx context.Context, userID uuid.UUID) ([]database.GetFeedFollowsRow, error)
+	GetByID(ctx context.Context, params database.GetFeedFollowParams) (database.GetFeedFollowRow, error)
+	GetByFeed(ctx context.Context, params database.GetFeedFollowByFeedParams) (database.FeedFollow, error)
+	UpdatePreferences(ctx context.Context, params database.UpdateFeedFollowPreferencesParams) error
 	Delete(ctx context.Context, params database.DeleteFeedFollowParams) error
 }
 
@@ -27,10 +30,22 @@ func (r *feedFollowRepository) Create(ctx context.Context, params database.Creat
 	return r.db.CreateFeedFollow(ctx, params)
 }
 
-func (r *feedFollowRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]database.FeedFollow, error) {
+func (r *feedFollowRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]database.GetFeedFollowsRow, error) {
 	return r.db.GetFeedFollows(ctx, userID)
 }
 
+func (r *feedFollowRepository) GetByID(ctx context.Context, params database.GetFeedFollowParams) (database.GetFeedFollowRow, error) {
+	return r.db.GetFeedFollow(ctx, params)
+}
+
+func (r *feedFollowRepository) GetByFeed(ctx context.Context, params database.GetFeedFollowByFeedParams) (database.FeedFollow, error) {
+	return r.db.GetFeedFollowByFeed(ctx, params)
+}
+
+func (r *feedFollowRepository) UpdatePreferences(ctx context.Context, params database.UpdateFeedFollowPreferencesParams) error {
+	return r.db.UpdateFeedFollowPreferences(ctx, params)
+}
+
 func (r *feedFollowRepository) Delete(ctx context.Context, params database.DeleteFeedFollowParams) error {
 	return r.db.DeleteFeedFollow(ctx, params)
 }
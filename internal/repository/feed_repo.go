@@ -9,10 +9,16 @@ import (
 
 type FeedRepository interface {
 	Create(ctx context.Context, params database.CreateFeedParams) (database.Feed, error)
-	GetAll(ctx context.Context) ([]database.Feed, error)
+	ListDirectory(ctx context.Context, params database.ListFeedDirectoryParams) ([]database.ListFeedDirectoryRow, error)
+	CountDirectory(ctx context.Context, search string) (int64, error)
 	GetByID(ctx context.Context, id uuid.UUID) (database.Feed, error)
+	GetByURL(ctx context.Context, url string) (database.Feed, error)
 	GetNextToFetch(ctx context.Context, limit int32) ([]database.Feed, error)
 	MarkAsFetched(ctx context.Context, id uuid.UUID) (database.Feed, error)
+	SetFetchError(ctx context.Context, params database.SetFeedFetchErrorParams) error
+	UpdateSiteInfo(ctx context.Context, params database.UpdateFeedSiteInfoParams) error
+	UpdateFavicon(ctx context.Context, params database.UpdateFeedFaviconParams) error
+	CountFollowers(ctx context.Context, id uuid.UUID) (int64, error)
 }
 
 type feedRepository struct {
@@ -29,14 +35,22 @@ func (r *feedRepository) Create(ctx context.Context, params database.CreateFeedP
 	return r.db.CreateFeed(ctx, params)
 }
 
-func (r *feedRepository) GetAll(ctx context.Context) ([]database.Feed, error) {
-	return r.db.GetFeeds(ctx)
+func (r *feedRepository) ListDirectory(ctx context.Context, params database.ListFeedDirectoryParams) ([]database.ListFeedDirectoryRow, error) {
+	return r.db.ListFeedDirectory(ctx, params)
+}
+
+func (r *feedRepository) CountDirectory(ctx context.Context, search string) (int64, error) {
+	return r.db.CountFeedDirectory(ctx, search)
 }
 
 func (r *feedRepository) GetByID(ctx context.Context, id uuid.UUID) (database.Feed, error) {
 	return r.db.GetFeedByID(ctx, id)
 }
 
+func (r *feedRepository) GetByURL(ctx context.Context, url string) (database.Feed, error) {
+	return r.db.GetFeedByURL(ctx, url)
+}
+
 func (r *feedRepository) GetNextToFetch(ctx context.Context, limit int32) ([]database.Feed, error) {
 	return r.db.GetNextFeedsToFetch(ctx, limit)
 }
@@ -44,3 +58,19 @@ func (r *feedRepository) GetNextToFetch(ctx context.Context, limit int32) ([]dat
 func (r *feedRepository) MarkAsFetched(ctx context.Context, id uuid.UUID) (database.Feed, error) {
 	return r.db.MarkFeedAsFetched(ctx, id)
 }
+
+func (r *feedRepository) UpdateSiteInfo(ctx context.Context, params database.UpdateFeedSiteInfoParams) error {
+	return r.db.UpdateFeedSiteInfo(ctx, params)
+}
+
+func (r *feedRepository) SetFetchError(ctx context.Context, params database.SetFeedFetchErrorParams) error {
+	return r.db.SetFeedFetchError(ctx, params)
+}
+
+func (r *feedRepository) UpdateFavicon(ctx context.Context, params database.UpdateFeedFaviconParams) error {
+	return r.db.UpdateFeedFavicon(ctx, params)
+}
+
+func (r *feedRepository) CountFollowers(ctx context.Context, id uuid.UUID) (int64, error) {
+	return r.db.CountFeedFollowers(ctx, id)
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+)
+
+type FeedTokenRepository interface {
+	Create(ctx context.Context, params database.CreateFeedTokenParams) (database.FeedToken, error)
+	GetByUser(ctx context.Context, userID uuid.UUID) ([]database.FeedToken, error)
+	Delete(ctx context.Context, params database.DeleteFeedTokenParams) (int64, error)
+	GetUserByTokenHash(ctx context.Context, tokenHash string) (database.User, error)
+}
+
+type feedTokenRepository struct {
+	db *database.Queries
+}
+
+func NewFeedTokenRepository(db *database.Queries) FeedTokenRepository {
+	return &feedTokenRepository{
+		db: db,
+	}
+}
+
+func (r *feedTokenRepository) Create(ctx context.Context, params database.CreateFeedTokenParams) (database.FeedToken, error) {
+	return r.db.CreateFeedToken(ctx, params)
+}
+
+func (r *feedTokenRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]database.FeedToken, error) {
+	return r.db.GetFeedTokens(ctx, userID)
+}
+
+func (r *feedTokenRepository) Delete(ctx context.Context, params database.DeleteFeedTokenParams) (int64, error) {
+	return r.db.DeleteFeedToken(ctx, params)
+}
+
+func (r *feedTokenRepository) GetUserByTokenHash(ctx context.Context, tokenHash string) (database.User, error) {
+	return r.db.GetUserByFeedToken(ctx, tokenHash)
+}
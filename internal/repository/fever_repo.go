@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+)
+
+type FeverRepository interface {
+	GetLastRefreshedAt(ctx context.Context, userID uuid.UUID) (time.Time, error)
+	GetGroups(ctx context.Context, userID uuid.UUID) ([]database.GetFeverGroupsRow, error)
+	GetFeeds(ctx context.Context, userID uuid.UUID) ([]database.GetFeverFeedsRow, error)
+	GetFavicons(ctx context.Context, userID uuid.UUID) ([]database.GetFeverFaviconsRow, error)
+	GetItems(ctx context.Context, params database.GetFeverItemsParams) ([]database.GetFeverItemsRow, error)
+	CountItems(ctx context.Context, userID uuid.UUID) (int64, error)
+	GetUnreadItemIDs(ctx context.Context, userID uuid.UUID) ([]int64, error)
+	GetSavedItemIDs(ctx context.Context, userID uuid.UUID) ([]int64, error)
+	GetPostID(ctx context.Context, params database.GetPostIDForFeverItemParams) (uuid.UUID, error)
+	MarkFeedRead(ctx context.Context, params database.MarkFeverFeedReadParams) error
+	MarkGroupRead(ctx context.Context, params database.MarkFeverGroupReadParams) error
+}
+
+type feverRepository struct {
+	db *database.Queries
+}
+
+func NewFeverRepository(db *database.Queries) FeverRepository {
+	return &feverRepository{
+		db: db,
+	}
+}
+
+func (r *feverRepository) GetLastRefreshedAt(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	return r.db.GetLastRefreshedAt(ctx, userID)
+}
+
+func (r *feverRepository) GetGroups(ctx context.Context, userID uuid.UUID) ([]database.GetFeverGroupsRow, error) {
+	return r.db.GetFeverGroups(ctx, userID)
+}
+
+func (r *feverRepository) GetFeeds(ctx context.Context, userID uuid.UUID) ([]database.GetFeverFeedsRow, error) {
+	return r.db.GetFeverFeeds(ctx, userID)
+}
+
+func (r *feverRepository) GetFavicons(ctx context.Context, userID uuid.UUID) ([]database.GetFeverFaviconsRow, error) {
+	return r.db.GetFeverFavicons(ctx, userID)
+}
+
+func (r *feverRepository) GetItems(ctx context.Context, params database.GetFeverItemsParams) ([]database.GetFeverItemsRow, error) {
+	return r.db.GetFeverItems(ctx, params)
+}
+
+func (r *feverRepository) CountItems(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return r.db.CountFeverItems(ctx, userID)
+}
+
+func (r *feverRepository) GetUnreadItemIDs(ctx context.Context, userID uuid.UUID) ([]int64, error) {
+	return r.db.GetFeverUnreadItemIDs(ctx, userID)
+}
+
+func (r *feverRepository) GetSavedItemIDs(ctx context.Context, userID uuid.UUID) ([]int64, error) {
+	return r.db.GetFeverSavedItemIDs(ctx, userID)
+}
+
+func (r *feverRepository) GetPostID(ctx context.Context, params database.GetPostIDForFeverItemParams) (uuid.UUID, error) {
+	return r.db.GetPostIDForFeverItem(ctx, params)
+}
+
+func (r *feverRepository) MarkFeedRead(ctx context.Context, params database.MarkFeverFeedReadParams) error {
+	return r.db.MarkFeverFeedRead(ctx, params)
+}
+
+func (r *feverRepository) MarkGroupRead(ctx context.Context, params database.MarkFeverGroupReadParams) error {
+	return r.db.MarkFeverGroupRead(ctx, params)
+}
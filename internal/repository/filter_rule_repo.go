@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+)
+
+type FilterRuleRepository interface {
+	Create(ctx context.Context, params database.CreateFilterRuleParams) (database.FilterRule, error)
+	GetByUser(ctx context.Context, userID uuid.UUID) ([]database.FilterRule, error)
+	GetByID(ctx context.Context, params database.GetFilterRuleParams) (database.FilterRule, error)
+	GetForFeed(ctx context.Context, feedID uuid.UUID) ([]database.FilterRule, error)
+	GetPostsInScope(ctx context.Context, params database.GetPostsInRuleScopeParams) ([]database.Post, error)
+	Delete(ctx context.Context, params database.DeleteFilterRuleParams) (int64, error)
+}
+
+type filterRuleRepository struct {
+	db *database.Queries
+}
+
+func NewFilterRuleRepository(db *database.Queries) FilterRuleRepository {
+	return &filterRuleRepository{
+		db: db,
+	}
+}
+
+func (r *filterRuleRepository) Create(ctx context.Context, params database.CreateFilterRuleParams) (database.FilterRule, error) {
+	return r.db.CreateFilterRule(ctx, params)
+}
+
+func (r *filterRuleRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]database.FilterRule, error) {
+	return r.db.GetFilterRules(ctx, userID)
+}
+
+func (r *filterRuleRepository) GetByID(ctx context.Context, params database.GetFilterRuleParams) (database.FilterRule, error) {
+	return r.db.GetFilterRule(ctx, params)
+}
+
+func (r *filterRuleRepository) GetForFeed(ctx context.Context, feedID uuid.UUID) ([]database.FilterRule, error) {
+	return r.db.GetFilterRulesForFeed(ctx, feedID)
+}
+
+func (r *filterRuleRepository) GetPostsInScope(ctx context.Context, params database.GetPostsInRuleScopeParams) ([]database.Post, error) {
+	return r.db.GetPostsInRuleScope(ctx, params)
+}
+
+func (r *filterRuleRepository) Delete(ctx context.Context, params database.DeleteFilterRuleParams) (int64, error) {
+	return r.db.DeleteFilterRule(ctx, params)
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+)
+
+type FolderRepository interface {
+	Create(ctx context.Context, params database.CreateFolderParams) (database.Folder, error)
+	GetByUser(ctx context.Context, userID uuid.UUID) ([]database.Folder, error)
+	GetByID(ctx context.Context, params database.GetFolderParams) (database.Folder, error)
+	GetByName(ctx context.Context, params database.GetFolderByNameParams) (database.Folder, error)
+	Delete(ctx context.Context, params database.DeleteFolderParams) (int64, error)
+}
+
+type folderRepository struct {
+	db *database.Queries
+}
+
+func NewFolderRepository(db *database.Queries) FolderRepository {
+	return &folderRepository{
+		db: db,
+	}
+}
+
+func (r *folderRepository) Create(ctx context.Context, params database.CreateFolderParams) (database.Folder, error) {
+	return r.db.CreateFolder(ctx, params)
+}
+
+func (r *folderRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]database.Folder, error) {
+	return r.db.GetFolders(ctx, userID)
+}
+
+func (r *folderRepository) GetByID(ctx context.Context, params database.GetFolderParams) (database.Folder, error) {
+	return r.db.GetFolder(ctx, params)
+}
+
+func (r *folderRepository) GetByName(ctx context.Context, params database.GetFolderByNameParams) (database.Folder, error) {
+	return r.db.GetFolderByName(ctx, params)
+}
+
+func (r *folderRepository) Delete(ctx context.Context, params database.DeleteFolderParams) (int64, error) {
+	return r.db.DeleteFolder(ctx, params)
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/hel1th/rssagg/internal/database"
+)
+
+// loggedDB logs queries by name with the attributes of their context, so
+// that a failing query can be traced to the request or scrape that ran it.
+// Arguments are never logged: they include API key hashes and passwords.
+type loggedDB struct {
+	db database.DBTX
+}
+
+// NewLoggedDB wraps db to log failed queries as errors and every query at
+// debug level.
+func NewLoggedDB(db database.DBTX) database.DBTX {
+	return &loggedDB{db: db}
+}
+
+func (l *loggedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	result, err := l.db.ExecContext(ctx, query, args...)
+	logQuery(ctx, query, start, err)
+	return result, err
+}
+
+func (l *loggedDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	start := time.Now()
+	stmt, err := l.db.PrepareContext(ctx, query)
+	logQuery(ctx, query, start, err)
+	return stmt, err
+}
+
+func (l *loggedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := l.db.QueryContext(ctx, query, args...)
+	logQuery(ctx, query, start, err)
+	return rows, err
+}
+
+// QueryRowContext can only log errors running the query; sql.ErrNoRows
+// comes from Scan, and is up to the caller.
+func (l *loggedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	start := time.Now()
+	row := l.db.QueryRowContext(ctx, query, args...)
+	logQuery(ctx, query, start, row.Err())
+	return row
+}
+
+func logQuery(ctx context.Context, query string, start time.Time, err error) {
+	attrs := []any{
+		slog.String("query", queryName(query)),
+		slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+	}
+
+	if err == nil {
+		slog.DebugContext(ctx, "Query", attrs...)
+		return
+	}
+
+	attrs = append(attrs, slog.Any("error", err))
+
+	// Canceled queries are the client going away, and constraint
+	// violations such as duplicates are for the caller to report
+	var pqErr *pq.Error
+	if errors.Is(err, context.Canceled) || (errors.As(err, &pqErr) && pqErr.Code.Class() == "23") {
+		slog.DebugContext(ctx, "Query failed", attrs...)
+		return
+	}
+	slog.ErrorContext(ctx, "Query failed", attrs...)
+}
+
+// queryName returns the sqlc name of query, from its "-- name: X :kind"
+// header.
+func queryName(query string) string {
+	header, _, _ := strings.Cut(query, "\n")
+	name, ok := strings.CutPrefix(header, "-- name: ")
+	if !ok {
+		return "unnamed"
+	}
+	name, _, _ = strings.Cut(name, " ")
+	return name
+}
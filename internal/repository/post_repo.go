@@ -7,8 +7,15 @@ import (
 )
 
 type PostRepository interface {
-	Create(ctx context.Context, params database.CreatePostParams) error
-	GetForUser(ctx context.Context, params database.GetPostsForUserParams) ([]database.Post, error)
+	Create(ctx context.Context, params database.CreatePostParams) (int64, error)
+	GetForUser(ctx context.Context, params database.GetPostsForUserParams) ([]database.GetPostsForUserRow, error)
+	GetForUserSince(ctx context.Context, params database.GetPostsForUserSinceParams) ([]database.GetPostsForUserSinceRow, error)
+	Publish(ctx context.Context, params database.PublishPostsParams) error
+	GetStreamHorizon(ctx context.Context) (int64, error)
+	GetForUserByID(ctx context.Context, params database.GetPostForUserParams) (database.Post, error)
+	ApplyState(ctx context.Context, params database.ApplyPostStateParams) error
+	SetRead(ctx context.Context, params database.SetPostReadParams) error
+	SetStarred(ctx context.Context, params database.SetPostStarredParams) error
 }
 
 type postRepository struct {
@@ -21,10 +28,38 @@ func NewPostRepository(db *database.Queries) PostRepository {
 	}
 }
 
-func (r *postRepository) Create(ctx context.Context, params database.CreatePostParams) error {
+func (r *postRepository) Create(ctx context.Context, params database.CreatePostParams) (int64, error) {
 	return r.db.CreatePost(ctx, params)
 }
 
-func (r *postRepository) GetForUser(ctx context.Context, params database.GetPostsForUserParams) ([]database.Post, error) {
+func (r *postRepository) GetForUser(ctx context.Context, params database.GetPostsForUserParams) ([]database.GetPostsForUserRow, error) {
 	return r.db.GetPostsForUser(ctx, params)
 }
+
+func (r *postRepository) GetForUserSince(ctx context.Context, params database.GetPostsForUserSinceParams) ([]database.GetPostsForUserSinceRow, error) {
+	return r.db.GetPostsForUserSince(ctx, params)
+}
+
+func (r *postRepository) Publish(ctx context.Context, params database.PublishPostsParams) error {
+	return r.db.PublishPosts(ctx, params)
+}
+
+func (r *postRepository) GetStreamHorizon(ctx context.Context) (int64, error) {
+	return r.db.GetPostStreamHorizon(ctx)
+}
+
+func (r *postRepository) GetForUserByID(ctx context.Context, params database.GetPostForUserParams) (database.Post, error) {
+	return r.db.GetPostForUser(ctx, params)
+}
+
+func (r *postRepository) ApplyState(ctx context.Context, params database.ApplyPostStateParams) error {
+	return r.db.ApplyPostState(ctx, params)
+}
+
+func (r *postRepository) SetRead(ctx context.Context, params database.SetPostReadParams) error {
+	return r.db.SetPostRead(ctx, params)
+}
+
+func (r *postRepository) SetStarred(ctx context.Context, params database.SetPostStarredParams) error {
+	return r.db.SetPostStarred(ctx, params)
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/hel1th/rssagg/internal/database"
+)
+
+type RateLimitRepository interface {
+	Take(ctx context.Context, params database.TakeRateLimitTokenParams) (database.TakeRateLimitTokenRow, error)
+	DeleteIdle(ctx context.Context, idleSeconds float64) (int64, error)
+}
+
+type rateLimitRepository struct {
+	db *database.Queries
+}
+
+func NewRateLimitRepository(db *database.Queries) RateLimitRepository {
+	return &rateLimitRepository{
+		db: db,
+	}
+}
+
+func (r *rateLimitRepository) Take(ctx context.Context, params database.TakeRateLimitTokenParams) (database.TakeRateLimitTokenRow, error) {
+	return r.db.TakeRateLimitToken(ctx, params)
+}
+
+func (r *rateLimitRepository) DeleteIdle(ctx context.Context, idleSeconds float64) (int64, error) {
+	return r.db.DeleteIdleRateLimitBuckets(ctx, idleSeconds)
+}
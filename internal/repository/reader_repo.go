@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+)
+
+type ReaderRepository interface {
+	GetSubscriptions(ctx context.Context, userID uuid.UUID) ([]database.GetReaderSubscriptionsRow, error)
+	GetSubscription(ctx context.Context, params database.GetReaderSubscriptionParams) (database.GetReaderSubscriptionRow, error)
+	GetItems(ctx context.Context, params database.GetReaderItemsParams) ([]database.GetReaderItemsRow, error)
+	GetItemIDs(ctx context.Context, params database.GetReaderItemIDsParams) ([]database.GetReaderItemIDsRow, error)
+	GetItemsByIDs(ctx context.Context, params database.GetReaderItemsByIDsParams) ([]database.GetReaderItemsByIDsRow, error)
+	SetItemsRead(ctx context.Context, params database.SetReaderItemsReadParams) error
+	SetItemsStarred(ctx context.Context, params database.SetReaderItemsStarredParams) error
+}
+
+type readerRepository struct {
+	db *database.Queries
+}
+
+func NewReaderRepository(db *database.Queries) ReaderRepository {
+	return &readerRepository{
+		db: db,
+	}
+}
+
+func (r *readerRepository) GetSubscriptions(ctx context.Context, userID uuid.UUID) ([]database.GetReaderSubscriptionsRow, error) {
+	return r.db.GetReaderSubscriptions(ctx, userID)
+}
+
+func (r *readerRepository) GetSubscription(ctx context.Context, params database.GetReaderSubscriptionParams) (database.GetReaderSubscriptionRow, error) {
+	return r.db.GetReaderSubscription(ctx, params)
+}
+
+func (r *readerRepository) GetItems(ctx context.Context, params database.GetReaderItemsParams) ([]database.GetReaderItemsRow, error) {
+	return r.db.GetReaderItems(ctx, params)
+}
+
+func (r *readerRepository) GetItemIDs(ctx context.Context, params database.GetReaderItemIDsParams) ([]database.GetReaderItemIDsRow, error) {
+	return r.db.GetReaderItemIDs(ctx, params)
+}
+
+func (r *readerRepository) GetItemsByIDs(ctx context.Context, params database.GetReaderItemsByIDsParams) ([]database.GetReaderItemsByIDsRow, error) {
+	return r.db.GetReaderItemsByIDs(ctx, params)
+}
+
+func (r *readerRepository) SetItemsRead(ctx context.Context, params database.SetReaderItemsReadParams) error {
+	return r.db.SetReaderItemsRead(ctx, params)
+}
+
+func (r *readerRepository) SetItemsStarred(ctx context.Context, params database.SetReaderItemsStarredParams) error {
+	return r.db.SetReaderItemsStarred(ctx, params)
+}
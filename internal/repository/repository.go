@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"database/sql"
+
 	"github.com/hel1th/rssagg/internal/database"
 )
 
@@ -9,13 +11,43 @@ type Repositories struct {
 	Feed       FeedRepository
 	FeedFollow FeedFollowRepository
 	Post       PostRepository
+	Folder     FolderRepository
+	FilterRule FilterRuleRepository
+	Tag        TagRepository
+	FeedToken  FeedTokenRepository
+	Webhook    WebhookRepository
+	WebSub     WebSubRepository
+	Fever      FeverRepository
+	Reader     ReaderRepository
+	APIKey     APIKeyRepository
+	Session    SessionRepository
+	Identity   UserIdentityRepository
+	Admin      AdminRepository
+	Scraper    ScraperRepository
+	Audit      AuditRepository
+	RateLimit  RateLimitRepository
 }
 
-func NewRepositories(db *database.Queries) *Repositories {
+func NewRepositories(db *database.Queries, conn *sql.DB) *Repositories {
 	return &Repositories{
 		User:       NewUserRepository(db),
 		Feed:       NewFeedRepository(db),
 		FeedFollow: NewFeedFollowRepository(db),
 		Post:       NewPostRepository(db),
+		Folder:     NewFolderRepository(db),
+		FilterRule: NewFilterRuleRepository(db),
+		Tag:        NewTagRepository(db),
+		FeedToken:  NewFeedTokenRepository(db),
+		Webhook:    NewWebhookRepository(db),
+		WebSub:     NewWebSubRepository(db),
+		Fever:      NewFeverRepository(db),
+		Reader:     NewReaderRepository(db),
+		APIKey:     NewAPIKeyRepository(db),
+		Session:    NewSessionRepository(db),
+		Identity:   NewUserIdentityRepository(db),
+		Admin:      NewAdminRepository(db, conn),
+		Scraper:    NewScraperRepository(db),
+		Audit:      NewAuditRepository(db),
+		RateLimit:  NewRateLimitRepository(db),
 	}
 }
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/hel1th/rssagg/internal/database"
+)
+
+type ScraperRepository interface {
+	BeginRun(ctx context.Context, params database.BeginScraperRunParams) error
+	FinishRun(ctx context.Context, workerID string) error
+	GetHeartbeats(ctx context.Context) ([]database.ScraperHeartbeat, error)
+	GetFeedStats(ctx context.Context) (database.GetScraperFeedStatsRow, error)
+	GetOldestFetchedAt(ctx context.Context) (sql.NullTime, error)
+	GetNextDueSince(ctx context.Context) (time.Time, error)
+	GetFailingFeeds(ctx context.Context, limit int32) ([]database.Feed, error)
+}
+
+type scraperRepository struct {
+	db *database.Queries
+}
+
+func NewScraperRepository(db *database.Queries) ScraperRepository {
+	return &scraperRepository{
+		db: db,
+	}
+}
+
+func (r *scraperRepository) BeginRun(ctx context.Context, params database.BeginScraperRunParams) error {
+	return r.db.BeginScraperRun(ctx, params)
+}
+
+func (r *scraperRepository) FinishRun(ctx context.Context, workerID string) error {
+	return r.db.FinishScraperRun(ctx, workerID)
+}
+
+func (r *scraperRepository) GetHeartbeats(ctx context.Context) ([]database.ScraperHeartbeat, error) {
+	return r.db.GetScraperHeartbeats(ctx)
+}
+
+func (r *scraperRepository) GetFeedStats(ctx context.Context) (database.GetScraperFeedStatsRow, error) {
+	return r.db.GetScraperFeedStats(ctx)
+}
+
+func (r *scraperRepository) GetOldestFetchedAt(ctx context.Context) (sql.NullTime, error) {
+	return r.db.GetOldestFetchedAt(ctx)
+}
+
+func (r *scraperRepository) GetNextDueSince(ctx context.Context) (time.Time, error) {
+	return r.db.GetNextDueSince(ctx)
+}
+
+func (r *scraperRepository) GetFailingFeeds(ctx context.Context, limit int32) ([]database.Feed, error) {
+	return r.db.GetFailingFeeds(ctx, limit)
+}
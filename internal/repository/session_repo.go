@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, params database.CreateSessionParams) (database.Session, error)
+	GetActiveByHash(ctx context.Context, tokenHash string) (database.GetActiveSessionByHashRow, error)
+	Touch(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteForUser(ctx context.Context, params database.DeleteUserSessionsParams) error
+	CreatePasswordReset(ctx context.Context, params database.CreatePasswordResetParams) (database.PasswordReset, error)
+	ConsumePasswordReset(ctx context.Context, tokenHash string) (database.PasswordReset, error)
+}
+
+type sessionRepository struct {
+	db *database.Queries
+}
+
+func NewSessionRepository(db *database.Queries) SessionRepository {
+	return &sessionRepository{
+		db: db,
+	}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, params database.CreateSessionParams) (database.Session, error) {
+	return r.db.CreateSession(ctx, params)
+}
+
+func (r *sessionRepository) GetActiveByHash(ctx context.Context, tokenHash string) (database.GetActiveSessionByHashRow, error) {
+	return r.db.GetActiveSessionByHash(ctx, tokenHash)
+}
+
+func (r *sessionRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	return r.db.TouchSession(ctx, id)
+}
+
+func (r *sessionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.DeleteSession(ctx, id)
+}
+
+func (r *sessionRepository) DeleteForUser(ctx context.Context, params database.DeleteUserSessionsParams) error {
+	return r.db.DeleteUserSessions(ctx, params)
+}
+
+func (r *sessionRepository) CreatePasswordReset(ctx context.Context, params database.CreatePasswordResetParams) (database.PasswordReset, error) {
+	return r.db.CreatePasswordReset(ctx, params)
+}
+
+func (r *sessionRepository) ConsumePasswordReset(ctx context.Context, tokenHash string) (database.PasswordReset, error) {
+	return r.db.ConsumePasswordReset(ctx, tokenHash)
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+)
+
+type TagRepository interface {
+	Create(ctx context.Context, params database.CreateTagParams) (database.Tag, error)
+	Upsert(ctx context.Context, params database.UpsertTagParams) (database.Tag, error)
+	GetByUser(ctx context.Context, userID uuid.UUID) ([]database.GetTagsRow, error)
+	GetByID(ctx context.Context, params database.GetTagParams) (database.Tag, error)
+	GetByName(ctx context.Context, params database.GetTagByNameParams) (database.Tag, error)
+	Rename(ctx context.Context, params database.RenameTagParams) (database.Tag, error)
+	Delete(ctx context.Context, params database.DeleteTagParams) (int64, error)
+	AddToPost(ctx context.Context, params database.AddPostTagParams) error
+	RemoveFromPost(ctx context.Context, params database.RemovePostTagParams) (int64, error)
+}
+
+type tagRepository struct {
+	db *database.Queries
+}
+
+func NewTagRepository(db *database.Queries) TagRepository {
+	return &tagRepository{
+		db: db,
+	}
+}
+
+func (r *tagRepository) Create(ctx context.Context, params database.CreateTagParams) (database.Tag, error) {
+	return r.db.CreateTag(ctx, params)
+}
+
+func (r *tagRepository) Upsert(ctx context.Context, params database.UpsertTagParams) (database.Tag, error) {
+	return r.db.UpsertTag(ctx, params)
+}
+
+func (r *tagRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]database.GetTagsRow, error) {
+	return r.db.GetTags(ctx, userID)
+}
+
+func (r *tagRepository) GetByID(ctx context.Context, params database.GetTagParams) (database.Tag, error) {
+	return r.db.GetTag(ctx, params)
+}
+
+func (r *tagRepository) GetByName(ctx context.Context, params database.GetTagByNameParams) (database.Tag, error) {
+	return r.db.GetTagByName(ctx, params)
+}
+
+func (r *tagRepository) Rename(ctx context.Context, params database.RenameTagParams) (database.Tag, error) {
+	return r.db.RenameTag(ctx, params)
+}
+
+func (r *tagRepository) Delete(ctx context.Context, params database.DeleteTagParams) (int64, error) {
+	return r.db.DeleteTag(ctx, params)
+}
+
+func (r *tagRepository) AddToPost(ctx context.Context, params database.AddPostTagParams) error {
+	return r.db.AddPostTag(ctx, params)
+}
+
+func (r *tagRepository) RemoveFromPost(ctx context.Context, params database.RemovePostTagParams) (int64, error) {
+	return r.db.RemovePostTag(ctx, params)
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+// tracedDB runs each query in a span named after it, so that slow requests
+// show which queries they waited on. As with loggedDB, arguments are left
+// out.
+type tracedDB struct {
+	db database.DBTX
+}
+
+// NewTracedDB wraps db to trace every query.
+func NewTracedDB(db database.DBTX) database.DBTX {
+	return &tracedDB{db: db}
+}
+
+func (t *tracedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startQuery(ctx, query)
+	result, err := t.db.ExecContext(ctx, query, args...)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (t *tracedDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	ctx, span := startQuery(ctx, query)
+	stmt, err := t.db.PrepareContext(ctx, query)
+	tracing.End(span, err)
+	return stmt, err
+}
+
+// QueryContext's span ends when the query returns its first rows, not
+// when they have all been read.
+func (t *tracedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := startQuery(ctx, query)
+	rows, err := t.db.QueryContext(ctx, query, args...)
+	tracing.End(span, err)
+	return rows, err
+}
+
+func (t *tracedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := startQuery(ctx, query)
+	row := t.db.QueryRowContext(ctx, query, args...)
+	tracing.End(span, row.Err())
+	return row
+}
+
+func startQuery(ctx context.Context, query string) (context.Context, trace.Span) {
+	return tracing.StartKind(ctx, queryName(query), trace.SpanKindClient,
+		semconv.DBSystemPostgreSQL,
+		semconv.DBQueryText(query),
+	)
+}
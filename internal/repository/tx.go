@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/hel1th/rssagg/internal/database"
+)
+
+// inTx runs fn with queries bound to a transaction on conn, traced and
+// logged like the queries run outside one. The transaction is committed if
+// fn succeeds and rolled back otherwise.
+func inTx(ctx context.Context, conn *sql.DB, fn func(q *database.Queries) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(database.New(NewTracedDB(NewLoggedDB(tx)))); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
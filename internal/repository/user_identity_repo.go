@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/hel1th/rssagg/internal/database"
+)
+
+type UserIdentityRepository interface {
+	Create(ctx context.Context, params database.CreateUserIdentityParams) (database.UserIdentity, error)
+	GetUser(ctx context.Context, params database.GetUserByIdentityParams) (database.GetUserByIdentityRow, error)
+	Touch(ctx context.Context, params database.TouchUserIdentityParams) error
+}
+
+type userIdentityRepository struct {
+	db *database.Queries
+}
+
+func NewUserIdentityRepository(db *database.Queries) UserIdentityRepository {
+	return &userIdentityRepository{
+		db: db,
+	}
+}
+
+func (r *userIdentityRepository) Create(ctx context.Context, params database.CreateUserIdentityParams) (database.UserIdentity, error) {
+	return r.db.CreateUserIdentity(ctx, params)
+}
+
+func (r *userIdentityRepository) GetUser(ctx context.Context, params database.GetUserByIdentityParams) (database.GetUserByIdentityRow, error) {
+	return r.db.GetUserByIdentity(ctx, params)
+}
+
+func (r *userIdentityRepository) Touch(ctx context.Context, params database.TouchUserIdentityParams) error {
+	return r.db.TouchUserIdentity(ctx, params)
+}
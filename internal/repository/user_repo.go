@@ -2,13 +2,20 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 
+	"github.com/google/uuid"
 	"github.com/hel1th/rssagg/internal/database"
 )
 
 type UserRepository interface {
 	Create(ctx context.Context, params database.CreateUserParams) (database.User, error)
-	GetByAPIKey(ctx context.Context, apiKey string) (database.User, error)
+	GetByID(ctx context.Context, id uuid.UUID) (database.User, error)
+	GetByUsername(ctx context.Context, username string) (database.User, error)
+	GetByEmail(ctx context.Context, email string) (database.User, error)
+	UpdatePassword(ctx context.Context, params database.UpdateUserPasswordParams) error
+	UpdateRole(ctx context.Context, params database.UpdateUserRoleParams) error
+	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type userRepository struct {
@@ -25,6 +32,27 @@ func (r *userRepository) Create(ctx context.Context, params database.CreateUserP
 	return r.db.CreateUser(ctx, params)
 }
 
-func (r *userRepository) GetByAPIKey(ctx context.Context, apiKey string) (database.User, error) {
-	return r.db.GetUserByAPIKey(ctx, apiKey)
+func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (database.User, error) {
+	return r.db.GetUserByID(ctx, id)
+}
+
+func (r *userRepository) GetByUsername(ctx context.Context, username string) (database.User, error) {
+	return r.db.GetUserByUsername(ctx, sql.NullString{String: username, Valid: true})
+}
+
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (database.User, error) {
+	return r.db.GetUserByEmail(ctx, sql.NullString{String: email, Valid: true})
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, params database.UpdateUserPasswordParams) error {
+	return r.db.UpdateUserPassword(ctx, params)
+}
+
+func (r *userRepository) UpdateRole(ctx context.Context, params database.UpdateUserRoleParams) error {
+	return r.db.UpdateUserRole(ctx, params)
+}
+
+func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.DeleteUser(ctx, id)
+	return err
 }
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, params database.CreateWebhookParams) (database.Webhook, error)
+	GetByUser(ctx context.Context, userID uuid.UUID) ([]database.Webhook, error)
+	GetByID(ctx context.Context, params database.GetWebhookParams) (database.Webhook, error)
+	Get(ctx context.Context, id uuid.UUID) (database.Webhook, error)
+	Delete(ctx context.Context, params database.DeleteWebhookParams) (int64, error)
+	GetForFeed(ctx context.Context, feedID uuid.UUID) ([]database.GetWebhooksForFeedRow, error)
+	AddDigestPost(ctx context.Context, params database.AddWebhookDigestPostParams) error
+	GetDueDigests(ctx context.Context, params database.GetDueWebhookDigestsParams) ([]uuid.UUID, error)
+	ClaimDigestPosts(ctx context.Context, webhookID uuid.UUID) ([]database.ClaimWebhookDigestPostsRow, error)
+	CreateDelivery(ctx context.Context, params database.CreateWebhookDeliveryParams) (int64, error)
+	GetDeliveries(ctx context.Context, params database.GetWebhookDeliveriesParams) ([]database.WebhookDelivery, error)
+	ClaimDueDeliveries(ctx context.Context, params database.ClaimDueWebhookDeliveriesParams) ([]database.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, params database.RecordWebhookDeliveryAttemptParams) error
+	RequeueDelivery(ctx context.Context, params database.RequeueWebhookDeliveryParams) (int64, error)
+}
+
+type webhookRepository struct {
+	db *database.Queries
+}
+
+func NewWebhookRepository(db *database.Queries) WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, params database.CreateWebhookParams) (database.Webhook, error) {
+	return r.db.CreateWebhook(ctx, params)
+}
+
+func (r *webhookRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]database.Webhook, error) {
+	return r.db.GetWebhooks(ctx, userID)
+}
+
+func (r *webhookRepository) GetByID(ctx context.Context, params database.GetWebhookParams) (database.Webhook, error) {
+	return r.db.GetWebhook(ctx, params)
+}
+
+func (r *webhookRepository) Get(ctx context.Context, id uuid.UUID) (database.Webhook, error) {
+	return r.db.GetWebhookByID(ctx, id)
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, params database.DeleteWebhookParams) (int64, error) {
+	return r.db.DeleteWebhook(ctx, params)
+}
+
+func (r *webhookRepository) GetForFeed(ctx context.Context, feedID uuid.UUID) ([]database.GetWebhooksForFeedRow, error) {
+	return r.db.GetWebhooksForFeed(ctx, feedID)
+}
+
+func (r *webhookRepository) AddDigestPost(ctx context.Context, params database.AddWebhookDigestPostParams) error {
+	return r.db.AddWebhookDigestPost(ctx, params)
+}
+
+func (r *webhookRepository) GetDueDigests(ctx context.Context, params database.GetDueWebhookDigestsParams) ([]uuid.UUID, error) {
+	return r.db.GetDueWebhookDigests(ctx, params)
+}
+
+func (r *webhookRepository) ClaimDigestPosts(ctx context.Context, webhookID uuid.UUID) ([]database.ClaimWebhookDigestPostsRow, error) {
+	return r.db.ClaimWebhookDigestPosts(ctx, webhookID)
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, params database.CreateWebhookDeliveryParams) (int64, error) {
+	return r.db.CreateWebhookDelivery(ctx, params)
+}
+
+func (r *webhookRepository) GetDeliveries(ctx context.Context, params database.GetWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	return r.db.GetWebhookDeliveries(ctx, params)
+}
+
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, params database.ClaimDueWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	return r.db.ClaimDueWebhookDeliveries(ctx, params)
+}
+
+func (r *webhookRepository) RecordAttempt(ctx context.Context, params database.RecordWebhookDeliveryAttemptParams) error {
+	return r.db.RecordWebhookDeliveryAttempt(ctx, params)
+}
+
+func (r *webhookRepository) RequeueDelivery(ctx context.Context, params database.RequeueWebhookDeliveryParams) (int64, error) {
+	return r.db.RequeueWebhookDelivery(ctx, params)
+}
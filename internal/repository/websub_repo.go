@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+)
+
+type WebSubRepository interface {
+	Upsert(ctx context.Context, params database.UpsertWebSubSubscriptionParams) (database.WebsubSubscription, error)
+	GetByID(ctx context.Context, id uuid.UUID) (database.WebsubSubscription, error)
+	GetByFeed(ctx context.Context, feedID uuid.UUID) (database.WebsubSubscription, error)
+	Activate(ctx context.Context, params database.ActivateWebSubSubscriptionParams) error
+	Deny(ctx context.Context, params database.DenyWebSubSubscriptionParams) error
+	RecordRequest(ctx context.Context, params database.RecordWebSubRequestParams) error
+	GetToRenew(ctx context.Context, params database.GetWebSubSubscriptionsToRenewParams) ([]database.WebsubSubscription, error)
+}
+
+type webSubRepository struct {
+	db *database.Queries
+}
+
+func NewWebSubRepository(db *database.Queries) WebSubRepository {
+	return &webSubRepository{
+		db: db,
+	}
+}
+
+func (r *webSubRepository) Upsert(ctx context.Context, params database.UpsertWebSubSubscriptionParams) (database.WebsubSubscription, error) {
+	return r.db.UpsertWebSubSubscription(ctx, params)
+}
+
+func (r *webSubRepository) GetByID(ctx context.Context, id uuid.UUID) (database.WebsubSubscription, error) {
+	return r.db.GetWebSubSubscription(ctx, id)
+}
+
+func (r *webSubRepository) GetByFeed(ctx context.Context, feedID uuid.UUID) (database.WebsubSubscription, error) {
+	return r.db.GetWebSubSubscriptionForFeed(ctx, feedID)
+}
+
+func (r *webSubRepository) Activate(ctx context.Context, params database.ActivateWebSubSubscriptionParams) error {
+	return r.db.ActivateWebSubSubscription(ctx, params)
+}
+
+func (r *webSubRepository) Deny(ctx context.Context, params database.DenyWebSubSubscriptionParams) error {
+	return r.db.DenyWebSubSubscription(ctx, params)
+}
+
+func (r *webSubRepository) RecordRequest(ctx context.Context, params database.RecordWebSubRequestParams) error {
+	return r.db.RecordWebSubRequest(ctx, params)
+}
+
+func (r *webSubRepository) GetToRenew(ctx context.Context, params database.GetWebSubSubscriptionsToRenewParams) ([]database.WebsubSubscription, error) {
+	return r.db.GetWebSubSubscriptionsToRenew(ctx, params)
+}
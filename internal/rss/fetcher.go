@@ -1,34 +1,91 @@
 package rss
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/tracing"
 )
 
+// Fetcher fetches feeds on behalf of their subscribers, whose number it
+// tells the site in its User-Agent.
 type Fetcher interface {
-	Fetch(url string) (*domain.RSSFeedData, error)
+	Fetch(ctx context.Context, url string, subscribers int64) (*domain.RSSFeedData, error)
+	// FetchFavicon returns the favicon of the site at siteURL as a
+	// base64 data URI without the "data:" scheme, e.g. "image/png;base64,...".
+	FetchFavicon(ctx context.Context, siteURL string, subscribers int64) (string, error)
+}
+
+// maxFaviconSize caps the size of a favicon kept for a feed.
+const maxFaviconSize = 64 << 10
+
+// DefaultUserAgent identifies us to the sites we fetch, the way well
+// behaved aggregators do. {subscribers} is replaced by the number of
+// users following the feed.
+const DefaultUserAgent = "rssagg/1.0 (+https://github.com/hel1th/rssagg; {subscribers} subscribers)"
+
+// DefaultTimeout bounds each request, waiting for the host's turn aside.
+const DefaultTimeout = 10 * time.Second
+
+type FetcherConfig struct {
+	// UserAgent is sent with every request, with {subscribers} replaced.
+	// DefaultUserAgent if empty.
+	UserAgent string
+
+	// Timeout bounds each request, from sending it to reading the body.
+	// DefaultTimeout if zero.
+	Timeout time.Duration
+
+	// Scheduler spaces out requests to each host. A Scheduler with the
+	// default limits if nil.
+	Scheduler *Scheduler
 }
 
 type httpFetcher struct {
-	client http.Client
+	client    http.Client
+	userAgent string
+	scheduler *Scheduler
 }
 
-func NewFetcher() Fetcher {
+func NewFetcher(config FetcherConfig) Fetcher {
+	if config.UserAgent == "" {
+		config.UserAgent = DefaultUserAgent
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.Scheduler == nil {
+		config.Scheduler = NewScheduler(SchedulerConfig{HostDelay: DefaultHostDelay, HostGroups: DefaultHostGroups})
+	}
+
 	return &httpFetcher{
-		client: http.Client{Timeout: 10 * time.Second},
+		client:    http.Client{Timeout: config.Timeout},
+		userAgent: config.UserAgent,
+		scheduler: config.Scheduler,
 	}
 }
 
-func (h *httpFetcher) Fetch(url string) (*domain.RSSFeedData, error) {
-	resp, err := h.client.Get(url)
+func (h *httpFetcher) Fetch(ctx context.Context, url string, subscribers int64) (*domain.RSSFeedData, error) {
+	resp, release, err := h.get(ctx, url, subscribers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch URL: %w", err)
 	}
+	defer release()
 	defer resp.Body.Close()
 
 	data, err := io.ReadAll(resp.Body)
@@ -36,45 +93,205 @@ func (h *httpFetcher) Fetch(url string) (*domain.RSSFeedData, error) {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	feed, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// WebSub allows advertising the hub in HTTP Link headers instead of,
+	// or as well as, the document itself.
+	hub, self := linkHeaderURLs(resp.Header.Values("Link"))
+	if feed.HubURL == "" {
+		feed.HubURL = hub
+	}
+	if feed.SelfURL == "" {
+		feed.SelfURL = self
+	}
+
+	return feed, nil
+}
+
+func (h *httpFetcher) FetchFavicon(ctx context.Context, siteURL string, subscribers int64) (string, error) {
+	base, err := url.Parse(siteURL)
+	if err != nil || base.Host == "" {
+		return "", fmt.Errorf("invalid site URL %q", siteURL)
+	}
+	faviconURL := base.ResolveReference(&url.URL{Path: "/favicon.ico"})
+
+	resp, release, err := h.get(ctx, faviconURL.String(), subscribers)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch favicon: %w", err)
+	}
+	defer release()
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("favicon request returned %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFaviconSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read favicon: %w", err)
+	}
+	if len(data) == 0 || len(data) > maxFaviconSize {
+		return "", fmt.Errorf("favicon is empty or larger than %d bytes", maxFaviconSize)
+	}
+
+	contentType := http.DetectContentType(data)
+	if header := resp.Header.Get("Content-Type"); strings.HasPrefix(header, "image/") {
+		contentType, _, _ = strings.Cut(header, ";")
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		return "", fmt.Errorf("favicon has non-image content type %s", contentType)
+	}
+
+	return contentType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// get sends a GET once the scheduler lets it. The returned function frees
+// the scheduler's slot and must be called once the body has been read.
+//
+// The request is traced from before waiting for the scheduler until the
+// body has been read, and carries the trace context.
+func (h *httpFetcher) get(ctx context.Context, url string, subscribers int64) (*http.Response, func(), error) {
+	ctx, span := tracing.StartKind(ctx, "GET", trace.SpanKindClient,
+		semconv.HTTPRequestMethodGet,
+		semconv.URLFull(url),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		tracing.End(span, err)
+		return nil, nil, err
+	}
+	req.Header.Set("User-Agent", strings.ReplaceAll(h.userAgent, "{subscribers}", strconv.FormatInt(subscribers, 10)))
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	release, err := h.scheduler.Acquire(ctx, url)
+	if err != nil {
+		tracing.End(span, err)
+		return nil, nil, err
+	}
+	span.AddEvent("scheduled")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		release()
+		tracing.End(span, err)
+		return nil, nil, err
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+
+	return resp, func() {
+		release()
+		span.End()
+	}, nil
+}
+
+// Parse decodes an RSS 2.0 document.
+func Parse(data []byte) (*domain.RSSFeedData, error) {
 	var rssFeed feedXML
 	if err := xml.Unmarshal(data, &rssFeed); err != nil {
 		return nil, fmt.Errorf("failed to parse RSS XML: %w", err)
 	}
 
-	return xmlToDomain(rssFeed), nil
+	feed := xmlToDomain(rssFeed)
+	feed.Size = len(data)
+
+	return feed, nil
 }
 
 type feedXML struct {
 	Channel struct {
-		Title       string    `xml:"title"`
-		Link        string    `xml:"link"`
-		Description string    `xml:"description"`
-		Item        []itemXML `xml:"item"`
+		Title string `xml:"title"`
+		// AtomLinks must come before Link so that <atom:link> elements
+		// don't land in the plain RSS <link>.
+		AtomLinks   []atomLinkXML `xml:"http://www.w3.org/2005/Atom link"`
+		Link        string        `xml:"link"`
+		Description string        `xml:"description"`
+		Item        []itemXML     `xml:"item"`
 	} `xml:"channel"`
 }
 
+type atomLinkXML struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
 type itemXML struct {
 	Title       string `xml:"title"`
 	Link        string `xml:"link"`
 	Description string `xml:"description"`
 	PubDate     string `xml:"pubDate"`
+	Author      string `xml:"author"`
+	Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
 }
 
 func xmlToDomain(xmlFeed feedXML) *domain.RSSFeedData {
 	items := make([]domain.RSSItemData, len(xmlFeed.Channel.Item))
 	for i, item := range xmlFeed.Channel.Item {
+		author := item.Author
+		if author == "" {
+			author = item.Creator
+		}
 		items[i] = domain.RSSItemData{
 			Title:       item.Title,
 			Description: item.Description,
 			Link:        item.Link,
 			PubDate:     item.PubDate,
+			Author:      author,
 		}
 	}
 
-	return &domain.RSSFeedData{
+	feed := &domain.RSSFeedData{
 		Title:       xmlFeed.Channel.Title,
 		Description: xmlFeed.Channel.Description,
 		Link:        xmlFeed.Channel.Link,
 		Items:       items,
 	}
+	for _, link := range xmlFeed.Channel.AtomLinks {
+		switch {
+		case link.Rel == "hub" && feed.HubURL == "":
+			feed.HubURL = link.Href
+		case link.Rel == "self" && feed.SelfURL == "":
+			feed.SelfURL = link.Href
+		}
+	}
+
+	return feed
+}
+
+// linkHeaderURLs extracts the rel="hub" and rel="self" targets from HTTP
+// Link header values such as `<https://hub.example>; rel="hub"`.
+func linkHeaderURLs(values []string) (hub, self string) {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			target = target[1 : len(target)-1]
+
+			for _, param := range parts[1:] {
+				key, val, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(key, "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(val, `"`)) {
+					switch {
+					case rel == "hub" && hub == "":
+						hub = target
+					case rel == "self" && self == "":
+						self = target
+					}
+				}
+			}
+		}
+	}
+	return hub, self
 }
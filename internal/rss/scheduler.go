@@ -0,0 +1,177 @@
+package rss
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scheduler defaults. Concurrency limits left zero in a SchedulerConfig
+// fall back to theirs; a zero HostDelay means no delay.
+const (
+	DefaultConcurrency     = 10
+	DefaultHostConcurrency = 2
+	DefaultHostDelay       = 2 * time.Second
+)
+
+// DefaultHostGroups are domains whose feeds live on many subdomains of
+// the same servers.
+var DefaultHostGroups = []string{"substack.com", "medium.com", "blogspot.com", "wordpress.com", "tumblr.com"}
+
+// hostSweepInterval is how often the Scheduler forgets idle hosts.
+const hostSweepInterval = time.Minute
+
+type SchedulerConfig struct {
+	// Concurrency caps requests in flight across all hosts.
+	Concurrency int
+
+	// HostConcurrency caps requests in flight to any one host.
+	HostConcurrency int
+
+	// HostDelay is the least time between starting two requests to the
+	// same host.
+	HostDelay time.Duration
+
+	// HostGroups are domains whose subdomains count as one host, so
+	// a.substack.com and b.substack.com share their limits.
+	HostGroups []string
+}
+
+type hostState struct {
+	slots chan struct{}
+	next  time.Time
+	users int
+}
+
+// Scheduler spaces out requests so that no host gets more than its share
+// of them, whichever feeds they are for.
+type Scheduler struct {
+	config    SchedulerConfig
+	global    chan struct{}
+	mu        sync.Mutex
+	hosts     map[string]*hostState
+	lastSweep time.Time
+}
+
+func NewScheduler(config SchedulerConfig) *Scheduler {
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultConcurrency
+	}
+	if config.HostConcurrency <= 0 {
+		config.HostConcurrency = DefaultHostConcurrency
+	}
+	if config.HostDelay < 0 {
+		config.HostDelay = 0
+	}
+	groups := make([]string, 0, len(config.HostGroups))
+	for _, group := range config.HostGroups {
+		if group = strings.ToLower(strings.Trim(strings.TrimSpace(group), ".")); group != "" {
+			groups = append(groups, group)
+		}
+	}
+	config.HostGroups = groups
+
+	return &Scheduler{
+		config:    config,
+		global:    make(chan struct{}, config.Concurrency),
+		hosts:     make(map[string]*hostState),
+		lastSweep: time.Now(),
+	}
+}
+
+// Acquire waits until a request to rawURL may start: until its host has a
+// free slot and its delay has passed, then until there is a free slot
+// overall. The returned function must be called once the request is done.
+func (s *Scheduler) Acquire(ctx context.Context, rawURL string) (func(), error) {
+	host := s.hostState(s.HostKey(rawURL))
+	leave := func() {
+		s.mu.Lock()
+		host.users--
+		s.mu.Unlock()
+	}
+
+	select {
+	case host.slots <- struct{}{}:
+	case <-ctx.Done():
+		leave()
+		return nil, ctx.Err()
+	}
+
+	s.mu.Lock()
+	start := time.Now()
+	if host.next.After(start) {
+		start = host.next
+	}
+	host.next = start.Add(s.config.HostDelay)
+	s.mu.Unlock()
+
+	if wait := time.Until(start); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			<-host.slots
+			leave()
+			return nil, ctx.Err()
+		}
+	}
+
+	select {
+	case s.global <- struct{}{}:
+	case <-ctx.Done():
+		<-host.slots
+		leave()
+		return nil, ctx.Err()
+	}
+
+	return func() {
+		<-s.global
+		<-host.slots
+		leave()
+	}, nil
+}
+
+// HostKey returns the host whose limits a request to rawURL counts
+// against: its host name, or the host group it belongs to.
+func (s *Scheduler) HostKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return rawURL
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, group := range s.config.HostGroups {
+		if host == group || strings.HasSuffix(host, "."+group) {
+			return group
+		}
+	}
+	return host
+}
+
+func (s *Scheduler) hostState(key string) *hostState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= hostSweepInterval {
+		// Hosts nobody is waiting on and whose delay has passed are no
+		// different from ones never seen
+		for k, h := range s.hosts {
+			if h.users == 0 && !h.next.After(now) {
+				delete(s.hosts, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	host, ok := s.hosts[key]
+	if !ok {
+		host = &hostState{slots: make(chan struct{}, s.config.HostConcurrency)}
+		s.hosts[key] = host
+	}
+	host.users++
+	return host
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	gosql "database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/auth"
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+// AccountService handles password logins and the sessions they start.
+type AccountService interface {
+	Login(ctx context.Context, username, password, userAgent, ip string) (*domain.User, *domain.Session, error)
+	StartSession(ctx context.Context, userID uuid.UUID, userAgent, ip string) (*domain.Session, error)
+	Authenticate(ctx context.Context, token string) (*domain.User, *domain.Session, error)
+	Logout(ctx context.Context, sessionID uuid.UUID) error
+	ChangePassword(ctx context.Context, user *domain.User, session *domain.Session, currentPassword, newPassword string) error
+	IssuePasswordReset(ctx context.Context, admin *domain.User, userID uuid.UUID) (*domain.PasswordReset, error)
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
+
+type accountService struct {
+	users    repository.UserRepository
+	sessions repository.SessionRepository
+	audit    AuditService
+}
+
+func NewAccountService(users repository.UserRepository, sessions repository.SessionRepository, audit AuditService) AccountService {
+	return &accountService{
+		users:    users,
+		sessions: sessions,
+		audit:    audit,
+	}
+}
+
+// Login checks a username and password and starts a session. The session's
+// plaintext Token and CSRFToken are only returned here.
+func (s *accountService) Login(ctx context.Context, username, password, userAgent, ip string) (*domain.User, *domain.Session, error) {
+	ctx, span := tracing.Start(ctx, "AccountService.Login")
+	defer span.End()
+
+	username = strings.ToLower(strings.TrimSpace(username))
+
+	// A missing user is still checked against a hash, so failed logins
+	// take as long whether or not the username exists.
+	var hash string
+	dbUser, err := s.users.GetByUsername(ctx, username)
+	if err == nil {
+		hash = dbUser.PasswordHash.String
+	} else if !errors.Is(err, gosql.ErrNoRows) {
+		return nil, nil, err
+	}
+	if !auth.CheckPassword(hash, password) {
+		return nil, nil, domain.ErrInvalidCredentials
+	}
+
+	user := domain.MapUserFromDB(dbUser)
+	if user.IsDisabled() {
+		return nil, nil, domain.ErrUserDisabled
+	}
+
+	session, err := s.StartSession(ctx, user.ID, userAgent, ip)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, session, nil
+}
+
+// StartSession logs a user in who was authenticated some other way, as by
+// single sign-on.
+func (s *accountService) StartSession(ctx context.Context, userID uuid.UUID, userAgent, ip string) (*domain.Session, error) {
+	ctx, span := tracing.Start(ctx, "AccountService.StartSession")
+	defer span.End()
+
+	session := domain.NewSession(userID, userAgent, ip)
+
+	token, err := auth.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	csrfToken, err := auth.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	dbSession, err := s.sessions.Create(ctx, database.CreateSessionParams{
+		ID:         session.ID,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+		UserID:     session.UserID,
+		TokenHash:  auth.HashToken(token),
+		CsrfToken:  csrfToken,
+		UserAgent:  session.UserAgent,
+		Ip:         session.IP,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	created := domain.MapSessionFromDB(dbSession)
+	created.Token = token
+	return created, nil
+}
+
+// Authenticate returns the user of an unexpired session, along with the
+// session.
+func (s *accountService) Authenticate(ctx context.Context, token string) (*domain.User, *domain.Session, error) {
+	ctx, span := tracing.Start(ctx, "AccountService.Authenticate")
+	defer span.End()
+
+	if token == "" {
+		return nil, nil, domain.ErrInvalidSession
+	}
+
+	row, err := s.sessions.GetActiveByHash(ctx, auth.HashToken(token))
+	if err != nil {
+		return nil, nil, notFound(err, domain.ErrInvalidSession)
+	}
+
+	if err := s.sessions.Touch(ctx, row.Session.ID); err != nil {
+		slog.ErrorContext(ctx, "Error recording use of session", "session_id", row.Session.ID, "error", err)
+	}
+
+	return domain.MapUserFromDB(row.User), domain.MapSessionFromDB(row.Session), nil
+}
+
+func (s *accountService) Logout(ctx context.Context, sessionID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "AccountService.Logout")
+	defer span.End()
+
+	return s.sessions.Delete(ctx, sessionID)
+}
+
+// ChangePassword sets a new password and signs the user out of every other
+// session. Users who already have a password must give it; users created
+// without one can set a first password this way. session is nil when the
+// request was authenticated with an API key.
+func (s *accountService) ChangePassword(ctx context.Context, user *domain.User, session *domain.Session, currentPassword, newPassword string) error {
+	ctx, span := tracing.Start(ctx, "AccountService.ChangePassword")
+	defer span.End()
+
+	if user.Username == nil {
+		return domain.ErrUsernameRequired
+	}
+	if user.HasPassword() && !auth.CheckPassword(user.PasswordHash, currentPassword) {
+		return domain.ErrInvalidCredentials
+	}
+
+	keep := uuid.NullUUID{}
+	if session != nil {
+		keep = uuid.NullUUID{UUID: session.ID, Valid: true}
+	}
+
+	if err := s.setPassword(ctx, user.ID, newPassword, keep); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEntry(&user.ID, &user.ID, domain.AuditUserChangePassword, domain.AuditTargetUser, &user.ID, nil, nil))
+	return nil
+}
+
+func (s *accountService) setPassword(ctx context.Context, userID uuid.UUID, password string, keep uuid.NullUUID) error {
+	if err := domain.ValidatePassword(password); err != nil {
+		return err
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	if err := s.users.UpdatePassword(ctx, database.UpdateUserPasswordParams{
+		ID:           userID,
+		PasswordHash: gosql.NullString{String: hash, Valid: true},
+	}); err != nil {
+		return err
+	}
+
+	return s.sessions.DeleteForUser(ctx, database.DeleteUserSessionsParams{
+		UserID: userID,
+		KeepID: keep,
+	})
+}
+
+// IssuePasswordReset creates a one-time token the user can set a new
+// password with. Only admins can issue one, and only for users who have a
+// username to log in with. The plaintext Token is only returned here.
+func (s *accountService) IssuePasswordReset(ctx context.Context, admin *domain.User, userID uuid.UUID) (*domain.PasswordReset, error) {
+	ctx, span := tracing.Start(ctx, "AccountService.IssuePasswordReset")
+	defer span.End()
+
+	if !admin.IsAdmin() {
+		return nil, domain.ErrAdminRequired
+	}
+
+	dbUser, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gosql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	if !dbUser.Username.Valid {
+		return nil, domain.ErrUsernameRequired
+	}
+
+	reset := domain.NewPasswordReset(dbUser.ID, admin.ID)
+
+	token, err := auth.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	dbReset, err := s.sessions.CreatePasswordReset(ctx, database.CreatePasswordResetParams{
+		ID:        reset.ID,
+		CreatedAt: reset.CreatedAt,
+		ExpiresAt: reset.ExpiresAt,
+		UserID:    reset.UserID,
+		CreatedBy: uuid.NullUUID{UUID: admin.ID, Valid: true},
+		TokenHash: auth.HashToken(token),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	created := domain.MapPasswordResetFromDB(dbReset)
+	created.Token = token
+	return created, nil
+}
+
+// ResetPassword redeems a password reset token and signs the user out
+// everywhere.
+func (s *accountService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	ctx, span := tracing.Start(ctx, "AccountService.ResetPassword")
+	defer span.End()
+
+	if err := domain.ValidatePassword(newPassword); err != nil {
+		return err
+	}
+	if token == "" {
+		return domain.ErrInvalidPasswordReset
+	}
+
+	reset, err := s.sessions.ConsumePasswordReset(ctx, auth.HashToken(token))
+	if err != nil {
+		if errors.Is(err, gosql.ErrNoRows) {
+			return domain.ErrInvalidPasswordReset
+		}
+		return err
+	}
+
+	if err := s.setPassword(ctx, reset.UserID, newPassword, uuid.NullUUID{}); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEntry(&reset.UserID, &reset.UserID, domain.AuditUserRedeemReset, domain.AuditTargetUser, &reset.UserID, nil, nil))
+	return nil
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	gosql "database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+// AdminService is what admins can do to any user, feed or post. Every
+// action is recorded in the audit log.
+type AdminService interface {
+	SearchUsers(ctx context.Context, query domain.AdminUserQuery) ([]*domain.AdminUser, int64, error)
+	SetUserDisabled(ctx context.Context, admin *domain.User, userID uuid.UUID, disabled bool) error
+	SetUserRole(ctx context.Context, admin *domain.User, userID uuid.UUID, role string) error
+	DeleteUser(ctx context.Context, admin *domain.User, userID uuid.UUID) error
+	IssuePasswordReset(ctx context.Context, admin *domain.User, userID uuid.UUID) (*domain.PasswordReset, error)
+	RefreshFeed(ctx context.Context, admin *domain.User, feedID uuid.UUID) (int, error)
+	SetFeedDisabled(ctx context.Context, admin *domain.User, feedID uuid.UUID, disabled bool) error
+	ReassignFeed(ctx context.Context, admin *domain.User, feedID, userID uuid.UUID) error
+	PurgePosts(ctx context.Context, admin *domain.User, query domain.PurgePostsQuery) (int64, error)
+}
+
+type adminService struct {
+	repo     repository.AdminRepository
+	users    repository.UserRepository
+	feeds    repository.FeedRepository
+	sessions repository.SessionRepository
+	accounts AccountService
+	rss      RSSService
+	audit    AuditService
+}
+
+func NewAdminService(repo repository.AdminRepository, users repository.UserRepository, feeds repository.FeedRepository, sessions repository.SessionRepository, accounts AccountService, rss RSSService, audit AuditService) AdminService {
+	return &adminService{
+		repo:     repo,
+		users:    users,
+		feeds:    feeds,
+		sessions: sessions,
+		accounts: accounts,
+		rss:      rss,
+		audit:    audit,
+	}
+}
+
+func (s *adminService) SearchUsers(ctx context.Context, query domain.AdminUserQuery) ([]*domain.AdminUser, int64, error) {
+	ctx, span := tracing.Start(ctx, "AdminService.SearchUsers")
+	defer span.End()
+
+	query.Validate()
+
+	rows, err := s.repo.SearchUsers(ctx, database.SearchUsersParams{
+		Search:     query.SearchPattern(),
+		PageLimit:  int32(query.Limit),
+		PageOffset: int32(query.Offset),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.repo.CountUsers(ctx, query.SearchPattern())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return domain.MapAdminUsersFromDB(rows), total, nil
+}
+
+// SetUserDisabled disables or re-enables a user. Disabling also ends their
+// sessions; their API keys and feed tokens stop working while disabled.
+func (s *adminService) SetUserDisabled(ctx context.Context, admin *domain.User, userID uuid.UUID, disabled bool) error {
+	ctx, span := tracing.Start(ctx, "AdminService.SetUserDisabled")
+	defer span.End()
+
+	if userID == admin.ID {
+		return domain.ErrCannotModifySelf
+	}
+
+	dbUser, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gosql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return err
+	}
+
+	updated, err := s.repo.SetUserDisabled(ctx, database.SetUserDisabledParams{
+		ID:       userID,
+		Disabled: disabled,
+	})
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	action := domain.AuditUserEnable
+	if disabled {
+		action = domain.AuditUserDisable
+		if err := s.sessions.DeleteForUser(ctx, database.DeleteUserSessionsParams{UserID: userID}); err != nil {
+			return err
+		}
+	}
+
+	s.record(ctx, admin, &userID, action, domain.AuditTargetUser, &userID,
+		map[string]any{"disabled": dbUser.DisabledAt.Valid},
+		map[string]any{"disabled": disabled})
+	return nil
+}
+
+// SetUserRole makes a user an admin or a plain user. admin is nil when
+// the role is set from the command line, as the first admin's is.
+func (s *adminService) SetUserRole(ctx context.Context, admin *domain.User, userID uuid.UUID, role string) error {
+	ctx, span := tracing.Start(ctx, "AdminService.SetUserRole")
+	defer span.End()
+
+	if !domain.IsValidUserRole(role) {
+		return domain.ErrInvalidUserRole
+	}
+	if admin != nil && userID == admin.ID {
+		return domain.ErrCannotModifySelf
+	}
+
+	dbUser, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gosql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return err
+	}
+	if dbUser.Role == role {
+		return nil
+	}
+
+	if err := s.users.UpdateRole(ctx, database.UpdateUserRoleParams{ID: userID, Role: role}); err != nil {
+		return err
+	}
+
+	s.record(ctx, admin, &userID, domain.AuditUserChangeRole, domain.AuditTargetUser, &userID,
+		map[string]any{"role": dbUser.Role},
+		map[string]any{"role": role})
+	return nil
+}
+
+// DeleteUser deletes a user with everything they own. Feeds they created
+// that other users follow are handed to the admin instead of going with
+// them.
+func (s *adminService) DeleteUser(ctx context.Context, admin *domain.User, userID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "AdminService.DeleteUser")
+	defer span.End()
+
+	if userID == admin.ID {
+		return domain.ErrCannotModifySelf
+	}
+
+	dbUser, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gosql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return err
+	}
+
+	handedOver, deleted, err := s.repo.DeleteUser(ctx, userID, admin.ID)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	before := domain.AuditUser(domain.MapUserFromDB(dbUser))
+	before["feeds_handed_over"] = handedOver
+	s.record(ctx, admin, &userID, domain.AuditUserDelete, domain.AuditTargetUser, &userID, before, nil)
+	return nil
+}
+
+func (s *adminService) IssuePasswordReset(ctx context.Context, admin *domain.User, userID uuid.UUID) (*domain.PasswordReset, error) {
+	ctx, span := tracing.Start(ctx, "AdminService.IssuePasswordReset")
+	defer span.End()
+
+	reset, err := s.accounts.IssuePasswordReset(ctx, admin, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, admin, &userID, domain.AuditUserIssueReset, domain.AuditTargetUser, &userID, nil, map[string]any{
+		"expires_at": reset.ExpiresAt,
+	})
+	return reset, nil
+}
+
+// RefreshFeed fetches a feed now, even if it is disabled, and returns how
+// many new posts it had.
+func (s *adminService) RefreshFeed(ctx context.Context, admin *domain.User, feedID uuid.UUID) (int, error) {
+	ctx, span := tracing.Start(ctx, "AdminService.RefreshFeed")
+	defer span.End()
+
+	dbFeed, err := s.feeds.GetByID(ctx, feedID)
+	if err != nil {
+		if errors.Is(err, gosql.ErrNoRows) {
+			return 0, domain.ErrFeedNotFound
+		}
+		return 0, err
+	}
+
+	newPosts, fetchErr := s.rss.FetchSingleFeed(ctx, *domain.MapFeedFromDB(dbFeed))
+
+	result := map[string]any{"new_posts": newPosts}
+	if fetchErr != nil {
+		result["error"] = fetchErr.Error()
+	}
+	s.record(ctx, admin, nil, domain.AuditFeedRefresh, domain.AuditTargetFeed, &feedID, nil, result)
+
+	return newPosts, fetchErr
+}
+
+func (s *adminService) SetFeedDisabled(ctx context.Context, admin *domain.User, feedID uuid.UUID, disabled bool) error {
+	ctx, span := tracing.Start(ctx, "AdminService.SetFeedDisabled")
+	defer span.End()
+
+	dbFeed, err := s.feeds.GetByID(ctx, feedID)
+	if err != nil {
+		if errors.Is(err, gosql.ErrNoRows) {
+			return domain.ErrFeedNotFound
+		}
+		return err
+	}
+
+	updated, err := s.repo.SetFeedDisabled(ctx, database.SetFeedDisabledParams{
+		ID:       feedID,
+		Disabled: disabled,
+	})
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return domain.ErrFeedNotFound
+	}
+
+	action := domain.AuditFeedEnable
+	if disabled {
+		action = domain.AuditFeedDisable
+	}
+	s.record(ctx, admin, nil, action, domain.AuditTargetFeed, &feedID,
+		map[string]any{"disabled": dbFeed.DisabledAt.Valid},
+		map[string]any{"disabled": disabled})
+	return nil
+}
+
+func (s *adminService) ReassignFeed(ctx context.Context, admin *domain.User, feedID, userID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "AdminService.ReassignFeed")
+	defer span.End()
+
+	dbFeed, err := s.feeds.GetByID(ctx, feedID)
+	if err != nil {
+		if errors.Is(err, gosql.ErrNoRows) {
+			return domain.ErrFeedNotFound
+		}
+		return err
+	}
+
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, gosql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return err
+	}
+
+	if _, err := s.repo.SetFeedOwner(ctx, database.SetFeedOwnerParams{
+		ID:     feedID,
+		UserID: userID,
+	}); err != nil {
+		return err
+	}
+
+	s.record(ctx, admin, &userID, domain.AuditFeedReassign, domain.AuditTargetFeed, &feedID,
+		map[string]any{"user_id": dbFeed.UserID},
+		map[string]any{"user_id": userID})
+	return nil
+}
+
+func (s *adminService) PurgePosts(ctx context.Context, admin *domain.User, query domain.PurgePostsQuery) (int64, error) {
+	ctx, span := tracing.Start(ctx, "AdminService.PurgePosts")
+	defer span.End()
+
+	if err := query.Validate(); err != nil {
+		return 0, err
+	}
+
+	params := database.PurgePostsParams{}
+	purge := map[string]any{}
+	if query.FeedID != nil {
+		params.FeedID = uuid.NullUUID{UUID: *query.FeedID, Valid: true}
+		purge["feed_id"] = *query.FeedID
+	}
+	if query.Before != nil {
+		params.Before = gosql.NullTime{Time: query.Before.UTC(), Valid: true}
+		purge["published_before"] = query.Before.UTC()
+	}
+
+	purged, err := s.repo.PurgePosts(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+
+	purge["posts"] = purged
+	s.record(ctx, admin, nil, domain.AuditPostsPurge, domain.AuditTargetPosts, query.FeedID, purge, nil)
+	return purged, nil
+}
+
+// record adds an admin's action on userID's account, or on no user's in
+// particular when it is nil, to the audit log. Actions taken from the
+// command line have no admin and are recorded without an actor.
+func (s *adminService) record(ctx context.Context, admin *domain.User, userID *uuid.UUID, action, targetType string, targetID *uuid.UUID, before, after any) {
+	var actorID *uuid.UUID
+	if admin != nil {
+		actorID = &admin.ID
+	}
+	s.audit.Record(ctx, domain.NewAuditEntry(actorID, userID, action, targetType, targetID, before, after))
+}
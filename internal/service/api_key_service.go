@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	gosql "database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/auth"
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, user *domain.User, name, scope string, expiresAt *time.Time) (*domain.APIKey, error)
+	GetUserAPIKeys(ctx context.Context, userID uuid.UUID) ([]*domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID, userID uuid.UUID) error
+	Authenticate(ctx context.Context, key string) (*domain.User, *domain.APIKey, error)
+	AuthenticateClientHash(ctx context.Context, clientHash string) (*domain.User, *domain.APIKey, error)
+}
+
+type apiKeyService struct {
+	repo  repository.APIKeyRepository
+	audit AuditService
+}
+
+func NewAPIKeyService(repo repository.APIKeyRepository, audit AuditService) APIKeyService {
+	return &apiKeyService{
+		repo:  repo,
+		audit: audit,
+	}
+}
+
+// CreateAPIKey creates a key for user. Only its hashes are stored, the
+// plaintext key is returned in Key this once.
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, user *domain.User, name, scope string, expiresAt *time.Time) (*domain.APIKey, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.CreateAPIKey")
+	defer span.End()
+
+	apiKey := domain.NewAPIKey(name, scope, expiresAt, user.ID)
+
+	if err := apiKey.Validate(); err != nil {
+		return nil, err
+	}
+
+	secret, err := auth.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	expires := gosql.NullTime{}
+	if apiKey.ExpiresAt != nil {
+		expires = gosql.NullTime{Time: apiKey.ExpiresAt.UTC(), Valid: true}
+	}
+
+	dbKey, err := s.repo.Create(ctx, database.CreateAPIKeyParams{
+		ID:         apiKey.ID,
+		CreatedAt:  apiKey.CreatedAt,
+		UpdatedAt:  apiKey.UpdatedAt,
+		UserID:     apiKey.UserID,
+		Name:       apiKey.Name,
+		Prefix:     secret[:domain.APIKeyPrefixLength],
+		KeyHash:    auth.HashToken(secret),
+		ClientHash: auth.HashToken(auth.ClientHash(user.Name, secret)),
+		Scope:      apiKey.Scope,
+		ExpiresAt:  expires,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	created := domain.MapAPIKeyFromDB(dbKey)
+	s.audit.Record(ctx, domain.NewAuditEntry(&user.ID, &user.ID, domain.AuditAPIKeyCreate, domain.AuditTargetAPIKey, &created.ID, nil, domain.AuditAPIKey(created)))
+
+	created.Key = secret
+	return created, nil
+}
+
+func (s *apiKeyService) GetUserAPIKeys(ctx context.Context, userID uuid.UUID) ([]*domain.APIKey, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.GetUserAPIKeys")
+	defer span.End()
+
+	if userID == uuid.Nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	dbKeys, err := s.repo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.MapAPIKeysFromDB(dbKeys), nil
+}
+
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, keyID, userID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "APIKeyService.RevokeAPIKey")
+	defer span.End()
+
+	dbKey, err := s.repo.Revoke(ctx, database.RevokeAPIKeyParams{
+		ID:     keyID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, gosql.ErrNoRows) {
+			return domain.ErrAPIKeyNotFound
+		}
+		return err
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEntry(&userID, &userID, domain.AuditAPIKeyRevoke, domain.AuditTargetAPIKey, &keyID, domain.AuditAPIKey(domain.MapAPIKeyFromDB(dbKey)), nil))
+	return nil
+}
+
+// Authenticate returns the owner of an active (not revoked or expired) key,
+// along with the key.
+func (s *apiKeyService) Authenticate(ctx context.Context, key string) (*domain.User, *domain.APIKey, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.Authenticate")
+	defer span.End()
+
+	if key == "" {
+		return nil, nil, domain.ErrInvalidAPIKey
+	}
+
+	row, err := s.repo.GetActiveByHash(ctx, auth.HashToken(key))
+	if err != nil {
+		return nil, nil, notFound(err, domain.ErrInvalidAPIKey)
+	}
+
+	return s.authenticated(ctx, row.User, row.ApiKey)
+}
+
+// AuthenticateClientHash is Authenticate for clients that send
+// md5("<user name>:<key>") instead of the key. Like the key, it is only
+// stored hashed.
+func (s *apiKeyService) AuthenticateClientHash(ctx context.Context, clientHash string) (*domain.User, *domain.APIKey, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.AuthenticateClientHash")
+	defer span.End()
+
+	if clientHash == "" {
+		return nil, nil, domain.ErrInvalidAPIKey
+	}
+
+	row, err := s.repo.GetActiveByClientHash(ctx, auth.HashToken(clientHash))
+	if err != nil {
+		return nil, nil, notFound(err, domain.ErrInvalidAPIKey)
+	}
+
+	return s.authenticated(ctx, row.User, row.ApiKey)
+}
+
+func (s *apiKeyService) authenticated(ctx context.Context, dbUser database.User, dbKey database.ApiKey) (*domain.User, *domain.APIKey, error) {
+	if err := s.repo.Touch(ctx, dbKey.ID); err != nil {
+		slog.ErrorContext(ctx, "Error recording use of API key", "api_key_id", dbKey.ID, "error", err)
+	}
+
+	return domain.MapUserFromDB(dbUser), domain.MapAPIKeyFromDB(dbKey), nil
+}
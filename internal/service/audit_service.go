@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/hel1th/rssagg/internal/audit"
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+// AuditService keeps the append-only audit log of changes to accounts,
+// subscriptions and feeds.
+type AuditService interface {
+	Record(ctx context.Context, entry *domain.AuditEntry)
+	GetEntries(ctx context.Context, query domain.AuditQuery) ([]*domain.AuditEntry, error)
+	Export(ctx context.Context, query domain.AuditQuery, write func(*domain.AuditEntry) error) error
+}
+
+type auditService struct {
+	repo repository.AuditRepository
+}
+
+func NewAuditService(repo repository.AuditRepository) AuditService {
+	return &auditService{
+		repo: repo,
+	}
+}
+
+// Record appends entry to the log, with the IP and user agent of the
+// request ctx carries. The change has already been made, so failing to
+// record it is logged rather than returned.
+func (s *auditService) Record(ctx context.Context, entry *domain.AuditEntry) {
+	ctx, span := tracing.Start(ctx, "AuditService.Record")
+	defer span.End()
+
+	req := audit.RequestFromContext(ctx)
+	entry.IP = req.IP
+	entry.UserAgent = req.UserAgent
+
+	if err := s.repo.Create(ctx, database.CreateAuditEntryParams{
+		ID:         entry.ID,
+		CreatedAt:  entry.CreatedAt,
+		ActorID:    nullUUID(entry.ActorID),
+		UserID:     nullUUID(entry.UserID),
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   nullUUID(entry.TargetID),
+		Ip:         entry.IP,
+		UserAgent:  entry.UserAgent,
+		Before:     auditJSON(ctx, entry.Action, entry.Before),
+		After:      auditJSON(ctx, entry.Action, entry.After),
+	}); err != nil {
+		slog.ErrorContext(ctx, "Error recording audit entry", "action", entry.Action, "entry_id", entry.ID, "error", err)
+	}
+}
+
+// GetEntries returns the newest entries first.
+func (s *auditService) GetEntries(ctx context.Context, query domain.AuditQuery) ([]*domain.AuditEntry, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.GetEntries")
+	defer span.End()
+
+	query.Validate()
+
+	dbEntries, err := s.repo.List(ctx, database.GetAuditLogParams{
+		UserID:     nullUUID(query.UserID),
+		Action:     query.Action,
+		PageLimit:  int32(query.Limit),
+		PageOffset: int32(query.Offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.MapAuditEntriesFromDB(dbEntries), nil
+}
+
+// Export passes every entry query matches to write, oldest first, ignoring
+// its Limit and Offset.
+func (s *auditService) Export(ctx context.Context, query domain.AuditQuery, write func(*domain.AuditEntry) error) error {
+	ctx, span := tracing.Start(ctx, "AuditService.Export")
+	defer span.End()
+
+	for offset := 0; ; offset += domain.AuditExportBatchSize {
+		dbEntries, err := s.repo.ListOldestFirst(ctx, database.ExportAuditLogParams{
+			UserID:     nullUUID(query.UserID),
+			Action:     query.Action,
+			PageLimit:  domain.AuditExportBatchSize,
+			PageOffset: int32(offset),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range domain.MapAuditEntriesFromDB(dbEntries) {
+			if err := write(entry); err != nil {
+				return err
+			}
+		}
+
+		if len(dbEntries) < domain.AuditExportBatchSize {
+			return nil
+		}
+	}
+}
+
+func auditJSON(ctx context.Context, action string, value any) json.RawMessage {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error encoding audit value", "action", action, "error", err)
+		return json.RawMessage("null")
+	}
+	return encoded
+}
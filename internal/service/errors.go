@@ -0,0 +1,44 @@
+package service
+
+import (
+	gosql "database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// Postgres error codes, from
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pqForeignKeyViolation = "23503"
+	pqUniqueViolation     = "23505"
+)
+
+// notFound returns missing if err is sql.ErrNoRows, and err otherwise, so
+// that failed queries aren't reported as rows that don't exist.
+func notFound(err, missing error) error {
+	if errors.Is(err, gosql.ErrNoRows) {
+		return missing
+	}
+	return err
+}
+
+// isUniqueViolation reports whether err is a Postgres unique_violation.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation
+}
+
+// isUniqueViolationOn is isUniqueViolation for one constraint or unique
+// index, for tables with more than one.
+func isUniqueViolationOn(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation && pqErr.Constraint == constraint
+}
+
+// isForeignKeyViolation reports whether err is a Postgres
+// foreign_key_violation, as when a row refers to one that doesn't exist.
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pqForeignKeyViolation
+}
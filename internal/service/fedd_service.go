@@ -7,27 +7,34 @@ import (
 	"github.com/hel1th/rssagg/internal/database"
 	"github.com/hel1th/rssagg/internal/domain"
 	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
 )
 
 type FeedService interface {
 	CreateFeed(ctx context.Context, name, url string, userID uuid.UUID) (*domain.Feed, error)
-	GetAllFeeds(ctx context.Context) ([]*domain.Feed, error)
+	ListFeedDirectory(ctx context.Context, query domain.FeedDirectoryQuery) ([]*domain.FeedDirectoryEntry, int64, error)
 	GetFeedByID(ctx context.Context, id uuid.UUID) (*domain.Feed, error)
+	GetFeedByURL(ctx context.Context, url string) (*domain.Feed, error)
 	GetNextFeedsToFetch(ctx context.Context, limit int) ([]*domain.Feed, error)
 	MarkFeedAsFetched(ctx context.Context, id uuid.UUID) (*domain.Feed, error)
 }
 
 type feedService struct {
-	repo repository.FeedRepository
+	repo  repository.FeedRepository
+	audit AuditService
 }
 
-func NewFeedService(repo repository.FeedRepository) FeedService {
+func NewFeedService(repo repository.FeedRepository, audit AuditService) FeedService {
 	return &feedService{
-		repo: repo,
+		repo:  repo,
+		audit: audit,
 	}
 }
 
 func (s *feedService) CreateFeed(ctx context.Context, name, url string, userID uuid.UUID) (*domain.Feed, error) {
+	ctx, span := tracing.Start(ctx, "FeedService.CreateFeed")
+	defer span.End()
+
 	feed := domain.NewFeed(name, url, userID)
 
 	if err := feed.Validate(); err != nil {
@@ -43,34 +50,72 @@ func (s *feedService) CreateFeed(ctx context.Context, name, url string, userID u
 		UserID:    feed.UserID,
 	})
 	if err != nil {
-		if err.Error() == "duplicate key value violates unique constraint" {
+		if isUniqueViolation(err) {
 			return nil, domain.ErrDuplicateFeed
 		}
 		return nil, err
 	}
 
-	return domain.MapFeedFromDB(dbFeed), nil
+	created := domain.MapFeedFromDB(dbFeed)
+	s.audit.Record(ctx, domain.NewAuditEntry(&userID, &userID, domain.AuditFeedCreate, domain.AuditTargetFeed, &created.ID, nil, domain.AuditFeed(created)))
+
+	return created, nil
 }
 
-func (s *feedService) GetAllFeeds(ctx context.Context) ([]*domain.Feed, error) {
-	dbFeeds, err := s.repo.GetAll(ctx)
+func (s *feedService) ListFeedDirectory(ctx context.Context, query domain.FeedDirectoryQuery) ([]*domain.FeedDirectoryEntry, int64, error) {
+	ctx, span := tracing.Start(ctx, "FeedService.ListFeedDirectory")
+	defer span.End()
+
+	if err := query.Validate(); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := s.repo.ListDirectory(ctx, database.ListFeedDirectoryParams{
+		Search:     query.SearchPattern(),
+		Sort:       query.Sort,
+		PageLimit:  int32(query.Limit),
+		PageOffset: int32(query.Offset),
+	})
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return domain.MapFeedsFromDB(dbFeeds), nil
+	total, err := s.repo.CountDirectory(ctx, query.SearchPattern())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return domain.MapFeedDirectoryFromDB(rows), total, nil
 }
 
 func (s *feedService) GetFeedByID(ctx context.Context, id uuid.UUID) (*domain.Feed, error) {
+	ctx, span := tracing.Start(ctx, "FeedService.GetFeedByID")
+	defer span.End()
+
 	dbFeed, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return nil, domain.ErrFeedNotFound
+		return nil, notFound(err, domain.ErrFeedNotFound)
+	}
+
+	return domain.MapFeedFromDB(dbFeed), nil
+}
+
+func (s *feedService) GetFeedByURL(ctx context.Context, url string) (*domain.Feed, error) {
+	ctx, span := tracing.Start(ctx, "FeedService.GetFeedByURL")
+	defer span.End()
+
+	dbFeed, err := s.repo.GetByURL(ctx, url)
+	if err != nil {
+		return nil, notFound(err, domain.ErrFeedNotFound)
 	}
 
 	return domain.MapFeedFromDB(dbFeed), nil
 }
 
 func (s *feedService) GetNextFeedsToFetch(ctx context.Context, limit int) ([]*domain.Feed, error) {
+	ctx, span := tracing.Start(ctx, "FeedService.GetNextFeedsToFetch")
+	defer span.End()
+
 	if limit <= 0 {
 		limit = 10 
 	}
@@ -84,9 +129,12 @@ func (s *feedService) GetNextFeedsToFetch(ctx context.Context, limit int) ([]*do
 }
 
 func (s *feedService) MarkFeedAsFetched(ctx context.Context, id uuid.UUID) (*domain.Feed, error) {
+	ctx, span := tracing.Start(ctx, "FeedService.MarkFeedAsFetched")
+	defer span.End()
+
 	dbFeed, err := s.repo.MarkAsFetched(ctx, id)
 	if err != nil {
-		return nil, domain.ErrFeedNotFound
+		return nil, notFound(err, domain.ErrFeedNotFound)
 	}
 
 	return domain.MapFeedFromDB(dbFeed), nil
@@ -2,37 +2,47 @@ package service
 
 import (
 	"context"
+	gosql "database/sql"
 
 	"github.com/google/uuid"
 	"github.com/hel1th/rssagg/internal/database"
 	"github.com/hel1th/rssagg/internal/domain"
 	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
 )
 
 type FeedFollowService interface {
 	FollowFeed(ctx context.Context, userID, feedID uuid.UUID) (*domain.FeedFollow, error)
 	GetUserFeedFollows(ctx context.Context, userID uuid.UUID) ([]*domain.FeedFollow, error)
+	UpdateFeedFollow(ctx context.Context, feedFollowID, userID uuid.UUID, update domain.FeedFollowUpdate) (*domain.FeedFollow, error)
 	UnfollowFeed(ctx context.Context, feedFollowID, userID uuid.UUID) error
 }
 
 type feedFollowService struct {
-	repo repository.FeedFollowRepository
+	repo       repository.FeedFollowRepository
+	folderRepo repository.FolderRepository
+	audit      AuditService
 }
 
-func NewFeedFollowService(repo repository.FeedFollowRepository) FeedFollowService {
+func NewFeedFollowService(repo repository.FeedFollowRepository, folderRepo repository.FolderRepository, audit AuditService) FeedFollowService {
 	return &feedFollowService{
-		repo: repo,
+		repo:       repo,
+		folderRepo: folderRepo,
+		audit:      audit,
 	}
 }
 
 func (s *feedFollowService) FollowFeed(ctx context.Context, userID, feedID uuid.UUID) (*domain.FeedFollow, error) {
+	ctx, span := tracing.Start(ctx, "FeedFollowService.FollowFeed")
+	defer span.End()
+
 	feedFollow := domain.NewFeedFollow(userID, feedID)
 	
 	if err := feedFollow.Validate(); err != nil {
 		return nil, err
 	}
 	
-	dbFeedFollow, err := s.repo.Create(ctx, database.CreateFeedFollowParams{
+	_, err := s.repo.Create(ctx, database.CreateFeedFollowParams{
 		ID:        feedFollow.ID,
 		CreatedAt: feedFollow.CreatedAt,
 		UpdatedAt: feedFollow.UpdatedAt,
@@ -40,16 +50,87 @@ func (s *feedFollowService) FollowFeed(ctx context.Context, userID, feedID uuid.
 		FeedID:    feedFollow.FeedID,
 	})
 	if err != nil {
-		if err.Error() == "duplicate key value violates unique constraint" {
+		if isUniqueViolation(err) {
 			return nil, domain.ErrDuplicateFeedFollow
 		}
+		if isForeignKeyViolation(err) {
+			return nil, domain.ErrFeedNotFound
+		}
+		return nil, err
+	}
+	
+	created, err := s.getFeedFollow(ctx, feedFollow.ID, userID)
+	if err != nil {
 		return nil, err
 	}
+	s.audit.Record(ctx, domain.NewAuditEntry(&userID, &userID, domain.AuditFeedFollowCreate, domain.AuditTargetFeedFollow, &created.ID, nil, domain.AuditFeedFollow(created)))
 	
-	return domain.MapFeedFollowFromDB(dbFeedFollow), nil
+	return created, nil
+}
+
+func (s *feedFollowService) UpdateFeedFollow(ctx context.Context, feedFollowID, userID uuid.UUID, update domain.FeedFollowUpdate) (*domain.FeedFollow, error) {
+	ctx, span := tracing.Start(ctx, "FeedFollowService.UpdateFeedFollow")
+	defer span.End()
+
+	feedFollow, err := s.getFeedFollow(ctx, feedFollowID, userID)
+	if err != nil {
+		return nil, err
+	}
+	before := domain.AuditFeedFollow(feedFollow)
+
+	feedFollow.Apply(update)
+	if err := feedFollow.Validate(); err != nil {
+		return nil, err
+	}
+
+	if feedFollow.FolderID != nil {
+		_, err := s.folderRepo.GetByID(ctx, database.GetFolderParams{
+			ID:     *feedFollow.FolderID,
+			UserID: userID,
+		})
+		if err != nil {
+			return nil, notFound(err, domain.ErrFolderNotFound)
+		}
+	}
+
+	title := gosql.NullString{}
+	if feedFollow.Title != nil {
+		title = gosql.NullString{String: *feedFollow.Title, Valid: true}
+	}
+
+	err = s.repo.UpdatePreferences(ctx, database.UpdateFeedFollowPreferencesParams{
+		ID:               feedFollow.ID,
+		UserID:           feedFollow.UserID,
+		Title:            title,
+		Muted:            feedFollow.Muted,
+		Priority:         feedFollow.Priority,
+		NotificationMode: feedFollow.NotificationMode,
+		FolderID:         nullUUID(feedFollow.FolderID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEntry(&userID, &userID, domain.AuditFeedFollowUpdate, domain.AuditTargetFeedFollow, &feedFollow.ID, before, domain.AuditFeedFollow(feedFollow)))
+	return feedFollow, nil
+}
+
+func (s *feedFollowService) getFeedFollow(ctx context.Context, feedFollowID, userID uuid.UUID) (*domain.FeedFollow, error) {
+	row, err := s.repo.GetByID(ctx, database.GetFeedFollowParams{
+		ID:     feedFollowID,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, notFound(err, domain.ErrFeedFollowNotFound)
+	}
+
+	return domain.MapFeedFollowRowFromDB(row), nil
 }
 
 func (s *feedFollowService) GetUserFeedFollows(ctx context.Context, userID uuid.UUID) ([]*domain.FeedFollow, error) {
+	ctx, span := tracing.Start(ctx, "FeedFollowService.GetUserFeedFollows")
+	defer span.End()
+
 	if userID == uuid.Nil {
 		return nil, domain.ErrInvalidUserID
 	}
@@ -63,6 +144,9 @@ func (s *feedFollowService) GetUserFeedFollows(ctx context.Context, userID uuid.
 }
 
 func (s *feedFollowService) UnfollowFeed(ctx context.Context, feedFollowID, userID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "FeedFollowService.UnfollowFeed")
+	defer span.End()
+
 	if feedFollowID == uuid.Nil {
 		return domain.ErrFeedFollowNotFound
 	}
@@ -70,13 +154,19 @@ func (s *feedFollowService) UnfollowFeed(ctx context.Context, feedFollowID, user
 		return domain.ErrInvalidUserID
 	}
 	
-	err := s.repo.Delete(ctx, database.DeleteFeedFollowParams{
+	feedFollow, err := s.getFeedFollow(ctx, feedFollowID, userID)
+	if err != nil {
+		return err
+	}
+	
+	err = s.repo.Delete(ctx, database.DeleteFeedFollowParams{
 		ID:     feedFollowID,
 		UserID: userID,
 	})
 	if err != nil {
-		return domain.ErrCannotUnfollowFeed
+		return err
 	}
 	
+	s.audit.Record(ctx, domain.NewAuditEntry(&userID, &userID, domain.AuditFeedFollowDelete, domain.AuditTargetFeedFollow, &feedFollowID, domain.AuditFeedFollow(feedFollow), nil))
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/auth"
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+type FeedTokenService interface {
+	CreateToken(ctx context.Context, name string, userID uuid.UUID) (*domain.FeedToken, error)
+	GetUserTokens(ctx context.Context, userID uuid.UUID) ([]*domain.FeedToken, error)
+	DeleteToken(ctx context.Context, tokenID, userID uuid.UUID) error
+	GetUserByToken(ctx context.Context, token string) (*domain.User, error)
+}
+
+type feedTokenService struct {
+	repo repository.FeedTokenRepository
+}
+
+func NewFeedTokenService(repo repository.FeedTokenRepository) FeedTokenService {
+	return &feedTokenService{
+		repo: repo,
+	}
+}
+
+func (s *feedTokenService) CreateToken(ctx context.Context, name string, userID uuid.UUID) (*domain.FeedToken, error) {
+	ctx, span := tracing.Start(ctx, "FeedTokenService.CreateToken")
+	defer span.End()
+
+	feedToken := domain.NewFeedToken(name, userID)
+
+	if err := feedToken.Validate(); err != nil {
+		return nil, err
+	}
+
+	secret, err := auth.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	dbToken, err := s.repo.Create(ctx, database.CreateFeedTokenParams{
+		ID:        feedToken.ID,
+		CreatedAt: feedToken.CreatedAt,
+		UserID:    feedToken.UserID,
+		Name:      feedToken.Name,
+		TokenHash: auth.HashToken(secret),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	created := domain.MapFeedTokenFromDB(dbToken)
+	created.Token = secret
+	return created, nil
+}
+
+func (s *feedTokenService) GetUserTokens(ctx context.Context, userID uuid.UUID) ([]*domain.FeedToken, error) {
+	ctx, span := tracing.Start(ctx, "FeedTokenService.GetUserTokens")
+	defer span.End()
+
+	if userID == uuid.Nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	dbTokens, err := s.repo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.MapFeedTokensFromDB(dbTokens), nil
+}
+
+func (s *feedTokenService) DeleteToken(ctx context.Context, tokenID, userID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "FeedTokenService.DeleteToken")
+	defer span.End()
+
+	deleted, err := s.repo.Delete(ctx, database.DeleteFeedTokenParams{
+		ID:     tokenID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return domain.ErrFeedTokenNotFound
+	}
+
+	return nil
+}
+
+func (s *feedTokenService) GetUserByToken(ctx context.Context, token string) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "FeedTokenService.GetUserByToken")
+	defer span.End()
+
+	if token == "" {
+		return nil, domain.ErrInvalidFeedToken
+	}
+
+	dbUser, err := s.repo.GetUserByTokenHash(ctx, auth.HashToken(token))
+	if err != nil {
+		return nil, notFound(err, domain.ErrInvalidFeedToken)
+	}
+
+	return domain.MapUserFromDB(dbUser), nil
+}
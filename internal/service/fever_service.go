@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+type FeverService interface {
+	Authenticate(ctx context.Context, apiKey string) (*domain.User, *domain.APIKey, error)
+	GetLastRefreshedAt(ctx context.Context, userID uuid.UUID) (time.Time, error)
+	GetGroups(ctx context.Context, userID uuid.UUID) ([]*domain.FeverGroup, error)
+	GetFeeds(ctx context.Context, userID uuid.UUID) ([]*domain.FeverFeed, error)
+	GetFavicons(ctx context.Context, userID uuid.UUID) ([]*domain.FeverFavicon, error)
+	GetItems(ctx context.Context, userID uuid.UUID, query domain.FeverItemQuery) ([]*domain.FeverItem, int64, error)
+	GetUnreadItemIDs(ctx context.Context, userID uuid.UUID) ([]int64, error)
+	GetSavedItemIDs(ctx context.Context, userID uuid.UUID) ([]int64, error)
+	MarkItem(ctx context.Context, userID uuid.UUID, itemID int64, as string) error
+	MarkFeedRead(ctx context.Context, userID uuid.UUID, feedID int64, before time.Time) error
+	MarkGroupRead(ctx context.Context, userID uuid.UUID, groupID int64, before time.Time) error
+}
+
+type feverService struct {
+	repo     repository.FeverRepository
+	postRepo repository.PostRepository
+	apiKeys  APIKeyService
+}
+
+func NewFeverService(repo repository.FeverRepository, postRepo repository.PostRepository, apiKeys APIKeyService) FeverService {
+	return &feverService{
+		repo:     repo,
+		postRepo: postRepo,
+		apiKeys:  apiKeys,
+	}
+}
+
+// Authenticate looks a user and API key up by the md5 of "name:api_key"
+// that Fever clients send as api_key.
+func (s *feverService) Authenticate(ctx context.Context, apiKey string) (*domain.User, *domain.APIKey, error) {
+	ctx, span := tracing.Start(ctx, "FeverService.Authenticate")
+	defer span.End()
+
+	apiKey = strings.ToLower(strings.TrimSpace(apiKey))
+	if apiKey == "" {
+		return nil, nil, domain.ErrInvalidFeverAPIKey
+	}
+
+	user, key, err := s.apiKeys.AuthenticateClientHash(ctx, apiKey)
+	if errors.Is(err, domain.ErrInvalidAPIKey) {
+		return nil, nil, domain.ErrInvalidFeverAPIKey
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, key, nil
+}
+
+func (s *feverService) GetLastRefreshedAt(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	ctx, span := tracing.Start(ctx, "FeverService.GetLastRefreshedAt")
+	defer span.End()
+
+	return s.repo.GetLastRefreshedAt(ctx, userID)
+}
+
+func (s *feverService) GetGroups(ctx context.Context, userID uuid.UUID) ([]*domain.FeverGroup, error) {
+	ctx, span := tracing.Start(ctx, "FeverService.GetGroups")
+	defer span.End()
+
+	rows, err := s.repo.GetGroups(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.MapFeverGroupsFromDB(rows), nil
+}
+
+func (s *feverService) GetFeeds(ctx context.Context, userID uuid.UUID) ([]*domain.FeverFeed, error) {
+	ctx, span := tracing.Start(ctx, "FeverService.GetFeeds")
+	defer span.End()
+
+	rows, err := s.repo.GetFeeds(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.MapFeverFeedsFromDB(rows), nil
+}
+
+func (s *feverService) GetFavicons(ctx context.Context, userID uuid.UUID) ([]*domain.FeverFavicon, error) {
+	ctx, span := tracing.Start(ctx, "FeverService.GetFavicons")
+	defer span.End()
+
+	rows, err := s.repo.GetFavicons(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.MapFeverFaviconsFromDB(rows), nil
+}
+
+// GetItems returns one page of items along with the total number of items
+// the user can see.
+func (s *feverService) GetItems(ctx context.Context, userID uuid.UUID, query domain.FeverItemQuery) ([]*domain.FeverItem, int64, error) {
+	ctx, span := tracing.Start(ctx, "FeverService.GetItems")
+	defer span.End()
+
+	params := database.GetFeverItemsParams{
+		UserID:    userID,
+		FilterIds: query.WithIDs != nil,
+		Ids:       query.WithIDs,
+		PageLimit: domain.FeverItemLimit,
+	}
+	if params.Ids == nil {
+		params.Ids = []int64{}
+	}
+	if len(params.Ids) > domain.FeverItemLimit {
+		params.Ids = params.Ids[:domain.FeverItemLimit]
+	}
+	if query.SinceID != nil {
+		params.SinceID = sql.NullInt64{Int64: *query.SinceID, Valid: true}
+	}
+	if query.MaxID != nil {
+		params.MaxID = sql.NullInt64{Int64: *query.MaxID, Valid: true}
+	}
+
+	rows, err := s.repo.GetItems(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.repo.CountItems(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return domain.MapFeverItemsFromDB(rows), total, nil
+}
+
+func (s *feverService) GetUnreadItemIDs(ctx context.Context, userID uuid.UUID) ([]int64, error) {
+	ctx, span := tracing.Start(ctx, "FeverService.GetUnreadItemIDs")
+	defer span.End()
+
+	return s.repo.GetUnreadItemIDs(ctx, userID)
+}
+
+func (s *feverService) GetSavedItemIDs(ctx context.Context, userID uuid.UUID) ([]int64, error) {
+	ctx, span := tracing.Start(ctx, "FeverService.GetSavedItemIDs")
+	defer span.End()
+
+	return s.repo.GetSavedItemIDs(ctx, userID)
+}
+
+func (s *feverService) MarkItem(ctx context.Context, userID uuid.UUID, itemID int64, as string) error {
+	ctx, span := tracing.Start(ctx, "FeverService.MarkItem")
+	defer span.End()
+
+	switch as {
+	case domain.FeverMarkRead, domain.FeverMarkUnread, domain.FeverMarkSaved, domain.FeverMarkUnsaved:
+	default:
+		return domain.ErrInvalidFeverMark
+	}
+
+	postID, err := s.repo.GetPostID(ctx, database.GetPostIDForFeverItemParams{
+		Seq:    itemID,
+		UserID: userID,
+	})
+	if err != nil {
+		return notFound(err, domain.ErrFeverItemNotFound)
+	}
+
+	switch as {
+	case domain.FeverMarkRead, domain.FeverMarkUnread:
+		return s.postRepo.SetRead(ctx, database.SetPostReadParams{
+			UserID: userID,
+			PostID: postID,
+			Read:   as == domain.FeverMarkRead,
+		})
+	default:
+		return s.postRepo.SetStarred(ctx, database.SetPostStarredParams{
+			UserID:  userID,
+			PostID:  postID,
+			Starred: as == domain.FeverMarkSaved,
+		})
+	}
+}
+
+func (s *feverService) MarkFeedRead(ctx context.Context, userID uuid.UUID, feedID int64, before time.Time) error {
+	ctx, span := tracing.Start(ctx, "FeverService.MarkFeedRead")
+	defer span.End()
+
+	return s.repo.MarkFeedRead(ctx, database.MarkFeverFeedReadParams{
+		UserID:  userID,
+		FeedSeq: feedID,
+		Before:  before,
+	})
+}
+
+// MarkGroupRead marks a folder read; group 0 covers every followed feed.
+func (s *feverService) MarkGroupRead(ctx context.Context, userID uuid.UUID, groupID int64, before time.Time) error {
+	ctx, span := tracing.Start(ctx, "FeverService.MarkGroupRead")
+	defer span.End()
+
+	return s.repo.MarkGroupRead(ctx, database.MarkFeverGroupReadParams{
+		UserID:   userID,
+		GroupSeq: groupID,
+		Before:   before,
+	})
+}
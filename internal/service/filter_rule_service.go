@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	gosql "database/sql"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+const (
+	ruleScanPageSize = 500
+	dryRunMaxScanned = 5000
+	dryRunSampleSize = 50
+)
+
+type FilterRuleService interface {
+	CreateRule(ctx context.Context, rule *domain.FilterRule) (*domain.FilterRule, error)
+	GetUserRules(ctx context.Context, userID uuid.UUID) ([]*domain.FilterRule, error)
+	DeleteRule(ctx context.Context, ruleID, userID uuid.UUID) error
+	ApplyRule(ctx context.Context, ruleID, userID uuid.UUID) (int, error)
+	DryRun(ctx context.Context, rule *domain.FilterRule) (*domain.FilterDryRun, error)
+	ApplyToNewPosts(ctx context.Context, feedID uuid.UUID, posts []*domain.Post) error
+}
+
+type filterRuleService struct {
+	repo       repository.FilterRuleRepository
+	followRepo repository.FeedFollowRepository
+	folderRepo repository.FolderRepository
+	postRepo   repository.PostRepository
+	tagRepo    repository.TagRepository
+}
+
+func NewFilterRuleService(
+	repo repository.FilterRuleRepository,
+	followRepo repository.FeedFollowRepository,
+	folderRepo repository.FolderRepository,
+	postRepo repository.PostRepository,
+	tagRepo repository.TagRepository,
+) FilterRuleService {
+	return &filterRuleService{
+		repo:       repo,
+		followRepo: followRepo,
+		folderRepo: folderRepo,
+		postRepo:   postRepo,
+		tagRepo:    tagRepo,
+	}
+}
+
+func (s *filterRuleService) CreateRule(ctx context.Context, rule *domain.FilterRule) (*domain.FilterRule, error) {
+	ctx, span := tracing.Start(ctx, "FilterRuleService.CreateRule")
+	defer span.End()
+
+	if err := s.validate(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	tagName := gosql.NullString{}
+	if rule.TagName != nil {
+		tagName = gosql.NullString{String: *rule.TagName, Valid: true}
+	}
+
+	dbRule, err := s.repo.Create(ctx, database.CreateFilterRuleParams{
+		ID:        rule.ID,
+		CreatedAt: rule.CreatedAt,
+		UpdatedAt: rule.UpdatedAt,
+		UserID:    rule.UserID,
+		Name:      rule.Name,
+		Scope:     rule.Scope,
+		FolderID:  nullUUID(rule.FolderID),
+		FeedID:    nullUUID(rule.FeedID),
+		Field:     rule.Field,
+		MatchType: rule.MatchType,
+		Pattern:   rule.Pattern,
+		Action:    rule.Action,
+		TagName:   tagName,
+		Enabled:   rule.Enabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.MapFilterRuleFromDB(dbRule), nil
+}
+
+func (s *filterRuleService) GetUserRules(ctx context.Context, userID uuid.UUID) ([]*domain.FilterRule, error) {
+	ctx, span := tracing.Start(ctx, "FilterRuleService.GetUserRules")
+	defer span.End()
+
+	if userID == uuid.Nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	dbRules, err := s.repo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.MapFilterRulesFromDB(dbRules), nil
+}
+
+func (s *filterRuleService) DeleteRule(ctx context.Context, ruleID, userID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "FilterRuleService.DeleteRule")
+	defer span.End()
+
+	deleted, err := s.repo.Delete(ctx, database.DeleteFilterRuleParams{
+		ID:     ruleID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return domain.ErrFilterRuleNotFound
+	}
+
+	return nil
+}
+
+// ApplyRule runs a saved rule retroactively over every post already in its scope
+// and returns the number of posts it acted on.
+func (s *filterRuleService) ApplyRule(ctx context.Context, ruleID, userID uuid.UUID) (int, error) {
+	ctx, span := tracing.Start(ctx, "FilterRuleService.ApplyRule")
+	defer span.End()
+
+	dbRule, err := s.repo.GetByID(ctx, database.GetFilterRuleParams{
+		ID:     ruleID,
+		UserID: userID,
+	})
+	if err != nil {
+		return 0, notFound(err, domain.ErrFilterRuleNotFound)
+	}
+
+	rule := domain.MapFilterRuleFromDB(dbRule)
+	if err := rule.Validate(); err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	err = s.scan(ctx, rule, 0, func(post *domain.Post) error {
+		if !rule.Matches(post) {
+			return nil
+		}
+		if err := s.applyAction(ctx, rule, post.ID); err != nil {
+			return err
+		}
+		applied++
+		return nil
+	})
+	if err != nil {
+		return applied, err
+	}
+
+	return applied, nil
+}
+
+// DryRun reports what an unsaved rule would match among the most recent posts
+// in its scope, without changing anything.
+func (s *filterRuleService) DryRun(ctx context.Context, rule *domain.FilterRule) (*domain.FilterDryRun, error) {
+	ctx, span := tracing.Start(ctx, "FilterRuleService.DryRun")
+	defer span.End()
+
+	if err := s.validate(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	result := &domain.FilterDryRun{Posts: []*domain.Post{}}
+	err := s.scan(ctx, rule, dryRunMaxScanned, func(post *domain.Post) error {
+		result.Scanned++
+		if !rule.Matches(post) {
+			return nil
+		}
+		result.Matched++
+		if len(result.Posts) < dryRunSampleSize {
+			result.Posts = append(result.Posts, post)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ApplyToNewPosts evaluates the rules of every follower of the feed against
+// freshly ingested posts.
+func (s *filterRuleService) ApplyToNewPosts(ctx context.Context, feedID uuid.UUID, posts []*domain.Post) error {
+	ctx, span := tracing.Start(ctx, "FilterRuleService.ApplyToNewPosts")
+	defer span.End()
+
+	if len(posts) == 0 {
+		return nil
+	}
+
+	dbRules, err := s.repo.GetForFeed(ctx, feedID)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range domain.MapFilterRulesFromDB(dbRules) {
+		if err := rule.Validate(); err != nil {
+			slog.WarnContext(ctx, "Skipping invalid filter rule", "rule_id", rule.ID, "error", err)
+			continue
+		}
+		for _, post := range posts {
+			if !rule.Matches(post) {
+				continue
+			}
+			if err := s.applyAction(ctx, rule, post.ID); err != nil {
+				slog.ErrorContext(ctx, "Error applying filter rule", "rule_id", rule.ID, "post_id", post.ID, "error", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *filterRuleService) validate(ctx context.Context, rule *domain.FilterRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	if rule.FolderID != nil {
+		_, err := s.folderRepo.GetByID(ctx, database.GetFolderParams{
+			ID:     *rule.FolderID,
+			UserID: rule.UserID,
+		})
+		if err != nil {
+			return notFound(err, domain.ErrFolderNotFound)
+		}
+	}
+	if rule.FeedID != nil {
+		_, err := s.followRepo.GetByFeed(ctx, database.GetFeedFollowByFeedParams{
+			FeedID: *rule.FeedID,
+			UserID: rule.UserID,
+		})
+		if err != nil {
+			return notFound(err, domain.ErrFeedFollowNotFound)
+		}
+	}
+
+	return nil
+}
+
+// scan walks the posts in the rule's scope, newest first, stopping after
+// limit posts when limit is positive.
+func (s *filterRuleService) scan(ctx context.Context, rule *domain.FilterRule, limit int, visit func(*domain.Post) error) error {
+	scanned := 0
+	for offset := 0; ; offset += ruleScanPageSize {
+		dbPosts, err := s.repo.GetPostsInScope(ctx, database.GetPostsInRuleScopeParams{
+			UserID:     rule.UserID,
+			Scope:      rule.Scope,
+			FeedID:     nullUUID(rule.FeedID),
+			FolderID:   nullUUID(rule.FolderID),
+			PageLimit:  ruleScanPageSize,
+			PageOffset: int32(offset),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, post := range domain.MapPostsFromDB(dbPosts) {
+			if limit > 0 && scanned >= limit {
+				return nil
+			}
+			scanned++
+			if err := visit(post); err != nil {
+				return err
+			}
+		}
+
+		if len(dbPosts) < ruleScanPageSize {
+			return nil
+		}
+	}
+}
+
+func (s *filterRuleService) applyAction(ctx context.Context, rule *domain.FilterRule, postID uuid.UUID) error {
+	if rule.Action == domain.FilterActionTag {
+		tag := domain.NewTag(*rule.TagName, rule.UserID)
+		dbTag, err := s.tagRepo.Upsert(ctx, database.UpsertTagParams{
+			ID:        tag.ID,
+			CreatedAt: tag.CreatedAt,
+			UpdatedAt: tag.UpdatedAt,
+			UserID:    tag.UserID,
+			Name:      tag.Name,
+		})
+		if err != nil {
+			return err
+		}
+		return s.tagRepo.AddToPost(ctx, database.AddPostTagParams{
+			TagID:  dbTag.ID,
+			PostID: postID,
+		})
+	}
+
+	return s.postRepo.ApplyState(ctx, database.ApplyPostStateParams{
+		UserID:   rule.UserID,
+		PostID:   postID,
+		MarkRead: rule.Action == domain.FilterActionMarkRead,
+		Star:     rule.Action == domain.FilterActionStar,
+		Hide:     rule.Action == domain.FilterActionHide,
+	})
+}
+
+func nullUUID(id *uuid.UUID) uuid.NullUUID {
+	if id == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *id, Valid: true}
+}
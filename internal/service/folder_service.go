@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+type FolderService interface {
+	CreateFolder(ctx context.Context, name string, userID uuid.UUID) (*domain.Folder, error)
+	GetUserFolders(ctx context.Context, userID uuid.UUID) ([]*domain.Folder, error)
+	GetFolder(ctx context.Context, folderID, userID uuid.UUID) (*domain.Folder, error)
+	DeleteFolder(ctx context.Context, folderID, userID uuid.UUID) error
+}
+
+type folderService struct {
+	repo repository.FolderRepository
+}
+
+func NewFolderService(repo repository.FolderRepository) FolderService {
+	return &folderService{
+		repo: repo,
+	}
+}
+
+func (s *folderService) CreateFolder(ctx context.Context, name string, userID uuid.UUID) (*domain.Folder, error) {
+	ctx, span := tracing.Start(ctx, "FolderService.CreateFolder")
+	defer span.End()
+
+	folder := domain.NewFolder(name, userID)
+
+	if err := folder.Validate(); err != nil {
+		return nil, err
+	}
+
+	dbFolder, err := s.repo.Create(ctx, database.CreateFolderParams{
+		ID:        folder.ID,
+		CreatedAt: folder.CreatedAt,
+		UpdatedAt: folder.UpdatedAt,
+		UserID:    folder.UserID,
+		Name:      folder.Name,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, domain.ErrDuplicateFolder
+		}
+		return nil, err
+	}
+
+	return domain.MapFolderFromDB(dbFolder), nil
+}
+
+func (s *folderService) GetUserFolders(ctx context.Context, userID uuid.UUID) ([]*domain.Folder, error) {
+	ctx, span := tracing.Start(ctx, "FolderService.GetUserFolders")
+	defer span.End()
+
+	if userID == uuid.Nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	dbFolders, err := s.repo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.MapFoldersFromDB(dbFolders), nil
+}
+
+func (s *folderService) GetFolder(ctx context.Context, folderID, userID uuid.UUID) (*domain.Folder, error) {
+	ctx, span := tracing.Start(ctx, "FolderService.GetFolder")
+	defer span.End()
+
+	dbFolder, err := s.repo.GetByID(ctx, database.GetFolderParams{
+		ID:     folderID,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, notFound(err, domain.ErrFolderNotFound)
+	}
+
+	return domain.MapFolderFromDB(dbFolder), nil
+}
+
+func (s *folderService) DeleteFolder(ctx context.Context, folderID, userID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "FolderService.DeleteFolder")
+	defer span.End()
+
+	deleted, err := s.repo.Delete(ctx, database.DeleteFolderParams{
+		ID:     folderID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return domain.ErrFolderNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+// HealthService checks what the process needs to do its work, for
+// readiness probes.
+type HealthService interface {
+	Ready(ctx context.Context) *domain.Health
+}
+
+// Pinger is a database connection pool, such as *sql.DB.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// SchemaChecker reports whether the database schema is as new as the code
+// expects, such as *migrate.Migrator.
+type SchemaChecker interface {
+	Check(ctx context.Context) error
+}
+
+type HealthOptions struct {
+	// Timeout bounds all checks together
+	Timeout time.Duration
+	// MaxHeartbeatAge is how long ago the latest scraper heartbeat may be
+	MaxHeartbeatAge time.Duration
+	// MaxQueueLag is how long the next feed due may have waited, or 0 for
+	// no limit
+	MaxQueueLag time.Duration
+	// RunsScraper makes the scraper check critical, for processes whose
+	// job is scraping. Elsewhere a stalled scraper only degrades health.
+	RunsScraper bool
+}
+
+type healthService struct {
+	db             Pinger
+	schema         SchemaChecker
+	scraperService ScraperService
+	options        HealthOptions
+	startedAt      time.Time
+}
+
+func NewHealthService(db Pinger, schema SchemaChecker, scraperService ScraperService, options HealthOptions) HealthService {
+	return &healthService{
+		db:             db,
+		schema:         schema,
+		scraperService: scraperService,
+		options:        options,
+		startedAt:      time.Now(),
+	}
+}
+
+// Ready runs the checks at once. Errors are logged rather than returned,
+// since they'd show where the database is to anyone probing.
+func (s *healthService) Ready(ctx context.Context) *domain.Health {
+	ctx, span := tracing.Start(ctx, "HealthService.Ready")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, s.options.Timeout)
+	defer cancel()
+
+	checks := []struct {
+		name     string
+		critical bool
+		run      func(context.Context) (string, error)
+	}{
+		{domain.HealthCheckDatabase, true, s.checkDatabase},
+		{domain.HealthCheckMigrations, true, s.checkMigrations},
+		{domain.HealthCheckScraper, s.options.RunsScraper, s.checkScraper},
+		{domain.HealthCheckQueue, false, s.checkQueue},
+	}
+
+	results := make([]*domain.HealthCheck, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			message, err := check.run(ctx)
+			result := &domain.HealthCheck{
+				Name:     check.name,
+				Status:   domain.HealthOK,
+				Critical: check.critical,
+				Latency:  time.Since(start),
+				Message:  message,
+			}
+			if err != nil {
+				result.Status = domain.HealthDown
+				if errors.Is(err, context.DeadlineExceeded) {
+					result.Message = "timed out"
+				}
+				slog.WarnContext(ctx, "Health check failed", "check", check.name, "error", err)
+			}
+			results[i] = result
+		}()
+	}
+	wg.Wait()
+
+	return domain.NewHealth(results)
+}
+
+func (s *healthService) checkDatabase(ctx context.Context) (string, error) {
+	if err := s.db.PingContext(ctx); err != nil {
+		return "unreachable", err
+	}
+	return "", nil
+}
+
+func (s *healthService) checkMigrations(ctx context.Context) (string, error) {
+	if err := s.schema.Check(ctx); err != nil {
+		return "schema is behind or unknown", err
+	}
+	return "", nil
+}
+
+func (s *healthService) checkScraper(ctx context.Context) (string, error) {
+	workers, err := s.scraperService.Heartbeats(ctx)
+	if err != nil {
+		return "failed to get heartbeats", err
+	}
+
+	if len(workers) > 0 {
+		age := time.Since(workers[0].LastBeatAt)
+		message := fmt.Sprintf("last heartbeat %s ago", age.Round(time.Second))
+		if age <= s.options.MaxHeartbeatAge {
+			return message, nil
+		}
+		if time.Since(s.startedAt) > s.options.MaxHeartbeatAge {
+			return message, fmt.Errorf("last scraper heartbeat is %s old", age.Round(time.Second))
+		}
+	} else if time.Since(s.startedAt) > s.options.MaxHeartbeatAge {
+		return "no heartbeats", errors.New("no scraper has ever run")
+	}
+
+	// A scraper starting with this process beats after its first interval
+	return "starting", nil
+}
+
+func (s *healthService) checkQueue(ctx context.Context) (string, error) {
+	lag, err := s.scraperService.QueueLag(ctx)
+	if err != nil {
+		return "failed to measure", err
+	}
+
+	message := fmt.Sprintf("next feed due %s ago", lag.Round(time.Second))
+	if s.options.MaxQueueLag > 0 && lag > s.options.MaxQueueLag {
+		return message, fmt.Errorf("queue lag %s is over %s", lag.Round(time.Second), s.options.MaxQueueLag)
+	}
+	return message, nil
+}
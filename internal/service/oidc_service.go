@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	gosql "database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/oidc"
+	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+// OIDCOptions configures how identity provider logins become users.
+type OIDCOptions struct {
+	// RoleClaim names the ID token claim roles are mapped from.
+	RoleClaim string
+
+	// RoleMapping maps RoleClaim values to roles. When it is empty roles
+	// are left alone, otherwise they are set on every login.
+	RoleMapping domain.OIDCRoleMapping
+
+	// AllowSignup creates users for identities that aren't linked to one
+	// yet and whose verified email matches no user they can be linked to.
+	AllowSignup bool
+}
+
+// OIDCService logs users in through an OpenID Connect identity provider.
+type OIDCService interface {
+	BeginLogin(ctx context.Context) (*domain.OIDCLogin, error)
+	CompleteLogin(ctx context.Context, login domain.OIDCLogin, code, userAgent, ip string) (*domain.User, *domain.Session, error)
+}
+
+type oidcService struct {
+	provider   *oidc.Provider
+	users      repository.UserRepository
+	identities repository.UserIdentityRepository
+	apiKeys    repository.APIKeyRepository
+	accounts   AccountService
+	audit      AuditService
+	options    OIDCOptions
+}
+
+func NewOIDCService(provider *oidc.Provider, users repository.UserRepository, identities repository.UserIdentityRepository, apiKeys repository.APIKeyRepository, accounts AccountService, audit AuditService, options OIDCOptions) OIDCService {
+	if options.RoleClaim == "" {
+		options.RoleClaim = domain.DefaultOIDCRoleClaim
+	}
+	return &oidcService{
+		provider:   provider,
+		users:      users,
+		identities: identities,
+		apiKeys:    apiKeys,
+		accounts:   accounts,
+		audit:      audit,
+		options:    options,
+	}
+}
+
+// BeginLogin returns where to send the user, along with the state, nonce
+// and PKCE verifier to hold on to until they come back.
+func (s *oidcService) BeginLogin(ctx context.Context) (*domain.OIDCLogin, error) {
+	ctx, span := tracing.Start(ctx, "OIDCService.BeginLogin")
+	defer span.End()
+
+	login := &domain.OIDCLogin{}
+	for _, secret := range []*string{&login.State, &login.Nonce, &login.CodeVerifier} {
+		value, err := oidc.RandomString()
+		if err != nil {
+			return nil, err
+		}
+		*secret = value
+	}
+
+	authURL, err := s.provider.AuthCodeURL(ctx, login.State, login.Nonce, oidc.CodeChallenge(login.CodeVerifier))
+	if err != nil {
+		return nil, err
+	}
+	login.AuthURL = authURL
+
+	return login, nil
+}
+
+// CompleteLogin redeems the code the provider redirected back with, finds
+// or creates the user its ID token identifies and starts a session. The
+// caller checks the returned state matches login.State.
+func (s *oidcService) CompleteLogin(ctx context.Context, login domain.OIDCLogin, code, userAgent, ip string) (*domain.User, *domain.Session, error) {
+	ctx, span := tracing.Start(ctx, "OIDCService.CompleteLogin")
+	defer span.End()
+
+	rawIDToken, err := s.provider.Exchange(ctx, code, login.CodeVerifier)
+	if err != nil {
+		slog.WarnContext(ctx, "OIDC code exchange failed", "error", err)
+		return nil, nil, domain.ErrOIDCLoginFailed
+	}
+
+	token, err := s.provider.VerifyIDToken(ctx, rawIDToken, login.Nonce)
+	if err != nil {
+		slog.WarnContext(ctx, "OIDC ID token rejected", "error", err)
+		return nil, nil, domain.ErrOIDCLoginFailed
+	}
+
+	user, err := s.resolveUser(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.IsDisabled() {
+		return nil, nil, domain.ErrUserDisabled
+	}
+
+	if len(s.options.RoleMapping) > 0 {
+		role := s.options.RoleMapping.Role(token.ClaimValues(s.options.RoleClaim))
+		if role != user.Role {
+			if err := s.users.UpdateRole(ctx, database.UpdateUserRoleParams{ID: user.ID, Role: role}); err != nil {
+				return nil, nil, err
+			}
+			s.audit.Record(ctx, domain.NewAuditEntry(nil, &user.ID, domain.AuditUserChangeRole, domain.AuditTargetUser, &user.ID,
+				map[string]any{"role": user.Role},
+				map[string]any{"role": role}))
+			user.Role = role
+		}
+	}
+
+	session, err := s.accounts.StartSession(ctx, user.ID, userAgent, ip)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, session, nil
+}
+
+// resolveUser returns the user linked to the token's identity. The first
+// time an identity logs in it is linked to the user with its verified
+// email, or to a new user. Emails of local users are never verified, so a
+// user with a password or API key of their own isn't linked: whoever
+// registered the address would share the account with the identity.
+func (s *oidcService) resolveUser(ctx context.Context, token *oidc.IDToken) (*domain.User, error) {
+	email := verifiedEmail(token)
+
+	row, err := s.identities.GetUser(ctx, database.GetUserByIdentityParams{
+		Issuer:  token.Issuer,
+		Subject: token.Subject,
+	})
+	if err == nil {
+		if err := s.identities.Touch(ctx, database.TouchUserIdentityParams{
+			ID:    row.UserIdentity.ID,
+			Email: nullString(email),
+		}); err != nil {
+			slog.ErrorContext(ctx, "Error recording login of identity", "identity_id", row.UserIdentity.ID, "error", err)
+		}
+		return domain.MapUserFromDB(row.User), nil
+	}
+	if !errors.Is(err, gosql.ErrNoRows) {
+		return nil, err
+	}
+
+	var user *domain.User
+	newUserEmail := email
+	if email != "" {
+		dbUser, err := s.users.GetByEmail(ctx, email)
+		if err == nil {
+			user = domain.MapUserFromDB(dbUser)
+		} else if !errors.Is(err, gosql.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	if user != nil {
+		hasCredentials, err := s.hasCredentials(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		if hasCredentials {
+			slog.WarnContext(ctx, "Not linking identity to user with credentials of their own", "user_id", user.ID, "issuer", token.Issuer, "subject", token.Subject)
+			if !s.options.AllowSignup {
+				return nil, domain.ErrOIDCEmailTaken
+			}
+			// The address belongs to the other user, so the new one
+			// goes without.
+			user, newUserEmail = nil, ""
+		}
+	}
+
+	if user == nil {
+		if !s.options.AllowSignup {
+			return nil, domain.ErrOIDCSignupDisabled
+		}
+		if user, err = s.createUser(ctx, token, newUserEmail); err != nil {
+			return nil, err
+		}
+	}
+
+	identity := domain.NewUserIdentity(user.ID, token.Issuer, token.Subject, nil)
+	if _, err := s.identities.Create(ctx, database.CreateUserIdentityParams{
+		ID:          identity.ID,
+		CreatedAt:   identity.CreatedAt,
+		LastLoginAt: identity.LastLoginAt,
+		UserID:      identity.UserID,
+		Issuer:      identity.Issuer,
+		Subject:     identity.Subject,
+		Email:       nullString(email),
+	}); err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.NewAuditEntry(nil, &user.ID, domain.AuditUserLinkIdentity, domain.AuditTargetUser, &user.ID, nil, map[string]any{
+		"issuer":  identity.Issuer,
+		"subject": identity.Subject,
+		"email":   email,
+	}))
+	return user, nil
+}
+
+// hasCredentials reports whether user can log in other than through an
+// identity provider: with a password or an active API key.
+func (s *oidcService) hasCredentials(ctx context.Context, user *domain.User) (bool, error) {
+	if user.HasPassword() {
+		return true, nil
+	}
+
+	dbKeys, err := s.apiKeys.GetByUser(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	for _, key := range domain.MapAPIKeysFromDB(dbKeys) {
+		if key.IsActive(now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// createUser provisions a user for an identity. It gets no password or API
+// key; it logs in through the provider and can create keys from there.
+func (s *oidcService) createUser(ctx context.Context, token *oidc.IDToken, email string) (*domain.User, error) {
+	user := domain.NewUser(displayName(token))
+
+	dbUser, err := s.users.Create(ctx, database.CreateUserParams{
+		ID:        user.ID,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+		Name:      user.Name,
+		Email:     nullString(email),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user for identity %s: %w", token.Subject, err)
+	}
+
+	created := domain.MapUserFromDB(dbUser)
+	s.audit.Record(ctx, domain.NewAuditEntry(nil, &created.ID, domain.AuditUserCreate, domain.AuditTargetUser, &created.ID, nil, domain.AuditUser(created)))
+	return created, nil
+}
+
+// verifiedEmail returns the token's email if the provider vouches for it.
+// Unverified emails are never used to link or store, since anyone could
+// claim someone else's.
+func verifiedEmail(token *oidc.IDToken) string {
+	if !token.EmailVerified {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(token.Email))
+}
+
+func displayName(token *oidc.IDToken) string {
+	for _, name := range []string{token.Name, token.PreferredUsername, token.Email, token.Subject} {
+		if name = strings.TrimSpace(name); name != "" {
+			if len(name) > 255 {
+				name = name[:255]
+			}
+			return name
+		}
+	}
+	return token.Subject
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	gosql "database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/oidc"
+)
+
+type fakeUserRepository struct {
+	users map[uuid.UUID]database.User
+}
+
+func (r *fakeUserRepository) Create(ctx context.Context, params database.CreateUserParams) (database.User, error) {
+	user := database.User{
+		ID:        params.ID,
+		CreatedAt: params.CreatedAt,
+		UpdatedAt: params.UpdatedAt,
+		Name:      params.Name,
+		Email:     params.Email,
+		Role:      domain.UserRoleUser,
+	}
+	r.users[user.ID] = user
+	return user, nil
+}
+
+func (r *fakeUserRepository) GetByID(ctx context.Context, id uuid.UUID) (database.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return database.User{}, gosql.ErrNoRows
+	}
+	return user, nil
+}
+
+func (r *fakeUserRepository) GetByUsername(ctx context.Context, username string) (database.User, error) {
+	for _, user := range r.users {
+		if user.Username.String == username {
+			return user, nil
+		}
+	}
+	return database.User{}, gosql.ErrNoRows
+}
+
+func (r *fakeUserRepository) GetByEmail(ctx context.Context, email string) (database.User, error) {
+	for _, user := range r.users {
+		if user.Email.Valid && user.Email.String == email {
+			return user, nil
+		}
+	}
+	return database.User{}, gosql.ErrNoRows
+}
+
+func (r *fakeUserRepository) UpdatePassword(ctx context.Context, params database.UpdateUserPasswordParams) error {
+	return nil
+}
+
+func (r *fakeUserRepository) UpdateRole(ctx context.Context, params database.UpdateUserRoleParams) error {
+	user := r.users[params.ID]
+	user.Role = params.Role
+	r.users[params.ID] = user
+	return nil
+}
+
+func (r *fakeUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.users, id)
+	return nil
+}
+
+type fakeUserIdentityRepository struct {
+	users      *fakeUserRepository
+	identities []database.UserIdentity
+}
+
+func (r *fakeUserIdentityRepository) Create(ctx context.Context, params database.CreateUserIdentityParams) (database.UserIdentity, error) {
+	identity := database.UserIdentity(params)
+	r.identities = append(r.identities, identity)
+	return identity, nil
+}
+
+func (r *fakeUserIdentityRepository) GetUser(ctx context.Context, params database.GetUserByIdentityParams) (database.GetUserByIdentityRow, error) {
+	for _, identity := range r.identities {
+		if identity.Issuer == params.Issuer && identity.Subject == params.Subject {
+			return database.GetUserByIdentityRow{UserIdentity: identity, User: r.users.users[identity.UserID]}, nil
+		}
+	}
+	return database.GetUserByIdentityRow{}, gosql.ErrNoRows
+}
+
+func (r *fakeUserIdentityRepository) Touch(ctx context.Context, params database.TouchUserIdentityParams) error {
+	return nil
+}
+
+type fakeAPIKeyRepository struct {
+	keys      []database.ApiKey
+	createErr error
+}
+
+func (r *fakeAPIKeyRepository) Create(ctx context.Context, params database.CreateAPIKeyParams) (database.ApiKey, error) {
+	if r.createErr != nil {
+		return database.ApiKey{}, r.createErr
+	}
+	key := database.ApiKey{
+		ID:        params.ID,
+		CreatedAt: params.CreatedAt,
+		UpdatedAt: params.UpdatedAt,
+		UserID:    params.UserID,
+		Name:      params.Name,
+		Prefix:    params.Prefix,
+		KeyHash:   params.KeyHash,
+		Scope:     params.Scope,
+		ExpiresAt: params.ExpiresAt,
+	}
+	r.keys = append(r.keys, key)
+	return key, nil
+}
+
+func (r *fakeAPIKeyRepository) GetByUser(ctx context.Context, userID uuid.UUID) ([]database.ApiKey, error) {
+	var keys []database.ApiKey
+	for _, key := range r.keys {
+		if key.UserID == userID {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (r *fakeAPIKeyRepository) GetActiveByHash(ctx context.Context, keyHash string) (database.GetActiveAPIKeyByHashRow, error) {
+	return database.GetActiveAPIKeyByHashRow{}, gosql.ErrNoRows
+}
+
+func (r *fakeAPIKeyRepository) GetActiveByClientHash(ctx context.Context, clientHash string) (database.GetActiveAPIKeyByClientHashRow, error) {
+	return database.GetActiveAPIKeyByClientHashRow{}, gosql.ErrNoRows
+}
+
+func (r *fakeAPIKeyRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (r *fakeAPIKeyRepository) Revoke(ctx context.Context, params database.RevokeAPIKeyParams) (database.ApiKey, error) {
+	return database.ApiKey{}, gosql.ErrNoRows
+}
+
+type fakeAuditService struct{}
+
+func (fakeAuditService) Record(ctx context.Context, entry *domain.AuditEntry) {}
+
+func (fakeAuditService) GetEntries(ctx context.Context, query domain.AuditQuery) ([]*domain.AuditEntry, error) {
+	return nil, nil
+}
+
+func (fakeAuditService) Export(ctx context.Context, query domain.AuditQuery, write func(*domain.AuditEntry) error) error {
+	return nil
+}
+
+const testIdentityEmail = "victim@corp.example"
+
+func newTestOIDCService(allowSignup bool) (*oidcService, *fakeUserRepository, *fakeAPIKeyRepository) {
+	users := &fakeUserRepository{users: make(map[uuid.UUID]database.User)}
+	apiKeys := &fakeAPIKeyRepository{}
+	s := &oidcService{
+		users:      users,
+		identities: &fakeUserIdentityRepository{users: users},
+		apiKeys:    apiKeys,
+		audit:      fakeAuditService{},
+		options:    OIDCOptions{AllowSignup: allowSignup},
+	}
+	return s, users, apiKeys
+}
+
+func addTestUser(users *fakeUserRepository, email, passwordHash string) database.User {
+	user := database.User{
+		ID:           uuid.New(),
+		Name:         "local",
+		Email:        gosql.NullString{String: email, Valid: true},
+		PasswordHash: gosql.NullString{String: passwordHash, Valid: passwordHash != ""},
+		Role:         domain.UserRoleUser,
+	}
+	users.users[user.ID] = user
+	return user
+}
+
+func testIDToken() *oidc.IDToken {
+	return &oidc.IDToken{
+		Issuer:        "https://idp.example",
+		Subject:       "victim",
+		Email:         testIdentityEmail,
+		EmailVerified: true,
+		Name:          "Victim",
+	}
+}
+
+func TestResolveUserDoesNotLinkUserWithPassword(t *testing.T) {
+	s, users, _ := newTestOIDCService(true)
+	squatter := addTestUser(users, testIdentityEmail, "$2a$10$hash")
+
+	user, err := s.resolveUser(context.Background(), testIDToken())
+	if err != nil {
+		t.Fatalf("resolveUser() error = %v", err)
+	}
+	if user.ID == squatter.ID {
+		t.Fatal("identity was linked to the user that registered its email with a password")
+	}
+	if user.Email != nil {
+		t.Errorf("new user Email = %q, want none", *user.Email)
+	}
+
+	// Later logins keep going to the new user.
+	again, err := s.resolveUser(context.Background(), testIDToken())
+	if err != nil {
+		t.Fatalf("resolveUser() second login error = %v", err)
+	}
+	if again.ID != user.ID {
+		t.Errorf("second login got user %s, want %s", again.ID, user.ID)
+	}
+}
+
+func TestResolveUserDoesNotLinkUserWithAPIKey(t *testing.T) {
+	s, users, apiKeys := newTestOIDCService(true)
+	squatter := addTestUser(users, testIdentityEmail, "")
+	apiKeys.keys = append(apiKeys.keys, database.ApiKey{ID: uuid.New(), UserID: squatter.ID, Scope: domain.APIKeyScopeAdmin})
+
+	user, err := s.resolveUser(context.Background(), testIDToken())
+	if err != nil {
+		t.Fatalf("resolveUser() error = %v", err)
+	}
+	if user.ID == squatter.ID {
+		t.Fatal("identity was linked to the user that registered its email with an API key")
+	}
+}
+
+func TestResolveUserWithoutSignupRefusesUserWithPassword(t *testing.T) {
+	s, users, _ := newTestOIDCService(false)
+	addTestUser(users, testIdentityEmail, "$2a$10$hash")
+
+	_, err := s.resolveUser(context.Background(), testIDToken())
+	if !errors.Is(err, domain.ErrOIDCEmailTaken) {
+		t.Fatalf("resolveUser() error = %v, want ErrOIDCEmailTaken", err)
+	}
+}
+
+func TestResolveUserLinksUserWithoutCredentials(t *testing.T) {
+	s, users, apiKeys := newTestOIDCService(false)
+	existing := addTestUser(users, testIdentityEmail, "")
+	revokedAt := gosql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true}
+	apiKeys.keys = append(apiKeys.keys, database.ApiKey{ID: uuid.New(), UserID: existing.ID, Scope: domain.APIKeyScopeAdmin, RevokedAt: revokedAt})
+
+	user, err := s.resolveUser(context.Background(), testIDToken())
+	if err != nil {
+		t.Fatalf("resolveUser() error = %v", err)
+	}
+	if user.ID != existing.ID {
+		t.Errorf("resolveUser() got user %s, want the existing user %s", user.ID, existing.ID)
+	}
+}
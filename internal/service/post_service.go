@@ -7,43 +7,96 @@ import (
 	"github.com/hel1th/rssagg/internal/database"
 	"github.com/hel1th/rssagg/internal/domain"
 	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
 )
 
 type PostService interface {
-	GetPostsForUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Post, error)
+	GetPostsForUser(ctx context.Context, userID uuid.UUID, query domain.TimelineQuery) ([]*domain.TimelinePost, error)
+	GetPostsForUserSince(ctx context.Context, userID uuid.UUID, after domain.StreamPosition, limit int) ([]*domain.StreamPost, error)
+	GetPostStreamStart(ctx context.Context) (domain.StreamPosition, error)
 }
 
 type postService struct {
-	repo repository.PostRepository
+	repo    repository.PostRepository
+	tagRepo repository.TagRepository
 }
 
-func NewPostService(repo repository.PostRepository) PostService {
-	return &postService{repo: repo}
+func NewPostService(repo repository.PostRepository, tagRepo repository.TagRepository) PostService {
+	return &postService{repo: repo, tagRepo: tagRepo}
 }
 
-func (s *postService) GetPostsForUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Post, error) {
+func (s *postService) GetPostsForUser(ctx context.Context, userID uuid.UUID, query domain.TimelineQuery) ([]*domain.TimelinePost, error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetPostsForUser")
+	defer span.End()
+
 	if userID == uuid.Nil {
 		return nil, domain.ErrInvalidUserID
 	}
 
-	if limit <= 0 {
-		limit = 10
-	}
-	if limit > 100 {
-		limit = 100
+	if err := query.Validate(); err != nil {
+		return nil, err
 	}
-	if offset < 0 {
-		offset = 0
+
+	tagID := uuid.NullUUID{}
+	if query.Tag != "" {
+		dbTag, err := s.tagRepo.GetByName(ctx, database.GetTagByNameParams{
+			UserID: userID,
+			Name:   query.Tag,
+		})
+		if err != nil {
+			return nil, notFound(err, domain.ErrTagNotFound)
+		}
+		tagID = uuid.NullUUID{UUID: dbTag.ID, Valid: true}
 	}
 
 	dbPosts, err := s.repo.GetForUser(ctx, database.GetPostsForUserParams{
-		UserID: userID,
-		Limit:  int32(limit),
-		Offset: int32(offset),
+		UserID:      userID,
+		TagID:       tagID,
+		FolderID:    nullUUID(query.FolderID),
+		StarredOnly: query.Starred,
+		Sort:        query.Sort,
+		PageLimit:   int32(query.Limit),
+		PageOffset:  int32(query.Offset),
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return domain.MapPostsFromDB(dbPosts), nil
+	return domain.MapTimelinePostsFromDB(dbPosts), nil
+}
+
+// GetPostsForUserSince returns the timeline posts after a stream position
+// in stream order, for clients following the post stream.
+func (s *postService) GetPostsForUserSince(ctx context.Context, userID uuid.UUID, after domain.StreamPosition, limit int) ([]*domain.StreamPost, error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetPostsForUserSince")
+	defer span.End()
+
+	if userID == uuid.Nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	dbPosts, err := s.repo.GetForUserSince(ctx, database.GetPostsForUserSinceParams{
+		UserID:    userID,
+		AfterXid:  after.XID,
+		AfterSeq:  after.Seq,
+		PageLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.MapStreamPostsFromDB(dbPosts), nil
+}
+
+// GetPostStreamStart returns the position a new stream starts after: every
+// post that isn't readable yet comes later.
+func (s *postService) GetPostStreamStart(ctx context.Context) (domain.StreamPosition, error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetPostStreamStart")
+	defer span.End()
+
+	xid, err := s.repo.GetStreamHorizon(ctx)
+	if err != nil {
+		return domain.StreamPosition{}, err
+	}
+	return domain.StreamPosition{XID: xid}, nil
 }
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/ratelimit"
+	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+// RateLimitService keeps rate limit buckets in Postgres, so limits hold
+// across every API instance. It is a ratelimit.Store.
+type RateLimitService interface {
+	Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error)
+	DeleteIdle(ctx context.Context, idle time.Duration) (int64, error)
+}
+
+type rateLimitService struct {
+	repo repository.RateLimitRepository
+}
+
+func NewRateLimitService(repo repository.RateLimitRepository) RateLimitService {
+	return &rateLimitService{
+		repo: repo,
+	}
+}
+
+func (s *rateLimitService) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	ctx, span := tracing.Start(ctx, "RateLimitService.Take")
+	defer span.End()
+
+	row, err := s.repo.Take(ctx, database.TakeRateLimitTokenParams{
+		Key:      key,
+		Capacity: float64(limit.Requests),
+		Rate:     limit.Rate(),
+	})
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+
+	return ratelimit.NewResult(limit, row.Tokens, row.Allowed), nil
+}
+
+// DeleteIdle deletes buckets unused for idle. Once idle is longer than any
+// limit's period they have all refilled, and deleting them changes nothing.
+func (s *rateLimitService) DeleteIdle(ctx context.Context, idle time.Duration) (int64, error) {
+	ctx, span := tracing.Start(ctx, "RateLimitService.DeleteIdle")
+	defer span.End()
+
+	return s.repo.DeleteIdle(ctx, idle.Seconds())
+}
@@ -0,0 +1,401 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/auth"
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/rss"
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+type ReaderService interface {
+	Login(ctx context.Context, name, apiKey string) (string, error)
+	GetSubscriptions(ctx context.Context, userID uuid.UUID) ([]*domain.ReaderSubscription, error)
+	Subscribe(ctx context.Context, userID uuid.UUID, feedURL, title, label string) (*domain.ReaderSubscription, error)
+	EditSubscription(ctx context.Context, userID uuid.UUID, streamID, title, addLabel, removeLabel string) error
+	Unsubscribe(ctx context.Context, userID uuid.UUID, streamID string) error
+	GetLabels(ctx context.Context, userID uuid.UUID) ([]string, error)
+	GetStreamItems(ctx context.Context, userID uuid.UUID, query domain.ReaderStreamQuery) ([]*domain.ReaderItem, *int64, error)
+	GetStreamItemIDs(ctx context.Context, userID uuid.UUID, query domain.ReaderStreamQuery) ([]*domain.ReaderItemRef, *int64, error)
+	GetItems(ctx context.Context, userID uuid.UUID, ids []int64) ([]*domain.ReaderItem, error)
+	EditTags(ctx context.Context, userID uuid.UUID, ids []int64, add, remove []string) error
+}
+
+type readerService struct {
+	repo       repository.ReaderRepository
+	feedRepo   repository.FeedRepository
+	folderRepo repository.FolderRepository
+	feeds      FeedService
+	follows    FeedFollowService
+	folders    FolderService
+	apiKeys    APIKeyService
+	fetcher    rss.Fetcher
+}
+
+func NewReaderService(repo repository.ReaderRepository, feedRepo repository.FeedRepository, folderRepo repository.FolderRepository, feeds FeedService, follows FeedFollowService, folders FolderService, apiKeys APIKeyService, fetcher rss.Fetcher) ReaderService {
+	return &readerService{
+		repo:       repo,
+		feedRepo:   feedRepo,
+		folderRepo: folderRepo,
+		feeds:      feeds,
+		follows:    follows,
+		folders:    folders,
+		apiKeys:    apiKeys,
+		fetcher:    fetcher,
+	}
+}
+
+// Login checks a user name and API key and returns the auth token clients
+// send back as "GoogleLogin auth=<token>": md5("<name>:<api_key>"), which
+// API keys are also looked up by.
+func (s *readerService) Login(ctx context.Context, name, apiKey string) (string, error) {
+	ctx, span := tracing.Start(ctx, "ReaderService.Login")
+	defer span.End()
+
+	if name == "" || apiKey == "" {
+		return "", domain.ErrInvalidReaderCredentials
+	}
+
+	token := auth.ClientHash(name, apiKey)
+	if _, _, err := s.apiKeys.AuthenticateClientHash(ctx, token); errors.Is(err, domain.ErrInvalidAPIKey) {
+		return "", domain.ErrInvalidReaderCredentials
+	} else if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (s *readerService) GetSubscriptions(ctx context.Context, userID uuid.UUID) ([]*domain.ReaderSubscription, error) {
+	ctx, span := tracing.Start(ctx, "ReaderService.GetSubscriptions")
+	defer span.End()
+
+	rows, err := s.repo.GetSubscriptions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.MapReaderSubscriptionsFromDB(rows), nil
+}
+
+// Subscribe follows the feed at feedURL, adding it to rssagg first if no one
+// has yet. New feeds are fetched once to check them and learn their title.
+func (s *readerService) Subscribe(ctx context.Context, userID uuid.UUID, feedURL, title, label string) (*domain.ReaderSubscription, error) {
+	ctx, span := tracing.Start(ctx, "ReaderService.Subscribe")
+	defer span.End()
+
+	feedURL = strings.TrimPrefix(strings.TrimSpace(feedURL), domain.ReaderFeedPrefix)
+
+	dbFeed, err := s.feedRepo.GetByURL(ctx, feedURL)
+	if err != nil {
+		// The subscriber is the feed's first
+		data, err := s.fetcher.Fetch(ctx, feedURL, 1)
+		if err != nil {
+			return nil, domain.ErrInvalidFeedURL
+		}
+
+		name := strings.TrimSpace(data.Title)
+		if name == "" {
+			name = feedURL
+		}
+		if _, err := s.feeds.CreateFeed(ctx, name, feedURL, userID); err != nil {
+			return nil, err
+		}
+
+		dbFeed, err = s.feedRepo.GetByURL(ctx, feedURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.subscription(ctx, userID, dbFeed.Seq); err != nil {
+		if _, err := s.follows.FollowFeed(ctx, userID, dbFeed.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	streamID := domain.ReaderFeedPrefix + strconv.FormatInt(dbFeed.Seq, 10)
+	if title != "" || label != "" {
+		if err := s.EditSubscription(ctx, userID, streamID, title, label, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	subscription := &domain.ReaderSubscription{
+		FeedID: dbFeed.Seq,
+		Title:  dbFeed.Name,
+		URL:    dbFeed.Url,
+	}
+	if title != "" {
+		subscription.Title = title
+	}
+	return subscription, nil
+}
+
+// EditSubscription renames a subscription and moves it between labels.
+// Labels are folders, and a feed is in at most one, so adding a label
+// replaces the current one.
+func (s *readerService) EditSubscription(ctx context.Context, userID uuid.UUID, streamID, title, addLabel, removeLabel string) error {
+	ctx, span := tracing.Start(ctx, "ReaderService.EditSubscription")
+	defer span.End()
+
+	feedID, err := readerFeedID(streamID)
+	if err != nil {
+		return err
+	}
+
+	row, err := s.subscription(ctx, userID, feedID)
+	if err != nil {
+		return err
+	}
+
+	var update domain.FeedFollowUpdate
+	if title != "" {
+		update.Title = &title
+	}
+	if name := readerLabel(removeLabel); name != "" && row.FolderName.String == name {
+		none := uuid.Nil
+		update.FolderID = &none
+	}
+	if name := readerLabel(addLabel); name != "" {
+		folder, err := s.folder(ctx, userID, name)
+		if err != nil {
+			return err
+		}
+		update.FolderID = &folder.ID
+	}
+
+	_, err = s.follows.UpdateFeedFollow(ctx, row.FeedFollowID, userID, update)
+	return err
+}
+
+func (s *readerService) Unsubscribe(ctx context.Context, userID uuid.UUID, streamID string) error {
+	ctx, span := tracing.Start(ctx, "ReaderService.Unsubscribe")
+	defer span.End()
+
+	feedID, err := readerFeedID(streamID)
+	if err != nil {
+		return err
+	}
+
+	row, err := s.subscription(ctx, userID, feedID)
+	if err != nil {
+		return err
+	}
+
+	return s.follows.UnfollowFeed(ctx, row.FeedFollowID, userID)
+}
+
+func (s *readerService) GetLabels(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "ReaderService.GetLabels")
+	defer span.End()
+
+	folders, err := s.folders.GetUserFolders(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]string, len(folders))
+	for i, folder := range folders {
+		labels[i] = folder.Name
+	}
+	return labels, nil
+}
+
+// GetStreamItems returns a page of the stream and the continuation for the
+// next page, which is nil on the last page.
+func (s *readerService) GetStreamItems(ctx context.Context, userID uuid.UUID, query domain.ReaderStreamQuery) ([]*domain.ReaderItem, *int64, error) {
+	ctx, span := tracing.Start(ctx, "ReaderService.GetStreamItems")
+	defer span.End()
+
+	params, err := readerItemsParams(userID, query, domain.ReaderMaxItemLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := s.repo.GetItems(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := domain.MapReaderItemsFromDB(rows)
+	var continuation *int64
+	if len(items) == int(params.PageLimit) {
+		continuation = &items[len(items)-1].ID
+	}
+	return items, continuation, nil
+}
+
+func (s *readerService) GetStreamItemIDs(ctx context.Context, userID uuid.UUID, query domain.ReaderStreamQuery) ([]*domain.ReaderItemRef, *int64, error) {
+	ctx, span := tracing.Start(ctx, "ReaderService.GetStreamItemIDs")
+	defer span.End()
+
+	params, err := readerItemsParams(userID, query, domain.ReaderMaxIDLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := s.repo.GetItemIDs(ctx, database.GetReaderItemIDsParams(params))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refs := domain.MapReaderItemRefsFromDB(rows)
+	var continuation *int64
+	if len(refs) == int(params.PageLimit) {
+		continuation = &refs[len(refs)-1].ID
+	}
+	return refs, continuation, nil
+}
+
+func (s *readerService) GetItems(ctx context.Context, userID uuid.UUID, ids []int64) ([]*domain.ReaderItem, error) {
+	ctx, span := tracing.Start(ctx, "ReaderService.GetItems")
+	defer span.End()
+
+	if len(ids) == 0 {
+		return []*domain.ReaderItem{}, nil
+	}
+
+	rows, err := s.repo.GetItemsByIDs(ctx, database.GetReaderItemsByIDsParams{
+		UserID: userID,
+		Ids:    ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.MapReaderItemsByIDsFromDB(rows), nil
+}
+
+// EditTags adds and removes the read and starred states. Other tags are
+// ignored, since rssagg has no per-item labels clients could rely on.
+func (s *readerService) EditTags(ctx context.Context, userID uuid.UUID, ids []int64, add, remove []string) error {
+	ctx, span := tracing.Start(ctx, "ReaderService.EditTags")
+	defer span.End()
+
+	if len(ids) == 0 {
+		return domain.ErrInvalidReaderItemID
+	}
+
+	apply := func(tags []string, set bool) error {
+		for _, tag := range tags {
+			var err error
+			switch domain.NormalizeReaderStreamID(tag) {
+			case domain.ReaderStreamRead:
+				err = s.repo.SetItemsRead(ctx, database.SetReaderItemsReadParams{
+					Read:   set,
+					UserID: userID,
+					Ids:    ids,
+				})
+			case domain.ReaderStreamStarred:
+				err = s.repo.SetItemsStarred(ctx, database.SetReaderItemsStarredParams{
+					Starred: set,
+					UserID:  userID,
+					Ids:     ids,
+				})
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := apply(remove, false); err != nil {
+		return err
+	}
+	return apply(add, true)
+}
+
+func (s *readerService) subscription(ctx context.Context, userID uuid.UUID, feedID int64) (database.GetReaderSubscriptionRow, error) {
+	row, err := s.repo.GetSubscription(ctx, database.GetReaderSubscriptionParams{
+		UserID: userID,
+		Seq:    feedID,
+	})
+	if err != nil {
+		return row, notFound(err, domain.ErrReaderSubscriptionNotFound)
+	}
+	return row, nil
+}
+
+// folder returns the user's folder called name, creating it if needed.
+func (s *readerService) folder(ctx context.Context, userID uuid.UUID, name string) (*domain.Folder, error) {
+	dbFolder, err := s.folderRepo.GetByName(ctx, database.GetFolderByNameParams{
+		UserID: userID,
+		Name:   name,
+	})
+	if err == nil {
+		return domain.MapFolderFromDB(dbFolder), nil
+	}
+
+	return s.folders.CreateFolder(ctx, name, userID)
+}
+
+func readerItemsParams(userID uuid.UUID, query domain.ReaderStreamQuery, maxLimit int) (database.GetReaderItemsParams, error) {
+	filter, err := query.Filter()
+	if err != nil {
+		return database.GetReaderItemsParams{}, err
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = domain.ReaderDefaultItemLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	params := database.GetReaderItemsParams{
+		UserID:         userID,
+		ReadOnly:       filter.ReadOnly,
+		ExcludeRead:    filter.ExcludeRead,
+		StarredOnly:    filter.StarredOnly,
+		ExcludeStarred: filter.ExcludeStarred,
+		OldestFirst:    query.OldestFirst,
+		PageLimit:      int32(limit),
+	}
+	if filter.FeedID != nil {
+		params.FeedSeq = sql.NullInt64{Int64: *filter.FeedID, Valid: true}
+	}
+	if filter.Folder != nil {
+		params.FolderName = sql.NullString{String: *filter.Folder, Valid: true}
+	}
+	if query.NewerThan != nil {
+		params.NewerThan = sql.NullTime{Time: *query.NewerThan, Valid: true}
+	}
+	if query.OlderThan != nil {
+		params.OlderThan = sql.NullTime{Time: *query.OlderThan, Valid: true}
+	}
+	if query.Continuation != nil {
+		params.Continuation = sql.NullInt64{Int64: *query.Continuation, Valid: true}
+	}
+	return params, nil
+}
+
+func readerFeedID(streamID string) (int64, error) {
+	if !strings.HasPrefix(streamID, domain.ReaderFeedPrefix) {
+		return 0, domain.ErrInvalidReaderStream
+	}
+	feedID, err := strconv.ParseInt(strings.TrimPrefix(streamID, domain.ReaderFeedPrefix), 10, 64)
+	if err != nil {
+		return 0, domain.ErrInvalidReaderStream
+	}
+	return feedID, nil
+}
+
+// readerLabel returns the folder name of a "user/-/label/<name>" tag.
+func readerLabel(tag string) string {
+	tag = domain.NormalizeReaderStreamID(tag)
+	if !strings.HasPrefix(tag, domain.ReaderLabelPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(tag, domain.ReaderLabelPrefix)
+}
@@ -4,56 +4,78 @@ import (
 	"context"
 	gosql "database/sql"
 	"fmt"
-	"log"
-	"strings"
+	"log/slog"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/hel1th/rssagg/internal/database"
 	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/logging"
+	"github.com/hel1th/rssagg/internal/metrics"
 	"github.com/hel1th/rssagg/internal/repository"
 	"github.com/hel1th/rssagg/internal/rss"
+	"github.com/hel1th/rssagg/internal/tracing"
 )
 
+// faviconRefreshInterval is how often a feed's site favicon is looked up again.
+const faviconRefreshInterval = 30 * 24 * time.Hour
+
 type RSSService interface {
 	FetchAndStoreFeeds(ctx context.Context, feeds []domain.Feed) error
 	FetchSingleFeed(ctx context.Context, feed domain.Feed) (int, error)
+	IngestPushedContent(ctx context.Context, feed domain.Feed, body []byte) (int, error)
 }
 
 type rssService struct {
-	postRepo repository.PostRepository
-	feedRepo repository.FeedRepository
-	fetcher  rss.Fetcher
+	postRepo    repository.PostRepository
+	feedRepo    repository.FeedRepository
+	filterRules FilterRuleService
+	webhooks    WebhookService
+	websub      WebSubService
+	fetcher     rss.Fetcher
+	metrics     *metrics.Metrics
 }
 
-func NewRSSService(postRepo repository.PostRepository, feedRepo repository.FeedRepository) RSSService {
-	return NewRSSServiceWithFetcher(postRepo, feedRepo, rss.NewFetcher())
+// NewRSSService creates the ingestion service. websub may be nil, in which
+// case feeds are only ever polled.
+func NewRSSService(postRepo repository.PostRepository, feedRepo repository.FeedRepository, filterRules FilterRuleService, webhooks WebhookService, websub WebSubService) RSSService {
+	return NewRSSServiceWithFetcher(postRepo, feedRepo, filterRules, webhooks, websub, rss.NewFetcher(rss.FetcherConfig{}), nil)
 }
 
-func NewRSSServiceWithFetcher(postRepo repository.PostRepository, feedRepo repository.FeedRepository, fetcher rss.Fetcher) RSSService {
+// NewRSSServiceWithFetcher creates the ingestion service with its own
+// fetcher. metrics may be nil, in which case nothing is measured.
+func NewRSSServiceWithFetcher(postRepo repository.PostRepository, feedRepo repository.FeedRepository, filterRules FilterRuleService, webhooks WebhookService, websub WebSubService, fetcher rss.Fetcher, metrics *metrics.Metrics) RSSService {
 	return &rssService{
-		postRepo: postRepo,
-		feedRepo: feedRepo,
-		fetcher:  fetcher,
+		postRepo:    postRepo,
+		feedRepo:    feedRepo,
+		filterRules: filterRules,
+		webhooks:    webhooks,
+		websub:      websub,
+		fetcher:     fetcher,
+		metrics:     metrics,
 	}
 }
 
 func (s *rssService) FetchAndStoreFeeds(ctx context.Context, feeds []domain.Feed) error {
+	ctx, span := tracing.Start(ctx, "RSSService.FetchAndStoreFeeds")
+	defer span.End()
+
 	var wg sync.WaitGroup
 
 	for _, feed := range feeds {
 		wg.Add(1)
 		go func(f domain.Feed) {
 			defer wg.Done()
+			defer s.metrics.FetchStarted()()
 
 			newPosts, err := s.FetchSingleFeed(ctx, f)
 			if err != nil {
-				log.Printf("Error fetching feed %s: %v", f.Name, err)
+				slog.WarnContext(ctx, "Error fetching feed", "feed_id", f.ID, "feed", f.Name, "error", err)
 				return
 			}
 
-			log.Printf("Feed %s collected. %d new posts", f.Name, newPosts)
+			slog.InfoContext(ctx, "Feed collected", "feed_id", f.ID, "feed", f.Name, "new_posts", newPosts)
 		}(feed)
 	}
 
@@ -61,7 +83,30 @@ func (s *rssService) FetchAndStoreFeeds(ctx context.Context, feeds []domain.Feed
 	return nil
 }
 
+// FetchSingleFeed fetches and stores a feed, recording on the feed whether
+// it failed, and why, for admins to look into.
 func (s *rssService) FetchSingleFeed(ctx context.Context, feed domain.Feed) (int, error) {
+	ctx, span := tracing.Start(ctx, "RSSService.FetchSingleFeed")
+	defer span.End()
+
+	ctx = logging.With(ctx, "feed_id", feed.ID, "feed", feed.Name)
+	newPosts, err := s.fetchSingleFeed(ctx, feed)
+
+	// Feeds that keep working don't need a write
+	if err != nil || feed.LastFetchError != nil {
+		fetchError := gosql.NullString{}
+		if err != nil {
+			fetchError = gosql.NullString{String: err.Error(), Valid: true}
+		}
+		if recordErr := s.feedRepo.SetFetchError(ctx, database.SetFeedFetchErrorParams{ID: feed.ID, LastFetchError: fetchError}); recordErr != nil {
+			slog.ErrorContext(ctx, "Error recording fetch result", "error", recordErr)
+		}
+	}
+
+	return newPosts, err
+}
+
+func (s *rssService) fetchSingleFeed(ctx context.Context, feed domain.Feed) (int, error) {
 	_, err := s.feedRepo.MarkAsFetched(ctx, feed.ID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to mark feed as fetched: %w", err)
@@ -71,32 +116,158 @@ func (s *rssService) FetchSingleFeed(ctx context.Context, feed domain.Feed) (int
 		return 0, fmt.Errorf("no RSS fetcher configured")
 	}
 
-	rssFeed, err := s.fetcher.Fetch(feed.URL)
+	// Sites see how many people they are serving in our User-Agent
+	subscribers, err := s.feedRepo.CountFollowers(ctx, feed.ID)
 	if err != nil {
+		return 0, fmt.Errorf("failed to count feed followers: %w", err)
+	}
+
+	start := time.Now()
+	rssFeed, err := s.fetcher.Fetch(ctx, feed.URL, subscribers)
+	if err != nil {
+		s.metrics.ObserveFetch(metrics.FetchError, time.Since(start), 0)
 		return 0, fmt.Errorf("failed to fetch RSS from URL: %w", err)
 	}
+	s.metrics.ObserveFetch(metrics.FetchSuccess, time.Since(start), rssFeed.Size)
+
+	if s.websub != nil && rssFeed.HubURL != "" {
+		if err := s.websub.Discover(ctx, feed, rssFeed.HubURL, rssFeed.SelfURL); err != nil {
+			slog.WarnContext(ctx, "Error subscribing to WebSub hub", "error", err)
+		}
+	}
+
+	if rssFeed.Link != "" && (feed.FaviconCheckedAt == nil || time.Since(*feed.FaviconCheckedAt) > faviconRefreshInterval) {
+		s.refreshFavicon(ctx, feed, rssFeed.Link, subscribers)
+	}
+
+	return s.store(ctx, feed, rssFeed)
+}
+
+// refreshFavicon looks up the site's favicon for clients such as Fever
+// readers that show one next to each feed. A missing favicon is stored as
+// such, so it isn't looked up again until the next refresh.
+func (s *rssService) refreshFavicon(ctx context.Context, feed domain.Feed, siteURL string, subscribers int64) {
+	favicon := gosql.NullString{}
+	dataURI, err := s.fetcher.FetchFavicon(ctx, siteURL, subscribers)
+	if err != nil {
+		slog.DebugContext(ctx, "No favicon", "error", err)
+	} else {
+		favicon = gosql.NullString{String: dataURI, Valid: true}
+	}
+
+	if err := s.feedRepo.UpdateFavicon(ctx, database.UpdateFeedFaviconParams{
+		ID:      feed.ID,
+		Favicon: favicon,
+	}); err != nil {
+		slog.ErrorContext(ctx, "Error saving favicon", "error", err)
+	}
+}
+
+// IngestPushedContent stores content a WebSub hub pushed for the feed,
+// through the same pipeline as polled content. Content for disabled feeds
+// is dropped.
+func (s *rssService) IngestPushedContent(ctx context.Context, feed domain.Feed, body []byte) (int, error) {
+	ctx, span := tracing.Start(ctx, "RSSService.IngestPushedContent")
+	defer span.End()
+
+	if feed.DisabledAt != nil {
+		return 0, nil
+	}
+	ctx = logging.With(ctx, "feed_id", feed.ID, "feed", feed.Name)
 
-	newPostCount := 0
+	rssFeed, err := rss.Parse(body)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.store(ctx, feed, rssFeed)
+}
+
+func (s *rssService) store(ctx context.Context, feed domain.Feed, rssFeed *domain.RSSFeedData) (int, error) {
+	titleChanged := rssFeed.Title != "" && (feed.SiteTitle == nil || *feed.SiteTitle != rssFeed.Title)
+	linkChanged := rssFeed.Link != "" && (feed.SiteURL == nil || *feed.SiteURL != rssFeed.Link)
+	if titleChanged || linkChanged {
+		siteTitle, siteURL := gosql.NullString{}, gosql.NullString{}
+		if rssFeed.Title != "" {
+			siteTitle = gosql.NullString{String: rssFeed.Title, Valid: true}
+		} else if feed.SiteTitle != nil {
+			siteTitle = gosql.NullString{String: *feed.SiteTitle, Valid: true}
+		}
+		if rssFeed.Link != "" {
+			siteURL = gosql.NullString{String: rssFeed.Link, Valid: true}
+		} else if feed.SiteURL != nil {
+			siteURL = gosql.NullString{String: *feed.SiteURL, Valid: true}
+		}
+
+		err := s.feedRepo.UpdateSiteInfo(ctx, database.UpdateFeedSiteInfoParams{
+			ID:        feed.ID,
+			SiteTitle: siteTitle,
+			SiteUrl:   siteURL,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "Error updating site info", "error", err)
+		}
+	}
+
+	var newPosts []*domain.Post
+	duplicates := 0
 	for _, item := range rssFeed.Items {
 		postData, err := s.parseRSSItem(item, feed.ID)
 		if err != nil {
-			log.Printf("Error parsing RSS item: %v", err)
+			slog.DebugContext(ctx, "Skipping invalid RSS item", "error", err)
 			continue
 		}
 
-		err = s.postRepo.Create(ctx, postData)
+		// Duplicate URLs are skipped by the insert and come back as 0 rows.
+		inserted, err := s.postRepo.Create(ctx, postData)
 		if err != nil {
-			if s.isDuplicateError(err) {
-				continue
-			}
-			log.Printf("Error creating post: %v", err)
+			slog.ErrorContext(ctx, "Error creating post", "error", err)
+			continue
+		}
+		if inserted == 0 {
+			duplicates++
 			continue
 		}
 
-		newPostCount++
+		newPosts = append(newPosts, domain.MapPostFromDB(database.Post{
+			ID:          postData.ID,
+			CreatedAt:   postData.CreatedAt,
+			UpdatedAt:   postData.UpdatedAt,
+			Title:       postData.Title,
+			Description: postData.Description,
+			PublishedAt: postData.PublishedAt,
+			Url:         postData.Url,
+			FeedID:      postData.FeedID,
+			Author:      postData.Author,
+		}))
 	}
 
-	return newPostCount, nil
+	s.metrics.CountPosts(len(newPosts), duplicates)
+
+	if s.filterRules != nil {
+		if err := s.filterRules.ApplyToNewPosts(ctx, feed.ID, newPosts); err != nil {
+			slog.ErrorContext(ctx, "Error applying filter rules", "error", err)
+		}
+	}
+
+	// Streams only see posts once filter rules have had a chance to hide them.
+	if len(newPosts) > 0 {
+		ids := make([]uuid.UUID, len(newPosts))
+		for i, post := range newPosts {
+			ids[i] = post.ID
+		}
+		if err := s.postRepo.Publish(ctx, database.PublishPostsParams{FeedID: feed.ID, Ids: ids}); err != nil {
+			slog.ErrorContext(ctx, "Error publishing posts to streams", "error", err)
+		}
+	}
+
+	if s.webhooks != nil {
+		if err := s.webhooks.EnqueueNewPosts(ctx, feed, newPosts); err != nil {
+			slog.ErrorContext(ctx, "Error queueing webhooks", "error", err)
+		}
+	}
+
+	return len(newPosts), nil
 }
 
 func (s *rssService) parseRSSItem(item domain.RSSItemData, feedID uuid.UUID) (database.CreatePostParams, error) {
@@ -116,6 +287,10 @@ func (s *rssService) parseRSSItem(item domain.RSSItemData, feedID uuid.UUID) (da
 	if item.Description != "" {
 		desc = gosql.NullString{String: item.Description, Valid: true}
 	}
+	author := gosql.NullString{}
+	if item.Author != "" {
+		author = gosql.NullString{String: item.Author, Valid: true}
+	}
 
 	now := time.Now().UTC()
 	return database.CreatePostParams{
@@ -127,9 +302,6 @@ func (s *rssService) parseRSSItem(item domain.RSSItemData, feedID uuid.UUID) (da
 		Url:         item.Link,
 		FeedID:      feedID,
 		PublishedAt: pubAt,
+		Author:      author,
 	}, nil
 }
-
-func (s *rssService) isDuplicateError(err error) bool {
-	return strings.Contains(err.Error(), "23505")
-}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	gosql "database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+// ScraperService records the scraper's heartbeat and reports how it and
+// the feeds it fetches are doing.
+type ScraperService interface {
+	RunStarted(ctx context.Context, feeds int) error
+	RunFinished(ctx context.Context) error
+	Status(ctx context.Context) (*domain.ScraperStatus, error)
+	Heartbeats(ctx context.Context) ([]*domain.ScraperWorker, error)
+	QueueLag(ctx context.Context) (time.Duration, error)
+}
+
+type scraperService struct {
+	repo      repository.ScraperRepository
+	workerID  string
+	startedAt time.Time
+}
+
+// NewScraperService returns a ScraperService whose heartbeat identifies
+// this process by host name and PID.
+func NewScraperService(repo repository.ScraperRepository) ScraperService {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return &scraperService{
+		repo:      repo,
+		workerID:  fmt.Sprintf("%s-%d", host, os.Getpid()),
+		startedAt: time.Now().UTC(),
+	}
+}
+
+func (s *scraperService) RunStarted(ctx context.Context, feeds int) error {
+	ctx, span := tracing.Start(ctx, "ScraperService.RunStarted")
+	defer span.End()
+
+	return s.repo.BeginRun(ctx, database.BeginScraperRunParams{
+		WorkerID:     s.workerID,
+		StartedAt:    s.startedAt,
+		LastRunFeeds: int32(feeds),
+	})
+}
+
+func (s *scraperService) RunFinished(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "ScraperService.RunFinished")
+	defer span.End()
+
+	return s.repo.FinishRun(ctx, s.workerID)
+}
+
+func (s *scraperService) Status(ctx context.Context) (*domain.ScraperStatus, error) {
+	ctx, span := tracing.Start(ctx, "ScraperService.Status")
+	defer span.End()
+
+	heartbeats, err := s.repo.GetHeartbeats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repo.GetFeedStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	failing, err := s.repo.GetFailingFeeds(ctx, domain.ScraperFailingFeedsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &domain.ScraperStatus{
+		Workers:           make([]*domain.ScraperWorker, len(heartbeats)),
+		TotalFeeds:        stats.Total,
+		DisabledFeeds:     stats.Disabled,
+		NeverFetchedFeeds: stats.NeverFetched,
+		FailingFeedCount:  stats.Failing,
+		FailingFeeds:      domain.MapFeedsFromDB(failing),
+	}
+	for i, heartbeat := range heartbeats {
+		status.Workers[i] = domain.MapScraperWorkerFromDB(heartbeat)
+	}
+
+	oldest, err := s.repo.GetOldestFetchedAt(ctx)
+	if err != nil && !errors.Is(err, gosql.ErrNoRows) {
+		return nil, err
+	}
+	if oldest.Valid {
+		status.OldestFetchedAt = &oldest.Time
+	}
+
+	return status, nil
+}
+
+// Heartbeats lists the scraper processes, the latest to beat first.
+func (s *scraperService) Heartbeats(ctx context.Context) ([]*domain.ScraperWorker, error) {
+	ctx, span := tracing.Start(ctx, "ScraperService.Heartbeats")
+	defer span.End()
+
+	heartbeats, err := s.repo.GetHeartbeats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := make([]*domain.ScraperWorker, len(heartbeats))
+	for i, heartbeat := range heartbeats {
+		workers[i] = domain.MapScraperWorkerFromDB(heartbeat)
+	}
+	return workers, nil
+}
+
+// QueueLag is how long the feed next in line to be fetched has waited,
+// or zero when there are no feeds to fetch.
+func (s *scraperService) QueueLag(ctx context.Context) (time.Duration, error) {
+	ctx, span := tracing.Start(ctx, "ScraperService.QueueLag")
+	defer span.End()
+
+	dueSince, err := s.repo.GetNextDueSince(ctx)
+	if errors.Is(err, gosql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return max(time.Since(dueSince), 0), nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+// syndicationItemLimit caps the number of posts republished in an output feed.
+const syndicationItemLimit = 50
+
+// SyndicationService builds the posts a user has collected into feeds that
+// can be republished as RSS, Atom or JSON Feed.
+type SyndicationService interface {
+	TimelineFeed(ctx context.Context, user *domain.User) (*domain.OutputFeed, error)
+	FolderFeed(ctx context.Context, userID, folderID uuid.UUID) (*domain.OutputFeed, error)
+	TagFeed(ctx context.Context, userID, tagID uuid.UUID) (*domain.OutputFeed, error)
+	StarredFeed(ctx context.Context, user *domain.User) (*domain.OutputFeed, error)
+}
+
+type syndicationService struct {
+	postService   PostService
+	tagService    TagService
+	folderService FolderService
+}
+
+func NewSyndicationService(postService PostService, tagService TagService, folderService FolderService) SyndicationService {
+	return &syndicationService{
+		postService:   postService,
+		tagService:    tagService,
+		folderService: folderService,
+	}
+}
+
+func (s *syndicationService) TimelineFeed(ctx context.Context, user *domain.User) (*domain.OutputFeed, error) {
+	ctx, span := tracing.Start(ctx, "SyndicationService.TimelineFeed")
+	defer span.End()
+
+	posts, err := s.postService.GetPostsForUser(ctx, user.ID, domain.TimelineQuery{
+		Limit: syndicationItemLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buildOutputFeed(
+		fmt.Sprintf("rssagg: %s", user.Name),
+		fmt.Sprintf("Posts from feeds followed by %s", user.Name),
+		posts,
+	), nil
+}
+
+func (s *syndicationService) FolderFeed(ctx context.Context, userID, folderID uuid.UUID) (*domain.OutputFeed, error) {
+	ctx, span := tracing.Start(ctx, "SyndicationService.FolderFeed")
+	defer span.End()
+
+	folder, err := s.folderService.GetFolder(ctx, folderID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := s.postService.GetPostsForUser(ctx, userID, domain.TimelineQuery{
+		FolderID: &folder.ID,
+		Limit:    syndicationItemLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buildOutputFeed(
+		fmt.Sprintf("rssagg: %s", folder.Name),
+		fmt.Sprintf("Posts from feeds in the %q folder", folder.Name),
+		posts,
+	), nil
+}
+
+func (s *syndicationService) TagFeed(ctx context.Context, userID, tagID uuid.UUID) (*domain.OutputFeed, error) {
+	ctx, span := tracing.Start(ctx, "SyndicationService.TagFeed")
+	defer span.End()
+
+	tag, err := s.tagService.GetTag(ctx, tagID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := s.postService.GetPostsForUser(ctx, userID, domain.TimelineQuery{
+		Tag:   tag.Name,
+		Limit: syndicationItemLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buildOutputFeed(
+		fmt.Sprintf("rssagg: %s", tag.Name),
+		fmt.Sprintf("Posts tagged %q", tag.Name),
+		posts,
+	), nil
+}
+
+func (s *syndicationService) StarredFeed(ctx context.Context, user *domain.User) (*domain.OutputFeed, error) {
+	ctx, span := tracing.Start(ctx, "SyndicationService.StarredFeed")
+	defer span.End()
+
+	posts, err := s.postService.GetPostsForUser(ctx, user.ID, domain.TimelineQuery{
+		Starred: true,
+		Limit:   syndicationItemLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buildOutputFeed(
+		fmt.Sprintf("rssagg: %s (starred)", user.Name),
+		fmt.Sprintf("Posts starred by %s", user.Name),
+		posts,
+	), nil
+}
+
+func buildOutputFeed(title, description string, posts []*domain.TimelinePost) *domain.OutputFeed {
+	feed := &domain.OutputFeed{
+		Title:       title,
+		Description: description,
+		Items:       make([]domain.OutputItem, len(posts)),
+	}
+
+	for i, post := range posts {
+		item := domain.OutputItem{
+			ID:          "urn:uuid:" + post.ID.String(),
+			Title:       post.Title,
+			Link:        post.URL,
+			Source:      post.FeedTitle,
+			PublishedAt: post.PublishedAt,
+		}
+		if post.Description != nil {
+			item.Description = *post.Description
+		}
+		if post.Author != nil {
+			item.Author = *post.Author
+		}
+		if post.PublishedAt.After(feed.Updated) {
+			feed.Updated = post.PublishedAt
+		}
+		feed.Items[i] = item
+	}
+
+	if feed.Updated.IsZero() {
+		feed.Updated = time.Now().UTC()
+	}
+
+	return feed
+}
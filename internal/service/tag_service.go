@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
+)
+
+type TagService interface {
+	CreateTag(ctx context.Context, name string, userID uuid.UUID) (*domain.Tag, error)
+	GetUserTags(ctx context.Context, userID uuid.UUID) ([]*domain.Tag, error)
+	GetTag(ctx context.Context, tagID, userID uuid.UUID) (*domain.Tag, error)
+	RenameTag(ctx context.Context, tagID, userID uuid.UUID, name string) (*domain.Tag, error)
+	DeleteTag(ctx context.Context, tagID, userID uuid.UUID) error
+	TagPost(ctx context.Context, tagID, postID, userID uuid.UUID) error
+	UntagPost(ctx context.Context, tagID, postID, userID uuid.UUID) error
+}
+
+type tagService struct {
+	repo     repository.TagRepository
+	postRepo repository.PostRepository
+}
+
+func NewTagService(repo repository.TagRepository, postRepo repository.PostRepository) TagService {
+	return &tagService{
+		repo:     repo,
+		postRepo: postRepo,
+	}
+}
+
+func (s *tagService) CreateTag(ctx context.Context, name string, userID uuid.UUID) (*domain.Tag, error) {
+	ctx, span := tracing.Start(ctx, "TagService.CreateTag")
+	defer span.End()
+
+	tag := domain.NewTag(name, userID)
+
+	if err := tag.Validate(); err != nil {
+		return nil, err
+	}
+
+	dbTag, err := s.repo.Create(ctx, database.CreateTagParams{
+		ID:        tag.ID,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+		UserID:    tag.UserID,
+		Name:      tag.Name,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, domain.ErrDuplicateTag
+		}
+		return nil, err
+	}
+
+	return domain.MapTagFromDB(dbTag), nil
+}
+
+func (s *tagService) GetUserTags(ctx context.Context, userID uuid.UUID) ([]*domain.Tag, error) {
+	ctx, span := tracing.Start(ctx, "TagService.GetUserTags")
+	defer span.End()
+
+	if userID == uuid.Nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	rows, err := s.repo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.MapTagsFromDB(rows), nil
+}
+
+func (s *tagService) GetTag(ctx context.Context, tagID, userID uuid.UUID) (*domain.Tag, error) {
+	ctx, span := tracing.Start(ctx, "TagService.GetTag")
+	defer span.End()
+
+	dbTag, err := s.repo.GetByID(ctx, database.GetTagParams{
+		ID:     tagID,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, notFound(err, domain.ErrTagNotFound)
+	}
+
+	return domain.MapTagFromDB(dbTag), nil
+}
+
+func (s *tagService) RenameTag(ctx context.Context, tagID, userID uuid.UUID, name string) (*domain.Tag, error) {
+	ctx, span := tracing.Start(ctx, "TagService.RenameTag")
+	defer span.End()
+
+	tag := domain.NewTag(name, userID)
+	if err := tag.Validate(); err != nil {
+		return nil, err
+	}
+
+	dbTag, err := s.repo.Rename(ctx, database.RenameTagParams{
+		ID:     tagID,
+		UserID: userID,
+		Name:   tag.Name,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, domain.ErrDuplicateTag
+		}
+		return nil, domain.ErrTagNotFound
+	}
+
+	return domain.MapTagFromDB(dbTag), nil
+}
+
+func (s *tagService) DeleteTag(ctx context.Context, tagID, userID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "TagService.DeleteTag")
+	defer span.End()
+
+	deleted, err := s.repo.Delete(ctx, database.DeleteTagParams{
+		ID:     tagID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return domain.ErrTagNotFound
+	}
+
+	return nil
+}
+
+func (s *tagService) TagPost(ctx context.Context, tagID, postID, userID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "TagService.TagPost")
+	defer span.End()
+
+	if _, err := s.GetTag(ctx, tagID, userID); err != nil {
+		return err
+	}
+
+	_, err := s.postRepo.GetForUserByID(ctx, database.GetPostForUserParams{
+		ID:     postID,
+		UserID: userID,
+	})
+	if err != nil {
+		return notFound(err, domain.ErrPostNotFound)
+	}
+
+	return s.repo.AddToPost(ctx, database.AddPostTagParams{
+		TagID:  tagID,
+		PostID: postID,
+	})
+}
+
+func (s *tagService) UntagPost(ctx context.Context, tagID, postID, userID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "TagService.UntagPost")
+	defer span.End()
+
+	if _, err := s.GetTag(ctx, tagID, userID); err != nil {
+		return err
+	}
+
+	removed, err := s.repo.RemoveFromPost(ctx, database.RemovePostTagParams{
+		TagID:  tagID,
+		PostID: postID,
+	})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return domain.ErrPostNotFound
+	}
+
+	return nil
+}
@@ -2,56 +2,96 @@ package service
 
 import (
 	"context"
+	gosql "database/sql"
+	"log/slog"
 
+	"github.com/hel1th/rssagg/internal/auth"
 	"github.com/hel1th/rssagg/internal/database"
 	"github.com/hel1th/rssagg/internal/domain"
 	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
 )
 
 type UserService interface {
-	CreateUser(ctx context.Context, name string) (*domain.User, error)
-	GetUserByAPIKey(ctx context.Context, apiKey string) (*domain.User, error)
+	CreateUser(ctx context.Context, registration domain.UserRegistration) (*domain.User, error)
 }
 
 type userService struct {
-	repo repository.UserRepository
+	repo    repository.UserRepository
+	apiKeys APIKeyService
+	audit   AuditService
 }
 
-func NewUserService(repo repository.UserRepository) UserService {
+func NewUserService(repo repository.UserRepository, apiKeys APIKeyService, audit AuditService) UserService {
 	return &userService{
-		repo: repo,
+		repo:    repo,
+		apiKeys: apiKeys,
+		audit:   audit,
 	}
 }
 
-func (s *userService) CreateUser(ctx context.Context, name string) (*domain.User, error) {
-	user := domain.NewUser(name)
+// CreateUser creates a user along with a default admin scoped API key,
+// returned in APIKey. A registration with a password can also log in with
+// its username.
+func (s *userService) CreateUser(ctx context.Context, registration domain.UserRegistration) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.CreateUser")
+	defer span.End()
+
+	registration.Normalize()
+	user := domain.NewUser(registration.Name)
 	
 	if err := user.Validate(); err != nil {
 		return nil, err
 	}
+	if err := registration.Validate(); err != nil {
+		return nil, err
+	}
 	
-	dbUser, err := s.repo.Create(ctx, database.CreateUserParams{
+	params := database.CreateUserParams{
 		ID:        user.ID,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
 		Name:      user.Name,
-	})
+		Username:  nullString(registration.Username),
+		Email:     nullString(registration.Email),
+	}
+	if registration.Password != "" {
+		hash, err := auth.HashPassword(registration.Password)
+		if err != nil {
+			return nil, err
+		}
+		params.PasswordHash = gosql.NullString{String: hash, Valid: true}
+		params.PasswordChangedAt = gosql.NullTime{Time: user.CreatedAt, Valid: true}
+	}
+	
+	dbUser, err := s.repo.Create(ctx, params)
 	if err != nil {
+		if isUniqueViolationOn(err, "users_username_idx") {
+			return nil, domain.ErrDuplicateUsername
+		}
+		if isUniqueViolationOn(err, "users_email_idx") {
+			return nil, domain.ErrDuplicateEmail
+		}
 		return nil, err
 	}
 	
-	return domain.MapUserFromDB(dbUser), nil
-}
-
-func (s *userService) GetUserByAPIKey(ctx context.Context, apiKey string) (*domain.User, error) {
-	if apiKey == "" {
-		return nil, domain.ErrInvalidAPIKey
-	}
+	created := domain.MapUserFromDB(dbUser)
 	
-	dbUser, err := s.repo.GetByAPIKey(ctx, apiKey)
+	// The user only exists together with its default key, so it is deleted
+	// again if the key can't be created.
+	apiKey, err := s.apiKeys.CreateAPIKey(ctx, created, domain.DefaultAPIKeyName, domain.APIKeyScopeAdmin, nil)
 	if err != nil {
-		return nil, domain.ErrUserNotFound
+		if deleteErr := s.repo.Delete(ctx, created.ID); deleteErr != nil {
+			slog.ErrorContext(ctx, "Failed to delete user without an API key", "user_id", created.ID, "error", deleteErr)
+		}
+		return nil, err
 	}
+	created.APIKey = apiKey.Key
+	s.audit.Record(ctx, domain.NewAuditEntry(nil, &created.ID, domain.AuditUserCreate, domain.AuditTargetUser, &created.ID, nil, domain.AuditUser(created)))
 	
-	return domain.MapUserFromDB(dbUser), nil
-}
\ No newline at end of file
+	return created, nil
+}
+
+func nullString(s string) gosql.NullString {
+	return gosql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+)
+
+func TestCreateUserCreatesDefaultAPIKey(t *testing.T) {
+	users := &fakeUserRepository{users: make(map[uuid.UUID]database.User)}
+	apiKeys := &fakeAPIKeyRepository{}
+	s := NewUserService(users, NewAPIKeyService(apiKeys, fakeAuditService{}), fakeAuditService{})
+
+	user, err := s.CreateUser(context.Background(), domain.UserRegistration{Name: "Jane"})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if user.APIKey == "" {
+		t.Error("CreateUser() returned no API key")
+	}
+	if len(apiKeys.keys) != 1 || apiKeys.keys[0].UserID != user.ID {
+		t.Errorf("CreateUser() stored keys %v, want one for user %s", apiKeys.keys, user.ID)
+	}
+}
+
+func TestCreateUserRemovesUserWhenAPIKeyFails(t *testing.T) {
+	users := &fakeUserRepository{users: make(map[uuid.UUID]database.User)}
+	keyErr := errors.New("connection reset")
+	apiKeys := &fakeAPIKeyRepository{createErr: keyErr}
+	s := NewUserService(users, NewAPIKeyService(apiKeys, fakeAuditService{}), fakeAuditService{})
+
+	_, err := s.CreateUser(context.Background(), domain.UserRegistration{Name: "Jane"})
+	if !errors.Is(err, keyErr) {
+		t.Fatalf("CreateUser() error = %v, want %v", err, keyErr)
+	}
+	if len(users.users) != 0 {
+		t.Errorf("CreateUser() left %d users behind without an API key", len(users.users))
+	}
+}
@@ -0,0 +1,491 @@
+package service
+
+import (
+	"context"
+	gosql "database/sql"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/auth"
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
+	"github.com/hel1th/rssagg/internal/webhook"
+)
+
+const (
+	// webhookMaxAttempts is how many times a delivery is tried before it is
+	// marked failed and left for a manual redelivery.
+	webhookMaxAttempts = 8
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+
+	// webhookLease keeps a claimed delivery away from other workers while it
+	// is being sent.
+	webhookLease = 2 * time.Minute
+)
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, hook *domain.Webhook) (*domain.Webhook, error)
+	GetUserWebhooks(ctx context.Context, userID uuid.UUID) ([]*domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, webhookID, userID uuid.UUID) error
+	GetDeliveries(ctx context.Context, webhookID, userID uuid.UUID, limit, offset int) ([]*domain.WebhookDelivery, error)
+	Redeliver(ctx context.Context, deliveryID, userID uuid.UUID) error
+	EnqueueNewPosts(ctx context.Context, feed domain.Feed, posts []*domain.Post) error
+	DeliverDue(ctx context.Context, batchSize int) (int, error)
+	QueueDueDigests(ctx context.Context, interval time.Duration, batchSize int) (int, error)
+}
+
+type webhookService struct {
+	repo       repository.WebhookRepository
+	feedRepo   repository.FeedRepository
+	folderRepo repository.FolderRepository
+	sender     webhook.Sender
+}
+
+func NewWebhookService(
+	repo repository.WebhookRepository,
+	feedRepo repository.FeedRepository,
+	folderRepo repository.FolderRepository,
+	sender webhook.Sender,
+) WebhookService {
+	return &webhookService{
+		repo:       repo,
+		feedRepo:   feedRepo,
+		folderRepo: folderRepo,
+		sender:     sender,
+	}
+}
+
+type webhookPayload struct {
+	Event     string             `json:"event"`
+	WebhookID uuid.UUID          `json:"webhook_id"`
+	CreatedAt time.Time          `json:"created_at"`
+	Feed      webhookFeedPayload `json:"feed"`
+	Post      webhookPostPayload `json:"post"`
+}
+
+type webhookDigestPayload struct {
+	Event     string                     `json:"event"`
+	WebhookID uuid.UUID                  `json:"webhook_id"`
+	CreatedAt time.Time                  `json:"created_at"`
+	Posts     []webhookDigestPostPayload `json:"posts"`
+}
+
+type webhookDigestPostPayload struct {
+	Feed webhookFeedPayload `json:"feed"`
+	Post webhookPostPayload `json:"post"`
+}
+
+type webhookFeedPayload struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	URL  string    `json:"url"`
+}
+
+type webhookPostPayload struct {
+	ID          uuid.UUID `json:"id"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Description *string   `json:"description"`
+	Author      *string   `json:"author"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+func (s *webhookService) CreateWebhook(ctx context.Context, hook *domain.Webhook) (*domain.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.CreateWebhook")
+	defer span.End()
+
+	if err := hook.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.sender.CheckURL(hook.URL); err != nil {
+		return nil, domain.ErrWebhookURLNotPublic
+	}
+
+	if hook.FeedID != nil {
+		if _, err := s.feedRepo.GetByID(ctx, *hook.FeedID); err != nil {
+			return nil, notFound(err, domain.ErrFeedNotFound)
+		}
+	}
+	if hook.FolderID != nil {
+		_, err := s.folderRepo.GetByID(ctx, database.GetFolderParams{
+			ID:     *hook.FolderID,
+			UserID: hook.UserID,
+		})
+		if err != nil {
+			return nil, notFound(err, domain.ErrFolderNotFound)
+		}
+	}
+
+	secret, err := auth.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	keyword := gosql.NullString{}
+	if hook.Keyword != nil {
+		keyword = gosql.NullString{String: *hook.Keyword, Valid: true}
+	}
+
+	dbWebhook, err := s.repo.Create(ctx, database.CreateWebhookParams{
+		ID:        hook.ID,
+		CreatedAt: hook.CreatedAt,
+		UpdatedAt: hook.UpdatedAt,
+		UserID:    hook.UserID,
+		Name:      hook.Name,
+		Url:       hook.URL,
+		Secret:    secret,
+		FeedID:    nullUUID(hook.FeedID),
+		FolderID:  nullUUID(hook.FolderID),
+		Keyword:   keyword,
+		Enabled:   hook.Enabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.MapWebhookFromDB(dbWebhook), nil
+}
+
+func (s *webhookService) GetUserWebhooks(ctx context.Context, userID uuid.UUID) ([]*domain.Webhook, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetUserWebhooks")
+	defer span.End()
+
+	if userID == uuid.Nil {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	dbWebhooks, err := s.repo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.MapWebhooksFromDB(dbWebhooks), nil
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, webhookID, userID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeleteWebhook")
+	defer span.End()
+
+	deleted, err := s.repo.Delete(ctx, database.DeleteWebhookParams{
+		ID:     webhookID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return domain.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (s *webhookService) GetDeliveries(ctx context.Context, webhookID, userID uuid.UUID, limit, offset int) ([]*domain.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetDeliveries")
+	defer span.End()
+
+	_, err := s.repo.GetByID(ctx, database.GetWebhookParams{
+		ID:     webhookID,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, notFound(err, domain.ErrWebhookNotFound)
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	dbDeliveries, err := s.repo.GetDeliveries(ctx, database.GetWebhookDeliveriesParams{
+		WebhookID:  webhookID,
+		UserID:     userID,
+		PageLimit:  int32(limit),
+		PageOffset: int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.MapWebhookDeliveriesFromDB(dbDeliveries), nil
+}
+
+// Redeliver puts a delivery back in the queue with a fresh attempt budget,
+// whatever its current status.
+func (s *webhookService) Redeliver(ctx context.Context, deliveryID, userID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.Redeliver")
+	defer span.End()
+
+	requeued, err := s.repo.RequeueDelivery(ctx, database.RequeueWebhookDeliveryParams{
+		ID:     deliveryID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if requeued == 0 {
+		return domain.ErrWebhookDeliveryNotFound
+	}
+
+	return nil
+}
+
+// EnqueueNewPosts queues a post.created delivery for every enabled webhook
+// whose feed, folder and keyword filters match a freshly ingested post, or
+// holds the post for the webhook's next digest, as the owner's follow of the
+// feed asks. Follows with notifications off get nothing.
+func (s *webhookService) EnqueueNewPosts(ctx context.Context, feed domain.Feed, posts []*domain.Post) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.EnqueueNewPosts")
+	defer span.End()
+
+	if len(posts) == 0 {
+		return nil
+	}
+
+	rows, err := s.repo.GetForFeed(ctx, feed.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, row := range rows {
+		hook := domain.MapWebhookFromDB(row.Webhook)
+		for _, post := range posts {
+			if !hook.Matches(post) {
+				continue
+			}
+
+			if row.NotificationMode == domain.NotificationModeDigest {
+				err := s.repo.AddDigestPost(ctx, database.AddWebhookDigestPostParams{
+					WebhookID: hook.ID,
+					PostID:    post.ID,
+					CreatedAt: now,
+				})
+				if err != nil {
+					slog.ErrorContext(ctx, "Error adding post to webhook digest", "webhook_id", hook.ID, "post_id", post.ID, "error", err)
+				}
+				continue
+			}
+
+			payload, err := json.Marshal(webhookPayload{
+				Event:     domain.WebhookEventPostCreated,
+				WebhookID: hook.ID,
+				CreatedAt: now,
+				Feed:      newWebhookFeedPayload(feed),
+				Post:      newWebhookPostPayload(post),
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = s.repo.CreateDelivery(ctx, database.CreateWebhookDeliveryParams{
+				ID:            uuid.New(),
+				CreatedAt:     now,
+				UpdatedAt:     now,
+				WebhookID:     hook.ID,
+				PostID:        uuid.NullUUID{UUID: post.ID, Valid: true},
+				Event:         domain.WebhookEventPostCreated,
+				Payload:       string(payload),
+				NextAttemptAt: now,
+			})
+			if err != nil {
+				slog.ErrorContext(ctx, "Error queueing webhook", "webhook_id", hook.ID, "post_id", post.ID, "error", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// QueueDueDigests queues a posts.digest delivery for up to batchSize
+// webhooks whose oldest waiting post has waited interval. It returns the
+// number of webhooks looked at.
+func (s *webhookService) QueueDueDigests(ctx context.Context, interval time.Duration, batchSize int) (int, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.QueueDueDigests")
+	defer span.End()
+
+	webhookIDs, err := s.repo.GetDueDigests(ctx, database.GetDueWebhookDigestsParams{
+		DueBefore: time.Now().UTC().Add(-interval),
+		BatchSize: int32(batchSize),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, webhookID := range webhookIDs {
+		if err := s.queueDigest(ctx, webhookID); err != nil {
+			slog.ErrorContext(ctx, "Error queueing webhook digest", "webhook_id", webhookID, "error", err)
+		}
+	}
+
+	return len(webhookIDs), nil
+}
+
+func (s *webhookService) queueDigest(ctx context.Context, webhookID uuid.UUID) error {
+	dbWebhook, err := s.repo.Get(ctx, webhookID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := s.repo.ClaimDigestPosts(ctx, webhookID)
+	if err != nil {
+		return err
+	}
+	// Posts held for a webhook that was disabled since are dropped, as
+	// instant deliveries would have been.
+	if len(rows) == 0 || !dbWebhook.Enabled {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	digest := webhookDigestPayload{
+		Event:     domain.WebhookEventPostsDigest,
+		WebhookID: webhookID,
+		CreatedAt: now,
+		Posts:     make([]webhookDigestPostPayload, len(rows)),
+	}
+	for i, row := range rows {
+		digest.Posts[i] = webhookDigestPostPayload{
+			Feed: newWebhookFeedPayload(*domain.MapFeedFromDB(row.Feed)),
+			Post: newWebhookPostPayload(domain.MapPostFromDB(row.Post)),
+		}
+	}
+
+	payload, err := json.Marshal(digest)
+	if err == nil {
+		_, err = s.repo.CreateDelivery(ctx, database.CreateWebhookDeliveryParams{
+			ID:            uuid.New(),
+			CreatedAt:     now,
+			UpdatedAt:     now,
+			WebhookID:     webhookID,
+			Event:         domain.WebhookEventPostsDigest,
+			Payload:       string(payload),
+			NextAttemptAt: now,
+		})
+	}
+	if err != nil {
+		// Put the posts back for the next try.
+		for _, row := range rows {
+			putBack := s.repo.AddDigestPost(ctx, database.AddWebhookDigestPostParams{
+				WebhookID: webhookID,
+				PostID:    row.Post.ID,
+				CreatedAt: row.QueuedAt,
+			})
+			if putBack != nil {
+				slog.ErrorContext(ctx, "Error returning post to webhook digest", "webhook_id", webhookID, "post_id", row.Post.ID, "error", putBack)
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// DeliverDue sends up to batchSize deliveries whose next attempt is due and
+// records the outcome of each, scheduling retries with exponential backoff.
+// It returns the number of deliveries attempted.
+func (s *webhookService) DeliverDue(ctx context.Context, batchSize int) (int, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeliverDue")
+	defer span.End()
+
+	dbDeliveries, err := s.repo.ClaimDueDeliveries(ctx, database.ClaimDueWebhookDeliveriesParams{
+		LeaseSeconds: int32(webhookLease / time.Second),
+		BatchSize:    int32(batchSize),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range domain.MapWebhookDeliveriesFromDB(dbDeliveries) {
+		s.deliver(ctx, delivery)
+	}
+
+	return len(dbDeliveries), nil
+}
+
+func (s *webhookService) deliver(ctx context.Context, delivery *domain.WebhookDelivery) {
+	var (
+		status  int
+		sendErr error
+	)
+
+	dbWebhook, err := s.repo.Get(ctx, delivery.WebhookID)
+	if err != nil {
+		sendErr = err
+	} else {
+		status, sendErr = s.sender.Send(ctx, webhook.Delivery{
+			ID:      delivery.ID.String(),
+			Event:   delivery.Event,
+			URL:     dbWebhook.Url,
+			Secret:  dbWebhook.Secret,
+			Payload: []byte(delivery.Payload),
+		})
+	}
+
+	now := time.Now().UTC()
+	params := database.RecordWebhookDeliveryAttemptParams{
+		ID:            delivery.ID,
+		Status:        domain.WebhookDeliverySucceeded,
+		NextAttemptAt: now,
+		LastAttemptAt: gosql.NullTime{Time: now, Valid: true},
+	}
+	if status != 0 {
+		params.ResponseStatus = gosql.NullInt32{Int32: int32(status), Valid: true}
+	}
+	if sendErr != nil {
+		attempts := delivery.Attempts + 1
+		params.LastError = gosql.NullString{String: sendErr.Error(), Valid: true}
+		if attempts >= webhookMaxAttempts {
+			params.Status = domain.WebhookDeliveryFailed
+		} else {
+			params.Status = domain.WebhookDeliveryPending
+			params.NextAttemptAt = now.Add(webhookBackoff(attempts))
+		}
+	}
+
+	if err := s.repo.RecordAttempt(ctx, params); err != nil {
+		slog.ErrorContext(ctx, "Error recording webhook delivery", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+func newWebhookFeedPayload(feed domain.Feed) webhookFeedPayload {
+	return webhookFeedPayload{
+		ID:   feed.ID,
+		Name: feed.Name,
+		URL:  feed.URL,
+	}
+}
+
+func newWebhookPostPayload(post *domain.Post) webhookPostPayload {
+	return webhookPostPayload{
+		ID:          post.ID,
+		Title:       post.Title,
+		URL:         post.URL,
+		Description: post.Description,
+		Author:      post.Author,
+		PublishedAt: post.PublishedAt,
+	}
+}
+
+// webhookBackoff returns the delay before retrying after the given number of
+// failed attempts: 30s, 1m, 2m, ... capped at six hours.
+func webhookBackoff(attempts int) time.Duration {
+	delay := webhookBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	gosql "database/sql"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hel1th/rssagg/internal/auth"
+	"github.com/hel1th/rssagg/internal/database"
+	"github.com/hel1th/rssagg/internal/domain"
+	"github.com/hel1th/rssagg/internal/repository"
+	"github.com/hel1th/rssagg/internal/tracing"
+	"github.com/hel1th/rssagg/internal/websub"
+)
+
+const (
+	// websubLeaseSeconds is the lease requested from hubs; they may grant
+	// a different one.
+	websubLeaseSeconds = 7 * 24 * 60 * 60
+	// websubRetryInterval spaces out repeated requests for a subscription
+	// that hasn't been verified or renewed yet.
+	websubRetryInterval = time.Hour
+)
+
+// WebSubService subscribes to the hubs feeds advertise so that new content
+// is pushed to the callback instead of being polled for.
+type WebSubService interface {
+	Discover(ctx context.Context, feed domain.Feed, hubURL, selfURL string) error
+	VerifyIntent(ctx context.Context, subscriptionID uuid.UUID, verification domain.WebSubVerification) (string, error)
+	VerifyContent(ctx context.Context, subscriptionID uuid.UUID, signature string, body []byte) (*domain.Feed, error)
+	RenewSubscriptions(ctx context.Context, batchSize int) (int, error)
+}
+
+type webSubService struct {
+	repo        repository.WebSubRepository
+	feedRepo    repository.FeedRepository
+	subscriber  websub.Subscriber
+	callbackURL string
+}
+
+// NewWebSubService creates the service. callbackURL is the public base URL
+// of this API as hubs can reach it, e.g. https://rssagg.example.com.
+func NewWebSubService(
+	repo repository.WebSubRepository,
+	feedRepo repository.FeedRepository,
+	subscriber websub.Subscriber,
+	callbackURL string,
+) WebSubService {
+	return &webSubService{
+		repo:        repo,
+		feedRepo:    feedRepo,
+		subscriber:  subscriber,
+		callbackURL: strings.TrimRight(callbackURL, "/"),
+	}
+}
+
+// Discover subscribes to the hub a fetched feed advertises, unless a
+// subscription for the same hub and topic is already active or in progress.
+func (s *webSubService) Discover(ctx context.Context, feed domain.Feed, hubURL, selfURL string) error {
+	ctx, span := tracing.Start(ctx, "WebSubService.Discover")
+	defer span.End()
+
+	if hubURL == "" || !isHTTPURL(hubURL) {
+		return nil
+	}
+
+	topicURL := selfURL
+	if topicURL == "" || !isHTTPURL(topicURL) {
+		topicURL = feed.URL
+	}
+
+	dbSub, err := s.repo.GetByFeed(ctx, feed.ID)
+	if err == nil && dbSub.HubUrl == hubURL && dbSub.TopicUrl == topicURL {
+		// Renewals and retries are left to RenewSubscriptions, and a hub
+		// that denied us isn't asked again for the same topic.
+		return nil
+	}
+	if err != nil && !errors.Is(err, gosql.ErrNoRows) {
+		return err
+	}
+
+	secret, err := auth.GenerateToken()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	dbSub, err = s.repo.Upsert(ctx, database.UpsertWebSubSubscriptionParams{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+		FeedID:    feed.ID,
+		HubUrl:    hubURL,
+		TopicUrl:  topicURL,
+		Secret:    secret,
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "Subscribing to WebSub hub", "hub_url", hubURL, "feed_id", feed.ID, "feed", feed.Name)
+	return s.subscribe(ctx, domain.MapWebSubSubscriptionFromDB(dbSub))
+}
+
+// VerifyIntent answers a hub's verification request and returns the
+// challenge to echo back. Requests that don't match a subscription we want
+// are refused with ErrWebSubSubscriptionNotFound or ErrWebSubTopicMismatch.
+func (s *webSubService) VerifyIntent(ctx context.Context, subscriptionID uuid.UUID, verification domain.WebSubVerification) (string, error) {
+	ctx, span := tracing.Start(ctx, "WebSubService.VerifyIntent")
+	defer span.End()
+
+	dbSub, err := s.repo.GetByID(ctx, subscriptionID)
+	if err != nil {
+		return "", notFound(err, domain.ErrWebSubSubscriptionNotFound)
+	}
+	sub := domain.MapWebSubSubscriptionFromDB(dbSub)
+
+	if verification.Topic != sub.TopicURL {
+		return "", domain.ErrWebSubTopicMismatch
+	}
+
+	switch verification.Mode {
+	case domain.WebSubModeSubscribe:
+		if sub.State == domain.WebSubStateDenied || verification.Challenge == "" {
+			return "", domain.ErrInvalidWebSubMode
+		}
+
+		lease := verification.LeaseSeconds
+		if lease <= 0 {
+			lease = websubLeaseSeconds
+		}
+		err := s.repo.Activate(ctx, database.ActivateWebSubSubscriptionParams{
+			ID:           sub.ID,
+			LeaseSeconds: gosql.NullInt32{Int32: int32(lease), Valid: true},
+			ExpiresAt:    gosql.NullTime{Time: time.Now().UTC().Add(time.Duration(lease) * time.Second), Valid: true},
+		})
+		if err != nil {
+			return "", err
+		}
+		return verification.Challenge, nil
+
+	case domain.WebSubModeDenied:
+		reason := verification.Reason
+		if reason == "" {
+			reason = "denied by hub"
+		}
+		err := s.repo.Deny(ctx, database.DenyWebSubSubscriptionParams{
+			ID:        sub.ID,
+			LastError: gosql.NullString{String: reason, Valid: true},
+		})
+		return "", err
+
+	default:
+		// We never unsubscribe while the feed exists; a stored subscription
+		// always means we still want it.
+		return "", domain.ErrInvalidWebSubMode
+	}
+}
+
+// VerifyContent checks that pushed content comes from the subscription's hub
+// and returns the feed it belongs to.
+func (s *webSubService) VerifyContent(ctx context.Context, subscriptionID uuid.UUID, signature string, body []byte) (*domain.Feed, error) {
+	ctx, span := tracing.Start(ctx, "WebSubService.VerifyContent")
+	defer span.End()
+
+	dbSub, err := s.repo.GetByID(ctx, subscriptionID)
+	if err != nil {
+		return nil, notFound(err, domain.ErrWebSubSubscriptionNotFound)
+	}
+
+	if !websub.VerifySignature(dbSub.Secret, signature, body) {
+		return nil, domain.ErrInvalidWebSubSignature
+	}
+
+	dbFeed, err := s.feedRepo.GetByID(ctx, dbSub.FeedID)
+	if err != nil {
+		return nil, notFound(err, domain.ErrFeedNotFound)
+	}
+
+	return domain.MapFeedFromDB(dbFeed), nil
+}
+
+// RenewSubscriptions re-sends subscription requests for leases about to
+// expire and for requests the hub never verified. It returns the number of
+// requests sent.
+func (s *webSubService) RenewSubscriptions(ctx context.Context, batchSize int) (int, error) {
+	ctx, span := tracing.Start(ctx, "WebSubService.RenewSubscriptions")
+	defer span.End()
+
+	dbSubs, err := s.repo.GetToRenew(ctx, database.GetWebSubSubscriptionsToRenewParams{
+		RetryBefore: time.Now().UTC().Add(-websubRetryInterval),
+		BatchSize:   int32(batchSize),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, dbSub := range dbSubs {
+		if err := s.subscribe(ctx, domain.MapWebSubSubscriptionFromDB(dbSub)); err != nil {
+			slog.WarnContext(ctx, "Error renewing WebSub subscription", "subscription_id", dbSub.ID, "error", err)
+		}
+	}
+
+	return len(dbSubs), nil
+}
+
+func (s *webSubService) subscribe(ctx context.Context, sub *domain.WebSubSubscription) error {
+	callback := s.callbackURL + "/v1/websub/callback?" + url.Values{"id": {sub.ID.String()}}.Encode()
+
+	err := s.subscriber.Subscribe(ctx, websub.SubscriptionRequest{
+		HubURL:       sub.HubURL,
+		TopicURL:     sub.TopicURL,
+		CallbackURL:  callback,
+		Secret:       sub.Secret,
+		LeaseSeconds: websubLeaseSeconds,
+	})
+
+	lastError := gosql.NullString{}
+	if err != nil {
+		lastError = gosql.NullString{String: err.Error(), Valid: true}
+	}
+	if recordErr := s.repo.RecordRequest(ctx, database.RecordWebSubRequestParams{
+		ID:        sub.ID,
+		LastError: lastError,
+	}); recordErr != nil {
+		slog.ErrorContext(ctx, "Error recording WebSub request", "subscription_id", sub.ID, "error", recordErr)
+	}
+
+	return err
+}
+
+func isHTTPURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
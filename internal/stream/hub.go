@@ -0,0 +1,92 @@
+package stream
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// NewPostsChannel is the Postgres NOTIFY channel new posts are announced on
+// once the scraper has run the feed's filter rules on them.
+const NewPostsChannel = "new_posts"
+
+// Hub fans Postgres notifications about new posts out to the stream
+// subscribers of this API instance. Every instance runs its own Hub, so a
+// post inserted by any scraper reaches clients connected to any instance.
+//
+// Subscribers are only woken up; they read the posts themselves, which keeps
+// per-user visibility rules in one query and lets a burst of inserts
+// collapse into a single read.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber and returns its wake-up channel together
+// with a function that unregisters it.
+func (h *Hub) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subscribers, ch)
+		h.mu.Unlock()
+	}
+}
+
+// Publish wakes every subscriber without blocking on slow ones; a
+// subscriber that hasn't consumed its previous wake-up keeps just one.
+func (h *Hub) Publish() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Listen relays notifications from Postgres until ctx is cancelled. The
+// listener reconnects on its own; subscribers are woken after a reconnect
+// too, since notifications sent while disconnected are lost.
+func (h *Hub) Listen(ctx context.Context, dbURL string) error {
+	listener := pq.NewListener(dbURL, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.WarnContext(ctx, "Post stream listener error", "error", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(NewPostsChannel); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "Listening for new posts", "channel", NewPostsChannel)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-listener.Notify:
+			h.Publish()
+		case <-time.After(90 * time.Second):
+			if err := listener.Ping(); err != nil {
+				slog.WarnContext(ctx, "Post stream listener ping failed", "error", err)
+			}
+		}
+	}
+}
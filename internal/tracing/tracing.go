@@ -0,0 +1,102 @@
+// Package tracing sets up OpenTelemetry tracing and starts spans.
+//
+// Spans are exported over OTLP/HTTP with ExporterOTLP, configured by the
+// standard OTEL_EXPORTER_OTLP_* and OTEL_TRACES_SAMPLER variables. Without an exporter spans cost next to nothing, but incoming
+// W3C trace context is still passed on.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName names us in traces unless OTEL_SERVICE_NAME says otherwise.
+const ServiceName = "rssagg"
+
+// Exporters Setup takes
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp"
+)
+
+const tracerName = "github.com/hel1th/rssagg"
+
+// Setup installs the W3C trace context propagator and, for ExporterOTLP,
+// a tracer provider exporting to endpoint, or to the one the environment
+// names if empty. The returned function flushes spans not yet exported.
+func Setup(ctx context.Context, exporter, endpoint string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	switch exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		var options []otlptracehttp.Option
+		if endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpointURL(endpoint))
+		}
+		spanExporter, err := otlptracehttp.New(ctx, options...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		return Install(spanExporter).Shutdown, nil
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q, expected otlp or none", exporter)
+	}
+}
+
+// Install makes spans go to exporter in batches, such as an in-memory
+// exporter from go.opentelemetry.io/otel/sdk/trace/tracetest in tests,
+// which can ForceFlush the returned provider before looking at them.
+func Install(exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	// Later detectors win, so OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES
+	// override our name
+	res, err := resource.New(context.Background(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(ServiceName)),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		res = resource.Default()
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider
+}
+
+// Start starts a span named name as a child of any span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartKind starts a span of the given kind, such as trace.SpanKindClient
+// for requests to other services.
+func StartKind(ctx context.Context, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// End ends span, marking it failed if err is not nil. Canceled contexts
+// are the caller going away and aren't failures.
+func End(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, context.Canceled) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
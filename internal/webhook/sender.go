@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	HeaderEvent     = "X-Rssagg-Event"
+	HeaderDelivery  = "X-Rssagg-Delivery"
+	HeaderTimestamp = "X-Rssagg-Timestamp"
+	HeaderSignature = "X-Rssagg-Signature-256"
+)
+
+// ErrPrivateAddress is returned for webhook URLs and connections to
+// loopback, private, link-local and other addresses that aren't public,
+// unless the sender allows them.
+var ErrPrivateAddress = errors.New("webhook address is not public")
+
+// nonPublicPrefixes are ranges that aren't public but that netip doesn't
+// classify as private, loopback or link-local.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+// Delivery is a single signed POST of a webhook payload.
+type Delivery struct {
+	ID      string
+	Event   string
+	URL     string
+	Secret  string
+	Payload []byte
+}
+
+type Sender interface {
+	// Send posts the delivery and returns the response status code. A
+	// non-2xx status is reported as an error together with the code.
+	Send(ctx context.Context, delivery Delivery) (int, error)
+	// CheckURL returns ErrPrivateAddress if rawURL's host is an address
+	// Send would refuse. Host names are not resolved: Send checks the
+	// address it dials.
+	CheckURL(rawURL string) error
+}
+
+type httpSender struct {
+	client       http.Client
+	allowPrivate bool
+}
+
+// NewSender returns a sender that refuses to connect to addresses that
+// aren't public, unless allowPrivate is set.
+func NewSender(allowPrivate bool) Sender {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !allowPrivate {
+		// Through a proxy, the proxy's address is the only one dialed.
+		transport.Proxy = nil
+		transport.DialContext = (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   refusePrivate,
+		}).DialContext
+	}
+
+	return &httpSender{
+		client:       http.Client{Timeout: 10 * time.Second, Transport: transport},
+		allowPrivate: allowPrivate,
+	}
+}
+
+func (s *httpSender) CheckURL(rawURL string) error {
+	if s.allowPrivate {
+		return nil
+	}
+
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	host := strings.ToLower(parsedURL.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrPrivateAddress
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && !IsPublic(ip) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// refusePrivate is a net.Dialer Control function that fails connections
+// to addresses that aren't public. It sees the address after DNS
+// resolution, so a host name can't be pointed at an internal address
+// once its webhook has been registered.
+func refusePrivate(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !IsPublic(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, ip)
+	}
+	return nil
+}
+
+// IsPublic reports whether ip is a public unicast address, as opposed to
+// loopback, private (RFC 1918 and IPv6 unique local), link-local (which
+// includes cloud metadata endpoints such as 169.254.169.254), multicast,
+// unspecified or otherwise reserved.
+func IsPublic(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *httpSender) Send(ctx context.Context, delivery Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "rssagg-webhooks")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook endpoint responded with %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature header value for a payload: the hex HMAC-SHA256
+// of "<timestamp>.<payload>" keyed by the webhook secret, prefixed "sha256=".
+// Receivers should recompute it and compare in constant time.
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
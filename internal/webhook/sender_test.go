@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "93.184.216.34", want: true},
+		{addr: "2606:4700::1111", want: true},
+		{addr: "127.0.0.1", want: false},
+		{addr: "::1", want: false},
+		{addr: "10.1.2.3", want: false},
+		{addr: "172.16.0.1", want: false},
+		{addr: "192.168.1.1", want: false},
+		{addr: "169.254.169.254", want: false},
+		{addr: "fe80::1", want: false},
+		{addr: "fd00::1", want: false},
+		{addr: "0.0.0.0", want: false},
+		{addr: "100.64.0.1", want: false},
+		{addr: "::ffff:127.0.0.1", want: false},
+	}
+	for _, tt := range tests {
+		if got := IsPublic(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("IsPublic(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	sender := NewSender(false)
+	for _, rawURL := range []string{
+		"http://localhost:8080/hook",
+		"http://127.0.0.1/hook",
+		"http://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"https://10.0.0.5/hook",
+	} {
+		if err := sender.CheckURL(rawURL); !errors.Is(err, ErrPrivateAddress) {
+			t.Errorf("CheckURL(%q) error = %v, want ErrPrivateAddress", rawURL, err)
+		}
+	}
+	if err := sender.CheckURL("https://hooks.example.com/rssagg"); err != nil {
+		t.Errorf("CheckURL() for a public host error = %v", err)
+	}
+
+	if err := NewSender(true).CheckURL("http://127.0.0.1/hook"); err != nil {
+		t.Errorf("CheckURL() with private targets allowed error = %v", err)
+	}
+}
+
+func TestSendRefusesPrivateAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	delivery := Delivery{ID: "1", Event: "post.created", URL: server.URL, Secret: "secret", Payload: []byte("{}")}
+
+	if _, err := NewSender(false).Send(context.Background(), delivery); !errors.Is(err, ErrPrivateAddress) {
+		t.Fatalf("Send() to %s error = %v, want ErrPrivateAddress", server.URL, err)
+	}
+
+	status, err := NewSender(true).Send(context.Background(), delivery)
+	if err != nil {
+		t.Fatalf("Send() with private targets allowed error = %v", err)
+	}
+	if status != http.StatusOK {
+		t.Errorf("Send() status = %d, want %d", status, http.StatusOK)
+	}
+}
@@ -0,0 +1,117 @@
+package websub
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries the hub's HMAC of pushed content.
+const SignatureHeader = "X-Hub-Signature"
+
+// SubscriptionRequest asks a hub to start or renew pushing a topic to a
+// callback.
+type SubscriptionRequest struct {
+	HubURL       string
+	TopicURL     string
+	CallbackURL  string
+	Secret       string
+	LeaseSeconds int
+}
+
+type Subscriber interface {
+	// Subscribe sends the subscription request. A nil error only means the
+	// hub accepted it; the subscription is confirmed once the hub verifies
+	// the intent at the callback.
+	Subscribe(ctx context.Context, req SubscriptionRequest) error
+}
+
+type httpSubscriber struct {
+	client *http.Client
+}
+
+// NewSubscriber returns a Subscriber sending requests with client, or with a
+// default client when client is nil.
+func NewSubscriber(client *http.Client) Subscriber {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &httpSubscriber{
+		client: client,
+	}
+}
+
+func (s *httpSubscriber) Subscribe(ctx context.Context, req SubscriptionRequest) error {
+	form := url.Values{
+		"hub.mode":     {"subscribe"},
+		"hub.topic":    {req.TopicURL},
+		"hub.callback": {req.CallbackURL},
+		"hub.secret":   {req.Secret},
+	}
+	if req.LeaseSeconds > 0 {
+		form.Set("hub.lease_seconds", strconv.Itoa(req.LeaseSeconds))
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.HubURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to build subscription request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to reach hub: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("hub rejected subscription with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// VerifySignature checks an X-Hub-Signature header ("method=hex") against
+// the HMAC of body keyed with secret. sha1, sha256, sha384 and sha512 are
+// accepted, as the WebSub recommendation allows.
+func VerifySignature(secret, header string, body []byte) bool {
+	method, signature, ok := strings.Cut(header, "=")
+	if !ok {
+		return false
+	}
+
+	var newHash func() hash.Hash
+	switch strings.ToLower(method) {
+	case "sha1":
+		newHash = sha1.New
+	case "sha256":
+		newHash = sha256.New
+	case "sha384":
+		newHash = sha512.New384
+	case "sha512":
+		newHash = sha512.New
+	default:
+		return false
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
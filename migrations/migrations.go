@@ -0,0 +1,10 @@
+// Package migrations embeds the goose migrations of the database schema,
+// so that the binary can apply them without the source tree.
+package migrations
+
+import "embed"
+
+// Schema holds the migrations under schema/, which sqlc also reads.
+//
+//go:embed schema/*.sql
+var Schema embed.FS
@@ -0,0 +1,53 @@
+-- name: SearchUsers :many
+SELECT users.*,
+       (SELECT COUNT(*) FROM feed_follows WHERE feed_follows.user_id = users.id) AS follow_count,
+       (SELECT COUNT(*) FROM feeds WHERE feeds.user_id = users.id) AS feed_count
+FROM users
+WHERE sqlc.arg(search)::text = ''
+   OR users.name ILIKE '%' || sqlc.arg(search)::text || '%' ESCAPE '\'
+   OR users.username ILIKE '%' || sqlc.arg(search)::text || '%' ESCAPE '\'
+   OR users.email ILIKE '%' || sqlc.arg(search)::text || '%' ESCAPE '\'
+ORDER BY users.created_at DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountSearchUsers :one
+SELECT COUNT(*) FROM users
+WHERE sqlc.arg(search)::text = ''
+   OR users.name ILIKE '%' || sqlc.arg(search)::text || '%' ESCAPE '\'
+   OR users.username ILIKE '%' || sqlc.arg(search)::text || '%' ESCAPE '\'
+   OR users.email ILIKE '%' || sqlc.arg(search)::text || '%' ESCAPE '\';
+
+-- name: SetUserDisabled :execrows
+UPDATE users
+SET disabled_at = CASE WHEN sqlc.arg(disabled)::boolean THEN NOW() END, updated_at = NOW()
+WHERE id = sqlc.arg(id);
+
+-- name: DeleteUser :execrows
+DELETE FROM users WHERE id = $1;
+
+-- name: HandOverSharedFeeds :execrows
+-- Feeds other users follow would go with their creator, so they are handed
+-- to new_owner_id first.
+UPDATE feeds
+SET user_id = sqlc.arg(new_owner_id), updated_at = NOW()
+WHERE feeds.user_id = sqlc.arg(user_id)
+  AND EXISTS (
+        SELECT 1 FROM feed_follows
+        WHERE feed_follows.feed_id = feeds.id
+          AND feed_follows.user_id <> sqlc.arg(user_id)
+  );
+
+-- name: SetFeedDisabled :execrows
+UPDATE feeds
+SET disabled_at = CASE WHEN sqlc.arg(disabled)::boolean THEN NOW() END, updated_at = NOW()
+WHERE id = sqlc.arg(id);
+
+-- name: SetFeedOwner :execrows
+UPDATE feeds
+SET user_id = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: PurgePosts :execrows
+DELETE FROM posts
+WHERE (sqlc.narg(feed_id)::uuid IS NULL OR feed_id = sqlc.narg(feed_id)::uuid)
+  AND (sqlc.narg(before)::timestamp IS NULL OR published_at < sqlc.narg(before)::timestamp);
@@ -0,0 +1,40 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys(id, created_at, updated_at, user_id, name, prefix, key_hash, client_hash, scope, expires_at)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetAPIKeysForUser :many
+SELECT * FROM api_keys WHERE user_id = $1 ORDER BY created_at;
+
+-- name: GetActiveAPIKeyByHash :one
+SELECT sqlc.embed(api_keys), sqlc.embed(users)
+FROM api_keys
+JOIN users ON users.id = api_keys.user_id
+WHERE api_keys.key_hash = $1
+  AND users.disabled_at IS NULL
+  AND api_keys.revoked_at IS NULL
+  AND (api_keys.expires_at IS NULL OR api_keys.expires_at > NOW());
+
+-- name: GetActiveAPIKeyByClientHash :one
+SELECT sqlc.embed(api_keys), sqlc.embed(users)
+FROM api_keys
+JOIN users ON users.id = api_keys.user_id
+WHERE api_keys.client_hash = $1
+  AND users.disabled_at IS NULL
+  AND api_keys.revoked_at IS NULL
+  AND (api_keys.expires_at IS NULL OR api_keys.expires_at > NOW())
+LIMIT 1;
+
+-- name: TouchAPIKey :exec
+-- Recording every request would write on every read, so last_used_at is
+-- only kept to the minute.
+UPDATE api_keys
+SET last_used_at = NOW()
+WHERE id = $1
+  AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute');
+
+-- name: RevokeAPIKey :one
+UPDATE api_keys
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+RETURNING *;
@@ -0,0 +1,19 @@
+-- name: CreateAuditEntry :exec
+INSERT INTO audit_log(id, created_at, actor_id, user_id, action, target_type, target_id, ip, user_agent, before, after)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
+
+-- name: GetAuditLog :many
+-- Newest first. A user's log is what they did and what was done to them.
+SELECT * FROM audit_log
+WHERE (sqlc.narg(user_id)::uuid IS NULL OR user_id = sqlc.narg(user_id) OR actor_id = sqlc.narg(user_id))
+  AND (sqlc.arg(action)::text = '' OR action = sqlc.arg(action))
+ORDER BY created_at DESC, id
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: ExportAuditLog :many
+-- Oldest first, so appends don't shift the pages of an export in progress.
+SELECT * FROM audit_log
+WHERE (sqlc.narg(user_id)::uuid IS NULL OR user_id = sqlc.narg(user_id) OR actor_id = sqlc.narg(user_id))
+  AND (sqlc.arg(action)::text = '' OR action = sqlc.arg(action))
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
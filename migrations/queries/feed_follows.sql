@@ -4,7 +4,31 @@ VALUES($1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: GetFeedFollows :many
-SELECT * FROM feed_follows WHERE user_id = $1;
+SELECT feed_follows.*, feeds.name AS feed_name
+FROM feed_follows
+JOIN feeds ON feeds.id = feed_follows.feed_id
+WHERE feed_follows.user_id = $1
+ORDER BY feed_follows.priority DESC, feed_follows.created_at;
+
+-- name: GetFeedFollow :one
+SELECT feed_follows.*, feeds.name AS feed_name
+FROM feed_follows
+JOIN feeds ON feeds.id = feed_follows.feed_id
+WHERE feed_follows.id = $1 AND feed_follows.user_id = $2;
+
+-- name: GetFeedFollowByFeed :one
+SELECT * FROM feed_follows
+WHERE feed_id = $1 AND user_id = $2;
+
+-- name: UpdateFeedFollowPreferences :exec
+UPDATE feed_follows
+SET title = $3,
+    muted = $4,
+    priority = $5,
+    notification_mode = $6,
+    folder_id = $7,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2;
 
 -- name: DeleteFeedFollow :exec
-DELETE FROM feed_follows WHERE id = $1 AND user_id = $2;
\ No newline at end of file
+DELETE FROM feed_follows WHERE id = $1 AND user_id = $2;
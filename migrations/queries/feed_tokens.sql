@@ -0,0 +1,19 @@
+-- name: CreateFeedToken :one
+INSERT INTO feed_tokens(id, created_at, user_id, name, token_hash)
+VALUES($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetFeedTokens :many
+SELECT * FROM feed_tokens WHERE user_id = $1 ORDER BY created_at;
+
+-- name: DeleteFeedToken :execrows
+DELETE FROM feed_tokens WHERE id = $1 AND user_id = $2;
+
+-- name: GetUserByFeedToken :one
+UPDATE feed_tokens
+SET last_used_at = NOW()
+FROM users
+WHERE feed_tokens.token_hash = $1
+  AND users.id = feed_tokens.user_id
+  AND users.disabled_at IS NULL
+RETURNING users.*;
@@ -3,19 +3,83 @@ INSERT INTO feeds(id, created_at, updated_at, name, url, user_id)
 VALUES($1, $2, $3, $4, $5, $6)
 RETURNING *;
 
--- name: GetFeeds :many
-SELECT * FROM feeds;
-
 -- name: GetFeedByID :one
 SELECT * FROM feeds WHERE id = $1;
 
+-- name: GetFeedByURL :one
+SELECT * FROM feeds WHERE url = $1;
+
 -- name: GetNextFeedsToFetch :many
-SELECT * FROM feeds
-ORDER BY last_fetched_at NULLS FIRST
+-- Feeds with a live WebSub lease get their content pushed, so they are only
+-- polled as a safety net every few hours.
+SELECT feeds.* FROM feeds
+LEFT JOIN websub_subscriptions
+  ON websub_subscriptions.feed_id = feeds.id
+ AND websub_subscriptions.state = 'active'
+ AND websub_subscriptions.expires_at > NOW()
+WHERE feeds.disabled_at IS NULL
+  AND (
+        websub_subscriptions.id IS NULL
+     OR feeds.last_fetched_at IS NULL
+     OR feeds.last_fetched_at < NOW() - INTERVAL '6 hours'
+  )
+ORDER BY feeds.last_fetched_at NULLS FIRST
 LIMIT $1;
 
 -- name: MarkFeedAsFetched :one
 UPDATE feeds
 SET last_fetched_at = NOW(), updated_at = NOW()
 WHERE id = $1
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: SetFeedFetchError :exec
+-- A NULL error marks the last fetch as successful.
+UPDATE feeds
+SET last_fetch_error = $2
+WHERE id = $1;
+
+-- name: UpdateFeedSiteInfo :exec
+UPDATE feeds
+SET site_title = $2, site_url = $3, updated_at = NOW()
+WHERE id = $1;
+
+-- name: CountFeedFollowers :one
+SELECT COUNT(*) FROM feed_follows WHERE feed_id = $1;
+
+-- name: UpdateFeedFavicon :exec
+UPDATE feeds
+SET favicon = $2, favicon_checked_at = NOW()
+WHERE id = $1;
+
+-- name: ListFeedDirectory :many
+SELECT feeds.*,
+       (SELECT COUNT(*) FROM feed_follows WHERE feed_follows.feed_id = feeds.id) AS follower_count,
+       COUNT(posts.id) FILTER (WHERE posts.published_at > NOW() - INTERVAL '30 days') AS recent_post_count,
+       MAX(posts.published_at) AS last_post_at
+FROM feeds
+LEFT JOIN posts ON posts.feed_id = feeds.id
+WHERE feeds.disabled_at IS NULL
+  AND (
+        sqlc.arg(search)::text = ''
+     OR feeds.name ILIKE '%' || sqlc.arg(search)::text || '%' ESCAPE '\'
+     OR feeds.url ILIKE '%' || sqlc.arg(search)::text || '%' ESCAPE '\'
+     OR feeds.site_title ILIKE '%' || sqlc.arg(search)::text || '%' ESCAPE '\'
+  )
+GROUP BY feeds.id
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'followers'
+        THEN (SELECT COUNT(*) FROM feed_follows WHERE feed_follows.feed_id = feeds.id)
+    END DESC,
+    CASE WHEN sqlc.arg(sort)::text = 'activity' THEN MAX(posts.published_at) END DESC NULLS LAST,
+    feeds.created_at DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountFeedDirectory :one
+SELECT COUNT(*) FROM feeds
+WHERE feeds.disabled_at IS NULL
+  AND (
+        sqlc.arg(search)::text = ''
+     OR feeds.name ILIKE '%' || sqlc.arg(search)::text || '%' ESCAPE '\'
+     OR feeds.url ILIKE '%' || sqlc.arg(search)::text || '%' ESCAPE '\'
+     OR feeds.site_title ILIKE '%' || sqlc.arg(search)::text || '%' ESCAPE '\'
+  );
@@ -0,0 +1,116 @@
+-- name: GetFeverGroups :many
+SELECT seq, name FROM folders WHERE user_id = $1 ORDER BY name;
+
+-- name: GetFeverFeeds :many
+SELECT feeds.seq,
+       COALESCE(feed_follows.title, feeds.name)::text AS title,
+       feeds.url,
+       feeds.site_url,
+       feeds.last_fetched_at,
+       folders.seq AS folder_seq
+FROM feed_follows
+JOIN feeds ON feeds.id = feed_follows.feed_id
+LEFT JOIN folders ON folders.id = feed_follows.folder_id
+WHERE feed_follows.user_id = $1
+ORDER BY feeds.seq;
+
+-- name: GetFeverFavicons :many
+SELECT feeds.seq, feeds.favicon
+FROM feed_follows
+JOIN feeds ON feeds.id = feed_follows.feed_id
+WHERE feed_follows.user_id = $1 AND feeds.favicon IS NOT NULL
+ORDER BY feeds.seq;
+
+-- name: GetFeverItems :many
+-- Items after since_id (oldest first), before max_id (newest first), or
+-- with the given IDs. Hidden posts are left out like in the timeline.
+SELECT posts.*,
+       feeds.seq AS feed_seq,
+       (post_states.read_at IS NOT NULL)::boolean AS is_read,
+       (post_states.starred_at IS NOT NULL)::boolean AS is_starred
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+JOIN feeds ON feeds.id = posts.feed_id
+LEFT JOIN post_states
+  ON post_states.post_id = posts.id
+ AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+  AND post_states.hidden_at IS NULL
+  AND (sqlc.narg(since_id)::bigint IS NULL OR posts.seq > sqlc.narg(since_id)::bigint)
+  AND (sqlc.narg(max_id)::bigint IS NULL OR posts.seq < sqlc.narg(max_id)::bigint)
+  AND (NOT sqlc.arg(filter_ids)::boolean OR posts.seq = ANY(sqlc.arg(ids)::bigint[]))
+ORDER BY
+    CASE WHEN sqlc.narg(max_id)::bigint IS NOT NULL THEN posts.seq END DESC,
+    posts.seq
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountFeverItems :one
+SELECT COUNT(*)
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+LEFT JOIN post_states
+  ON post_states.post_id = posts.id
+ AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = $1
+  AND post_states.hidden_at IS NULL;
+
+-- name: GetFeverUnreadItemIDs :many
+SELECT posts.seq
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+LEFT JOIN post_states
+  ON post_states.post_id = posts.id
+ AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = $1
+  AND post_states.hidden_at IS NULL
+  AND post_states.read_at IS NULL
+ORDER BY posts.seq;
+
+-- name: GetFeverSavedItemIDs :many
+SELECT posts.seq
+FROM posts
+JOIN post_states ON post_states.post_id = posts.id
+WHERE post_states.user_id = $1
+  AND post_states.starred_at IS NOT NULL
+ORDER BY posts.seq;
+
+-- name: GetPostIDForFeverItem :one
+SELECT posts.id
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+WHERE posts.seq = $1 AND feed_follows.user_id = $2;
+
+-- name: MarkFeverFeedRead :exec
+-- Marks a feed's posts read up to "before", as Fever clients do when the
+-- user catches up on a feed.
+INSERT INTO post_states(user_id, post_id, created_at, updated_at, read_at)
+SELECT feed_follows.user_id, posts.id, NOW(), NOW(), NOW()
+FROM posts
+JOIN feeds ON feeds.id = posts.feed_id
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+  AND feeds.seq = sqlc.arg(feed_seq)
+  AND posts.created_at <= sqlc.arg(before)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read_at = COALESCE(post_states.read_at, NOW()),
+    updated_at = NOW();
+
+-- name: MarkFeverGroupRead :exec
+-- Group 0 is Fever's "Kindling" super group of all feeds.
+INSERT INTO post_states(user_id, post_id, created_at, updated_at, read_at)
+SELECT feed_follows.user_id, posts.id, NOW(), NOW(), NOW()
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+LEFT JOIN folders ON folders.id = feed_follows.folder_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+  AND (sqlc.arg(group_seq)::bigint = 0 OR folders.seq = sqlc.arg(group_seq)::bigint)
+  AND posts.created_at <= sqlc.arg(before)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read_at = COALESCE(post_states.read_at, NOW()),
+    updated_at = NOW();
+
+-- name: GetLastRefreshedAt :one
+SELECT COALESCE(MAX(feeds.last_fetched_at), TIMESTAMP 'epoch')::timestamp AS last_refreshed_at
+FROM feed_follows
+JOIN feeds ON feeds.id = feed_follows.feed_id
+WHERE feed_follows.user_id = $1;
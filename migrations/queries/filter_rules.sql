@@ -0,0 +1,43 @@
+-- name: CreateFilterRule :one
+INSERT INTO filter_rules(
+    id, created_at, updated_at, user_id, name, scope, folder_id, feed_id,
+    field, match_type, pattern, action, tag_name, enabled
+)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+RETURNING *;
+
+-- name: GetFilterRules :many
+SELECT * FROM filter_rules WHERE user_id = $1 ORDER BY created_at;
+
+-- name: GetFilterRule :one
+SELECT * FROM filter_rules WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteFilterRule :execrows
+DELETE FROM filter_rules WHERE id = $1 AND user_id = $2;
+
+-- name: GetFilterRulesForFeed :many
+SELECT filter_rules.*
+FROM filter_rules
+JOIN feed_follows
+  ON feed_follows.user_id = filter_rules.user_id
+ AND feed_follows.feed_id = sqlc.arg(feed_id)
+WHERE filter_rules.enabled
+  AND (
+        filter_rules.scope = 'global'
+     OR (filter_rules.scope = 'feed' AND filter_rules.feed_id = sqlc.arg(feed_id))
+     OR (filter_rules.scope = 'folder' AND filter_rules.folder_id = feed_follows.folder_id)
+  )
+ORDER BY filter_rules.created_at;
+
+-- name: GetPostsInRuleScope :many
+SELECT posts.*
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+  AND (
+        sqlc.arg(scope)::text = 'global'
+     OR (sqlc.arg(scope)::text = 'feed' AND posts.feed_id = sqlc.narg(feed_id))
+     OR (sqlc.arg(scope)::text = 'folder' AND feed_follows.folder_id = sqlc.narg(folder_id))
+  )
+ORDER BY posts.published_at DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
@@ -0,0 +1,16 @@
+-- name: CreateFolder :one
+INSERT INTO folders(id, created_at, updated_at, user_id, name)
+VALUES($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetFolders :many
+SELECT * FROM folders WHERE user_id = $1 ORDER BY name;
+
+-- name: GetFolder :one
+SELECT * FROM folders WHERE id = $1 AND user_id = $2;
+
+-- name: GetFolderByName :one
+SELECT * FROM folders WHERE user_id = $1 AND name = $2;
+
+-- name: DeleteFolder :execrows
+DELETE FROM folders WHERE id = $1 AND user_id = $2;
@@ -0,0 +1,42 @@
+-- name: ApplyPostState :exec
+INSERT INTO post_states(user_id, post_id, created_at, updated_at, read_at, starred_at, hidden_at)
+VALUES(
+    sqlc.arg(user_id),
+    sqlc.arg(post_id),
+    NOW(),
+    NOW(),
+    CASE WHEN sqlc.arg(mark_read)::boolean THEN NOW() END,
+    CASE WHEN sqlc.arg(star)::boolean THEN NOW() END,
+    CASE WHEN sqlc.arg(hide)::boolean THEN NOW() END
+)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read_at = COALESCE(post_states.read_at, EXCLUDED.read_at),
+    starred_at = COALESCE(post_states.starred_at, EXCLUDED.starred_at),
+    hidden_at = COALESCE(post_states.hidden_at, EXCLUDED.hidden_at),
+    updated_at = NOW();
+
+-- name: SetPostRead :exec
+INSERT INTO post_states(user_id, post_id, created_at, updated_at, read_at)
+VALUES(
+    sqlc.arg(user_id),
+    sqlc.arg(post_id),
+    NOW(),
+    NOW(),
+    CASE WHEN sqlc.arg(read)::boolean THEN NOW() END
+)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read_at = CASE WHEN sqlc.arg(read)::boolean THEN COALESCE(post_states.read_at, NOW()) END,
+    updated_at = NOW();
+
+-- name: SetPostStarred :exec
+INSERT INTO post_states(user_id, post_id, created_at, updated_at, starred_at)
+VALUES(
+    sqlc.arg(user_id),
+    sqlc.arg(post_id),
+    NOW(),
+    NOW(),
+    CASE WHEN sqlc.arg(starred)::boolean THEN NOW() END
+)
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET starred_at = CASE WHEN sqlc.arg(starred)::boolean THEN COALESCE(post_states.starred_at, NOW()) END,
+    updated_at = NOW();
@@ -1,4 +1,4 @@
--- name: CreatePost :exec
+-- name: CreatePost :execrows
 INSERT INTO posts (
                   id,
                   created_at,
@@ -7,19 +7,100 @@ INSERT INTO posts (
                   description,
                   url,
                   feed_id,
-                  published_at
+                  published_at,
+                  author
                 )
-VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
 ON CONFLICT (url) DO NOTHING;
 -- RETURNING *;
 
 -- name: GetPostsForUser :many
+SELECT posts.*,
+       COALESCE(feed_follows.title, feeds.name)::text AS feed_title,
+       feed_follows.priority AS feed_priority,
+       (post_states.read_at IS NOT NULL)::boolean AS is_read,
+       (post_states.starred_at IS NOT NULL)::boolean AS is_starred
+FROM posts
+JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+JOIN feeds ON feeds.id = posts.feed_id
+LEFT JOIN post_states
+  ON post_states.post_id = posts.id
+ AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+  AND (
+        sqlc.narg(tag_id)::uuid IS NULL
+     OR EXISTS (
+            SELECT 1 FROM post_tags
+            WHERE post_tags.post_id = posts.id
+              AND post_tags.tag_id = sqlc.narg(tag_id)::uuid
+        )
+  )
+  AND (
+        sqlc.narg(folder_id)::uuid IS NULL
+     OR feed_follows.folder_id = sqlc.narg(folder_id)::uuid
+  )
+  AND (NOT sqlc.arg(starred_only)::boolean OR post_states.starred_at IS NOT NULL)
+  -- Muted feeds and hidden posts only drop out of the main and folder
+  -- timelines; tagged and starred posts were picked out by the user.
+  AND (
+        sqlc.narg(tag_id)::uuid IS NOT NULL
+     OR sqlc.arg(starred_only)::boolean
+     OR (NOT feed_follows.muted AND post_states.hidden_at IS NULL)
+  )
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::text = 'priority' THEN feed_follows.priority END DESC,
+    posts.published_at DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: GetPostsForUserSince :many
+-- Same shape as GetPostsForUser, for streaming: posts after a given stream
+-- position, oldest first, without muted feeds or hidden posts. Posts not
+-- published yet, or published by transactions that may still be running,
+-- are left for a later read.
+SELECT posts.*,
+       COALESCE(feed_follows.title, feeds.name)::text AS feed_title,
+       feed_follows.priority AS feed_priority,
+       (post_states.read_at IS NOT NULL)::boolean AS is_read,
+       (post_states.starred_at IS NOT NULL)::boolean AS is_starred
+FROM posts
+JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+JOIN feeds ON feeds.id = posts.feed_id
+LEFT JOIN post_states
+  ON post_states.post_id = posts.id
+ AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+  AND (posts.stream_xid, posts.seq) > (sqlc.arg(after_xid)::bigint, sqlc.arg(after_seq)::bigint)
+  AND posts.stream_xid < pg_snapshot_xmin(pg_current_snapshot())::text::bigint
+  AND NOT feed_follows.muted
+  AND post_states.hidden_at IS NULL
+ORDER BY posts.stream_xid, posts.seq
+LIMIT sqlc.arg(page_limit);
+
+-- name: PublishPosts :exec
+-- Releases new posts to the stream once filter rules have run on them, and
+-- wakes the streams when that commits.
+WITH published AS (
+    UPDATE posts
+    SET stream_xid = pg_current_xact_id()::text::bigint
+    WHERE id = ANY(sqlc.arg(ids)::uuid[]) AND stream_xid IS NULL
+    RETURNING id
+)
+SELECT pg_notify(
+    'new_posts',
+    json_build_object('feed_id', sqlc.arg(feed_id)::uuid, 'posts', COUNT(*))::text
+)
+FROM published;
+
+-- name: GetPostStreamHorizon :one
+-- The oldest transaction still running. Every post written from here on
+-- has a stream_xid at least this large.
+SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint AS xid;
+
+-- name: GetPostForUser :one
 SELECT posts.*
 FROM posts
 JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
-WHERE feed_follows.user_id = $1
-ORDER BY posts.published_at DESC
-LIMIT $2 OFFSET $3;
+WHERE posts.id = $1 AND feed_follows.user_id = $2;
 
 -- -- name: GetNextFeedsToFetch :many
 -- SELECT * FROM feeds
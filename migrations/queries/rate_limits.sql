@@ -0,0 +1,19 @@
+-- name: TakeRateLimitToken :one
+-- Refills the bucket for the time since it was last used, then takes a
+-- token if it has one, in a single statement so concurrent requests can't
+-- both take the last token.
+INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+VALUES (sqlc.arg(key), sqlc.arg(capacity)::float8 - 1, TRUE, NOW())
+ON CONFLICT (key) DO UPDATE SET
+    tokens = CASE
+        WHEN LEAST(sqlc.arg(capacity)::float8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::float8 * sqlc.arg(rate)::float8) >= 1
+        THEN LEAST(sqlc.arg(capacity)::float8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::float8 * sqlc.arg(rate)::float8) - 1
+        ELSE LEAST(sqlc.arg(capacity)::float8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::float8 * sqlc.arg(rate)::float8)
+    END,
+    allowed = LEAST(sqlc.arg(capacity)::float8, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::float8 * sqlc.arg(rate)::float8) >= 1,
+    updated_at = NOW()
+RETURNING tokens, allowed;
+
+-- name: DeleteIdleRateLimitBuckets :execrows
+DELETE FROM rate_limit_buckets
+WHERE updated_at < NOW() - make_interval(secs => sqlc.arg(idle_seconds)::float8);
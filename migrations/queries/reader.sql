@@ -0,0 +1,126 @@
+-- name: GetReaderSubscriptions :many
+SELECT feed_follows.id AS feed_follow_id,
+       feeds.seq,
+       COALESCE(feed_follows.title, feeds.name)::text AS title,
+       feeds.url,
+       feeds.site_url,
+       folders.name AS folder_name,
+       feed_follows.created_at
+FROM feed_follows
+JOIN feeds ON feeds.id = feed_follows.feed_id
+LEFT JOIN folders ON folders.id = feed_follows.folder_id
+WHERE feed_follows.user_id = $1
+ORDER BY title;
+
+-- name: GetReaderSubscription :one
+SELECT feed_follows.id AS feed_follow_id, folders.name AS folder_name
+FROM feed_follows
+JOIN feeds ON feeds.id = feed_follows.feed_id
+LEFT JOIN folders ON folders.id = feed_follows.folder_id
+WHERE feed_follows.user_id = $1 AND feeds.seq = $2;
+
+-- name: GetReaderItems :many
+-- A page of a stream, newest first unless oldest_first. The continuation
+-- is the seq of the last item of the previous page.
+SELECT posts.*,
+       feeds.seq AS feed_seq,
+       COALESCE(feed_follows.title, feeds.name)::text AS feed_title,
+       feeds.site_url AS feed_site_url,
+       folders.name AS folder_name,
+       (post_states.read_at IS NOT NULL)::boolean AS is_read,
+       (post_states.starred_at IS NOT NULL)::boolean AS is_starred
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+JOIN feeds ON feeds.id = posts.feed_id
+LEFT JOIN folders ON folders.id = feed_follows.folder_id
+LEFT JOIN post_states
+  ON post_states.post_id = posts.id
+ AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+  AND post_states.hidden_at IS NULL
+  AND (sqlc.narg(feed_seq)::bigint IS NULL OR feeds.seq = sqlc.narg(feed_seq)::bigint)
+  AND (sqlc.narg(folder_name)::text IS NULL OR folders.name = sqlc.narg(folder_name)::text)
+  AND (NOT sqlc.arg(read_only)::boolean OR post_states.read_at IS NOT NULL)
+  AND (NOT sqlc.arg(exclude_read)::boolean OR post_states.read_at IS NULL)
+  AND (NOT sqlc.arg(starred_only)::boolean OR post_states.starred_at IS NOT NULL)
+  AND (NOT sqlc.arg(exclude_starred)::boolean OR post_states.starred_at IS NULL)
+  AND (sqlc.narg(newer_than)::timestamp IS NULL OR posts.created_at >= sqlc.narg(newer_than)::timestamp)
+  AND (sqlc.narg(older_than)::timestamp IS NULL OR posts.created_at < sqlc.narg(older_than)::timestamp)
+  AND (sqlc.narg(continuation)::bigint IS NULL
+       OR (sqlc.arg(oldest_first)::boolean AND posts.seq > sqlc.narg(continuation)::bigint)
+       OR (NOT sqlc.arg(oldest_first)::boolean AND posts.seq < sqlc.narg(continuation)::bigint))
+ORDER BY
+    CASE WHEN sqlc.arg(oldest_first)::boolean THEN posts.seq END,
+    posts.seq DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: GetReaderItemIDs :many
+-- Same stream filters as GetReaderItems, without the content.
+SELECT posts.seq, posts.created_at
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+JOIN feeds ON feeds.id = posts.feed_id
+LEFT JOIN folders ON folders.id = feed_follows.folder_id
+LEFT JOIN post_states
+  ON post_states.post_id = posts.id
+ AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+  AND post_states.hidden_at IS NULL
+  AND (sqlc.narg(feed_seq)::bigint IS NULL OR feeds.seq = sqlc.narg(feed_seq)::bigint)
+  AND (sqlc.narg(folder_name)::text IS NULL OR folders.name = sqlc.narg(folder_name)::text)
+  AND (NOT sqlc.arg(read_only)::boolean OR post_states.read_at IS NOT NULL)
+  AND (NOT sqlc.arg(exclude_read)::boolean OR post_states.read_at IS NULL)
+  AND (NOT sqlc.arg(starred_only)::boolean OR post_states.starred_at IS NOT NULL)
+  AND (NOT sqlc.arg(exclude_starred)::boolean OR post_states.starred_at IS NULL)
+  AND (sqlc.narg(newer_than)::timestamp IS NULL OR posts.created_at >= sqlc.narg(newer_than)::timestamp)
+  AND (sqlc.narg(older_than)::timestamp IS NULL OR posts.created_at < sqlc.narg(older_than)::timestamp)
+  AND (sqlc.narg(continuation)::bigint IS NULL
+       OR (sqlc.arg(oldest_first)::boolean AND posts.seq > sqlc.narg(continuation)::bigint)
+       OR (NOT sqlc.arg(oldest_first)::boolean AND posts.seq < sqlc.narg(continuation)::bigint))
+ORDER BY
+    CASE WHEN sqlc.arg(oldest_first)::boolean THEN posts.seq END,
+    posts.seq DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: GetReaderItemsByIDs :many
+SELECT posts.*,
+       feeds.seq AS feed_seq,
+       COALESCE(feed_follows.title, feeds.name)::text AS feed_title,
+       feeds.site_url AS feed_site_url,
+       folders.name AS folder_name,
+       (post_states.read_at IS NOT NULL)::boolean AS is_read,
+       (post_states.starred_at IS NOT NULL)::boolean AS is_starred
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+JOIN feeds ON feeds.id = posts.feed_id
+LEFT JOIN folders ON folders.id = feed_follows.folder_id
+LEFT JOIN post_states
+  ON post_states.post_id = posts.id
+ AND post_states.user_id = feed_follows.user_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+  AND posts.seq = ANY(sqlc.arg(ids)::bigint[])
+ORDER BY posts.seq DESC;
+
+-- name: SetReaderItemsRead :exec
+INSERT INTO post_states(user_id, post_id, created_at, updated_at, read_at)
+SELECT feed_follows.user_id, posts.id, NOW(), NOW(),
+       CASE WHEN sqlc.arg(read)::boolean THEN NOW() END
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+  AND posts.seq = ANY(sqlc.arg(ids)::bigint[])
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET read_at = CASE WHEN sqlc.arg(read)::boolean THEN COALESCE(post_states.read_at, NOW()) END,
+    updated_at = NOW();
+
+-- name: SetReaderItemsStarred :exec
+INSERT INTO post_states(user_id, post_id, created_at, updated_at, starred_at)
+SELECT feed_follows.user_id, posts.id, NOW(), NOW(),
+       CASE WHEN sqlc.arg(starred)::boolean THEN NOW() END
+FROM posts
+JOIN feed_follows ON feed_follows.feed_id = posts.feed_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+  AND posts.seq = ANY(sqlc.arg(ids)::bigint[])
+ON CONFLICT (user_id, post_id) DO UPDATE
+SET starred_at = CASE WHEN sqlc.arg(starred)::boolean THEN COALESCE(post_states.starred_at, NOW()) END,
+    updated_at = NOW();
@@ -0,0 +1,56 @@
+-- name: BeginScraperRun :exec
+INSERT INTO scraper_heartbeats(worker_id, started_at, last_beat_at, last_run_started_at, last_run_feeds)
+VALUES($1, $2, NOW(), NOW(), $3)
+ON CONFLICT (worker_id) DO UPDATE
+SET started_at = EXCLUDED.started_at,
+    last_beat_at = NOW(),
+    last_run_started_at = NOW(),
+    last_run_feeds = EXCLUDED.last_run_feeds;
+
+-- name: FinishScraperRun :exec
+UPDATE scraper_heartbeats
+SET last_beat_at = NOW(), last_run_finished_at = NOW()
+WHERE worker_id = $1;
+
+-- name: GetScraperHeartbeats :many
+SELECT * FROM scraper_heartbeats ORDER BY last_beat_at DESC;
+
+-- name: GetScraperFeedStats :one
+SELECT COUNT(*) AS total,
+       COUNT(*) FILTER (WHERE disabled_at IS NOT NULL) AS disabled,
+       COUNT(*) FILTER (WHERE disabled_at IS NULL AND last_fetched_at IS NULL) AS never_fetched,
+       COUNT(*) FILTER (WHERE disabled_at IS NULL AND last_fetch_error IS NOT NULL) AS failing
+FROM feeds;
+
+-- name: GetOldestFetchedAt :one
+-- How far behind the scraper is: the longest any enabled feed has gone
+-- without a fetch.
+SELECT last_fetched_at FROM feeds
+WHERE disabled_at IS NULL AND last_fetched_at IS NOT NULL
+ORDER BY last_fetched_at
+LIMIT 1;
+
+-- name: GetNextDueSince :one
+-- Since when the feed next in line to be fetched has waited: its last
+-- fetch, or its creation if it was never fetched. Mirrors
+-- GetNextFeedsToFetch.
+SELECT COALESCE(feeds.last_fetched_at, feeds.created_at)::timestamp AS due_since
+FROM feeds
+LEFT JOIN websub_subscriptions
+  ON websub_subscriptions.feed_id = feeds.id
+ AND websub_subscriptions.state = 'active'
+ AND websub_subscriptions.expires_at > NOW()
+WHERE feeds.disabled_at IS NULL
+  AND (
+        websub_subscriptions.id IS NULL
+     OR feeds.last_fetched_at IS NULL
+     OR feeds.last_fetched_at < NOW() - INTERVAL '6 hours'
+  )
+ORDER BY feeds.last_fetched_at NULLS FIRST
+LIMIT 1;
+
+-- name: GetFailingFeeds :many
+SELECT * FROM feeds
+WHERE disabled_at IS NULL AND last_fetch_error IS NOT NULL
+ORDER BY last_fetched_at DESC
+LIMIT $1;
@@ -0,0 +1,38 @@
+-- name: CreateSession :one
+INSERT INTO sessions(id, created_at, last_seen_at, expires_at, user_id, token_hash, csrf_token, user_agent, ip)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetActiveSessionByHash :one
+SELECT sqlc.embed(sessions), sqlc.embed(users)
+FROM sessions
+JOIN users ON users.id = sessions.user_id
+WHERE sessions.token_hash = $1
+  AND sessions.expires_at > NOW()
+  AND users.disabled_at IS NULL;
+
+-- name: TouchSession :exec
+-- Like API keys, last_seen_at is only kept to the minute.
+UPDATE sessions
+SET last_seen_at = NOW()
+WHERE id = $1 AND last_seen_at < NOW() - INTERVAL '1 minute';
+
+-- name: DeleteSession :exec
+DELETE FROM sessions WHERE id = $1;
+
+-- name: DeleteUserSessions :exec
+-- Signs a user out everywhere except, if given, the session keep_id.
+DELETE FROM sessions
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(keep_id)::uuid IS NULL OR id <> sqlc.narg(keep_id)::uuid);
+
+-- name: CreatePasswordReset :one
+INSERT INTO password_resets(id, created_at, expires_at, user_id, created_by, token_hash)
+VALUES($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ConsumePasswordReset :one
+UPDATE password_resets
+SET used_at = NOW()
+WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+RETURNING *;
@@ -0,0 +1,41 @@
+-- name: CreateTag :one
+INSERT INTO tags(id, created_at, updated_at, user_id, name)
+VALUES($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: UpsertTag :one
+INSERT INTO tags(id, created_at, updated_at, user_id, name)
+VALUES($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: GetTags :many
+SELECT tags.*, COUNT(post_tags.post_id) AS post_count
+FROM tags
+LEFT JOIN post_tags ON post_tags.tag_id = tags.id
+WHERE tags.user_id = $1
+GROUP BY tags.id
+ORDER BY tags.name;
+
+-- name: GetTag :one
+SELECT * FROM tags WHERE id = $1 AND user_id = $2;
+
+-- name: GetTagByName :one
+SELECT * FROM tags WHERE user_id = $1 AND name = $2;
+
+-- name: RenameTag :one
+UPDATE tags
+SET name = $3, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteTag :execrows
+DELETE FROM tags WHERE id = $1 AND user_id = $2;
+
+-- name: AddPostTag :exec
+INSERT INTO post_tags(tag_id, post_id, created_at)
+VALUES($1, $2, NOW())
+ON CONFLICT (tag_id, post_id) DO NOTHING;
+
+-- name: RemovePostTag :execrows
+DELETE FROM post_tags WHERE tag_id = $1 AND post_id = $2;
@@ -0,0 +1,15 @@
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities(id, created_at, last_login_at, user_id, issuer, subject, email)
+VALUES($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetUserByIdentity :one
+SELECT sqlc.embed(user_identities), sqlc.embed(users)
+FROM user_identities
+JOIN users ON users.id = user_identities.user_id
+WHERE user_identities.issuer = $1 AND user_identities.subject = $2;
+
+-- name: TouchUserIdentity :exec
+UPDATE user_identities
+SET last_login_at = NOW(), email = $2
+WHERE id = $1;
@@ -1,7 +1,23 @@
 -- name: CreateUser :one
-INSERT INTO users(id, created_at, updated_at, name, api_key)
-VALUES($1, $2, $3, $4, encode(sha256(random()::text::bytea), 'hex'))
+INSERT INTO users(id, created_at, updated_at, name, username, email, password_hash, password_changed_at)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING *;
 
--- name: GetUserByAPIKey :one
-SELECT * FROM users WHERE api_key = $1;
\ No newline at end of file
+-- name: GetUserByID :one
+SELECT * FROM users WHERE id = $1;
+
+-- name: GetUserByUsername :one
+SELECT * FROM users WHERE username = $1;
+
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET password_hash = $2, password_changed_at = NOW(), updated_at = NOW()
+WHERE id = $1;
+
+-- name: GetUserByEmail :one
+SELECT * FROM users WHERE email = $1;
+
+-- name: UpdateUserRole :exec
+UPDATE users
+SET role = $2, updated_at = NOW()
+WHERE id = $1;
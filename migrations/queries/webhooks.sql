@@ -0,0 +1,111 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks(
+    id, created_at, updated_at, user_id, name, url, secret, feed_id, folder_id, keyword, enabled
+)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING *;
+
+-- name: GetWebhooks :many
+SELECT * FROM webhooks WHERE user_id = $1 ORDER BY created_at;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks WHERE id = $1 AND user_id = $2;
+
+-- name: GetWebhookByID :one
+SELECT * FROM webhooks WHERE id = $1;
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks WHERE id = $1 AND user_id = $2;
+
+-- name: GetWebhooksForFeed :many
+-- Along with each webhook, how its owner wants to hear about the feed.
+SELECT sqlc.embed(webhooks), feed_follows.notification_mode
+FROM webhooks
+JOIN feed_follows
+  ON feed_follows.user_id = webhooks.user_id
+ AND feed_follows.feed_id = sqlc.arg(feed_id)
+WHERE webhooks.enabled
+  AND feed_follows.notification_mode <> 'off'
+  AND (webhooks.feed_id IS NULL OR webhooks.feed_id = sqlc.arg(feed_id))
+  AND (webhooks.folder_id IS NULL OR webhooks.folder_id = feed_follows.folder_id)
+ORDER BY webhooks.created_at;
+
+-- name: AddWebhookDigestPost :exec
+INSERT INTO webhook_digest_posts(webhook_id, post_id, created_at)
+VALUES($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: GetDueWebhookDigests :many
+-- Webhooks whose oldest waiting post has waited a whole digest interval.
+SELECT webhook_id
+FROM webhook_digest_posts
+GROUP BY webhook_id
+HAVING MIN(created_at) <= sqlc.arg(due_before)::timestamp
+LIMIT sqlc.arg(batch_size);
+
+-- name: ClaimWebhookDigestPosts :many
+-- Takes a webhook's waiting posts out of the digest, so a concurrent worker
+-- can't send them again.
+WITH claimed AS (
+    DELETE FROM webhook_digest_posts
+    WHERE webhook_id = $1
+    RETURNING post_id, created_at
+)
+SELECT sqlc.embed(posts), sqlc.embed(feeds), claimed.created_at AS queued_at
+FROM claimed
+JOIN posts ON posts.id = claimed.post_id
+JOIN feeds ON feeds.id = posts.feed_id
+ORDER BY posts.published_at;
+
+-- name: CreateWebhookDelivery :execrows
+INSERT INTO webhook_deliveries(
+    id, created_at, updated_at, webhook_id, post_id, event, payload, next_attempt_at
+)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (webhook_id, post_id, event) DO NOTHING;
+
+-- name: GetWebhookDeliveries :many
+SELECT webhook_deliveries.*
+FROM webhook_deliveries
+JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id
+WHERE webhook_deliveries.webhook_id = sqlc.arg(webhook_id)
+  AND webhooks.user_id = sqlc.arg(user_id)
+ORDER BY webhook_deliveries.created_at DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: ClaimDueWebhookDeliveries :many
+-- Leases due deliveries by pushing next_attempt_at forward, so concurrent
+-- workers skip them; the lease lapses on its own if a worker dies mid-send.
+UPDATE webhook_deliveries
+SET next_attempt_at = NOW() + sqlc.arg(lease_seconds)::int * INTERVAL '1 second'
+WHERE id IN (
+    SELECT id FROM webhook_deliveries
+    WHERE status = 'pending' AND next_attempt_at <= NOW()
+    ORDER BY next_attempt_at
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: RecordWebhookDeliveryAttempt :exec
+UPDATE webhook_deliveries
+SET status = $2,
+    attempts = attempts + 1,
+    next_attempt_at = $3,
+    last_attempt_at = $4,
+    response_status = $5,
+    last_error = $6,
+    updated_at = $4
+WHERE id = $1;
+
+-- name: RequeueWebhookDelivery :execrows
+UPDATE webhook_deliveries
+SET status = 'pending',
+    attempts = 0,
+    next_attempt_at = NOW(),
+    last_error = NULL,
+    updated_at = NOW()
+FROM webhooks
+WHERE webhook_deliveries.id = sqlc.arg(id)
+  AND webhooks.id = webhook_deliveries.webhook_id
+  AND webhooks.user_id = sqlc.arg(user_id);
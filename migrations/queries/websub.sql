@@ -0,0 +1,52 @@
+-- name: UpsertWebSubSubscription :one
+-- Starts a new subscription attempt for a feed, replacing the hub, topic and
+-- secret of any previous one.
+INSERT INTO websub_subscriptions(id, created_at, updated_at, feed_id, hub_url, topic_url, secret)
+VALUES($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (feed_id) DO UPDATE
+SET updated_at = EXCLUDED.updated_at,
+    hub_url = EXCLUDED.hub_url,
+    topic_url = EXCLUDED.topic_url,
+    secret = EXCLUDED.secret,
+    state = 'pending',
+    last_error = NULL
+RETURNING *;
+
+-- name: GetWebSubSubscription :one
+SELECT * FROM websub_subscriptions WHERE id = $1;
+
+-- name: GetWebSubSubscriptionForFeed :one
+SELECT * FROM websub_subscriptions WHERE feed_id = $1;
+
+-- name: ActivateWebSubSubscription :exec
+UPDATE websub_subscriptions
+SET state = 'active',
+    lease_seconds = $2,
+    expires_at = $3,
+    last_error = NULL,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: DenyWebSubSubscription :exec
+UPDATE websub_subscriptions
+SET state = 'denied', last_error = $2, expires_at = NULL, updated_at = NOW()
+WHERE id = $1;
+
+-- name: RecordWebSubRequest :exec
+-- Notes a (re)subscription request sent to the hub and its error, if any.
+UPDATE websub_subscriptions
+SET last_error = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: GetWebSubSubscriptionsToRenew :many
+-- Active leases with less than a tenth of their time left, and requests the
+-- hub hasn't verified yet, unless one was sent since retry_before.
+SELECT * FROM websub_subscriptions
+WHERE updated_at < sqlc.arg(retry_before)::timestamp
+  AND (
+        (state = 'active'
+         AND expires_at - COALESCE(lease_seconds, 0) * INTERVAL '1 second' / 10 < NOW())
+     OR state = 'pending'
+  )
+ORDER BY updated_at
+LIMIT sqlc.arg(batch_size);
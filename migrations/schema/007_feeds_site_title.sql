@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE feeds ADD COLUMN site_title TEXT;
+
+CREATE INDEX posts_feed_id_published_at_idx ON posts(feed_id, published_at DESC);
+
+-- +goose Down
+DROP INDEX posts_feed_id_published_at_idx;
+
+ALTER TABLE feeds DROP COLUMN site_title;
@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE feed_follows
+    ADD COLUMN title TEXT,
+    ADD COLUMN muted BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN priority INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN notification_mode TEXT NOT NULL DEFAULT 'off'
+        CHECK (notification_mode IN ('off', 'instant', 'digest'));
+
+-- +goose Down
+ALTER TABLE feed_follows
+    DROP COLUMN notification_mode,
+    DROP COLUMN priority,
+    DROP COLUMN muted,
+    DROP COLUMN title;
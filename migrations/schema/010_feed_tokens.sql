@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE feed_tokens (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    last_used_at TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE feed_tokens;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- seq gives posts a strictly increasing insertion order, used as the SSE
+-- event ID so a client can resume where it left off.
+ALTER TABLE posts ADD COLUMN seq BIGSERIAL NOT NULL;
+CREATE UNIQUE INDEX posts_seq_idx ON posts(seq);
+
+-- +goose StatementBegin
+CREATE FUNCTION notify_new_post() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify(
+        'new_posts',
+        json_build_object('id', NEW.id, 'feed_id', NEW.feed_id, 'seq', NEW.seq)::text
+    );
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER posts_notify_insert
+    AFTER INSERT ON posts
+    FOR EACH ROW EXECUTE FUNCTION notify_new_post();
+
+-- +goose Down
+DROP TRIGGER posts_notify_insert ON posts;
+DROP FUNCTION notify_new_post();
+ALTER TABLE posts DROP COLUMN seq;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Fever clients log in with md5("<user name>:<api key>").
+ALTER TABLE users ADD COLUMN fever_api_key VARCHAR(32)
+    GENERATED ALWAYS AS (md5(name || ':' || api_key)) STORED;
+CREATE INDEX users_fever_api_key_idx ON users(fever_api_key);
+
+-- Fever identifies feeds and groups by integer, like posts.seq.
+ALTER TABLE feeds ADD COLUMN seq BIGSERIAL NOT NULL;
+CREATE UNIQUE INDEX feeds_seq_idx ON feeds(seq);
+ALTER TABLE folders ADD COLUMN seq BIGSERIAL NOT NULL;
+CREATE UNIQUE INDEX folders_seq_idx ON folders(seq);
+
+ALTER TABLE feeds ADD COLUMN site_url TEXT;
+ALTER TABLE feeds ADD COLUMN favicon TEXT;
+ALTER TABLE feeds ADD COLUMN favicon_checked_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN favicon_checked_at;
+ALTER TABLE feeds DROP COLUMN favicon;
+ALTER TABLE feeds DROP COLUMN site_url;
+ALTER TABLE folders DROP COLUMN seq;
+ALTER TABLE feeds DROP COLUMN seq;
+ALTER TABLE users DROP COLUMN fever_api_key;
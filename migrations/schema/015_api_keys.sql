@@ -0,0 +1,40 @@
+-- +goose Up
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    prefix VARCHAR(8) NOT NULL,
+    key_hash VARCHAR(64) UNIQUE NOT NULL,
+    -- md5("<user name>:<key>"), what Fever and Google Reader API clients
+    -- authenticate with.
+    client_hash VARCHAR(32) NOT NULL,
+    scope TEXT NOT NULL CHECK (scope IN ('read', 'write', 'admin')),
+    last_used_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX api_keys_user_id_idx ON api_keys(user_id);
+CREATE INDEX api_keys_client_hash_idx ON api_keys(client_hash);
+
+-- Existing keys carry over as full access keys.
+INSERT INTO api_keys(id, created_at, updated_at, user_id, name, prefix, key_hash, client_hash, scope)
+SELECT gen_random_uuid(), NOW(), NOW(), id, 'default', LEFT(api_key, 8),
+       encode(sha256(api_key::bytea), 'hex'), fever_api_key, 'admin'
+FROM users;
+
+ALTER TABLE users DROP COLUMN fever_api_key;
+ALTER TABLE users DROP COLUMN api_key;
+
+-- +goose Down
+-- Keys are only stored hashed, so users get new random keys back.
+ALTER TABLE users ADD COLUMN api_key VARCHAR(64) UNIQUE NOT NULL DEFAULT (
+    encode(sha256(random()::text::bytea), 'hex')
+);
+ALTER TABLE users ADD COLUMN fever_api_key VARCHAR(32)
+    GENERATED ALWAYS AS (md5(name || ':' || api_key)) STORED;
+CREATE INDEX users_fever_api_key_idx ON users(fever_api_key);
+
+DROP TABLE api_keys;
@@ -0,0 +1,36 @@
+-- +goose Up
+-- Disabled users can't authenticate; disabled feeds aren't fetched.
+ALTER TABLE users ADD COLUMN disabled_at TIMESTAMP;
+ALTER TABLE feeds ADD COLUMN disabled_at TIMESTAMP;
+
+-- The error of the feed's last failed fetch, cleared when a fetch succeeds.
+ALTER TABLE feeds ADD COLUMN last_fetch_error TEXT;
+
+-- One row per scraper process, updated every run.
+CREATE TABLE scraper_heartbeats (
+    worker_id TEXT PRIMARY KEY,
+    started_at TIMESTAMP NOT NULL,
+    last_beat_at TIMESTAMP NOT NULL,
+    last_run_started_at TIMESTAMP,
+    last_run_finished_at TIMESTAMP,
+    last_run_feeds INT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE admin_actions (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    admin_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id UUID,
+    details JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX admin_actions_created_at_idx ON admin_actions(created_at);
+
+-- +goose Down
+DROP TABLE admin_actions;
+DROP TABLE scraper_heartbeats;
+ALTER TABLE feeds DROP COLUMN last_fetch_error;
+ALTER TABLE feeds DROP COLUMN disabled_at;
+ALTER TABLE users DROP COLUMN disabled_at;
@@ -0,0 +1,66 @@
+-- +goose Up
+-- Ids aren't foreign keys, so entries outlive the users and feeds they
+-- mention. user_id is the user whose account or subscriptions changed.
+-- before is JSON null for creations, after for deletions.
+CREATE TABLE audit_log (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    actor_id UUID,
+    user_id UUID,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id UUID,
+    ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    before JSONB NOT NULL DEFAULT 'null',
+    after JSONB NOT NULL DEFAULT 'null'
+);
+
+CREATE INDEX audit_log_created_at_idx ON audit_log(created_at);
+CREATE INDEX audit_log_actor_id_idx ON audit_log(actor_id);
+CREATE INDEX audit_log_user_id_idx ON audit_log(user_id);
+
+-- +goose StatementBegin
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER audit_log_no_update_or_delete BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
+
+-- The admin action log becomes part of the audit log.
+INSERT INTO audit_log(id, created_at, actor_id, user_id, action, target_type, target_id, after)
+SELECT id, created_at, admin_id,
+       CASE WHEN target_type = 'user' THEN target_id END,
+       action, target_type, target_id, details
+FROM admin_actions;
+
+DROP TABLE admin_actions;
+
+-- +goose Down
+CREATE TABLE admin_actions (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    admin_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id UUID,
+    details JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX admin_actions_created_at_idx ON admin_actions(created_at);
+
+INSERT INTO admin_actions(id, created_at, admin_id, action, target_type, target_id, details)
+SELECT id, created_at, (SELECT users.id FROM users WHERE users.id = audit_log.actor_id),
+       action, target_type, target_id, CASE WHEN after = 'null' THEN '{}' ELSE after END
+FROM audit_log
+WHERE action IN ('user.disable', 'user.enable', 'user.delete', 'user.password_reset',
+                 'feed.refresh', 'feed.disable', 'feed.enable', 'feed.reassign', 'posts.purge');
+
+DROP TABLE audit_log;
+DROP FUNCTION audit_log_append_only;
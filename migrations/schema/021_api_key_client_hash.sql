@@ -0,0 +1,15 @@
+-- +goose Up
+-- client_hash is what Fever and Google Reader clients log in with, so it is
+-- stored hashed like key_hash: sha256 of the hex md5("<user name>:<key>").
+DROP INDEX api_keys_client_hash_idx;
+ALTER TABLE api_keys ALTER COLUMN client_hash TYPE VARCHAR(64);
+UPDATE api_keys SET client_hash = encode(sha256(client_hash::bytea), 'hex');
+CREATE INDEX api_keys_client_hash_idx ON api_keys(client_hash);
+
+-- +goose Down
+-- The md5 can't be recovered, so Fever and Google Reader clients stop
+-- authenticating until their keys are replaced.
+DROP INDEX api_keys_client_hash_idx;
+UPDATE api_keys SET client_hash = LEFT(client_hash, 32);
+ALTER TABLE api_keys ALTER COLUMN client_hash TYPE VARCHAR(32);
+CREATE INDEX api_keys_client_hash_idx ON api_keys(client_hash);
@@ -0,0 +1,12 @@
+-- +goose Up
+-- seq is taken when a post is inserted, not when it commits, so a post can
+-- become visible after one with a higher seq. The stream orders posts by
+-- the ID of the transaction that wrote them instead, and only reads those
+-- older than every transaction still running, which can't gain new posts.
+-- Posts from before this migration all sort first, by seq.
+ALTER TABLE posts ADD COLUMN stream_xid BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE posts ALTER COLUMN stream_xid SET DEFAULT pg_current_xact_id()::text::bigint;
+CREATE INDEX posts_stream_idx ON posts(stream_xid, seq);
+
+-- +goose Down
+ALTER TABLE posts DROP COLUMN stream_xid;
@@ -0,0 +1,31 @@
+-- +goose Up
+-- Posts used to be announced by a trigger as they were inserted, before the
+-- feed's filter rules had hidden any of them. They are now published to the
+-- stream once the rules have run: stream_xid stays NULL until then, and the
+-- same statement sends the notification.
+DROP TRIGGER posts_notify_insert ON posts;
+DROP FUNCTION notify_new_post();
+
+ALTER TABLE posts ALTER COLUMN stream_xid DROP DEFAULT;
+ALTER TABLE posts ALTER COLUMN stream_xid DROP NOT NULL;
+
+-- +goose Down
+UPDATE posts SET stream_xid = 0 WHERE stream_xid IS NULL;
+ALTER TABLE posts ALTER COLUMN stream_xid SET NOT NULL;
+ALTER TABLE posts ALTER COLUMN stream_xid SET DEFAULT pg_current_xact_id()::text::bigint;
+
+-- +goose StatementBegin
+CREATE FUNCTION notify_new_post() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify(
+        'new_posts',
+        json_build_object('id', NEW.id, 'feed_id', NEW.feed_id, 'seq', NEW.seq)::text
+    );
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER posts_notify_insert
+    AFTER INSERT ON posts
+    FOR EACH ROW EXECUTE FUNCTION notify_new_post();
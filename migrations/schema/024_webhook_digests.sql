@@ -0,0 +1,27 @@
+-- +goose Up
+-- Webhooks now honour each follow's notification_mode, so new follows get
+-- instant deliveries unless they ask otherwise. Existing follows keep the
+-- mode they have.
+ALTER TABLE feed_follows ALTER COLUMN notification_mode SET DEFAULT 'instant';
+
+-- Posts from follows in digest mode wait here until their webhook's digest
+-- is sent.
+CREATE TABLE webhook_digest_posts (
+    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (webhook_id, post_id)
+);
+
+CREATE INDEX webhook_digest_posts_created_at_idx ON webhook_digest_posts(created_at);
+
+-- A digest delivery covers many posts, so it has none of its own.
+ALTER TABLE webhook_deliveries ALTER COLUMN post_id DROP NOT NULL;
+
+-- +goose Down
+DELETE FROM webhook_deliveries WHERE post_id IS NULL;
+ALTER TABLE webhook_deliveries ALTER COLUMN post_id SET NOT NULL;
+
+DROP TABLE webhook_digest_posts;
+
+ALTER TABLE feed_follows ALTER COLUMN notification_mode SET DEFAULT 'off';